@@ -0,0 +1,250 @@
+package golog
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// The adapters below let dependencies that log through their own interfaces
+// write into a golog Logger without golog importing those libraries. Each
+// one satisfies the other library's interface structurally.
+
+// missingValue is logged for a key without a value at the end of a
+// key/value list.
+const missingValue = "(MISSING)"
+
+// KeyValueLogger adapts a Logger to the leveled "message plus alternating
+// keys and values" style, which is the shape of retryablehttp.LeveledLogger:
+//
+//	client := retryablehttp.NewClient()
+//	client.Logger = golog.NewKeyValueLogger(jl)
+//
+// Keys that aren't strings are formatted with fmt, error values are logged
+// as their message (or, for errors.Join style aggregates, the list of their
+// messages, as by Err), and a trailing key without a value is logged as
+// "(MISSING)".
+type KeyValueLogger struct {
+	logger Logger
+}
+
+// NewKeyValueLogger returns a KeyValueLogger writing to l.
+func NewKeyValueLogger(l Logger) KeyValueLogger {
+	return KeyValueLogger{logger: l}
+}
+
+// Debug logs msg at debug level.
+func (adapter KeyValueLogger) Debug(msg string, keysAndValues ...any) {
+	adapter.log(DebugLevel, msg, keysAndValues)
+}
+
+// Info logs msg at info level.
+func (adapter KeyValueLogger) Info(msg string, keysAndValues ...any) {
+	adapter.log(InfoLevel, msg, keysAndValues)
+}
+
+// Warn logs msg at warn level.
+func (adapter KeyValueLogger) Warn(msg string, keysAndValues ...any) {
+	adapter.log(WarnLevel, msg, keysAndValues)
+}
+
+// Error logs msg at error level.
+func (adapter KeyValueLogger) Error(msg string, keysAndValues ...any) {
+	adapter.log(ErrorLevel, msg, keysAndValues)
+}
+
+func (adapter KeyValueLogger) log(level Level, msg string, keysAndValues []any) {
+	if !enabled(adapter.logger, level) {
+		return
+	}
+	logAt(adapter.logger, level, msg, keyValueFields(nil, keysAndValues))
+}
+
+// KitLogger adapts a Logger to go-kit's log.Logger interface:
+//
+//	var kitLogger kitlog.Logger = golog.NewKitLogger(jl)
+//	level.Info(kitLogger).Log("msg", "listening", "addr", addr)
+//
+// The "level" key (as set by go-kit's level package) picks the level, info
+// by default, and "msg" or "message" the message. The remaining pairs become
+// fields, converted as by KeyValueLogger.
+type KitLogger struct {
+	logger Logger
+}
+
+// NewKitLogger returns a KitLogger writing to l.
+func NewKitLogger(l Logger) KitLogger {
+	return KitLogger{logger: l}
+}
+
+// Log implements go-kit's log.Logger. It never returns an error.
+func (adapter KitLogger) Log(keyvals ...any) error {
+	level := InfoLevel
+	for i := 0; i+1 < len(keyvals); i += 2 {
+		if key, ok := keyvals[i].(string); ok && key == "level" {
+			level = foreignLevel(fmt.Sprint(keyvals[i+1]), InfoLevel)
+		}
+	}
+	if !enabled(adapter.logger, level) {
+		return nil
+	}
+
+	var message string
+	fields := make([]Field, 0, len(keyvals)/2+1)
+	for i := 0; i < len(keyvals); i += 2 {
+		switch key, _ := keyvals[i].(string); {
+		case key == "level" && i+1 < len(keyvals):
+			continue
+		case (key == "msg" || key == "message") && i+1 < len(keyvals) && message == "":
+			message = fmt.Sprint(keyvals[i+1])
+			continue
+		}
+		fields = keyValueFields(fields, keyvals[i:min(i+2, len(keyvals))])
+	}
+	logAt(adapter.logger, level, message, fields)
+	return nil
+}
+
+// keyValueFields appends the alternating keys and values of keysAndValues to
+// fields.
+func keyValueFields(fields []Field, keysAndValues []any) []Field {
+	for i := 0; i < len(keysAndValues); i += 2 {
+		var value any = missingValue
+		if i+1 < len(keysAndValues) {
+			value = keysAndValues[i+1]
+		}
+		key := keyString(keysAndValues[i])
+		if err, ok := value.(error); ok {
+			if messages, ok := errorMessages(err); ok {
+				fields = append(fields, Any(key, messages))
+				continue
+			}
+			fields = append(fields, Str(key, err.Error()))
+			continue
+		}
+		fields = append(fields, Any(key, value))
+	}
+	return fields
+}
+
+func keyString(key any) string {
+	switch typed := key.(type) {
+	case string:
+		return typed
+	case fmt.Stringer:
+		return typed.String()
+	default:
+		return fmt.Sprint(key)
+	}
+}
+
+// jsonLineFormat describes the JSON lines written by another logging
+// library, so its output can be re-emitted as golog entries.
+type jsonLineFormat struct {
+	levelKey   string
+	messageKey string
+	// timeKey is discarded in favour of the logger's own timestamp.
+	timeKey string
+	// keyPrefix is trimmed from the remaining keys.
+	keyPrefix string
+}
+
+var (
+	hclogFormat   = jsonLineFormat{levelKey: "@level", messageKey: "@message", timeKey: "@timestamp", keyPrefix: "@"}
+	zerologFormat = jsonLineFormat{levelKey: "level", messageKey: "message", timeKey: "time"}
+	zapFormat     = jsonLineFormat{levelKey: "level", messageKey: "msg", timeKey: "ts"}
+)
+
+// NewHCLogWriter returns an io.Writer for the Output of a hashicorp/go-hclog
+// logger that has JSONFormat enabled. Each JSON line hclog writes becomes one
+// entry at its "@level" with its "@message"; the other keys become fields
+// with the "@" prefix dropped, so "@module" is logged as "module". hclog's
+// own "@timestamp" is discarded in favour of the logger's. Lines that aren't
+// JSON are logged as the message at info level.
+//
+//	hclog.New(&hclog.LoggerOptions{
+//	    Name:       "raft",
+//	    Output:     golog.NewHCLogWriter(jl),
+//	    JSONFormat: true,
+//	})
+func NewHCLogWriter(l Logger) io.Writer {
+	return &messageWriter{logger: l, level: InfoLevel, decode: hclogFormat.decode}
+}
+
+// NewZerologWriter returns an io.Writer that re-emits the JSON lines of a
+// zerolog logger as entries, so code still on zerolog ends up in the same
+// pipeline during a migration. "level" and "message" become the entry's
+// level and message, "time" is discarded and the other keys become fields.
+//
+//	zl := zerolog.New(golog.NewZerologWriter(jl)).With().Str("component", "cache").Logger()
+func NewZerologWriter(l Logger) io.Writer {
+	return &messageWriter{logger: l, level: InfoLevel, decode: zerologFormat.decode}
+}
+
+// NewZapWriter returns an io.Writer that re-emits the JSON lines of a zap
+// logger as entries. Pass it to a zapcore.Core built with zap's production
+// JSON encoder; "level" and "msg" become the entry's level and message, "ts"
+// is discarded and the other keys, such as "caller" and "logger", become
+// fields. golog doesn't depend on zap, so it can't provide a zapcore.Core
+// itself:
+//
+//	core := zapcore.NewCore(
+//	    zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()),
+//	    zapcore.AddSync(golog.NewZapWriter(jl)),
+//	    zapcore.DebugLevel,
+//	)
+//	zl := zap.New(core)
+func NewZapWriter(l Logger) io.Writer {
+	return &messageWriter{logger: l, level: InfoLevel, decode: zapFormat.decode}
+}
+
+// decode parses one line in format. Levels golog doesn't have map to the
+// nearest one: trace to debug, and fatal, panic and dpanic to error.
+func (format jsonLineFormat) decode(line []byte, fallback Level) (Level, string, []Field, bool) {
+	var record map[string]json.RawMessage
+	if err := json.Unmarshal(line, &record); err != nil {
+		return fallback, "", nil, false
+	}
+
+	level := fallback
+	var message string
+	if raw, ok := record[format.levelKey]; ok {
+		var name string
+		_ = json.Unmarshal(raw, &name)
+		level = foreignLevel(name, fallback)
+	}
+	if raw, ok := record[format.messageKey]; ok {
+		_ = json.Unmarshal(raw, &message)
+	}
+	delete(record, format.levelKey)
+	delete(record, format.messageKey)
+	delete(record, format.timeKey)
+
+	keys := make([]string, 0, len(record))
+	for key := range record {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	fields := make([]Field, 0, len(keys))
+	for _, key := range keys {
+		fields = append(fields, Any(strings.TrimPrefix(key, format.keyPrefix), record[key]))
+	}
+	return level, message, fields, true
+}
+
+// foreignLevel maps a level name used by another logging library to a
+// Level.
+func foreignLevel(name string, fallback Level) Level {
+	switch strings.ToLower(name) {
+	case "trace":
+		return DebugLevel
+	case "fatal", "panic", "dpanic":
+		return ErrorLevel
+	}
+	if level, err := ParseLevel(name); err == nil && level != OffLevel {
+		return level
+	}
+	return fallback
+}
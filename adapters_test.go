@@ -0,0 +1,131 @@
+package golog
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestKeyValueLogger(t *testing.T) {
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(WithOutput(buf), WithLevel(InfoLevel))
+
+	// The method set of retryablehttp.LeveledLogger.
+	var leveled interface {
+		Error(msg string, keysAndValues ...interface{})
+		Info(msg string, keysAndValues ...interface{})
+		Debug(msg string, keysAndValues ...interface{})
+		Warn(msg string, keysAndValues ...interface{})
+	} = NewKeyValueLogger(jl)
+
+	leveled.Debug("dropped", "a", 1)
+	leveled.Warn("retrying request", "url", "http://example.com", "attempt", 2, "error", errors.New("timeout"), 7, "key", "dangling")
+
+	entries := readEntries(t, buf.Bytes())
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(entries))
+	}
+	fields := entries[0].FieldMap()
+	if entries[0].Level != WarnLevel || entries[0].Message != "retrying request" {
+		t.Fatalf("unexpected entry: %+v", entries[0])
+	}
+	if fields["url"] != "http://example.com" || fields["attempt"] != int64(2) || fields["error"] != "timeout" ||
+		fields["7"] != "key" || fields["dangling"] != missingValue {
+		t.Fatalf("unexpected fields: %v", fields)
+	}
+}
+
+func TestKitLogger(t *testing.T) {
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(WithOutput(buf), WithLevel(InfoLevel))
+
+	// The method set of go-kit's log.Logger.
+	var kit interface {
+		Log(keyvals ...interface{}) error
+	} = NewKitLogger(jl)
+
+	if err := kit.Log("level", "debug", "msg", "dropped"); err != nil {
+		t.Fatalf("Log returned %v", err)
+	}
+	_ = kit.Log("level", levelValue("error"), "msg", "listen failed", "addr", ":8080", "err", errors.New("in use"))
+	_ = kit.Log("event", "started")
+
+	entries := readEntries(t, buf.Bytes())
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	first := entries[0].FieldMap()
+	if entries[0].Level != ErrorLevel || entries[0].Message != "listen failed" || first["addr"] != ":8080" || first["err"] != "in use" {
+		t.Fatalf("unexpected entry: %+v %v", entries[0], first)
+	}
+	if _, ok := first["level"]; ok {
+		t.Fatalf("expected the level key to be consumed: %v", first)
+	}
+	if entries[1].Level != InfoLevel || entries[1].Message != "" || entries[1].FieldMap()["event"] != "started" {
+		t.Fatalf("expected an info entry without a message, got %+v", entries[1])
+	}
+}
+
+// levelValue mimics go-kit's level.Value, a fmt.Stringer.
+type levelValue string
+
+func (value levelValue) String() string { return string(value) }
+
+func TestHCLogWriter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(WithOutput(buf), WithLevel(DebugLevel))
+	writer := NewHCLogWriter(jl)
+
+	lines := `{"@level":"trace","@message":"heartbeat","@module":"raft","@timestamp":"2024-01-02T03:04:05.000000Z","term":3}` + "\n" +
+		`{"@level":"warn","@message":"slow apply","@module":"raft","peers":["a","b"]}` + "\n" +
+		"not json\n"
+	if _, err := io.WriteString(writer, lines[:40]); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := io.WriteString(writer, lines[40:]); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	entries := readEntries(t, buf.Bytes())
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(entries))
+	}
+	first := entries[0].FieldMap()
+	if entries[0].Level != DebugLevel || entries[0].Message != "heartbeat" || first["module"] != "raft" || first["term"] != int64(3) {
+		t.Fatalf("unexpected entry: %+v %v", entries[0], first)
+	}
+	if _, ok := first["timestamp"]; ok {
+		t.Fatalf("expected hclog's timestamp to be dropped: %v", first)
+	}
+	if entries[1].Level != WarnLevel || len(entries[1].FieldMap()["peers"].([]any)) != 2 {
+		t.Fatalf("unexpected entry: %+v", entries[1])
+	}
+	if entries[2].Level != InfoLevel || entries[2].Message != "not json" {
+		t.Fatalf("expected non-JSON lines to be logged as messages, got %+v", entries[2])
+	}
+}
+
+func TestZerologAndZapWriters(t *testing.T) {
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(WithOutput(buf), WithLevel(DebugLevel))
+
+	_, _ = io.WriteString(NewZerologWriter(jl), `{"level":"error","component":"cache","time":"2024-01-02T03:04:05Z","message":"evict failed"}`+"\n")
+	_, _ = io.WriteString(NewZapWriter(jl), `{"level":"dpanic","ts":1704164645.1,"logger":"api","caller":"main.go:12","msg":"bad state","n":1.5}`+"\n")
+
+	entries := readEntries(t, buf.Bytes())
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	zerolog := entries[0].FieldMap()
+	if entries[0].Level != ErrorLevel || entries[0].Message != "evict failed" || zerolog["component"] != "cache" || len(zerolog) != 1 {
+		t.Fatalf("unexpected zerolog entry: %+v %v", entries[0], zerolog)
+	}
+	zap := entries[1].FieldMap()
+	if entries[1].Level != ErrorLevel || entries[1].Message != "bad state" || zap["logger"] != "api" || zap["caller"] != "main.go:12" || zap["n"] != 1.5 {
+		t.Fatalf("unexpected zap entry: %+v %v", entries[1], zap)
+	}
+	if _, ok := zap["ts"]; ok {
+		t.Fatalf("expected zap's timestamp to be dropped: %v", zap)
+	}
+}
@@ -0,0 +1,149 @@
+package golog
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The Append helpers expose the encoder used by the logger's hot path, so
+// custom LogWriters and hooks can build JSON with append semantics instead of
+// going through bytes.Buffer or encoding/json. Each one appends the encoded
+// value to dst and returns the extended slice, like strconv.AppendInt.
+
+// reflectEncoder is the package-default encoder with the struct fallback
+// enabled, matching what a JSONLogger writes for Any fields.
+var reflectEncoder = encoder{reflectFallback: true}
+
+// AppendString appends s as a quoted JSON string.
+func AppendString(dst []byte, s string) []byte {
+	return appendQuoteBytes(dst, s)
+}
+
+// AppendInt appends i as a JSON number.
+func AppendInt(dst []byte, i int64) []byte {
+	return strconv.AppendInt(dst, i, 10)
+}
+
+// AppendUint appends u as a JSON number.
+func AppendUint(dst []byte, u uint64) []byte {
+	return strconv.AppendUint(dst, u, 10)
+}
+
+// AppendFloat appends f as a JSON number. NaN and ±Inf are written as null.
+func AppendFloat(dst []byte, f float64) []byte {
+	dst, _ = defaultEncoder.appendFloat(dst, f, 64)
+	return dst
+}
+
+// AppendBool appends b as true or false.
+func AppendBool(dst []byte, b bool) []byte {
+	return strconv.AppendBool(dst, b)
+}
+
+// AppendTime appends t as a quoted RFC 3339 timestamp in UTC, the format of
+// the logger's timestamp field.
+func AppendTime(dst []byte, t time.Time) []byte {
+	dst = append(dst, '"')
+	dst = appendRFC3339NanoUTC(dst, t.UTC())
+	return append(dst, '"')
+}
+
+// AppendDuration appends d as integer nanoseconds.
+func AppendDuration(dst []byte, d time.Duration) []byte {
+	return strconv.AppendInt(dst, int64(d), 10)
+}
+
+// AppendValue appends any value the logger can encode, including structs,
+// maps and slices. Values that can't be encoded, or only those parts of a
+// container, are written as "<unsupported>".
+func AppendValue(dst []byte, value any) []byte {
+	return reflectEncoder.appendValueOrPlaceholder(dst, value)
+}
+
+// EncodeAny appends value to dst like AppendValue, but reports a value that
+// can't be encoded, or any part of it, with ErrUnsupportedType and returns
+// dst unchanged instead of writing a placeholder. Its single entry point and
+// plain error make it a convenient target for fuzzing the encoder.
+func EncodeAny(dst []byte, value any) ([]byte, error) {
+	encoded, ok := reflectEncoder.appendValue(dst, value)
+	if !ok {
+		return dst, ErrUnsupportedType
+	}
+	return encoded, nil
+}
+
+// AppendField appends f as a `"key":value` pair, without a separator.
+func AppendField(dst []byte, f Field) []byte {
+	dst = appendQuoteBytes(dst, f.key)
+	dst = append(dst, ':')
+	return reflectEncoder.appendFieldValue(dst, f)
+}
+
+// AppendEntry appends the record jsonLogger would write for a call at level,
+// without writing it, so the encoder can feed custom transports, ring
+// buffers or write-ahead logs. The record carries the logger's timestamp,
+// base fields and LogWriter format; each map in fields is added sorted by
+// key, in the order given. Level filtering, sinks, schemas and sequence
+// numbers are left to the caller: the entry is encoded whatever the level.
+//
+//	record := jl.AppendEntry(buf[:0], golog.InfoLevel, "order placed", map[string]any{"order_id": 42})
+//	wal.Append(record)
+func (jsonLogger *JSONLogger) AppendEntry(dst []byte, level Level, msg string, fields ...map[string]any) []byte {
+	scratchPtr := fieldScratchPool.Get().(*[]Field)
+	scratch := (*scratchPtr)[:0]
+	for _, props := range fields {
+		scratch = appendMapFields(scratch, props)
+	}
+
+	dst = jsonLogger.current().appendEntry(dst, 0, level, msg, scratch)
+
+	clear(scratch)
+	*scratchPtr = scratch[:0]
+	fieldScratchPool.Put(scratchPtr)
+	return dst
+}
+
+// appendMapFields appends the entries of props to fields as Any fields,
+// sorted by key.
+func appendMapFields(fields []Field, props map[string]any) []Field {
+	start := len(fields)
+	for key, value := range props {
+		fields = append(fields, Any(key, value))
+	}
+	slices.SortFunc(fields[start:], func(a, b Field) int {
+		return strings.Compare(a.key, b.key)
+	})
+	return fields
+}
+
+// maxPooledBufferSize keeps AcquireBuffer's pool from pinning the memory of
+// unusually large entries.
+const maxPooledBufferSize = 64 << 10
+
+var appendBufferPool = sync.Pool{
+	New: func() any {
+		buffer := make([]byte, 0, 512)
+		return &buffer
+	},
+}
+
+// AcquireBuffer returns an empty byte slice from a shared pool. Append to
+// *buffer and hand it back with ReleaseBuffer when done. Pooling pointers to
+// slices keeps the round trip allocation free.
+func AcquireBuffer() *[]byte {
+	buffer := appendBufferPool.Get().(*[]byte)
+	*buffer = (*buffer)[:0]
+	return buffer
+}
+
+// ReleaseBuffer returns a buffer obtained from AcquireBuffer to the pool. The
+// buffer must not be used afterwards.
+func ReleaseBuffer(buffer *[]byte) {
+	if buffer == nil || cap(*buffer) > maxPooledBufferSize {
+		return
+	}
+	appendBufferPool.Put(buffer)
+}
@@ -1,10 +1,84 @@
 package golog
 
 import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"iter"
+	"maps"
+	"math"
+	"math/big"
+	"net"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"slices"
 	"strconv"
 	"time"
+	"unicode/utf8"
 )
 
+// DurationFormat controls how time.Duration values are encoded.
+type DurationFormat uint8
+
+const (
+	// DurationNanos encodes durations as integer nanoseconds (the default).
+	DurationNanos DurationFormat = iota
+	// DurationString encodes durations using time.Duration.String, e.g. "1.5s".
+	DurationString
+)
+
+// FloatPolicy controls how NaN and ±Inf float values are encoded. JSON has
+// no representation for them, so writing them verbatim would corrupt the
+// NDJSON stream for strict parsers.
+type FloatPolicy uint8
+
+const (
+	// FloatAsNull encodes non-finite floats as null (the default).
+	FloatAsNull FloatPolicy = iota
+	// FloatAsString encodes non-finite floats as the strings "NaN", "+Inf"
+	// and "-Inf".
+	FloatAsString
+	// FloatAsError treats non-finite floats as unsupported values, so the
+	// field is written as "<unsupported>".
+	FloatAsError
+)
+
+// encoder holds the value-encoding policy of a logger. The zero value
+// encodes with the package defaults.
+type encoder struct {
+	durationFormat  DurationFormat
+	floatPolicy     FloatPolicy
+	bigNumberFormat BigNumberFormat
+	// strict enables UTF-8 validation and escaping of U+2028/U+2029.
+	strict bool
+	// reflectFallback routes values the type switch doesn't cover (structs,
+	// typed maps and slices, pointers) through the cached reflection
+	// encoder instead of reporting them as unsupported.
+	reflectFallback bool
+	// omitEmpty drops top-level fields whose value is nil, an empty string
+	// or a zero number.
+	omitEmpty bool
+	// maxFieldLength caps string values; see WithMaxFieldLength.
+	maxFieldLength int
+	// keyPrefix namespaces field keys; see WithFieldPrefix.
+	keyPrefix string
+	// scrubbers mask sensitive data in string values; see WithScrubbers.
+	scrubbers []Scrubber
+	// protoMarshal encodes protobuf messages; see WithProtoMarshaler.
+	protoMarshal func(ProtoMessage) ([]byte, error)
+	// maxDepth and maxFields bound nesting and members per container; see
+	// WithMaxDepth and WithMaxFields.
+	maxDepth  int
+	maxFields int
+	// sortMapKeys writes map members in key order; see WithDeterministic.
+	sortMapKeys bool
+}
+
+// defaultEncoder is used by helpers that are not bound to a logger. It keeps
+// the reflection-free behaviour documented on FastEncode.
+var defaultEncoder encoder
+
 func appendQuoteBytes(dst []byte, inputString string) []byte {
 	dst = append(dst, '"')
 	segmentStart := 0
@@ -17,25 +91,53 @@ func appendQuoteBytes(dst []byte, inputString string) []byte {
 		if segmentStart < charIndex {
 			dst = append(dst, inputString[segmentStart:charIndex]...)
 		}
+		dst = appendEscapedASCII(dst, currentChar)
+		segmentStart = charIndex + 1
+	}
 
-		switch currentChar {
-		case '\\':
-			dst = append(dst, `\\`...)
-		case '"':
-			dst = append(dst, `\"`...)
-		case '\n':
-			dst = append(dst, `\n`...)
-		case '\r':
-			dst = append(dst, `\r`...)
-		case '\t':
-			dst = append(dst, `\t`...)
-		default:
-			const hexDigits = "0123456789abcdef"
-			dst = append(dst, "\\u00"...)
-			dst = append(dst, hexDigits[currentChar>>4], hexDigits[currentChar&0xF])
+	if segmentStart < len(inputString) {
+		dst = append(dst, inputString[segmentStart:]...)
+	}
+
+	return append(dst, '"')
+}
+
+// appendQuoteStrict is appendQuoteBytes with full UTF-8 handling: invalid
+// byte sequences are replaced with \ufffd and U+2028/U+2029 are escaped, so
+// the result is always valid RFC 8259 JSON. ASCII input takes the same fast
+// path as appendQuoteBytes.
+func appendQuoteStrict(dst []byte, inputString string) []byte {
+	dst = append(dst, '"')
+	segmentStart := 0
+	for charIndex := 0; charIndex < len(inputString); {
+		currentChar := inputString[charIndex]
+		if currentChar < utf8.RuneSelf {
+			if currentChar >= 0x20 && currentChar != '\\' && currentChar != '"' {
+				charIndex++
+				continue
+			}
+			dst = append(dst, inputString[segmentStart:charIndex]...)
+			dst = appendEscapedASCII(dst, currentChar)
+			charIndex++
+			segmentStart = charIndex
+			continue
 		}
 
-		segmentStart = charIndex + 1
+		decoded, size := utf8.DecodeRuneInString(inputString[charIndex:])
+		switch {
+		case decoded == utf8.RuneError && size == 1:
+			dst = append(dst, inputString[segmentStart:charIndex]...)
+			dst = append(dst, `\ufffd`...)
+		case decoded == '\u2028' || decoded == '\u2029':
+			dst = append(dst, inputString[segmentStart:charIndex]...)
+			dst = append(dst, `\u202`...)
+			dst = append(dst, hexDigits[decoded&0xF])
+		default:
+			charIndex += size
+			continue
+		}
+		charIndex += size
+		segmentStart = charIndex
 	}
 
 	if segmentStart < len(inputString) {
@@ -45,12 +147,52 @@ func appendQuoteBytes(dst []byte, inputString string) []byte {
 	return append(dst, '"')
 }
 
+const hexDigits = "0123456789abcdef"
+
+// appendEscapedASCII writes the JSON escape sequence for an ASCII byte that
+// can't appear verbatim inside a string.
+func appendEscapedASCII(dst []byte, currentChar byte) []byte {
+	switch currentChar {
+	case '\\':
+		return append(dst, `\\`...)
+	case '"':
+		return append(dst, `\"`...)
+	case '\n':
+		return append(dst, `\n`...)
+	case '\r':
+		return append(dst, `\r`...)
+	case '\t':
+		return append(dst, `\t`...)
+	default:
+		dst = append(dst, "\\u00"...)
+		return append(dst, hexDigits[currentChar>>4], hexDigits[currentChar&0xF])
+	}
+}
+
+// appendString quotes a string according to the encoder's strictness.
+func (enc *encoder) appendString(dst []byte, value string) []byte {
+	if enc.strict {
+		return appendQuoteStrict(dst, value)
+	}
+	return appendQuoteBytes(dst, value)
+}
+
 func appendValueBytes(dst []byte, value any) ([]byte, bool) {
+	return defaultEncoder.appendValue(dst, value)
+}
+
+func (enc *encoder) appendValue(dst []byte, value any) ([]byte, bool) {
+	return enc.appendValueAt(dst, value, 0)
+}
+
+// appendValueAt is appendValue for a value nested inside depth containers,
+// which WithMaxDepth limits.
+func (enc *encoder) appendValueAt(dst []byte, value any, depth int) ([]byte, bool) {
 	switch typedValue := value.(type) {
 	case nil:
 		return append(dst, "null"...), true
 	case string:
-		return appendQuoteBytes(dst, typedValue), true
+		return enc.appendStringValue(dst, typedValue), true
 	case bool:
 		if typedValue {
 			return append(dst, "true"...), true
@@ -77,9 +219,9 @@ func appendValueBytes(dst []byte, value any) ([]byte, bool) {
 	case uint64:
 		return strconv.AppendUint(dst, typedValue, 10), true
 	case float32:
-		return strconv.AppendFloat(dst, float64(typedValue), 'g', -1, 32), true
+		return enc.appendFloat(dst, float64(typedValue), 32)
 	case float64:
-		return strconv.AppendFloat(dst, typedValue, 'g', -1, 64), true
+		return enc.appendFloat(dst, typedValue, 64)
 	case time.Time:
 		dst = append(dst, '"')
 		t := typedValue.UTC()
@@ -87,27 +229,170 @@ func appendValueBytes(dst []byte, value any) ([]byte, bool) {
 		dst = append(dst, appendRFC3339NanoUTC(tsBuf[:0], t)...)
 		dst = append(dst, '"')
 		return dst, true
+	case time.Duration:
+		return enc.appendDuration(dst, typedValue), true
+	case net.IP, netip.Addr, netip.AddrPort, netip.Prefix, *url.URL, url.URL, time.Month, time.Weekday:
+		return enc.appendTextValue(dst, value), true
+	case json.Number:
+		return enc.appendJSONNumber(dst, typedValue)
+	case *big.Int:
+		return enc.appendBigInt(dst, typedValue), true
+	case *big.Float:
+		return enc.appendBigFloat(dst, typedValue)
+	case json.RawMessage:
+		if len(typedValue) == 0 {
+			return append(dst, "null"...), true
+		}
+		return appendCompactJSON(dst, typedValue)
+	case rawJSONSource:
+		mark := len(dst)
+		dst, ok := typedValue.appendTo(dst)
+		if !ok {
+			return dst[:mark], false
+		}
+		if len(dst) == mark {
+			return append(dst, "null"...), true
+		}
+		return compactJSONAt(dst, mark)
+	case []byte:
+		if typedValue == nil {
+			return append(dst, "null"...), true
+		}
+		dst = append(dst, '"')
+		dst = base64.StdEncoding.AppendEncode(dst, typedValue)
+		return append(dst, '"'), true
 	case map[string]any:
-		return appendMapBytes(dst, typedValue)
+		return enc.appendMap(dst, typedValue, depth)
+	case map[string]string:
+		return appendMapOf(enc, dst, typedValue, depth, appendStringElement)
+	case map[string]int:
+		return appendMapOf(enc, dst, typedValue, depth, appendIntElement)
+	case map[string]int64:
+		return appendMapOf(enc, dst, typedValue, depth, appendInt64Element)
+	case map[string]uint64:
+		return appendMapOf(enc, dst, typedValue, depth, appendUint64Element)
+	case map[string]float64:
+		return appendMapOf(enc, dst, typedValue, depth, appendFloat64Element)
+	case map[string]bool:
+		return appendMapOf(enc, dst, typedValue, depth, appendBoolElement)
+	case []string:
+		return appendSliceOf(enc, dst, typedValue, depth, appendStringElement)
+	case []int:
+		return appendSliceOf(enc, dst, typedValue, depth, appendIntElement)
+	case []int64:
+		return appendSliceOf(enc, dst, typedValue, depth, appendInt64Element)
+	case []uint64:
+		return appendSliceOf(enc, dst, typedValue, depth, appendUint64Element)
+	case []float64:
+		return appendSliceOf(enc, dst, typedValue, depth, appendFloat64Element)
+	case []bool:
+		return appendSliceOf(enc, dst, typedValue, depth, appendBoolElement)
 	case []any:
-		return appendSliceBytes(dst, typedValue)
+		return enc.appendSlice(dst, typedValue, depth)
 	default:
+		if message, ok := value.(ProtoMessage); ok && enc.protoMarshal != nil {
+			if messageValue := reflect.ValueOf(message); messageValue.Kind() == reflect.Pointer && messageValue.IsNil() {
+				return append(dst, "null"...), true
+			}
+			return enc.appendProto(dst, message)
+		}
+		if _, ok := value.(error); ok {
+			if errorValue := reflect.ValueOf(value); isErrorValue(errorValue) {
+				return enc.appendReflect(dst, errorValue, depth)
+			}
+		}
+		if enc.reflectFallback {
+			return enc.appendReflect(dst, reflect.ValueOf(value), depth)
+		}
+		return dst, false
+	}
+}
+
+// appendValueOrPlaceholder encodes value, replacing anything the encoder
+// can't handle with "<unsupported>" so the surrounding entry stays valid.
+// Inside maps, slices and structs only the offending leaves are replaced.
+func (enc *encoder) appendValueOrPlaceholder(dst []byte, value any) []byte {
+	dst, _ = enc.appendValueLenient(dst, value, "")
+	return dst
+}
+
+// appendCompactJSON appends encoded, a JSON value produced elsewhere, with
+// insignificant whitespace removed so the entry stays on one line. It
+// reports false, leaving dst as it was, when encoded isn't valid JSON.
+func appendCompactJSON(dst, encoded []byte) ([]byte, bool) {
+	buffer := bytes.NewBuffer(dst)
+	if err := json.Compact(buffer, encoded); err != nil {
+		return dst, false
+	}
+	return buffer.Bytes(), true
+}
+
+// compactJSONAt compacts the JSON value appended to dst after mark, like
+// appendCompactJSON. It reports false, with dst cut back to mark, when the
+// value isn't valid JSON.
+func compactJSONAt(dst []byte, mark int) ([]byte, bool) {
+	encoded := dst[mark:]
+	if !bytes.ContainsAny(encoded, " \t\r\n") {
+		// Without whitespace there is nothing to remove, only the value to
+		// check.
+		if !json.Valid(encoded) {
+			return dst[:mark], false
+		}
+		return dst, true
+	}
+	return appendCompactJSON(dst[:mark], bytes.Clone(encoded))
+}
+
+// appendFloat encodes value with the given bit size, applying the
+// non-finite float policy. It returns false only under FloatAsError.
+func (enc *encoder) appendFloat(dst []byte, value float64, bitSize int) ([]byte, bool) {
+	if !math.IsNaN(value) && !math.IsInf(value, 0) {
+		return strconv.AppendFloat(dst, value, 'g', -1, bitSize), true
+	}
+
+	switch enc.floatPolicy {
+	case FloatAsString:
+		switch {
+		case math.IsNaN(value):
+			return append(dst, `"NaN"`...), true
+		case value > 0:
+			return append(dst, `"+Inf"`...), true
+		default:
+			return append(dst, `"-Inf"`...), true
+		}
+	case FloatAsError:
 		return dst, false
+	default:
+		return append(dst, "null"...), true
 	}
 }
 
-func appendMapBytes(dst []byte, mapData map[string]any) ([]byte, bool) {
+func (enc *encoder) appendDuration(dst []byte, duration time.Duration) []byte {
+	if enc.durationFormat == DurationString {
+		return appendQuoteBytes(dst, duration.String())
+	}
+	return strconv.AppendInt(dst, int64(duration), 10)
+}
+
+func (enc *encoder) appendMap(dst []byte, mapData map[string]any, depth int) ([]byte, bool) {
+	if enc.depthExceeded(depth) {
+		return appendQuoteBytes(dst, TruncatedMarker), true
+	}
 	dst = append(dst, '{')
-	first := true
-	for key, value := range mapData {
-		if !first {
+	written := 0
+	for key, value := range mapMembers(mapData, enc.sortMapKeys) {
+		if written == enc.maxFields && written > 0 {
+			dst = enc.appendTruncatedMembers(dst, len(mapData)-written)
+			break
+		}
+		if written > 0 {
 			dst = append(dst, ',')
 		}
-		first = false
-		dst = appendQuoteBytes(dst, key)
+		written++
+		dst = enc.appendString(dst, key)
 		dst = append(dst, ':')
 		var ok bool
-		dst, ok = appendValueBytes(dst, value)
+		dst, ok = enc.appendValueAt(dst, value, depth+1)
 		if !ok {
 			return dst, false
 		}
@@ -116,14 +401,124 @@ func appendMapBytes(dst []byte, mapData map[string]any) ([]byte, bool) {
 	return dst, true
 }
 
-func appendSliceBytes(dst []byte, values []any) ([]byte, bool) {
+// appendMapOf encodes a map with a concrete element type without boxing each
+// value into an interface. Like the reflection encoder, a nil map is null.
+func appendMapOf[V any](enc *encoder, dst []byte, mapData map[string]V, depth int, appendElement func(*encoder, []byte, V) ([]byte, bool)) ([]byte, bool) {
+	if mapData == nil {
+		return append(dst, "null"...), true
+	}
+	if enc.depthExceeded(depth) {
+		return appendQuoteBytes(dst, TruncatedMarker), true
+	}
+	dst = append(dst, '{')
+	written := 0
+	for key, value := range mapMembers(mapData, enc.sortMapKeys) {
+		if written == enc.maxFields && written > 0 {
+			dst = enc.appendTruncatedMembers(dst, len(mapData)-written)
+			break
+		}
+		if written > 0 {
+			dst = append(dst, ',')
+		}
+		written++
+		dst = enc.appendString(dst, key)
+		dst = append(dst, ':')
+		var ok bool
+		dst, ok = appendElement(enc, dst, value)
+		if !ok {
+			return dst, false
+		}
+	}
+	return append(dst, '}'), true
+}
+
+// mapMembers ranges over mapData, in key order when sorted is set and in
+// Go's random map order otherwise.
+func mapMembers[V any](mapData map[string]V, sorted bool) iter.Seq2[string, V] {
+	return func(yield func(string, V) bool) {
+		if !sorted {
+			for key, value := range mapData {
+				if !yield(key, value) {
+					return
+				}
+			}
+			return
+		}
+		for _, key := range slices.Sorted(maps.Keys(mapData)) {
+			if !yield(key, mapData[key]) {
+				return
+			}
+		}
+	}
+}
+
+// appendSliceOf is appendMapOf for slices. A nil slice is null.
+func appendSliceOf[V any](enc *encoder, dst []byte, values []V, depth int, appendElement func(*encoder, []byte, V) ([]byte, bool)) ([]byte, bool) {
+	if values == nil {
+		return append(dst, "null"...), true
+	}
+	if enc.depthExceeded(depth) {
+		return appendQuoteBytes(dst, TruncatedMarker), true
+	}
+	dst = append(dst, '[')
+	for i, value := range values {
+		if i == enc.maxFields && i > 0 {
+			dst = appendTruncatedElements(dst)
+			break
+		}
+		if i > 0 {
+			dst = append(dst, ',')
+		}
+		var ok bool
+		dst, ok = appendElement(enc, dst, value)
+		if !ok {
+			return dst, false
+		}
+	}
+	return append(dst, ']'), true
+}
+
+// Element encoders for appendMapOf and appendSliceOf.
+
+func appendStringElement(enc *encoder, dst []byte, value string) ([]byte, bool) {
+	return enc.appendStringValue(dst, value), true
+}
+
+func appendIntElement(_ *encoder, dst []byte, value int) ([]byte, bool) {
+	return strconv.AppendInt(dst, int64(value), 10), true
+}
+
+func appendInt64Element(_ *encoder, dst []byte, value int64) ([]byte, bool) {
+	return strconv.AppendInt(dst, value, 10), true
+}
+
+func appendUint64Element(_ *encoder, dst []byte, value uint64) ([]byte, bool) {
+	return strconv.AppendUint(dst, value, 10), true
+}
+
+func appendFloat64Element(enc *encoder, dst []byte, value float64) ([]byte, bool) {
+	return enc.appendFloat(dst, value, 64)
+}
+
+func appendBoolElement(_ *encoder, dst []byte, value bool) ([]byte, bool) {
+	return strconv.AppendBool(dst, value), true
+}
+
+func (enc *encoder) appendSlice(dst []byte, values []any, depth int) ([]byte, bool) {
+	if enc.depthExceeded(depth) {
+		return appendQuoteBytes(dst, TruncatedMarker), true
+	}
 	dst = append(dst, '[')
 	for i, value := range values {
+		if i == enc.maxFields && i > 0 {
+			dst = appendTruncatedElements(dst)
+			break
+		}
 		if i > 0 {
 			dst = append(dst, ',')
 		}
 		var ok bool
-		dst, ok = appendValueBytes(dst, value)
+		dst, ok = enc.appendValueAt(dst, value, depth+1)
 		if !ok {
 			return dst, false
 		}
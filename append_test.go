@@ -0,0 +1,127 @@
+package golog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestAppendHelpers(t *testing.T) {
+	type payload struct {
+		Name string `json:"name"`
+	}
+
+	tests := []struct {
+		name string
+		got  []byte
+		want string
+	}{
+		{name: "string", got: AppendString(nil, "a\"b\n"), want: `"a\"b\n"`},
+		{name: "int", got: AppendInt([]byte("x="), -42), want: "x=-42"},
+		{name: "uint", got: AppendUint(nil, 42), want: "42"},
+		{name: "float", got: AppendFloat(nil, 1.5), want: "1.5"},
+		{name: "nan", got: AppendFloat(nil, math.NaN()), want: "null"},
+		{name: "bool", got: AppendBool(nil, true), want: "true"},
+		{name: "time", got: AppendTime(nil, time.Date(2024, 1, 2, 3, 4, 5, 6000, time.FixedZone("X", 3600))), want: `"2024-01-02T02:04:05.000006Z"`},
+		{name: "duration", got: AppendDuration(nil, time.Millisecond), want: "1000000"},
+		{name: "struct value", got: AppendValue(nil, payload{Name: "n"}), want: `{"name":"n"}`},
+		{name: "unsupported", got: AppendValue(nil, make(chan int)), want: `"<unsupported>"`},
+		{name: "field", got: AppendField(nil, Int("n", 1)), want: `"n":1`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if string(tc.got) != tc.want {
+				t.Fatalf("expected %s, got %s", tc.want, tc.got)
+			}
+		})
+	}
+}
+
+func TestAcquireBufferRoundTrip(t *testing.T) {
+	buffer := AcquireBuffer()
+	*buffer = append(*buffer, '{')
+	*buffer = AppendField(*buffer, Str("k", "v"))
+	*buffer = append(*buffer, '}')
+
+	var decoded map[string]any
+	if err := json.Unmarshal(*buffer, &decoded); err != nil || decoded["k"] != "v" {
+		t.Fatalf("unexpected buffer %s: %v", *buffer, err)
+	}
+	ReleaseBuffer(buffer)
+
+	if reused := AcquireBuffer(); len(*reused) != 0 {
+		t.Fatalf("expected an empty buffer, got %q", *reused)
+	}
+	ReleaseBuffer(nil)
+}
+
+func TestAppendEntry(t *testing.T) {
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(
+		WithOutput(buf),
+		WithLevel(ErrorLevel),
+		WithClock(func() time.Time { return time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC) }),
+		WithBaseFields(map[string]any{"service": "api"}),
+	)
+
+	record := jl.AppendEntry([]byte("prefix "), DebugLevel, "queued",
+		map[string]any{"b": 2, "a": "x"},
+		map[string]any{"c": true},
+	)
+	want := `prefix {"timestamp":"2024-01-02T03:04:05Z","level":"debug","message":"queued","service":"api","a":"x","b":2,"c":true}` + "\n"
+	if string(record) != want {
+		t.Fatalf("expected %s, got %s", want, record)
+	}
+	if buf.Len() != 0 {
+		t.Fatalf("AppendEntry must not write to the output, got %s", buf.String())
+	}
+
+	record = jl.AppendEntry(record[:0], InfoLevel, "bare")
+	if !json.Valid(record) {
+		t.Fatalf("invalid record without fields: %s", record)
+	}
+}
+
+func TestEncodeAny(t *testing.T) {
+	got, err := EncodeAny([]byte("x="), map[string]any{"a": []int{1}})
+	if err != nil || string(got) != `x={"a":[1]}` {
+		t.Fatalf("unexpected encoding %s (err=%v)", got, err)
+	}
+	got, err = EncodeAny([]byte("x="), map[string]any{"a": make(chan int)})
+	if !errors.Is(err, ErrUnsupportedType) || string(got) != "x=" {
+		t.Fatalf("expected ErrUnsupportedType and dst unchanged, got %s (err=%v)", got, err)
+	}
+}
+
+func FuzzEncodeAny(f *testing.F) {
+	f.Add("key", "value", int64(1), 1.5, true, []byte("raw"))
+	f.Add("", "\x00\"\\\u2028", int64(math.MinInt64), math.Inf(1), false, []byte(nil))
+	f.Add("k\xff", "a\xc3", int64(-1), math.NaN(), true, []byte{0xff})
+
+	f.Fuzz(func(t *testing.T, key, s string, i int64, fl float64, b bool, raw []byte) {
+		value := map[string]any{
+			key:      []any{s, i, fl, b, raw},
+			"nested": map[string]string{key: s},
+			"ints":   []int64{i},
+		}
+		encoded, err := EncodeAny(nil, value)
+		if err != nil {
+			t.Fatalf("EncodeAny(%#v): %v", value, err)
+		}
+		if !utf8.ValidString(key) || !utf8.ValidString(s) {
+			return
+		}
+		var decoded map[string]any
+		if err := json.Unmarshal(encoded, &decoded); err != nil {
+			t.Fatalf("invalid JSON %s: %v", encoded, err)
+		}
+		if got := decoded["nested"].(map[string]any)[key]; key != "nested" && got != s {
+			t.Fatalf("string round trip: expected %q, got %q", s, got)
+		}
+	})
+}
@@ -0,0 +1,447 @@
+package golog
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Defaults applied to zero AsyncConfig fields.
+const (
+	defaultAsyncMaxBatchBytes = 64 << 10
+	defaultAsyncMaxLatency    = 100 * time.Millisecond
+	defaultAsyncMaxQueueBytes = 4 << 20
+)
+
+// errNoSpillPath is recorded when DeliverySpillToDisk is set without a
+// SpillPath.
+var errNoSpillPath = errors.New("spill to disk delivery needs AsyncConfig.SpillPath")
+
+// DeliveryPolicy selects what the async writer does with entries it can't
+// queue, trading logging latency against durability. Stats counts what each
+// policy did.
+type DeliveryPolicy uint8
+
+const (
+	// DeliveryBlock makes logging calls wait for room in the queue, so no
+	// entry is lost while the output keeps up, at the cost of stalling the
+	// application when it doesn't. Counted in Stats.BlockedWrites and
+	// Stats.BlockedTime.
+	DeliveryBlock DeliveryPolicy = iota
+	// DeliveryDropNewest rejects the entry being logged, whose write fails
+	// with ErrQueueFull. Counted in Stats.DroppedNewest.
+	DeliveryDropNewest
+	// DeliveryDropOldest discards the oldest queued entries to make room,
+	// keeping the most recent ones. Counted in Stats.DroppedOldest.
+	DeliveryDropOldest
+	// DeliverySpillToDisk appends the entry to AsyncConfig.SpillPath and
+	// also saves batches the output fails to write there; see SpillPath.
+	// Counted in Stats.SpilledEntries.
+	DeliverySpillToDisk
+)
+
+var deliveryPolicyNames = []string{"block", "drop_newest", "drop_oldest", "spill_to_disk"}
+
+func (policy DeliveryPolicy) String() string {
+	if int(policy) < len(deliveryPolicyNames) {
+		return deliveryPolicyNames[policy]
+	}
+	return "unknown"
+}
+
+// AsyncConfig tunes WithAsync. Zero fields use the defaults.
+type AsyncConfig struct {
+	// MaxBatchBytes starts a write as soon as this many bytes are queued.
+	// Defaults to 64 KiB.
+	MaxBatchBytes int
+	// MaxLatency bounds how long an entry waits in the queue before it is
+	// written. Defaults to 100ms.
+	MaxLatency time.Duration
+	// MaxQueueBytes bounds the queued bytes. Delivery decides what happens
+	// to entries logged while the queue is full. Defaults to 4 MiB.
+	MaxQueueBytes int
+	// Delivery is the policy for entries logged while the queue is full.
+	// Defaults to DeliveryBlock, or DeliverySpillToDisk when SpillPath is
+	// set. Under every policy but DeliverySpillToDisk, batches the output
+	// fails to write are dropped.
+	Delivery DeliveryPolicy
+	// BatchTimeout bounds each batch write when the output implements
+	// ContextWriter, so a dead network collector can't stall the queue.
+	// Entries of a batch that fails or times out are dropped and counted in
+	// Stats.DroppedEntries. Zero means no timeout.
+	BatchTimeout time.Duration
+	// SpillPath names the file DeliverySpillToDisk uses for the entries the
+	// queue can't take: logging calls append to it instead of blocking while
+	// the queue is full, and batches the output fails to write are saved to
+	// it instead of being dropped. Once the output accepts writes again the file is
+	// written out, oldest entries first, and truncated. Entries still in it
+	// when the process exits are delivered by the next logger using the same
+	// path, so delivery is at-least-once: a crash while the file is being
+	// written out repeats entries, and spilled entries can reach the output
+	// behind newer ones. If the file can't be opened or written, the writer
+	// blocks and drops as without it and the error is counted in
+	// Stats.WriteErrors.
+	SpillPath string
+}
+
+// WithAsync moves writes off the logging goroutine. Formatted entries are
+// appended to an in-memory queue and a background goroutine writes
+// everything queued in a single Write call, once MaxBatchBytes are pending or
+// MaxLatency has passed. This trades a bounded delay for far fewer syscalls
+// and lock acquisitions under load.
+//
+// It wraps the output configured so far, so pass it after WithOutput (and
+// after WithCompressedOutput to compress whole batches). Call Flush to write
+// the queue immediately and Close to stop the background goroutine.
+func WithAsync(config AsyncConfig) Option {
+	return func(jsonLogger *JSONLogger) {
+		jsonLogger.async = newAsyncWriter(jsonLogger.output, config, &jsonLogger.writeErrors)
+		jsonLogger.output = jsonLogger.async
+		// The queue is already serialized; the logger's write lock would only
+		// add a second acquisition per entry.
+		jsonLogger.lockWrites = false
+	}
+}
+
+// asyncWriter queues writes and hands them to the output in batches.
+type asyncWriter struct {
+	output io.Writer
+	config AsyncConfig
+	errors *errorTracker
+	// spill is the overflow file set by AsyncConfig.SpillPath, if any.
+	spill *spillFile
+
+	mutex   sync.Mutex
+	notFull *sync.Cond
+	pending []byte
+	entries int
+	// sizes holds the length of each queued entry under DeliveryDropOldest.
+	sizes  []int
+	closed bool
+
+	// flushMutex serializes batch writes so batches reach the output in
+	// order. spare is only touched while holding it.
+	flushMutex sync.Mutex
+	spare      []byte
+
+	wake chan struct{}
+	stop chan struct{}
+	done chan struct{}
+
+	// inFlight is the number of entries in the batch being written.
+	inFlight atomic.Int64
+
+	batches        atomic.Uint64
+	batchedEntries atomic.Uint64
+	batchedBytes   atomic.Uint64
+	droppedEntries atomic.Uint64
+	spilledEntries atomic.Uint64
+	blockedWrites  atomic.Uint64
+	blockedTime    atomic.Int64
+	droppedNewest  atomic.Uint64
+	droppedOldest  atomic.Uint64
+}
+
+func newAsyncWriter(output io.Writer, config AsyncConfig, errors *errorTracker) *asyncWriter {
+	if config.MaxBatchBytes <= 0 {
+		config.MaxBatchBytes = defaultAsyncMaxBatchBytes
+	}
+	if config.MaxLatency <= 0 {
+		config.MaxLatency = defaultAsyncMaxLatency
+	}
+	if config.MaxQueueBytes <= 0 {
+		config.MaxQueueBytes = defaultAsyncMaxQueueBytes
+	}
+	config.MaxBatchBytes = min(config.MaxBatchBytes, config.MaxQueueBytes)
+	if config.SpillPath != "" && config.Delivery == DeliveryBlock {
+		config.Delivery = DeliverySpillToDisk
+	}
+
+	writer := &asyncWriter{
+		output:  output,
+		config:  config,
+		errors:  errors,
+		pending: make([]byte, 0, config.MaxBatchBytes),
+		spare:   make([]byte, 0, config.MaxBatchBytes),
+		wake:    make(chan struct{}, 1),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	writer.notFull = sync.NewCond(&writer.mutex)
+	if config.Delivery == DeliverySpillToDisk {
+		if config.SpillPath == "" {
+			errors.record(errNoSpillPath)
+		} else if spill, err := openSpillFile(config.SpillPath); err != nil {
+			errors.record(err)
+		} else {
+			writer.spill = spill
+		}
+	}
+	go writer.run()
+	return writer
+}
+
+// Write queues a copy of p, applying the delivery policy while the queue is
+// full. It fails once the writer is closed.
+func (writer *asyncWriter) Write(p []byte) (int, error) {
+	writer.mutex.Lock()
+	// Entries follow those already spilled, so they leave in order.
+	if writer.spill != nil && !writer.closed && (writer.queueFull(len(p)) || writer.spill.pending()) {
+		err := writer.spill.append(p, 1)
+		if err == nil {
+			writer.spilledEntries.Add(1)
+			writer.mutex.Unlock()
+			return len(p), nil
+		}
+		writer.errors.record(err)
+	}
+	if !writer.closed && writer.queueFull(len(p)) {
+		switch writer.config.Delivery {
+		case DeliveryDropNewest:
+			writer.mutex.Unlock()
+			writer.droppedNewest.Add(1)
+			writer.droppedEntries.Add(1)
+			return 0, ErrQueueFull
+		case DeliveryDropOldest:
+			writer.dropOldest(len(p))
+		default:
+			// DeliverySpillToDisk blocks too when the spill file fails.
+			start := time.Now()
+			for !writer.closed && writer.queueFull(len(p)) {
+				writer.notFull.Wait()
+			}
+			writer.blockedWrites.Add(1)
+			writer.blockedTime.Add(int64(time.Since(start)))
+		}
+	}
+	if writer.closed {
+		writer.mutex.Unlock()
+		return 0, ErrWriterClosed
+	}
+	writer.pending = append(writer.pending, p...)
+	writer.entries++
+	if writer.config.Delivery == DeliveryDropOldest {
+		writer.sizes = append(writer.sizes, len(p))
+	}
+	full := len(writer.pending) >= writer.config.MaxBatchBytes
+	writer.mutex.Unlock()
+
+	if full {
+		select {
+		case writer.wake <- struct{}{}:
+		default:
+		}
+	}
+	return len(p), nil
+}
+
+// queueFull reports whether n more bytes would overflow the queue. An empty
+// queue takes an entry of any size. The caller holds the mutex.
+func (writer *asyncWriter) queueFull(n int) bool {
+	return len(writer.pending) > 0 && len(writer.pending)+n > writer.config.MaxQueueBytes
+}
+
+// dropOldest discards queued entries, oldest first, until n more bytes fit.
+// The caller holds the mutex.
+func (writer *asyncWriter) dropOldest(n int) {
+	dropped, cut := 0, 0
+	for dropped < len(writer.sizes) && cut < len(writer.pending) && len(writer.pending)-cut+n > writer.config.MaxQueueBytes {
+		cut += writer.sizes[dropped]
+		dropped++
+	}
+	writer.pending = writer.pending[:copy(writer.pending, writer.pending[cut:])]
+	writer.sizes = writer.sizes[:copy(writer.sizes, writer.sizes[dropped:])]
+	writer.entries -= dropped
+	writer.droppedOldest.Add(uint64(dropped))
+	writer.droppedEntries.Add(uint64(dropped))
+}
+
+func (writer *asyncWriter) run() {
+	defer close(writer.done)
+
+	ticker := time.NewTicker(writer.config.MaxLatency)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-writer.wake:
+		case <-ticker.C:
+		case <-writer.stop:
+			return
+		}
+		_ = writer.writeBatch(context.Background())
+	}
+}
+
+// writeBatch writes everything queued so far in one call, then the spilled
+// entries. When the write fails the batch is spilled or, without a spill
+// file, its entries are counted as dropped.
+func (writer *asyncWriter) writeBatch(ctx context.Context) error {
+	writer.flushMutex.Lock()
+	defer writer.flushMutex.Unlock()
+
+	err := writer.writeQueued(ctx)
+	if writer.spill == nil {
+		return err
+	}
+	// Entries queued while the batch was written are older than the
+	// spilled ones, which they would otherwise overtake.
+	for err == nil && writer.spill.pending() && writer.queuedBytes() > 0 {
+		err = writer.writeQueued(ctx)
+	}
+	if err == nil {
+		err = writer.drainSpill(ctx)
+	}
+	return err
+}
+
+// writeQueued writes the queue in one call. The caller holds flushMutex.
+func (writer *asyncWriter) writeQueued(ctx context.Context) error {
+	writer.mutex.Lock()
+	batch, entries := writer.pending, writer.entries
+	writer.pending, writer.entries = writer.spare[:0], 0
+	writer.sizes = writer.sizes[:0]
+	writer.inFlight.Store(int64(entries))
+	writer.notFull.Broadcast()
+	writer.mutex.Unlock()
+
+	var err error
+	if len(batch) > 0 {
+		err = writer.writeOutput(ctx, batch)
+		writer.batches.Add(1)
+		if err != nil {
+			writer.errors.record(err)
+			if writer.spill == nil || writer.spill.append(batch, entries) != nil {
+				writer.droppedEntries.Add(uint64(entries))
+			} else {
+				writer.spilledEntries.Add(uint64(entries))
+			}
+		} else {
+			writer.batchedEntries.Add(uint64(entries))
+			writer.batchedBytes.Add(uint64(len(batch)))
+		}
+	}
+	writer.inFlight.Store(0)
+	writer.spare = batch[:0]
+	return err
+}
+
+// drainSpill writes the spilled entries in batches of up to MaxBatchBytes,
+// stopping at the first failure, which leaves the rest spilled. The caller
+// holds flushMutex.
+func (writer *asyncWriter) drainSpill(ctx context.Context) error {
+	for writer.spill.pending() {
+		batch, entries, offset, err := writer.spill.next(writer.spare[:0], writer.config.MaxBatchBytes)
+		writer.spare = batch[:0]
+		if err != nil {
+			writer.errors.record(err)
+			return err
+		}
+		writer.batches.Add(1)
+		if err := writer.writeOutput(ctx, batch); err != nil {
+			writer.errors.record(err)
+			return err
+		}
+		writer.batchedEntries.Add(uint64(entries))
+		writer.batchedBytes.Add(uint64(len(batch)))
+		if err := writer.spill.advance(offset, entries); err != nil {
+			writer.errors.record(err)
+			return err
+		}
+	}
+	return nil
+}
+
+// writeOutput hands batch to the output, passing ctx (bounded by
+// BatchTimeout) to outputs that implement ContextWriter.
+func (writer *asyncWriter) writeOutput(ctx context.Context, batch []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	contextWriter, ok := writer.output.(ContextWriter)
+	if !ok {
+		_, err := writer.output.Write(batch)
+		return err
+	}
+	if writer.config.BatchTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, writer.config.BatchTimeout)
+		defer cancel()
+	}
+	_, err := contextWriter.WriteContext(ctx, batch)
+	return err
+}
+
+// Flush writes the queue now and flushes the output if it buffers data.
+func (writer *asyncWriter) Flush() error {
+	err := writer.writeBatch(context.Background())
+	if flusher, ok := writer.output.(interface{ Flush() error }); ok {
+		if flushErr := flusher.Flush(); err == nil {
+			err = flushErr
+		}
+	}
+	return err
+}
+
+// Close stops accepting entries, writes what is queued and closes the
+// output (standard streams excepted). It is safe to call more than once.
+func (writer *asyncWriter) Close() error {
+	_, err := writer.shutdown(context.Background())
+	return err
+}
+
+// shutdown is Close bounded by ctx. It reports the number of entries dropped
+// since it was called: failed writes, plus whatever was still queued or in
+// flight when ctx ended.
+func (writer *asyncWriter) shutdown(ctx context.Context) (int, error) {
+	writer.mutex.Lock()
+	if writer.closed {
+		writer.mutex.Unlock()
+		return 0, nil
+	}
+	writer.closed = true
+	writer.notFull.Broadcast()
+	writer.mutex.Unlock()
+
+	droppedBefore := writer.droppedEntries.Load()
+	close(writer.stop)
+
+	result := make(chan error, 1)
+	go func() {
+		<-writer.done
+		err := writer.writeBatch(ctx)
+		if flusher, ok := writer.output.(interface{ Flush() error }); ok && err == nil {
+			err = flusher.Flush()
+		}
+		if closeErr := closeOutput(writer.output); err == nil {
+			err = closeErr
+		}
+		if writer.spill != nil {
+			if closeErr := writer.spill.close(); err == nil {
+				err = closeErr
+			}
+		}
+		result <- err
+	}()
+
+	select {
+	case err := <-result:
+		return int(writer.droppedEntries.Load() - droppedBefore), err
+	case <-ctx.Done():
+		writer.mutex.Lock()
+		abandoned := writer.entries + int(writer.inFlight.Load())
+		writer.mutex.Unlock()
+		// The abandoned entries are added to Stats.DroppedEntries once the
+		// background write gives up on them.
+		return int(writer.droppedEntries.Load()-droppedBefore) + abandoned, ctx.Err()
+	}
+}
+
+// queuedBytes reports the bytes waiting to be written.
+func (writer *asyncWriter) queuedBytes() int {
+	writer.mutex.Lock()
+	defer writer.mutex.Unlock()
+	return len(writer.pending)
+}
@@ -0,0 +1,252 @@
+package golog
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// countingWriter records every Write call it receives.
+type countingWriter struct {
+	mutex  sync.Mutex
+	writes int
+	buf    bytes.Buffer
+	delay  time.Duration
+}
+
+func (writer *countingWriter) Write(p []byte) (int, error) {
+	time.Sleep(writer.delay)
+	writer.mutex.Lock()
+	defer writer.mutex.Unlock()
+	writer.writes++
+	return writer.buf.Write(p)
+}
+
+func (writer *countingWriter) snapshot() (int, []byte) {
+	writer.mutex.Lock()
+	defer writer.mutex.Unlock()
+	return writer.writes, append([]byte(nil), writer.buf.Bytes()...)
+}
+
+func TestWithAsyncCoalescesWrites(t *testing.T) {
+	output := &countingWriter{}
+	jl := NewJSONLoggerWithOptions(WithOutput(output), WithAsync(AsyncConfig{MaxLatency: time.Hour}))
+
+	for i := 0; i < 100; i++ {
+		jl.Info("queued", Int("i", i))
+	}
+	if err := jl.Flush(); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+
+	writes, data := output.snapshot()
+	if writes != 1 {
+		t.Fatalf("expected a single coalesced write, got %d", writes)
+	}
+	entries := readEntries(t, data)
+	if len(entries) != 100 {
+		t.Fatalf("expected 100 entries, got %d", len(entries))
+	}
+	for i, entry := range entries {
+		if field, _ := entry.Field("i"); field.Value() != int64(i) {
+			t.Fatalf("entries out of order at %d: %v", i, field.Value())
+		}
+	}
+
+	stats := jl.Stats()
+	if stats.Batches != 1 || stats.BatchedEntries != 100 || stats.BatchedBytes != uint64(len(data)) || stats.QueuedBytes != 0 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+	if err := jl.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+}
+
+func TestWithAsyncWritesAfterMaxLatency(t *testing.T) {
+	output := &countingWriter{}
+	jl := NewJSONLoggerWithOptions(WithOutput(output), WithAsync(AsyncConfig{MaxLatency: 10 * time.Millisecond}))
+	defer jl.Close()
+
+	jl.Info("eventually written")
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if _, data := output.snapshot(); len(data) > 0 {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("entry was not written within the latency bound")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestWithAsyncBlocksWhenQueueIsFull(t *testing.T) {
+	output := &countingWriter{delay: time.Millisecond}
+	jl := NewJSONLoggerWithOptions(
+		WithOutput(output),
+		WithAsync(AsyncConfig{MaxBatchBytes: 256, MaxQueueBytes: 512, MaxLatency: time.Millisecond}),
+	)
+
+	var wg sync.WaitGroup
+	for worker := 0; worker < 4; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				jl.Info("pressure", Int("i", i))
+			}
+		}()
+	}
+	wg.Wait()
+	if err := jl.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	_, data := output.snapshot()
+	if entries := readEntries(t, data); len(entries) != 200 {
+		t.Fatalf("expected no entries to be lost, got %d", len(entries))
+	}
+	if stats := jl.Stats(); stats.Batches < 2 {
+		t.Fatalf("expected the small queue to force several batches, got %+v", stats)
+	}
+}
+
+func TestAsyncWriterRejectsWritesAfterClose(t *testing.T) {
+	writer := newAsyncWriter(&countingWriter{}, AsyncConfig{}, nil)
+	if err := writer.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("second close: %v", err)
+	}
+	if _, err := writer.Write([]byte("late\n")); !errors.Is(err, ErrWriterClosed) {
+		t.Fatalf("expected ErrWriterClosed, got %v", err)
+	}
+}
+
+// fillStalledQueue writes r0 to writer, waits for it to be stuck in output's
+// gate, then queues r1 and r2, filling the 6-byte queue.
+func fillStalledQueue(t *testing.T, writer *asyncWriter, output *gateWriter) {
+	t.Helper()
+	for i, record := range []string{"r0\n", "r1\n", "r2\n"} {
+		if _, err := writer.Write([]byte(record)); err != nil {
+			t.Fatalf("write %s: %v", record, err)
+		}
+		if i == 0 {
+			<-output.entered
+		}
+	}
+}
+
+func stalledQueueConfig(policy DeliveryPolicy) AsyncConfig {
+	return AsyncConfig{MaxBatchBytes: 3, MaxQueueBytes: 6, MaxLatency: time.Hour, Delivery: policy}
+}
+
+func TestAsyncDeliveryDropNewest(t *testing.T) {
+	output := newGateWriter()
+	output.block()
+	writer := newAsyncWriter(output, stalledQueueConfig(DeliveryDropNewest), &errorTracker{})
+	fillStalledQueue(t, writer, output)
+
+	if _, err := writer.Write([]byte("r3\n")); !errors.Is(err, ErrQueueFull) {
+		t.Fatalf("expected ErrQueueFull, got %v", err)
+	}
+	output.release()
+	if err := writer.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if got := string(output.Bytes()); got != "r0\nr1\nr2\n" {
+		t.Fatalf("expected the newest entry to be dropped, got %q", got)
+	}
+	if writer.droppedNewest.Load() != 1 || writer.droppedEntries.Load() != 1 || writer.droppedOldest.Load() != 0 {
+		t.Fatalf("unexpected counters: newest %d, total %d", writer.droppedNewest.Load(), writer.droppedEntries.Load())
+	}
+}
+
+func TestAsyncDeliveryDropOldest(t *testing.T) {
+	output := newGateWriter()
+	output.block()
+	writer := newAsyncWriter(output, stalledQueueConfig(DeliveryDropOldest), &errorTracker{})
+	fillStalledQueue(t, writer, output)
+
+	for _, record := range []string{"r3\n", "r4\n"} {
+		if _, err := writer.Write([]byte(record)); err != nil {
+			t.Fatalf("write %s: %v", record, err)
+		}
+	}
+	output.release()
+	go func() {
+		for range output.entered {
+		}
+	}()
+	if err := writer.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if got := string(output.Bytes()); got != "r0\nr3\nr4\n" {
+		t.Fatalf("expected the oldest queued entries to be dropped, got %q", got)
+	}
+	if writer.droppedOldest.Load() != 2 || writer.droppedEntries.Load() != 2 || writer.batchedEntries.Load() != 3 {
+		t.Fatalf("unexpected counters: oldest %d, total %d", writer.droppedOldest.Load(), writer.droppedEntries.Load())
+	}
+}
+
+func TestAsyncDeliveryBlockStats(t *testing.T) {
+	output := newGateWriter()
+	output.block()
+	jl := NewJSONLoggerWithOptions(WithOutput(output), WithAsync(AsyncConfig{MaxBatchBytes: 1, MaxQueueBytes: 1, MaxLatency: time.Hour}))
+
+	jl.Info("first")
+	<-output.entered
+	jl.Info("queued")
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		jl.Info("blocked")
+	}()
+	time.Sleep(20 * time.Millisecond)
+	select {
+	case <-done:
+		t.Fatal("expected the logging call to wait for room in the queue")
+	default:
+	}
+	output.release()
+	<-done
+	go func() {
+		for range output.entered {
+		}
+	}()
+	if err := jl.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	stats := jl.Stats()
+	if stats.Delivery != DeliveryBlock || stats.BlockedWrites != 1 || stats.BlockedTime < 20*time.Millisecond || stats.DroppedEntries != 0 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+	if entries := readEntries(t, output.Bytes()); len(entries) != 3 {
+		t.Fatalf("expected every entry to be written, got %d", len(entries))
+	}
+}
+
+func TestAsyncDeliveryPolicyDefaults(t *testing.T) {
+	jl := NewJSONLoggerWithOptions(WithOutput(&countingWriter{}), WithAsync(AsyncConfig{SpillPath: t.TempDir() + "/spill"}))
+	if stats := jl.Stats(); stats.Delivery != DeliverySpillToDisk {
+		t.Fatalf("expected SpillPath to select DeliverySpillToDisk, got %v", stats.Delivery)
+	}
+	jl.Close()
+
+	jl = NewJSONLoggerWithOptions(WithOutput(&countingWriter{}), WithAsync(AsyncConfig{Delivery: DeliverySpillToDisk}))
+	if stats := jl.Stats(); !errors.Is(stats.LastWriteError, errNoSpillPath) {
+		t.Fatalf("expected the missing spill path to be reported, got %v", stats.LastWriteError)
+	}
+	jl.Close()
+
+	for policy, name := range map[DeliveryPolicy]string{DeliveryBlock: "block", DeliveryDropNewest: "drop_newest", DeliveryDropOldest: "drop_oldest", DeliverySpillToDisk: "spill_to_disk", 9: "unknown"} {
+		if policy.String() != name {
+			t.Fatalf("expected %q, got %q", name, policy.String())
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package golog
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// Audit field keys written by AuditLogger.
+const (
+	AuditSeqKey       = "seq"
+	AuditChainHashKey = "chain_hash"
+)
+
+// AuditLogger wraps a JSONLogger to make its output tamper evident. Every
+// record carries a monotonically increasing seq (starting at 1) and a
+// chain_hash: the hex SHA-256 of the previous record exactly as written,
+// trailing newline included. The first record chains from 64 zeros.
+//
+// Editing, reordering or removing a record breaks the chain at the next
+// record; VerifyAuditChain detects this. Removing records from the end can
+// only be detected against an externally stored anchor, such as the result of
+// Head saved periodically.
+//
+// Like the core keys, seq and chain_hash are written as they are:
+// WithFieldPrefix, WithMaxFieldLength and scrubbers don't apply to them.
+//
+// Entries are formatted and written under a single lock so the order in the
+// output always matches the chain. AuditLogger implements Logger.
+type AuditLogger struct {
+	logger *JSONLogger
+
+	mutex    sync.Mutex
+	seq      uint64
+	prevHash [sha256.Size]byte
+	buffer   []byte
+}
+
+// NewAuditLogger returns an AuditLogger writing through jsonLogger, which
+// supplies the output, level, base fields and encoding options.
+func NewAuditLogger(jsonLogger *JSONLogger) *AuditLogger {
+	return &AuditLogger{logger: jsonLogger}
+}
+
+// Info logs a message at info level.
+func (audit *AuditLogger) Info(message string, fields ...Field) {
+	audit.log(InfoLevel, message, fields)
+}
+
+// Warn logs a message at warn level.
+func (audit *AuditLogger) Warn(message string, fields ...Field) {
+	audit.log(WarnLevel, message, fields)
+}
+
+// Error logs a message at error level.
+func (audit *AuditLogger) Error(message string, fields ...Field) {
+	audit.log(ErrorLevel, message, fields)
+}
+
+// Debug logs a message at debug level.
+func (audit *AuditLogger) Debug(message string, fields ...Field) {
+	audit.log(DebugLevel, message, fields)
+}
+
+// Head returns the seq of the last record written and the hash that the next
+// record will carry as its chain_hash.
+func (audit *AuditLogger) Head() (uint64, string) {
+	audit.mutex.Lock()
+	defer audit.mutex.Unlock()
+	return audit.seq, hex.EncodeToString(audit.prevHash[:])
+}
+
+func (audit *AuditLogger) log(logLevel Level, message string, fields []Field) {
+	jsonLogger := audit.logger
+	configured := Level(atomic.LoadInt32((*int32)(&jsonLogger.level)))
+	if configured > logLevel && (configured == OffLevel || !forced(fields)) {
+		return
+	}
+
+	audit.mutex.Lock()
+	defer audit.mutex.Unlock()
+
+	var hashHex [sha256.Size * 2]byte
+	hex.Encode(hashHex[:], audit.prevHash[:])
+
+	// Encode and write with one configuration, as every other logging path
+	// does, even if Reconfigure replaces it meanwhile.
+	config, inflight := jsonLogger.acquire()
+	audit.buffer = config.appendAuditEntry(audit.buffer[:0], audit.seq+1, hashHex[:], logLevel, message, fields)
+	config.writeOutput(audit.buffer)
+	inflight.release()
+
+	audit.seq++
+	audit.prevHash = sha256.Sum256(audit.buffer)
+}
+
+// appendAuditEntry formats an audit record. The default writer writes seq
+// and chain_hash after the core keys, like SequenceKey, so field prefixing,
+// length capping, scrubbing and WithMaxFields leave them intact; other
+// LogWriters receive them as the first fields.
+func (jsonLogger *JSONLogger) appendAuditEntry(dst []byte, seq uint64, chainHash []byte, logLevel Level, message string, fields []Field) []byte {
+	writer, ok := jsonLogger.writer.(jsonLogWriter)
+	if !ok {
+		auditFields := make([]Field, 0, len(fields)+2)
+		auditFields = append(auditFields,
+			Field{key: AuditSeqKey, uintVal: seq, kind: fieldKindUint},
+			Str(AuditChainHashKey, string(chainHash)),
+		)
+		return jsonLogger.appendEntry(dst, 0, logLevel, message, append(auditFields, fields...))
+	}
+
+	timestamp, fields := jsonLogger.entryTime(fields)
+	dst = writer.appendHeader(dst, timestamp, logLevel, message)
+	dst = append(dst, `,"`+AuditSeqKey+`":`...)
+	dst = strconv.AppendUint(dst, seq, 10)
+	dst = append(dst, `,"`+AuditChainHashKey+`":"`...)
+	dst = append(dst, chainHash...)
+	dst = append(dst, '"')
+	return writer.appendBody(dst, jsonLogger.sortedFields(jsonLogger.encoder.limitFields(fields)))
+}
+
+// VerifyAuditChain reads NDJSON written by an AuditLogger and checks that
+// seq increases by one per record and that every chain_hash matches the
+// previous line. It returns nil for an intact chain, or an error naming the
+// first record that doesn't match.
+func VerifyAuditChain(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxReaderLineSize)
+
+	var prevHash [sha256.Size]byte
+	var expectedSeq uint64 = 1
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		var record struct {
+			Seq       uint64 `json:"seq"`
+			ChainHash string `json:"chain_hash"`
+		}
+		if err := json.Unmarshal(line, &record); err != nil {
+			return fmt.Errorf("audit record %d: %w", expectedSeq, err)
+		}
+		if record.Seq != expectedSeq {
+			return fmt.Errorf("audit record %d: unexpected seq %d", expectedSeq, record.Seq)
+		}
+		if record.ChainHash != hex.EncodeToString(prevHash[:]) {
+			return fmt.Errorf("audit record %d: chain_hash mismatch", expectedSeq)
+		}
+
+		hash := sha256.New()
+		hash.Write(line)
+		hash.Write([]byte{'\n'})
+		hash.Sum(prevHash[:0])
+		expectedSeq++
+	}
+	return scanner.Err()
+}
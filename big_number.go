@@ -0,0 +1,128 @@
+package golog
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+)
+
+// BigNumberFormat controls how arbitrary-precision numbers (*big.Int,
+// *big.Float and json.Number) are encoded.
+type BigNumberFormat uint8
+
+const (
+	// BigNumberAsNumber encodes them as JSON numbers with every digit (the
+	// default).
+	BigNumberAsNumber BigNumberFormat = iota
+	// BigNumberAsString encodes them as decimal strings, for consumers that
+	// parse JSON numbers into float64 and would round them.
+	BigNumberAsString
+)
+
+var (
+	bigIntType     = reflect.TypeFor[big.Int]()
+	bigFloatType   = reflect.TypeFor[big.Float]()
+	jsonNumberType = reflect.TypeFor[json.Number]()
+)
+
+// WithBigNumberFormat sets how *big.Int, *big.Float and json.Number values
+// are encoded: as JSON numbers (BigNumberAsNumber, the default) or as
+// strings (BigNumberAsString). Amounts keep every digit either way:
+//
+//	jl.Info("transfer", Any("amount", amount)) // "amount":123456789012345678901234567890
+//
+// Infinite *big.Float values follow WithFloatPolicy, and a json.Number that
+// isn't a valid number is written as "<unsupported>" unless it is written as
+// a string. An empty json.Number is 0, as in encoding/json.
+func WithBigNumberFormat(format BigNumberFormat) Option {
+	return func(jsonLogger *JSONLogger) {
+		jsonLogger.encoder.bigNumberFormat = format
+	}
+}
+
+func (enc *encoder) appendBigInt(dst []byte, value *big.Int) []byte {
+	if value == nil {
+		return append(dst, "null"...)
+	}
+	if enc.bigNumberFormat == BigNumberAsString {
+		dst = append(dst, '"')
+		return append(value.Append(dst, 10), '"')
+	}
+	return value.Append(dst, 10)
+}
+
+func (enc *encoder) appendBigFloat(dst []byte, value *big.Float) ([]byte, bool) {
+	if value == nil {
+		return append(dst, "null"...), true
+	}
+	if value.IsInf() {
+		return enc.appendFloat(dst, math.Inf(value.Sign()), 64)
+	}
+	if enc.bigNumberFormat == BigNumberAsString {
+		dst = append(dst, '"')
+		return append(value.Append(dst, 'g', -1), '"'), true
+	}
+	return value.Append(dst, 'g', -1), true
+}
+
+func (enc *encoder) appendJSONNumber(dst []byte, value json.Number) ([]byte, bool) {
+	if value == "" {
+		value = "0"
+	}
+	if enc.bigNumberFormat == BigNumberAsString {
+		return enc.appendStringValue(dst, string(value)), true
+	}
+	if !isJSONNumber(string(value)) {
+		return dst, false
+	}
+	return append(dst, value...), true
+}
+
+// bigNumberPointer returns a *big.Int or *big.Float for a big.Int or
+// big.Float reached by reflection, copying it when it isn't addressable.
+func bigNumberPointer(value reflect.Value) any {
+	if value.CanAddr() {
+		return value.Addr().Interface()
+	}
+	pointer := reflect.New(value.Type())
+	pointer.Elem().Set(value)
+	return pointer.Interface()
+}
+
+// binaryNumber returns the value a binary writer encodes for an
+// arbitrary-precision number: an int64 or float64 when the number fits one
+// exactly, and its decimal string otherwise or under BigNumberAsString.
+func (enc *encoder) binaryNumber(value any) any {
+	var text string
+	switch typedValue := value.(type) {
+	case *big.Int:
+		if typedValue == nil {
+			return nil
+		}
+		if typedValue.IsInt64() && enc.bigNumberFormat == BigNumberAsNumber {
+			return typedValue.Int64()
+		}
+		text = typedValue.String()
+	case *big.Float:
+		if typedValue == nil {
+			return nil
+		}
+		if float, accuracy := typedValue.Float64(); accuracy == big.Exact && enc.bigNumberFormat == BigNumberAsNumber {
+			return float
+		}
+		text = typedValue.Text('g', -1)
+	case json.Number:
+		if enc.bigNumberFormat == BigNumberAsNumber {
+			if integer, err := strconv.ParseInt(string(typedValue), 10, 64); err == nil {
+				return integer
+			}
+			if float, err := strconv.ParseFloat(string(typedValue), 64); err == nil && isJSONNumber(string(typedValue)) {
+				return float
+			}
+		}
+		text = string(typedValue)
+	}
+	return text
+}
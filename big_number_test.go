@@ -0,0 +1,111 @@
+package golog
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestBigNumbers(t *testing.T) {
+	huge, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	type invoice struct {
+		Total    big.Int     `json:"total"`
+		Rate     *big.Float  `json:"rate"`
+		Refund   *big.Int    `json:"refund"`
+		Quantity json.Number `json:"quantity"`
+	}
+
+	tests := []struct {
+		name   string
+		format BigNumberFormat
+		want   []string
+	}{
+		{
+			name:   "numbers",
+			format: BigNumberAsNumber,
+			want: []string{
+				`"int":123456789012345678901234567890`,
+				`"float":0.125`,
+				`"number":12.50`,
+				`"invoice":{"total":123456789012345678901234567890,"rate":1e+100,"refund":null,"quantity":0}`,
+			},
+		},
+		{
+			name:   "strings",
+			format: BigNumberAsString,
+			want: []string{
+				`"int":"123456789012345678901234567890"`,
+				`"float":"0.125"`,
+				`"number":"12.50"`,
+				`"invoice":{"total":"123456789012345678901234567890","rate":"1e+100","refund":null,"quantity":"0"}`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			jl := NewJSONLoggerWithOptions(WithOutput(buf), WithBigNumberFormat(tt.format))
+			jl.Info("amounts",
+				Any("int", huge),
+				Any("float", big.NewFloat(0.125)),
+				Any("number", json.Number("12.50")),
+				Any("invoice", invoice{Total: *huge, Rate: new(big.Float).SetFloat64(1e100)}),
+			)
+			out := buf.String()
+			for _, want := range tt.want {
+				if !strings.Contains(out, want) {
+					t.Fatalf("expected %s in %s", want, out)
+				}
+			}
+			if !json.Valid(bytes.TrimSpace(buf.Bytes())) {
+				t.Fatalf("invalid JSON: %s", out)
+			}
+		})
+	}
+}
+
+func TestBigNumberEdgeCases(t *testing.T) {
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(WithOutput(buf), WithFloatPolicy(FloatAsString))
+	jl.Info("edges",
+		Any("bad", json.Number("12abc")),
+		Any("inf", new(big.Float).SetInf(true)),
+		Any("nil", (*big.Float)(nil)),
+		Any("list", []any{json.Number("1"), json.Number("x")}),
+	)
+	out := buf.String()
+	for _, want := range []string{
+		`"bad":"<unsupported>"`,
+		`"inf":"-Inf"`,
+		`"nil":null`,
+		`"list":[1,"<unsupported>"]`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %s in %s", want, out)
+		}
+	}
+}
+
+func TestBigNumbersBinary(t *testing.T) {
+	huge, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	enc := binaryEncoder{format: BinaryMessagePack, encoder: &encoder{}}
+	tests := []struct {
+		value any
+		want  any
+	}{
+		{value: big.NewInt(-5), want: int64(-5)},
+		{value: huge, want: "123456789012345678901234567890"},
+		{value: big.NewFloat(0.5), want: 0.5},
+		{value: json.Number("7"), want: int64(7)},
+		{value: json.Number("1.5"), want: 1.5},
+	}
+	for _, tt := range tests {
+		got := enc.appendValueOrPlaceholder(nil, tt.value)
+		want := enc.appendValueOrPlaceholder(nil, tt.want)
+		if !bytes.Equal(got, want) {
+			t.Fatalf("%v: got %x, want %x", tt.value, got, want)
+		}
+	}
+}
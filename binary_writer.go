@@ -0,0 +1,445 @@
+package golog
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// BinaryFormat selects the record encoding used by BinaryLogWriter.
+type BinaryFormat uint8
+
+const (
+	// BinaryCBOR encodes records as CBOR maps (RFC 8949).
+	BinaryCBOR BinaryFormat = iota
+	// BinaryMessagePack encodes records as MessagePack maps.
+	BinaryMessagePack
+)
+
+// WithBinaryFormat formats entries as length-prefixed CBOR or MessagePack
+// records instead of JSON lines. See BinaryLogWriter for the framing.
+func WithBinaryFormat(format BinaryFormat) Option {
+	return WithLogWriter(&BinaryLogWriter{Format: format})
+}
+
+// BinaryLogWriter encodes each entry as a single CBOR or MessagePack map
+// holding the same keys as the JSON output (timestamp, level, message, base
+// fields and per-call fields). Every record is preceded by its length as a
+// 4-byte big-endian unsigned integer, so a reader can split the stream
+// without parsing it.
+//
+// Values map onto native types: strings, integers, floats (including NaN and
+// ±Inf), booleans, null, byte strings for []byte, arrays and maps. Timestamps
+// are RFC 3339 strings (CBOR tag 0), durations follow the logger's
+// DurationFormat, and structs are written as maps following their json tags,
+// or as what their MarshalJSON or MarshalText method returns.
+// Values that can't be represented are written as the string "<unsupported>".
+type BinaryLogWriter struct {
+	Format BinaryFormat
+
+	logger *JSONLogger
+}
+
+func (writer *BinaryLogWriter) bindLogger(jsonLogger *JSONLogger) {
+	writer.logger = jsonLogger
+}
+
+// AppendLog implements LogWriter.
+func (writer *BinaryLogWriter) AppendLog(dst []byte, entry Entry) []byte {
+	jsonLogger := entry.config(writer.logger)
+	enc := binaryEncoder{format: writer.Format, encoder: &jsonLogger.encoder}
+
+	start := len(dst)
+	dst = append(dst, 0, 0, 0, 0)
+
+	fields := entry.Fields
+	count := 3
+	for i := range fields {
+		if !((fields[i].omitEmpty || enc.encoder.omitEmpty) && fields[i].isEmpty()) {
+			count++
+		}
+	}
+
+	dst = enc.appendMapHeader(dst, count)
+	dst = enc.appendString(dst, "timestamp")
+	var tsBuf [64]byte
+	dst = enc.appendString(dst, string(jsonLogger.appendTimestamp(tsBuf[:0], entry.Time)))
+	dst = enc.appendString(dst, "level")
+	dst = enc.appendLevel(dst, jsonLogger.levelName(entry.Level))
+	dst = enc.appendString(dst, "message")
+	dst = enc.appendString(dst, entry.Message)
+
+	for i := range fields {
+		field := &fields[i]
+		if (field.omitEmpty || enc.encoder.omitEmpty) && field.isEmpty() {
+			continue
+		}
+		dst = enc.appendString(dst, enc.encoder.prefixedKey(field.key))
+		dst = enc.appendField(dst, field)
+	}
+
+	binary.BigEndian.PutUint32(dst[start:], uint32(len(dst)-start-4))
+	return dst
+}
+
+// appendLevel writes a level name, as a number when WithLevelStrings set a
+// numeric one, matching the JSON output.
+func (enc binaryEncoder) appendLevel(dst []byte, name string) []byte {
+	if !isJSONNumber(name) {
+		return enc.appendString(dst, name)
+	}
+	if integer, err := strconv.ParseInt(name, 10, 64); err == nil {
+		return enc.appendInt(dst, integer)
+	}
+	float, _ := strconv.ParseFloat(name, 64)
+	return enc.appendFloat(dst, float)
+}
+
+// binaryEncoder writes values in either CBOR or MessagePack, sharing the
+// type dispatch between the two formats.
+type binaryEncoder struct {
+	format  BinaryFormat
+	encoder *encoder
+}
+
+// appendField encodes a typed Field value without boxing scalars.
+func (enc binaryEncoder) appendField(dst []byte, field *Field) []byte {
+	switch field.kind {
+	case fieldKindStr:
+		return enc.appendStringValue(dst, field.strVal)
+	case fieldKindInt:
+		return enc.appendInt(dst, field.intVal)
+	case fieldKindUint:
+		return enc.appendUint(dst, field.uintVal)
+	case fieldKindFloat:
+		return enc.appendFloat(dst, field.fltVal)
+	case fieldKindBool:
+		return enc.appendBool(dst, field.boolVal)
+	case fieldKindDuration:
+		if enc.encoder.durationFormat == DurationString {
+			return enc.appendString(dst, time.Duration(field.intVal).String())
+		}
+		return enc.appendInt(dst, field.intVal)
+	default:
+		return enc.appendValueOrPlaceholder(dst, field.anyVal)
+	}
+}
+
+func (enc binaryEncoder) appendValueOrPlaceholder(dst []byte, value any) []byte {
+	mark := len(dst)
+	encoded, ok := enc.appendValue(dst, reflect.ValueOf(value), 0)
+	if !ok {
+		return enc.appendString(encoded[:mark], "<unsupported>")
+	}
+	return encoded
+}
+
+// appendMarshaler encodes a value from marshalerOf: MarshalText output as a
+// string, and MarshalJSON output decoded into native maps, arrays and
+// scalars, as structs are written.
+func (enc binaryEncoder) appendMarshaler(dst []byte, marshaler any, depth int) ([]byte, bool) {
+	if jsonMarshaler, ok := marshaler.(json.Marshaler); ok {
+		encoded, err := jsonMarshaler.MarshalJSON()
+		if err != nil {
+			return dst, false
+		}
+		decoder := json.NewDecoder(bytes.NewReader(encoded))
+		decoder.UseNumber()
+		var decoded any
+		if err := decoder.Decode(&decoded); err != nil {
+			return dst, false
+		}
+		return enc.appendValue(dst, reflect.ValueOf(decoded), depth)
+	}
+	text, err := marshaler.(encoding.TextMarshaler).MarshalText()
+	if err != nil {
+		return dst, false
+	}
+	return enc.appendStringValue(dst, string(text)), true
+}
+
+func (enc binaryEncoder) appendValue(dst []byte, value reflect.Value, depth int) ([]byte, bool) {
+	if depth > maxReflectDepth {
+		return dst, false
+	}
+	if !value.IsValid() {
+		return enc.appendNil(dst), true
+	}
+
+	if value.CanInterface() {
+		switch value.Type() {
+		case timeType:
+			var tsBuf [64]byte
+			formatted := appendRFC3339NanoUTC(tsBuf[:0], value.Interface().(time.Time).UTC())
+			if enc.format == BinaryCBOR {
+				dst = append(dst, 0xc0)
+			}
+			return enc.appendString(dst, string(formatted)), true
+		case durationType:
+			if enc.encoder.durationFormat == DurationString {
+				return enc.appendString(dst, time.Duration(value.Int()).String()), true
+			}
+			return enc.appendInt(dst, value.Int()), true
+		case rawMessageType:
+			return enc.appendString(dst, string(value.Bytes())), true
+		case rawJSONSourceType:
+			raw, ok := value.Interface().(rawJSONSource).appendTo(nil)
+			if !ok {
+				return dst, false
+			}
+			return enc.appendString(dst, string(raw)), true
+		case byteSliceType:
+			if value.IsNil() {
+				return enc.appendNil(dst), true
+			}
+			return enc.appendBytes(dst, value.Bytes()), true
+		case jsonNumberType:
+			return enc.appendValue(dst, reflect.ValueOf(enc.encoder.binaryNumber(value.Interface())), depth)
+		case bigIntType, bigFloatType:
+			return enc.appendValue(dst, reflect.ValueOf(enc.encoder.binaryNumber(bigNumberPointer(value))), depth)
+		}
+		if isTextValueType(value.Type()) {
+			if text, ok := textForm(value.Interface()); ok {
+				return enc.appendStringValue(dst, text), true
+			}
+			return enc.appendNil(dst), true
+		}
+		if value.Kind() != reflect.Pointer && value.Kind() != reflect.Interface {
+			if marshaler, ok := marshalerOf(value); ok {
+				return enc.appendMarshaler(dst, marshaler, depth)
+			}
+		}
+	}
+
+	switch value.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if value.IsNil() {
+			return enc.appendNil(dst), true
+		}
+		return enc.appendValue(dst, value.Elem(), depth+1)
+	case reflect.String:
+		return enc.appendStringValue(dst, value.String()), true
+	case reflect.Bool:
+		return enc.appendBool(dst, value.Bool()), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return enc.appendInt(dst, value.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return enc.appendUint(dst, value.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return enc.appendFloat(dst, value.Float()), true
+	case reflect.Map:
+		if !isMapKeyType(value.Type().Key()) {
+			return dst, false
+		}
+		if value.IsNil() {
+			return enc.appendNil(dst), true
+		}
+		dst = enc.appendMapHeader(dst, value.Len())
+		iter := value.MapRange()
+		for iter.Next() {
+			key, ok := mapKeyString(iter.Key())
+			if !ok {
+				return dst, false
+			}
+			dst = enc.appendString(dst, key)
+			dst, ok = enc.appendValue(dst, iter.Value(), depth+1)
+			if !ok {
+				return dst, false
+			}
+		}
+		return dst, true
+	case reflect.Slice, reflect.Array:
+		if value.Kind() == reflect.Slice && value.IsNil() {
+			return enc.appendNil(dst), true
+		}
+		dst = enc.appendArrayHeader(dst, value.Len())
+		for i := 0; i < value.Len(); i++ {
+			var ok bool
+			dst, ok = enc.appendValue(dst, value.Index(i), depth+1)
+			if !ok {
+				return dst, false
+			}
+		}
+		return dst, true
+	case reflect.Struct:
+		plan := planFor(value.Type())
+		present := make([]reflect.Value, 0, len(plan.fields))
+		names := make([]string, 0, len(plan.fields))
+		for i := range plan.fields {
+			fieldValue, ok := fieldByIndex(value, plan.fields[i].index)
+			if !ok || (plan.fields[i].omitEmpty && isEmptyValue(fieldValue)) {
+				continue
+			}
+			present = append(present, fieldValue)
+			names = append(names, plan.fields[i].name)
+		}
+		dst = enc.appendMapHeader(dst, len(present))
+		for i, fieldValue := range present {
+			dst = enc.appendString(dst, names[i])
+			var ok bool
+			dst, ok = enc.appendValue(dst, fieldValue, depth+1)
+			if !ok {
+				return dst, false
+			}
+		}
+		return dst, true
+	default:
+		return dst, false
+	}
+}
+
+func (enc binaryEncoder) appendNil(dst []byte) []byte {
+	if enc.format == BinaryCBOR {
+		return append(dst, 0xf6)
+	}
+	return append(dst, 0xc0)
+}
+
+func (enc binaryEncoder) appendBool(dst []byte, value bool) []byte {
+	if enc.format == BinaryCBOR {
+		if value {
+			return append(dst, 0xf5)
+		}
+		return append(dst, 0xf4)
+	}
+	if value {
+		return append(dst, 0xc3)
+	}
+	return append(dst, 0xc2)
+}
+
+func (enc binaryEncoder) appendInt(dst []byte, value int64) []byte {
+	if value >= 0 {
+		return enc.appendUint(dst, uint64(value))
+	}
+	if enc.format == BinaryCBOR {
+		return appendCBORHeader(dst, 1, uint64(-1-value))
+	}
+	switch {
+	case value >= -32:
+		return append(dst, byte(value))
+	case value >= math.MinInt8:
+		return append(dst, 0xd0, byte(value))
+	case value >= math.MinInt16:
+		return binary.BigEndian.AppendUint16(append(dst, 0xd1), uint16(value))
+	case value >= math.MinInt32:
+		return binary.BigEndian.AppendUint32(append(dst, 0xd2), uint32(value))
+	default:
+		return binary.BigEndian.AppendUint64(append(dst, 0xd3), uint64(value))
+	}
+}
+
+func (enc binaryEncoder) appendUint(dst []byte, value uint64) []byte {
+	if enc.format == BinaryCBOR {
+		return appendCBORHeader(dst, 0, value)
+	}
+	switch {
+	case value <= 0x7f:
+		return append(dst, byte(value))
+	case value <= math.MaxUint8:
+		return append(dst, 0xcc, byte(value))
+	case value <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(dst, 0xcd), uint16(value))
+	case value <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(dst, 0xce), uint32(value))
+	default:
+		return binary.BigEndian.AppendUint64(append(dst, 0xcf), value)
+	}
+}
+
+func (enc binaryEncoder) appendFloat(dst []byte, value float64) []byte {
+	if enc.format == BinaryCBOR {
+		return binary.BigEndian.AppendUint64(append(dst, 0xfb), math.Float64bits(value))
+	}
+	return binary.BigEndian.AppendUint64(append(dst, 0xcb), math.Float64bits(value))
+}
+
+// appendStringValue writes a string value, scrubbed as in the JSON output.
+func (enc binaryEncoder) appendStringValue(dst []byte, value string) []byte {
+	return enc.appendString(dst, enc.encoder.stringValue(value))
+}
+
+func (enc binaryEncoder) appendString(dst []byte, value string) []byte {
+	if enc.format == BinaryCBOR {
+		return append(appendCBORHeader(dst, 3, uint64(len(value))), value...)
+	}
+	length := len(value)
+	switch {
+	case length < 32:
+		dst = append(dst, 0xa0|byte(length))
+	case length <= math.MaxUint8:
+		dst = append(dst, 0xd9, byte(length))
+	case length <= math.MaxUint16:
+		dst = binary.BigEndian.AppendUint16(append(dst, 0xda), uint16(length))
+	default:
+		dst = binary.BigEndian.AppendUint32(append(dst, 0xdb), uint32(length))
+	}
+	return append(dst, value...)
+}
+
+func (enc binaryEncoder) appendBytes(dst []byte, value []byte) []byte {
+	if enc.format == BinaryCBOR {
+		return append(appendCBORHeader(dst, 2, uint64(len(value))), value...)
+	}
+	length := len(value)
+	switch {
+	case length <= math.MaxUint8:
+		dst = append(dst, 0xc4, byte(length))
+	case length <= math.MaxUint16:
+		dst = binary.BigEndian.AppendUint16(append(dst, 0xc5), uint16(length))
+	default:
+		dst = binary.BigEndian.AppendUint32(append(dst, 0xc6), uint32(length))
+	}
+	return append(dst, value...)
+}
+
+func (enc binaryEncoder) appendArrayHeader(dst []byte, length int) []byte {
+	if enc.format == BinaryCBOR {
+		return appendCBORHeader(dst, 4, uint64(length))
+	}
+	switch {
+	case length < 16:
+		return append(dst, 0x90|byte(length))
+	case length <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(dst, 0xdc), uint16(length))
+	default:
+		return binary.BigEndian.AppendUint32(append(dst, 0xdd), uint32(length))
+	}
+}
+
+func (enc binaryEncoder) appendMapHeader(dst []byte, length int) []byte {
+	if enc.format == BinaryCBOR {
+		return appendCBORHeader(dst, 5, uint64(length))
+	}
+	switch {
+	case length < 16:
+		return append(dst, 0x80|byte(length))
+	case length <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(dst, 0xde), uint16(length))
+	default:
+		return binary.BigEndian.AppendUint32(append(dst, 0xdf), uint32(length))
+	}
+}
+
+// appendCBORHeader writes a CBOR initial byte for major type and argument,
+// followed by the argument bytes when it doesn't fit in the initial byte.
+func appendCBORHeader(dst []byte, major byte, argument uint64) []byte {
+	major <<= 5
+	switch {
+	case argument < 24:
+		return append(dst, major|byte(argument))
+	case argument <= math.MaxUint8:
+		return append(dst, major|24, byte(argument))
+	case argument <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(dst, major|25), uint16(argument))
+	case argument <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(dst, major|26), uint32(argument))
+	default:
+		return binary.BigEndian.AppendUint64(append(dst, major|27), argument)
+	}
+}
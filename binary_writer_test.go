@@ -0,0 +1,325 @@
+package golog
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+	"time"
+)
+
+// decodeBinaryRecords splits a length-prefixed stream and decodes each record
+// with decode.
+func decodeBinaryRecords(t *testing.T, stream []byte, decode func([]byte) (any, []byte)) []map[string]any {
+	t.Helper()
+
+	var records []map[string]any
+	for len(stream) > 0 {
+		if len(stream) < 4 {
+			t.Fatalf("truncated length prefix")
+		}
+		length := binary.BigEndian.Uint32(stream)
+		record := stream[4 : 4+length]
+		stream = stream[4+length:]
+
+		value, rest := decode(record)
+		if len(rest) != 0 {
+			t.Fatalf("record has %d trailing bytes", len(rest))
+		}
+		m, ok := value.(map[string]any)
+		if !ok {
+			t.Fatalf("expected record to decode to a map, got %#v", value)
+		}
+		records = append(records, m)
+	}
+	return records
+}
+
+// decodeCBOR decodes the subset of CBOR produced by BinaryLogWriter.
+func decodeCBOR(data []byte) (any, []byte) {
+	initial := data[0]
+	major, info := initial>>5, initial&0x1f
+	data = data[1:]
+
+	if major == 7 {
+		switch initial {
+		case 0xf4:
+			return false, data
+		case 0xf5:
+			return true, data
+		case 0xf6:
+			return nil, data
+		case 0xfb:
+			return math.Float64frombits(binary.BigEndian.Uint64(data)), data[8:]
+		}
+		panic("unexpected simple value")
+	}
+
+	var argument uint64
+	switch {
+	case info < 24:
+		argument = uint64(info)
+	case info == 24:
+		argument, data = uint64(data[0]), data[1:]
+	case info == 25:
+		argument, data = uint64(binary.BigEndian.Uint16(data)), data[2:]
+	case info == 26:
+		argument, data = uint64(binary.BigEndian.Uint32(data)), data[4:]
+	default:
+		argument, data = binary.BigEndian.Uint64(data), data[8:]
+	}
+
+	switch major {
+	case 0:
+		return int64(argument), data
+	case 1:
+		return -1 - int64(argument), data
+	case 2:
+		return append([]byte(nil), data[:argument]...), data[argument:]
+	case 3:
+		return string(data[:argument]), data[argument:]
+	case 4:
+		out := make([]any, argument)
+		for i := range out {
+			out[i], data = decodeCBOR(data)
+		}
+		return out, data
+	case 5:
+		out := make(map[string]any, argument)
+		for i := uint64(0); i < argument; i++ {
+			var key, value any
+			key, data = decodeCBOR(data)
+			value, data = decodeCBOR(data)
+			out[key.(string)] = value
+		}
+		return out, data
+	case 6:
+		return decodeCBOR(data)
+	}
+	panic("unexpected major type")
+}
+
+// decodeMsgPack decodes the subset of MessagePack produced by BinaryLogWriter.
+func decodeMsgPack(data []byte) (any, []byte) {
+	b := data[0]
+	data = data[1:]
+
+	readLength := func(size int) int {
+		var n int
+		switch size {
+		case 1:
+			n = int(data[0])
+		case 2:
+			n = int(binary.BigEndian.Uint16(data))
+		case 4:
+			n = int(binary.BigEndian.Uint32(data))
+		}
+		data = data[size:]
+		return n
+	}
+	readMap := func(n int) (any, []byte) {
+		out := make(map[string]any, n)
+		for i := 0; i < n; i++ {
+			var key, value any
+			key, data = decodeMsgPack(data)
+			value, data = decodeMsgPack(data)
+			out[key.(string)] = value
+		}
+		return out, data
+	}
+	readArray := func(n int) (any, []byte) {
+		out := make([]any, n)
+		for i := range out {
+			out[i], data = decodeMsgPack(data)
+		}
+		return out, data
+	}
+
+	switch {
+	case b <= 0x7f:
+		return int64(b), data
+	case b >= 0xe0:
+		return int64(int8(b)), data
+	case b&0xf0 == 0x80:
+		return readMap(int(b & 0x0f))
+	case b&0xf0 == 0x90:
+		return readArray(int(b & 0x0f))
+	case b&0xe0 == 0xa0:
+		n := int(b & 0x1f)
+		return string(data[:n]), data[n:]
+	}
+
+	switch b {
+	case 0xc0:
+		return nil, data
+	case 0xc2:
+		return false, data
+	case 0xc3:
+		return true, data
+	case 0xc4, 0xc5, 0xc6:
+		n := readLength(map[byte]int{0xc4: 1, 0xc5: 2, 0xc6: 4}[b])
+		return append([]byte(nil), data[:n]...), data[n:]
+	case 0xcb:
+		return math.Float64frombits(binary.BigEndian.Uint64(data)), data[8:]
+	case 0xcc:
+		return int64(data[0]), data[1:]
+	case 0xcd:
+		return int64(binary.BigEndian.Uint16(data)), data[2:]
+	case 0xce:
+		return int64(binary.BigEndian.Uint32(data)), data[4:]
+	case 0xcf:
+		return int64(binary.BigEndian.Uint64(data)), data[8:]
+	case 0xd0:
+		return int64(int8(data[0])), data[1:]
+	case 0xd1:
+		return int64(int16(binary.BigEndian.Uint16(data))), data[2:]
+	case 0xd2:
+		return int64(int32(binary.BigEndian.Uint32(data))), data[4:]
+	case 0xd3:
+		return int64(binary.BigEndian.Uint64(data)), data[8:]
+	case 0xd9, 0xda, 0xdb:
+		n := readLength(map[byte]int{0xd9: 1, 0xda: 2, 0xdb: 4}[b])
+		return string(data[:n]), data[n:]
+	case 0xdc, 0xdd:
+		return readArray(readLength(map[byte]int{0xdc: 2, 0xdd: 4}[b]))
+	case 0xde, 0xdf:
+		return readMap(readLength(map[byte]int{0xde: 2, 0xdf: 4}[b]))
+	}
+	panic("unexpected msgpack byte")
+}
+
+func TestBinaryLogWriterRoundTrip(t *testing.T) {
+	type point struct {
+		X int `json:"x"`
+		Y int `json:"y,omitempty"`
+	}
+
+	formats := []struct {
+		name   string
+		format BinaryFormat
+		decode func([]byte) (any, []byte)
+	}{
+		{name: "cbor", format: BinaryCBOR, decode: decodeCBOR},
+		{name: "msgpack", format: BinaryMessagePack, decode: decodeMsgPack},
+	}
+
+	for _, tc := range formats {
+		t.Run(tc.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			jl := NewJSONLoggerWithOptions(
+				WithOutput(buf),
+				WithBinaryFormat(tc.format),
+				WithBaseField("service", "api"),
+			)
+
+			jl.Info("first",
+				Int("small", -3),
+				Int("big", -100000),
+				Float64("ratio", 0.25),
+				Bool("ok", true),
+				Duration("took", time.Millisecond),
+				Any("raw", []byte{1, 2, 3}),
+				Any("point", point{X: 1}),
+				Any("device", deviceID{1, 2, 3, 4}),
+				Any("amount", money{cents: 1250, currency: "EUR"}),
+				Any("list", []any{"a", nil, 300}),
+				Str("long", string(bytes.Repeat([]byte("x"), 300))),
+			)
+			jl.Warn("second")
+
+			records := decodeBinaryRecords(t, buf.Bytes(), tc.decode)
+			if len(records) != 2 {
+				t.Fatalf("expected 2 records, got %d", len(records))
+			}
+
+			first := records[0]
+			if first["level"] != "info" || first["message"] != "first" || first["service"] != "api" {
+				t.Fatalf("unexpected core fields: %v", first)
+			}
+			if _, err := time.Parse(time.RFC3339Nano, first["timestamp"].(string)); err != nil {
+				t.Fatalf("timestamp not RFC3339: %v", err)
+			}
+			if first["small"] != int64(-3) || first["big"] != int64(-100000) {
+				t.Fatalf("unexpected ints: %v %v", first["small"], first["big"])
+			}
+			if first["ratio"] != 0.25 || first["ok"] != true || first["took"] != int64(time.Millisecond) {
+				t.Fatalf("unexpected scalars: %v", first)
+			}
+			if !bytes.Equal(first["raw"].([]byte), []byte{1, 2, 3}) {
+				t.Fatalf("unexpected bytes: %v", first["raw"])
+			}
+			pointValue := first["point"].(map[string]any)
+			if len(pointValue) != 1 || pointValue["x"] != int64(1) {
+				t.Fatalf("unexpected struct: %v", pointValue)
+			}
+			amount, _ := first["amount"].(map[string]any)
+			if first["device"] != "01020304" || amount["amount"] != 12.5 || amount["currency"] != "EUR" {
+				t.Fatalf("expected marshalers to be honored: %v %v", first["device"], first["amount"])
+			}
+			list := first["list"].([]any)
+			if len(list) != 3 || list[0] != "a" || list[1] != nil || list[2] != int64(300) {
+				t.Fatalf("unexpected list: %v", list)
+			}
+			if len(first["long"].(string)) != 300 {
+				t.Fatalf("unexpected long string length: %d", len(first["long"].(string)))
+			}
+			if records[1]["level"] != "warn" {
+				t.Fatalf("unexpected second record: %v", records[1])
+			}
+		})
+	}
+}
+
+func TestBinaryEncoderKnownEncodings(t *testing.T) {
+	cbor := binaryEncoder{format: BinaryCBOR, encoder: &defaultEncoder}
+	msgpack := binaryEncoder{format: BinaryMessagePack, encoder: &defaultEncoder}
+
+	tests := []struct {
+		name string
+		got  []byte
+		want []byte
+	}{
+		{name: "cbor map", got: cbor.appendValueOrPlaceholder(nil, map[string]any{"a": 1}), want: []byte{0xa1, 0x61, 'a', 0x01}},
+		{name: "cbor negative", got: cbor.appendInt(nil, -500), want: []byte{0x39, 0x01, 0xf3}},
+		{name: "cbor unsupported", got: cbor.appendValueOrPlaceholder(nil, make(chan int)), want: append([]byte{0x6d}, "<unsupported>"...)},
+		{name: "msgpack map", got: msgpack.appendValueOrPlaceholder(nil, map[string]any{"a": 1}), want: []byte{0x81, 0xa1, 'a', 0x01}},
+		{name: "msgpack negative", got: msgpack.appendInt(nil, -500), want: []byte{0xd1, 0xfe, 0x0c}},
+		{name: "msgpack uint16", got: msgpack.appendUint(nil, 1000), want: []byte{0xcd, 0x03, 0xe8}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if !bytes.Equal(tc.got, tc.want) {
+				t.Fatalf("expected % x, got % x", tc.want, tc.got)
+			}
+		})
+	}
+}
+
+func TestBinaryLogWriterLevelStrings(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		format BinaryFormat
+		decode func([]byte) (any, []byte)
+	}{
+		{name: "cbor", format: BinaryCBOR, decode: decodeCBOR},
+		{name: "msgpack", format: BinaryMessagePack, decode: decodeMsgPack},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			jl := NewJSONLoggerWithOptions(
+				WithOutput(buf),
+				WithBinaryFormat(tc.format),
+				WithLevelStrings(map[Level]string{WarnLevel: "WARNING", ErrorLevel: "50"}),
+			)
+			jl.Info("default")
+			jl.Warn("renamed")
+			jl.Error("numeric")
+
+			records := decodeBinaryRecords(t, buf.Bytes(), tc.decode)
+			if records[0]["level"] != "info" || records[1]["level"] != "WARNING" || records[2]["level"] != int64(50) {
+				t.Fatalf("expected the configured level names, got %v %v %v", records[0]["level"], records[1]["level"], records[2]["level"])
+			}
+		})
+	}
+}
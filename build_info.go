@@ -0,0 +1,43 @@
+package golog
+
+import "runtime/debug"
+
+// Base field keys written by WithBuildInfo.
+const (
+	BuildVersionKey     = "version"
+	BuildRevisionKey    = "vcs.revision"
+	BuildTimeKey        = "vcs.time"
+	BuildVCSModifiedKey = "vcs.modified"
+)
+
+// readBuildInfo is debug.ReadBuildInfo, replaceable in tests.
+var readBuildInfo = debug.ReadBuildInfo
+
+// WithBuildInfo adds base fields identifying the running build, read from
+// runtime/debug.ReadBuildInfo: the main module version, the VCS revision and
+// commit time, and whether the working tree had uncommitted changes. Values
+// the toolchain didn't record, such as the "(devel)" version of a local
+// build or the VCS settings under -buildvcs=false, are skipped.
+//
+//	jl := NewJSONLoggerWithOptions(WithBuildInfo(), WithEnvironment())
+func WithBuildInfo() Option {
+	return func(jsonLogger *JSONLogger) {
+		info, ok := readBuildInfo()
+		if !ok {
+			return
+		}
+		if version := info.Main.Version; version != "" && version != "(devel)" {
+			WithBaseField(BuildVersionKey, version)(jsonLogger)
+		}
+		for _, setting := range info.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				WithBaseField(BuildRevisionKey, setting.Value)(jsonLogger)
+			case "vcs.time":
+				WithBaseField(BuildTimeKey, setting.Value)(jsonLogger)
+			case "vcs.modified":
+				WithBaseField(BuildVCSModifiedKey, setting.Value == "true")(jsonLogger)
+			}
+		}
+	}
+}
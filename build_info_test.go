@@ -0,0 +1,55 @@
+package golog
+
+import (
+	"runtime/debug"
+	"testing"
+)
+
+func stubBuildInfo(t *testing.T, info *debug.BuildInfo, ok bool) {
+	t.Helper()
+	original := readBuildInfo
+	readBuildInfo = func() (*debug.BuildInfo, bool) { return info, ok }
+	t.Cleanup(func() { readBuildInfo = original })
+}
+
+func TestWithBuildInfoAddsVersionAndVCSFields(t *testing.T) {
+	stubBuildInfo(t, &debug.BuildInfo{
+		Main: debug.Module{Path: "example.com/api", Version: "v1.4.2"},
+		Settings: []debug.BuildSetting{
+			{Key: "-trimpath", Value: "true"},
+			{Key: "vcs", Value: "git"},
+			{Key: "vcs.revision", Value: "3f2a9c1"},
+			{Key: "vcs.time", Value: "2026-10-01T12:00:00Z"},
+			{Key: "vcs.modified", Value: "true"},
+		},
+	}, true)
+
+	jl := NewJSONLoggerWithOptions(WithBuildInfo())
+
+	want := map[string]any{
+		BuildVersionKey:     "v1.4.2",
+		BuildRevisionKey:    "3f2a9c1",
+		BuildTimeKey:        "2026-10-01T12:00:00Z",
+		BuildVCSModifiedKey: true,
+	}
+	if len(jl.baseFields) != len(want) {
+		t.Fatalf("expected %v, got %v", want, jl.baseFields)
+	}
+	for key, value := range want {
+		if jl.baseFields[key] != value {
+			t.Fatalf("expected %s=%v, got %v", key, value, jl.baseFields)
+		}
+	}
+}
+
+func TestWithBuildInfoSkipsUnrecordedValues(t *testing.T) {
+	stubBuildInfo(t, &debug.BuildInfo{Main: debug.Module{Version: "(devel)"}}, true)
+	if jl := NewJSONLoggerWithOptions(WithBuildInfo()); len(jl.baseFields) != 0 {
+		t.Fatalf("expected no fields for a local build, got %v", jl.baseFields)
+	}
+
+	stubBuildInfo(t, nil, false)
+	if jl := NewJSONLoggerWithOptions(WithBuildInfo()); len(jl.baseFields) != 0 {
+		t.Fatalf("expected no fields without build info, got %v", jl.baseFields)
+	}
+}
@@ -0,0 +1,173 @@
+package golog
+
+import (
+	"compress/gzip"
+	"io"
+	"sync"
+	"time"
+)
+
+// CompressWriter is a streaming compressor. *gzip.Writer satisfies it, as
+// does the zstd encoder from github.com/klauspost/compress/zstd.
+type CompressWriter interface {
+	io.Writer
+	// Flush writes any buffered data so everything written so far can be
+	// decompressed, without ending the stream.
+	Flush() error
+	// Close flushes and terminates the stream. It does not close the
+	// underlying writer.
+	Close() error
+}
+
+// CompressionCodec creates a CompressWriter writing to w. GzipCodec is
+// built in; other codecs plug in with a small adapter:
+//
+//	zstdCodec := func(w io.Writer) golog.CompressWriter {
+//	    encoder, _ := zstd.NewWriter(w)
+//	    return encoder
+//	}
+type CompressionCodec func(w io.Writer) CompressWriter
+
+// GzipCodec compresses with gzip at the default compression level.
+func GzipCodec(w io.Writer) CompressWriter {
+	return gzip.NewWriter(w)
+}
+
+// Default flush boundaries used by WithCompressedOutput.
+const (
+	defaultCompressFlushEvery    = 64
+	defaultCompressFlushInterval = time.Second
+)
+
+// CompressedWriter compresses records written to it and flushes the
+// compressor at regular boundaries, so a file that is still being written,
+// or was cut off by a crash, decompresses up to the last boundary. It is
+// safe for concurrent use.
+type CompressedWriter struct {
+	// FlushEvery flushes after this many writes. Zero disables count based
+	// flushing.
+	FlushEvery int
+	// FlushInterval flushes written data at most this long after the
+	// previous flush, from a timer when no further write comes. Zero
+	// disables time based flushing.
+	FlushInterval time.Duration
+
+	dst io.Writer
+
+	mutex     sync.Mutex
+	stream    CompressWriter
+	pending   int
+	lastFlush time.Time
+	// timer flushes pending data once FlushInterval has passed; nil when
+	// it isn't armed.
+	timer *time.Timer
+	// timerErr is the error of the last flush made by the timer, returned
+	// by the next Flush or Close.
+	timerErr error
+	closed   bool
+}
+
+// NewCompressedWriter returns a CompressedWriter that writes codec output
+// to dst, flushing every 64 records or once a second, whichever comes first.
+func NewCompressedWriter(dst io.Writer, codec CompressionCodec) *CompressedWriter {
+	return &CompressedWriter{
+		FlushEvery:    defaultCompressFlushEvery,
+		FlushInterval: defaultCompressFlushInterval,
+		dst:           dst,
+		stream:        codec(dst),
+		lastFlush:     time.Now(),
+	}
+}
+
+// Write compresses p, flushing the stream when a boundary is reached.
+func (writer *CompressedWriter) Write(p []byte) (int, error) {
+	writer.mutex.Lock()
+	defer writer.mutex.Unlock()
+
+	n, err := writer.stream.Write(p)
+	if err != nil {
+		return n, err
+	}
+	writer.pending++
+	wait := writer.FlushInterval - time.Since(writer.lastFlush)
+	switch {
+	case (writer.FlushEvery > 0 && writer.pending >= writer.FlushEvery) || (writer.FlushInterval > 0 && wait <= 0):
+		err = writer.flushLocked()
+	case writer.FlushInterval > 0 && writer.timer == nil:
+		writer.timer = time.AfterFunc(wait, writer.flushOnTimer)
+	}
+	return n, err
+}
+
+// Flush forces a flush boundary.
+func (writer *CompressedWriter) Flush() error {
+	writer.mutex.Lock()
+	defer writer.mutex.Unlock()
+	err := writer.flushLocked()
+	if writer.timerErr != nil {
+		err, writer.timerErr = writer.timerErr, nil
+	}
+	return err
+}
+
+// flushOnTimer flushes the data written since the last flush, for writers
+// that went idle before reaching a boundary.
+func (writer *CompressedWriter) flushOnTimer() {
+	writer.mutex.Lock()
+	defer writer.mutex.Unlock()
+	writer.timer = nil
+	if writer.closed || writer.pending == 0 {
+		return
+	}
+	if err := writer.flushLocked(); err != nil {
+		writer.timerErr = err
+	}
+}
+
+func (writer *CompressedWriter) flushLocked() error {
+	writer.pending = 0
+	writer.lastFlush = time.Now()
+	return writer.stream.Flush()
+}
+
+// Close stops the flush timer and terminates the compressed stream. Like
+// gzip.Writer, it does not close the underlying writer.
+func (writer *CompressedWriter) Close() error {
+	writer.mutex.Lock()
+	defer writer.mutex.Unlock()
+	writer.closed = true
+	if writer.timer != nil {
+		writer.timer.Stop()
+		writer.timer = nil
+	}
+	err := writer.stream.Close()
+	if writer.timerErr != nil {
+		err, writer.timerErr = writer.timerErr, nil
+	}
+	return err
+}
+
+// Unwrap returns the writer the compressed stream goes to.
+func (writer *CompressedWriter) Unwrap() io.Writer {
+	return writer.dst
+}
+
+// closeWrapper is Close, which leaves the underlying writer open.
+func (writer *CompressedWriter) closeWrapper() error {
+	return writer.Close()
+}
+
+// WithCompressedOutput compresses the logger's output with codec, using the
+// default flush boundaries of NewCompressedWriter. It wraps the output
+// configured so far, so pass it after WithOutput. Call Close on the logger
+// to terminate the stream before closing the underlying file.
+//
+//	file, _ := os.Create("debug.json.gz")
+//	defer file.Close()
+//	jl := NewJSONLoggerWithOptions(WithOutput(file), WithCompressedOutput(GzipCodec))
+//	defer jl.Close()
+func WithCompressedOutput(codec CompressionCodec) Option {
+	return func(jsonLogger *JSONLogger) {
+		jsonLogger.output = NewCompressedWriter(jsonLogger.output, codec)
+	}
+}
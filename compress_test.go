@@ -0,0 +1,103 @@
+package golog
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithCompressedOutputRoundTrip(t *testing.T) {
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(WithOutput(buf), WithCompressedOutput(GzipCodec))
+
+	for i := 0; i < 200; i++ {
+		jl.Info("verbose debug output", Int("i", i))
+	}
+	if err := jl.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	compressedSize := buf.Len()
+	reader, err := gzip.NewReader(buf)
+	if err != nil {
+		t.Fatalf("gzip reader: %v", err)
+	}
+	plain, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("decompress: %v", err)
+	}
+	if got := strings.Count(string(plain), "\n"); got != 200 {
+		t.Fatalf("expected 200 lines, got %d", got)
+	}
+	if compressedSize*4 > len(plain) {
+		t.Fatalf("expected repetitive logs to compress well, got %d -> %d bytes", len(plain), compressedSize)
+	}
+}
+
+func TestCompressedWriterFlushBoundariesKeepPartialStreamReadable(t *testing.T) {
+	buf := &bytes.Buffer{}
+	writer := NewCompressedWriter(buf, GzipCodec)
+	writer.FlushEvery = 2
+	writer.FlushInterval = 0
+
+	for _, line := range []string{"one\n", "two\n", "three\n"} {
+		if _, err := writer.Write([]byte(line)); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+
+	// The stream was never closed: everything up to the last boundary must
+	// still decompress.
+	reader, err := gzip.NewReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("gzip reader: %v", err)
+	}
+	plain, err := io.ReadAll(reader)
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("expected truncated stream, got %v", err)
+	}
+	if string(plain) != "one\ntwo\n" {
+		t.Fatalf("expected records up to the flush boundary, got %q", plain)
+	}
+}
+
+func TestCompressedWriterFlushesIdleStreamOnTimer(t *testing.T) {
+	output := &countingWriter{}
+	writer := NewCompressedWriter(output, GzipCodec)
+	writer.FlushEvery = 0
+	writer.FlushInterval = 10 * time.Millisecond
+
+	if _, err := writer.Write([]byte("idle\n")); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		_, data := output.snapshot()
+		reader, err := gzip.NewReader(bytes.NewReader(data))
+		if err == nil {
+			if plain, _ := io.ReadAll(reader); string(plain) == "idle\n" {
+				break
+			}
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected the timer to flush the idle stream")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if _, err := writer.Write([]byte("last\n")); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	writes, _ := output.snapshot()
+	time.Sleep(30 * time.Millisecond)
+	if after, _ := output.snapshot(); after != writes {
+		t.Fatalf("expected Close to stop the timer, got %d more writes", after-writes)
+	}
+}
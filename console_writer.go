@@ -0,0 +1,271 @@
+package golog
+
+import (
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// ColorMode controls whether ConsoleLogWriter emits ANSI colors.
+type ColorMode uint8
+
+const (
+	// ColorAuto colors output only when the logger writes directly to a
+	// terminal, the NO_COLOR environment variable is unset or empty and
+	// TERM is not "dumb".
+	ColorAuto ColorMode = iota
+	// ColorAlways always colors output.
+	ColorAlways
+	// ColorNever never colors output.
+	ColorNever
+)
+
+// Theme holds the ANSI SGR sequences ConsoleLogWriter writes before each
+// part of a line, e.g. "\x1b[31m" for red. An empty sequence leaves that
+// part uncolored.
+type Theme struct {
+	Debug     string
+	Info      string
+	Warn      string
+	Error     string
+	Timestamp string
+	Message   string
+	Key       string
+}
+
+// DefaultTheme returns the theme used when ConsoleLogWriter.Theme is nil:
+// dim timestamps, bold messages, cyan keys and one color per level.
+func DefaultTheme() Theme {
+	return Theme{
+		Debug:     levelMetadata[DebugLevel].Color,
+		Info:      levelMetadata[InfoLevel].Color,
+		Warn:      levelMetadata[WarnLevel].Color,
+		Error:     levelMetadata[ErrorLevel].Color,
+		Timestamp: "\x1b[2m",
+		Message:   "\x1b[1m",
+		Key:       "\x1b[36m",
+	}
+}
+
+// levelColor returns the sequence for level.
+func (theme *Theme) levelColor(level Level) string {
+	switch level {
+	case DebugLevel:
+		return theme.Debug
+	case InfoLevel:
+		return theme.Info
+	case WarnLevel:
+		return theme.Warn
+	default:
+		return theme.Error
+	}
+}
+
+const ansiReset = "\x1b[0m"
+
+// devTimeFormat is the millisecond wall-clock layout used by WithDevFormat.
+const devTimeFormat = "15:04:05.000"
+
+// ConsoleLogWriter writes human-readable lines for terminals:
+//
+//	2024-05-01T12:00:00.123Z INFO  server started port=8080 mode="read only"
+//
+// String values are written bare unless they need quoting; other values are
+// written as compact JSON. Control characters in the message and keys are
+// escaped as in Go string literals, so they can't start a new line or a
+// terminal sequence. Colors follow Color and Theme.
+type ConsoleLogWriter struct {
+	// Color selects when to emit colors. Defaults to ColorAuto.
+	Color ColorMode
+	// Theme sets the colors. Nil uses DefaultTheme.
+	Theme *Theme
+	// TimeFormat, when set, renders timestamps in local time with this
+	// layout instead of the logger's UTC time format.
+	TimeFormat string
+	// Glyphs writes a single symbol per level (· • ▲ ✖) instead of the
+	// padded level name.
+	Glyphs bool
+
+	logger *JSONLogger
+	// output is the destination checked for a terminal when it differs
+	// from the logger's output, as inside a WriterSink.
+	output    io.Writer
+	colorOnce sync.Once
+	colored   bool
+	theme     Theme
+}
+
+// WithConsole formats entries with a ConsoleLogWriter using automatic color
+// detection and the default theme.
+func WithConsole() Option {
+	return WithLogWriter(&ConsoleLogWriter{})
+}
+
+func (writer *ConsoleLogWriter) bindLogger(jsonLogger *JSONLogger) {
+	writer.logger = jsonLogger
+}
+
+func (writer *ConsoleLogWriter) bindOutput(output io.Writer) {
+	writer.output = output
+}
+
+// WithDevFormat formats entries as compact console lines for local
+// iteration, with millisecond local times and level glyphs:
+//
+//	15:04:05.123 ✖ payment failed order=42 err="card declined"
+func WithDevFormat() Option {
+	return WithLogWriter(&ConsoleLogWriter{TimeFormat: devTimeFormat, Glyphs: true})
+}
+
+// resolveColor decides once whether to color output. Detection looks at
+// the logger's output when the first entry is written, so it sees the final
+// WithOutput; outputs wrapped by WithAsync and similar options are not
+// detected as terminals.
+func (writer *ConsoleLogWriter) resolveColor() {
+	switch writer.Color {
+	case ColorAlways:
+		writer.colored = true
+	case ColorAuto:
+		output := writer.output
+		if output == nil && writer.logger != nil {
+			output = writer.logger.current().output
+		}
+		writer.colored = os.Getenv("NO_COLOR") == "" && os.Getenv("TERM") != "dumb" && isTerminal(output)
+	}
+	if writer.Theme != nil {
+		writer.theme = *writer.Theme
+	} else {
+		writer.theme = DefaultTheme()
+	}
+}
+
+// isTerminal reports whether w is a character device such as a TTY.
+func isTerminal(w io.Writer) bool {
+	file, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// AppendLog implements LogWriter.
+func (writer *ConsoleLogWriter) AppendLog(dst []byte, entry Entry) []byte {
+	writer.colorOnce.Do(writer.resolveColor)
+	jsonLogger := entry.config(writer.logger)
+	enc := &jsonLogger.encoder
+	theme := &writer.theme
+
+	dst = writer.startColor(dst, theme.Timestamp)
+	if writer.TimeFormat != "" {
+		dst = entry.Time.Local().AppendFormat(dst, writer.TimeFormat)
+	} else {
+		dst = jsonLogger.appendTimestamp(dst, entry.Time)
+	}
+	dst = writer.endColor(dst, theme.Timestamp)
+	dst = append(dst, ' ')
+
+	levelColor := theme.levelColor(entry.Level)
+	dst = writer.startColor(dst, levelColor)
+	switch {
+	case entry.Level < DebugLevel || entry.Level > ErrorLevel:
+		dst = append(dst, entry.Level.String()...)
+	case writer.Glyphs:
+		dst = append(dst, levelMetadata[entry.Level].Glyph...)
+	default:
+		dst = append(dst, levelMetadata[entry.Level].Label...)
+	}
+	dst = writer.endColor(dst, levelColor)
+	dst = append(dst, ' ')
+
+	dst = writer.startColor(dst, theme.Message)
+	dst = appendConsoleText(dst, entry.Message)
+	dst = writer.endColor(dst, theme.Message)
+
+	for i := range entry.Fields {
+		field := &entry.Fields[i]
+		if (field.omitEmpty || enc.omitEmpty) && field.isEmpty() {
+			continue
+		}
+		dst = append(dst, ' ')
+		dst = writer.startColor(dst, theme.Key)
+		if enc.keyPrefix != "" && !strings.HasPrefix(field.key, enc.keyPrefix) {
+			dst = append(dst, enc.keyPrefix...)
+		}
+		dst = appendConsoleText(dst, field.key)
+		dst = append(dst, '=')
+		dst = writer.endColor(dst, theme.Key)
+		dst = appendConsoleValue(dst, enc, field)
+	}
+	return append(dst, '\n')
+}
+
+func (writer *ConsoleLogWriter) startColor(dst []byte, color string) []byte {
+	if !writer.colored || color == "" {
+		return dst
+	}
+	return append(dst, color...)
+}
+
+func (writer *ConsoleLogWriter) endColor(dst []byte, color string) []byte {
+	if !writer.colored || color == "" {
+		return dst
+	}
+	return append(dst, ansiReset...)
+}
+
+// appendConsoleValue writes a string field bare when that is unambiguous and
+// everything else as compact JSON. Strings are scrubbed either way.
+func appendConsoleValue(dst []byte, enc *encoder, field *Field) []byte {
+	value := field.strVal
+	isString := field.kind == fieldKindStr
+	if field.kind == fieldKindAny {
+		value, isString = field.anyVal.(string)
+	}
+	if !isString {
+		return enc.appendFieldValue(dst, *field)
+	}
+	value = enc.stringValue(value)
+	if !needsConsoleQuoting(value) {
+		return appendConsoleText(dst, value)
+	}
+	return enc.appendString(dst, value)
+}
+
+// appendConsoleText appends s with control characters, C1 ones included,
+// escaped as in Go string literals, and invalid UTF-8 bytes as \xNN.
+func appendConsoleText(dst []byte, s string) []byte {
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == utf8.RuneError && size == 1:
+			dst = append(dst, '\\', 'x', hexDigits[s[i]>>4], hexDigits[s[i]&0xf])
+		case r < ' ' || r == 0x7f || (r >= 0x80 && r < 0xa0):
+			// AppendQuoteRune writes the escape between single quotes.
+			mark := len(dst)
+			dst = strconv.AppendQuoteRune(dst, r)
+			dst = append(dst[:mark], dst[mark+1:len(dst)-1]...)
+		default:
+			dst = append(dst, s[i:i+size]...)
+		}
+		i += size
+	}
+	return dst
+}
+
+// needsConsoleQuoting reports whether s is empty or contains spaces, quotes,
+// '=', control characters or invalid UTF-8.
+func needsConsoleQuoting(s string) bool {
+	if s == "" || !utf8.ValidString(s) {
+		return true
+	}
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; c <= ' ' || c == '"' || c == '=' || c == '\\' || c == 0x7f {
+			return true
+		}
+	}
+	return false
+}
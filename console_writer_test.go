@@ -0,0 +1,118 @@
+package golog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConsoleLogWriterFormatsLines(t *testing.T) {
+	buf := &bytes.Buffer{}
+	clock := time.Date(2024, 5, 1, 12, 0, 0, 123000000, time.UTC)
+	jl := NewJSONLoggerWithOptions(
+		WithOutput(buf),
+		WithConsole(),
+		WithClock(func() time.Time { return clock }),
+		WithBaseField("service", "api"),
+	)
+
+	jl.Info("server started", Int("port", 8080), Str("mode", "read only"), Str("empty", ""), Any("tags", []any{"a"}))
+	jl.Error("failed", Bool("retry", false))
+
+	want := `2024-05-01T12:00:00.123Z INFO  server started service=api port=8080 mode="read only" empty="" tags=["a"]` + "\n" +
+		`2024-05-01T12:00:00.123Z ERROR failed service=api retry=false` + "\n"
+	if buf.String() != want {
+		t.Fatalf("unexpected console output:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestConsoleLogWriterColors(t *testing.T) {
+	buf := &bytes.Buffer{}
+	theme := Theme{Warn: "<w>", Key: "<k>", Timestamp: "<t>"}
+	jl := NewJSONLoggerWithOptions(WithOutput(buf), WithLogWriter(&ConsoleLogWriter{Color: ColorAlways, Theme: &theme}))
+
+	jl.Warn("slow", Int("ms", 900))
+
+	line := buf.String()
+	for _, want := range []string{"<t>", "<w>WARN " + ansiReset, " slow ", "<k>ms=" + ansiReset + "900"} {
+		if !strings.Contains(line, want) {
+			t.Fatalf("expected %q in %q", want, line)
+		}
+	}
+}
+
+func TestConsoleLogWriterAutoColor(t *testing.T) {
+	tests := []struct {
+		name    string
+		noColor string
+		colored bool
+	}{
+		{name: "buffer output"},
+		{name: "no color override", noColor: "1"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("NO_COLOR", tc.noColor)
+			buf := &bytes.Buffer{}
+			jl := NewJSONLoggerWithOptions(WithOutput(buf), WithConsole())
+
+			jl.Info("plain")
+
+			if strings.Contains(buf.String(), "\x1b[") != tc.colored {
+				t.Fatalf("unexpected coloring: %q", buf.String())
+			}
+		})
+	}
+}
+
+func TestConsoleLogWriterEscapesControlCharacters(t *testing.T) {
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(WithOutput(buf), WithConsole())
+
+	jl.Info("login failed\nINFO  admin logged in\x1b[2J", Str("user\r", "eve\u009b31m"), Str("note", "a\x1bb"))
+
+	line := buf.String()
+	if strings.Count(line, "\n") != 1 || strings.ContainsAny(line, "\x1b\r\u009b") {
+		t.Fatalf("expected control characters to be escaped, got %q", line)
+	}
+	for _, want := range []string{`login failed\nINFO  admin logged in\x1b[2J`, `user\r=eve\u009b31m`, `note="a\u001bb"`} {
+		if !strings.Contains(line, want) {
+			t.Fatalf("expected %s in %q", want, line)
+		}
+	}
+}
+
+func TestNeedsConsoleQuoting(t *testing.T) {
+	tests := map[string]bool{
+		"simple":     false,
+		"café":       false,
+		"":           true,
+		"two words":  true,
+		"k=v":        true,
+		`say "hi"`:   true,
+		"tab\there":  true,
+		"bad\xffutf": true,
+	}
+	for input, want := range tests {
+		if got := needsConsoleQuoting(input); got != want {
+			t.Fatalf("needsConsoleQuoting(%q) = %v, want %v", input, got, want)
+		}
+	}
+}
+
+func TestWithDevFormat(t *testing.T) {
+	buf := &bytes.Buffer{}
+	clock := time.Date(2024, 5, 1, 12, 30, 45, 678000000, time.Local)
+	jl := NewJSONLoggerWithOptions(WithOutput(buf), WithDevFormat(), WithClock(func() time.Time { return clock }))
+
+	jl.Error("payment failed", Int("order", 42), Str("err", "card declined"))
+	jl.Info("ok")
+
+	want := `12:30:45.678 ✖ payment failed order=42 err="card declined"` + "\n" +
+		"12:30:45.678 • ok\n"
+	if buf.String() != want {
+		t.Fatalf("unexpected dev output:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
@@ -0,0 +1,82 @@
+package golog
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// ContextExtractor appends fields derived from ctx (request IDs, trace IDs,
+// tenant names) to fields and returns the extended slice. It is called for
+// every entry logged through one of the ctx-aware methods and must not retain
+// fields.
+type ContextExtractor func(ctx context.Context, fields []Field) []Field
+
+// WithContextExtractor registers an extractor consulted by InfoContext,
+// WarnContext, ErrorContext and DebugContext. Extractors run in registration
+// order and their fields are written before the per-call fields.
+func WithContextExtractor(extractor ContextExtractor) Option {
+	return func(jsonLogger *JSONLogger) {
+		if extractor == nil {
+			return
+		}
+		jsonLogger.contextExtractors = append(jsonLogger.contextExtractors, extractor)
+	}
+}
+
+// InfoContext logs a message at info level, adding fields from the
+// registered context extractors.
+func (jsonLogger *JSONLogger) InfoContext(ctx context.Context, message string, fields ...Field) {
+	jsonLogger.logContext(ctx, InfoLevel, message, fields)
+}
+
+// WarnContext logs a message at warn level, adding fields from the
+// registered context extractors.
+func (jsonLogger *JSONLogger) WarnContext(ctx context.Context, message string, fields ...Field) {
+	jsonLogger.logContext(ctx, WarnLevel, message, fields)
+}
+
+// ErrorContext logs a message at error level, adding fields from the
+// registered context extractors.
+func (jsonLogger *JSONLogger) ErrorContext(ctx context.Context, message string, fields ...Field) {
+	jsonLogger.logContext(ctx, ErrorLevel, message, fields)
+}
+
+// DebugContext logs a message at debug level, adding fields from the
+// registered context extractors.
+func (jsonLogger *JSONLogger) DebugContext(ctx context.Context, message string, fields ...Field) {
+	jsonLogger.logContext(ctx, DebugLevel, message, fields)
+}
+
+// logContext runs the context extractors and collects the PushScope fields
+// into a pooled slice and logs the result. Without either it is equivalent
+// to logFields.
+func (jsonLogger *JSONLogger) logContext(ctx context.Context, logLevel Level, message string, fields []Field) {
+	config := jsonLogger.current()
+	if Level(atomic.LoadInt32((*int32)(&jsonLogger.level))) > logLevel && config.flightRecorder == nil && !forced(fields) {
+		return
+	}
+	if ctx == nil {
+		jsonLogger.logFields(logLevel, message, fields)
+		return
+	}
+	extractors := config.contextExtractors
+	scope := scopeFrom(ctx)
+	if scope == nil && len(extractors) == 0 {
+		jsonLogger.logFields(logLevel, message, fields)
+		return
+	}
+
+	scratchPtr := fieldScratchPool.Get().(*[]Field)
+	scratch := (*scratchPtr)[:0]
+	for _, extractor := range extractors {
+		scratch = extractor(ctx, scratch)
+	}
+	scratch = appendScopeFields(scratch, scope)
+	scratch = append(scratch, fields...)
+
+	jsonLogger.logFields(logLevel, message, scratch)
+
+	clear(scratch)
+	*scratchPtr = scratch[:0]
+	fieldScratchPool.Put(scratchPtr)
+}
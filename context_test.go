@@ -0,0 +1,70 @@
+package golog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+type tenantKey struct{}
+
+func TestContextMethodsApplyExtractors(t *testing.T) {
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(
+		WithOutput(buf),
+		WithLevel(DebugLevel),
+		WithContextExtractor(func(ctx context.Context, fields []Field) []Field {
+			if tenant, ok := ctx.Value(tenantKey{}).(string); ok {
+				fields = append(fields, Str("tenant", tenant))
+			}
+			return fields
+		}),
+		WithContextExtractor(nil),
+	)
+
+	ctx := context.WithValue(context.Background(), tenantKey{}, "acme")
+	jl.DebugContext(ctx, "loaded", Int("rows", 3))
+	jl.InfoContext(context.Background(), "no tenant")
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d", len(lines))
+	}
+	var first, second map[string]any
+	if err := json.Unmarshal(lines[0], &first); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if err := json.Unmarshal(lines[1], &second); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if first["tenant"] != "acme" || first["rows"] != float64(3) || first["level"] != "debug" {
+		t.Fatalf("unexpected first entry: %v", first)
+	}
+	if _, ok := second["tenant"]; ok {
+		t.Fatalf("unexpected tenant on second entry: %v", second)
+	}
+}
+
+func TestContextMethodsRespectLevel(t *testing.T) {
+	buf := &bytes.Buffer{}
+	called := false
+	jl := NewJSONLoggerWithOptions(
+		WithOutput(buf),
+		WithLevel(ErrorLevel),
+		WithContextExtractor(func(ctx context.Context, fields []Field) []Field {
+			called = true
+			return fields
+		}),
+	)
+
+	jl.WarnContext(context.Background(), "dropped")
+	jl.ErrorContext(context.Background(), "kept")
+
+	if !bytes.Contains(buf.Bytes(), []byte(`"kept"`)) || bytes.Contains(buf.Bytes(), []byte("dropped")) {
+		t.Fatalf("unexpected output: %s", buf.String())
+	}
+	if !called {
+		t.Fatalf("expected extractor to run for the error entry")
+	}
+}
@@ -0,0 +1,91 @@
+// Package correlation propagates a request ID through HTTP handlers and into
+// log entries.
+//
+// Middleware reads the incoming X-Request-ID header (or generates a new ID),
+// echoes it on the response and stores it in the request context. Register
+// Extractor on the logger and every entry written with a ctx-aware method
+// carries a request_id field:
+//
+//	jl := golog.NewJSONLoggerWithOptions(golog.WithContextExtractor(correlation.Extractor))
+//	http.Handle("/", correlation.Middleware(handler))
+//
+//	// inside the handler
+//	jl.InfoContext(r.Context(), "order created")
+package correlation
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+
+	"github.com/KostLabs/golog"
+)
+
+const (
+	// Header is the HTTP header carrying the request ID.
+	Header = "X-Request-ID"
+	// FieldKey is the log field the request ID is written under.
+	FieldKey = golog.KeyRequestID
+)
+
+// maxIDLength bounds accepted incoming IDs so a client can't inflate every
+// log line of a request.
+const maxIDLength = 128
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying id.
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the request ID stored in ctx, if any.
+func FromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(contextKey{}).(string)
+	return id, ok && id != ""
+}
+
+// NewID returns a random 128-bit ID encoded as 32 hex characters.
+func NewID() string {
+	var raw [16]byte
+	_, _ = rand.Read(raw[:])
+	return hex.EncodeToString(raw[:])
+}
+
+// Middleware ensures every request has an ID. A valid incoming X-Request-ID
+// is reused so IDs survive service hops; otherwise a new one is generated.
+// The ID is set on the response header and stored in the request context.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(Header)
+		if !validID(id) {
+			id = NewID()
+		}
+		w.Header().Set(Header, id)
+		next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), id)))
+	})
+}
+
+// Extractor is a golog.ContextExtractor that adds the request ID from ctx as
+// a request_id field.
+func Extractor(ctx context.Context, fields []golog.Field) []golog.Field {
+	if id, ok := FromContext(ctx); ok {
+		fields = append(fields, golog.Str(FieldKey, id))
+	}
+	return fields
+}
+
+// validID accepts non-empty IDs of printable ASCII without spaces, which
+// keeps untrusted header values from injecting control characters into logs.
+func validID(id string) bool {
+	if id == "" || len(id) > maxIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
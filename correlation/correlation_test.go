@@ -0,0 +1,66 @@
+package correlation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/KostLabs/golog"
+)
+
+func TestMiddlewarePropagatesIncomingID(t *testing.T) {
+	buf := &bytes.Buffer{}
+	jl := golog.NewJSONLoggerWithOptions(golog.WithOutput(buf), golog.WithContextExtractor(Extractor))
+
+	handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		jl.InfoContext(r.Context(), "handled", golog.Str("path", r.URL.Path))
+	}))
+
+	request := httptest.NewRequest(http.MethodGet, "/orders", nil)
+	request.Header.Set(Header, "abc-123")
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+
+	if got := recorder.Header().Get(Header); got != "abc-123" {
+		t.Fatalf("expected response header to echo the ID, got %q", got)
+	}
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if entry[FieldKey] != "abc-123" || entry["path"] != "/orders" {
+		t.Fatalf("unexpected entry: %v", entry)
+	}
+}
+
+func TestMiddlewareGeneratesIDForMissingOrInvalidHeader(t *testing.T) {
+	for _, incoming := range []string{"", "bad\nid", strings.Repeat("x", maxIDLength+1)} {
+		var seen string
+		handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			seen, _ = FromContext(r.Context())
+		}))
+
+		request := httptest.NewRequest(http.MethodGet, "/", nil)
+		if incoming != "" {
+			request.Header[Header] = []string{incoming}
+		}
+		handler.ServeHTTP(httptest.NewRecorder(), request)
+
+		if len(seen) != 32 || seen == incoming {
+			t.Fatalf("expected a generated ID for %q, got %q", incoming, seen)
+		}
+	}
+}
+
+func TestExtractorWithoutID(t *testing.T) {
+	if fields := Extractor(context.Background(), nil); len(fields) != 0 {
+		t.Fatalf("expected no fields, got %d", len(fields))
+	}
+	if fields := Extractor(NewContext(context.Background(), "id"), nil); len(fields) != 1 || fields[0].Value() != "id" {
+		t.Fatalf("expected request_id field, got %v", fields)
+	}
+}
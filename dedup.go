@@ -0,0 +1,189 @@
+package golog
+
+import (
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Field keys written on summary entries by Deduper.
+const (
+	DedupCountKey     = "count"
+	DedupFirstSeenKey = "first_seen"
+	DedupLastSeenKey  = "last_seen"
+)
+
+// Deduper collapses repeated entries. The first occurrence of an entry is
+// forwarded immediately; identical entries within the following window are
+// only counted. When the window closes, and at least one duplicate was seen,
+// a single summary entry is forwarded carrying the first occurrence's
+// fields plus count (all occurrences), first_seen and last_seen.
+//
+// Entries are identical when level, message and the values of the selected
+// keys match. Values are compared as the wrapped JSONLogger encodes them, or
+// as the reflection encoder does for other loggers, so structs take part
+// field by field. Deduper implements Logger.
+type Deduper struct {
+	next   Logger
+	window time.Duration
+	keys   []string
+
+	mutex  sync.Mutex
+	groups map[string]*dedupGroup
+}
+
+type dedupGroup struct {
+	level     Level
+	message   string
+	fields    []Field
+	count     int
+	firstSeen time.Time
+	lastSeen  time.Time
+	timer     *time.Timer
+}
+
+// Dedup wraps l so entries repeated within window are collapsed. keys selects
+// the fields that take part in the identity; with no keys only level and
+// message are compared.
+func Dedup(l Logger, window time.Duration, keys ...string) *Deduper {
+	return &Deduper{
+		next:   l,
+		window: window,
+		keys:   keys,
+		groups: make(map[string]*dedupGroup),
+	}
+}
+
+func (deduper *Deduper) Info(message string, fields ...Field) {
+	deduper.log(InfoLevel, message, fields)
+}
+
+func (deduper *Deduper) Warn(message string, fields ...Field) {
+	deduper.log(WarnLevel, message, fields)
+}
+
+func (deduper *Deduper) Error(message string, fields ...Field) {
+	deduper.log(ErrorLevel, message, fields)
+}
+
+func (deduper *Deduper) Debug(message string, fields ...Field) {
+	deduper.log(DebugLevel, message, fields)
+}
+
+// Enabled reports whether the wrapped logger writes entries at level.
+func (deduper *Deduper) Enabled(level Level) bool {
+	return enabled(deduper.next, level)
+}
+
+// Flush forwards the summaries of all open windows now, then flushes the
+// wrapped logger.
+func (deduper *Deduper) Flush() error {
+	deduper.mutex.Lock()
+	groups := deduper.groups
+	deduper.groups = make(map[string]*dedupGroup)
+	deduper.mutex.Unlock()
+
+	for _, group := range groups {
+		group.timer.Stop()
+		deduper.emitSummary(group)
+	}
+	return flushLogger(deduper.next)
+}
+
+func (deduper *Deduper) log(level Level, message string, fields []Field) {
+	key := deduper.identity(level, message, fields)
+	now := deduper.now()
+
+	deduper.mutex.Lock()
+	if group, ok := deduper.groups[key]; ok {
+		group.count++
+		group.lastSeen = now
+		deduper.mutex.Unlock()
+		return
+	}
+	group := &dedupGroup{
+		level:     level,
+		message:   message,
+		fields:    append([]Field(nil), fields...),
+		count:     1,
+		firstSeen: now,
+		lastSeen:  now,
+	}
+	deduper.groups[key] = group
+	group.timer = time.AfterFunc(deduper.window, func() { deduper.closeWindow(key, group) })
+	deduper.mutex.Unlock()
+
+	logAt(deduper.next, level, message, fields)
+}
+
+// now reads the wrapped JSONLogger's clock, so first_seen and last_seen
+// agree with its timestamps under WithClock, and time.Now for other loggers.
+func (deduper *Deduper) now() time.Time {
+	if jsonLogger, ok := deduper.next.(*JSONLogger); ok {
+		return jsonLogger.clock()
+	}
+	return time.Now()
+}
+
+// closeWindow ends group's window and forwards its summary.
+func (deduper *Deduper) closeWindow(key string, group *dedupGroup) {
+	deduper.mutex.Lock()
+	if deduper.groups[key] != group {
+		// Already flushed.
+		deduper.mutex.Unlock()
+		return
+	}
+	delete(deduper.groups, key)
+	deduper.mutex.Unlock()
+
+	deduper.emitSummary(group)
+}
+
+func (deduper *Deduper) emitSummary(group *dedupGroup) {
+	if group.count < 2 {
+		return
+	}
+	fields := append(group.fields,
+		Int(DedupCountKey, group.count),
+		Any(DedupFirstSeenKey, group.firstSeen),
+		Any(DedupLastSeenKey, group.lastSeen),
+	)
+	logAt(deduper.next, group.level, group.message, fields)
+}
+
+// identity builds the grouping key from level, message and selected fields.
+func (deduper *Deduper) identity(level Level, message string, fields []Field) string {
+	key := make([]byte, 0, 64)
+	key = append(key, byte(level))
+	key = strconv.AppendQuote(key, message)
+	if len(deduper.keys) == 0 {
+		return string(key)
+	}
+	enc := &reflectEncoder
+	if jsonLogger, ok := deduper.next.(*JSONLogger); ok {
+		enc = &jsonLogger.current().encoder
+	}
+	for _, selected := range deduper.keys {
+		for i := len(fields) - 1; i >= 0; i-- {
+			if fields[i].key == selected {
+				key = enc.appendField(key, fields[i])
+				break
+			}
+		}
+	}
+	return string(key)
+}
+
+// logAt forwards an entry to the level method of l.
+func logAt(l Logger, level Level, message string, fields []Field) {
+	switch level {
+	case DebugLevel:
+		l.Debug(message, fields...)
+	case InfoLevel:
+		l.Info(message, fields...)
+	case WarnLevel:
+		l.Warn(message, fields...)
+	default:
+		l.Error(message, fields...)
+	}
+}
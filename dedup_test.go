@@ -0,0 +1,154 @@
+package golog
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+	"time"
+)
+
+// lockedBuffer is a bytes.Buffer safe for writes from timer goroutines.
+type lockedBuffer struct {
+	mutex sync.Mutex
+	buf   bytes.Buffer
+}
+
+func (buffer *lockedBuffer) Write(p []byte) (int, error) {
+	buffer.mutex.Lock()
+	defer buffer.mutex.Unlock()
+	return buffer.buf.Write(p)
+}
+
+func (buffer *lockedBuffer) Bytes() []byte {
+	buffer.mutex.Lock()
+	defer buffer.mutex.Unlock()
+	return append([]byte(nil), buffer.buf.Bytes()...)
+}
+
+func TestDedupCollapsesRepeatedEntries(t *testing.T) {
+	buf := &bytes.Buffer{}
+	deduper := Dedup(NewJSONLoggerWithOptions(WithOutput(buf)), time.Hour, "host")
+
+	for i := 0; i < 5; i++ {
+		deduper.Error("connection refused", Str("host", "db1"), Int("attempt", i))
+	}
+	deduper.Error("connection refused", Str("host", "db2"))
+	deduper.Warn("connection refused", Str("host", "db1"))
+	deduper.Flush()
+
+	entries := readEntries(t, buf.Bytes())
+	if len(entries) != 4 {
+		t.Fatalf("expected 3 first occurrences and 1 summary, got %d", len(entries))
+	}
+	summary := entries[3]
+	if count, _ := summary.Field(DedupCountKey); count.Value() != int64(5) {
+		t.Fatalf("expected count 5, got %v", count.Value())
+	}
+	if host, _ := summary.Field("host"); host.Value() != "db1" || summary.Level != ErrorLevel {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+	if attempt, _ := summary.Field("attempt"); attempt.Value() != int64(0) {
+		t.Fatalf("expected the first occurrence's fields, got attempt=%v", attempt.Value())
+	}
+	for _, key := range []string{DedupFirstSeenKey, DedupLastSeenKey} {
+		if _, ok := summary.Field(key); !ok {
+			t.Fatalf("expected %s on summary", key)
+		}
+	}
+}
+
+func TestDedupEmitsSummaryWhenWindowCloses(t *testing.T) {
+	buf := &lockedBuffer{}
+	deduper := Dedup(NewJSONLoggerWithOptions(WithOutput(buf)), 20*time.Millisecond)
+
+	deduper.Info("tick")
+	deduper.Info("tick")
+
+	deadline := time.Now().Add(2 * time.Second)
+	for len(readEntries(t, buf.Bytes())) < 2 {
+		if time.Now().After(deadline) {
+			t.Fatalf("summary was not emitted")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	deduper.Info("tick")
+	deduper.Flush()
+	if entries := readEntries(t, buf.Bytes()); len(entries) != 3 {
+		t.Fatalf("expected a new window to forward the entry without a summary, got %d entries", len(entries))
+	}
+}
+
+func TestDedupFlushFlushesWrappedLogger(t *testing.T) {
+	output := &countingWriter{}
+	jl := NewJSONLoggerWithOptions(WithOutput(output), WithAsync(AsyncConfig{MaxLatency: time.Hour}))
+	defer jl.Close()
+
+	var l Logger = Dedup(jl, time.Hour)
+	l.Warn("disk full")
+	l.Warn("disk full")
+	flusher, ok := l.(FlushLogger)
+	if !ok {
+		t.Fatal("expected Deduper to implement FlushLogger")
+	}
+	if err := flusher.Flush(); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+
+	_, data := output.snapshot()
+	entries := readEntries(t, data)
+	if len(entries) != 2 {
+		t.Fatalf("expected the entry and its summary to reach the output, got %d", len(entries))
+	}
+	if count, _ := entries[1].Field(DedupCountKey); count.Value() != int64(2) {
+		t.Fatalf("expected the summary last, got %v", entries[1].FieldMap())
+	}
+}
+
+func TestDedupComparesStructValues(t *testing.T) {
+	type target struct {
+		Host string
+		Port int
+	}
+	buf := &bytes.Buffer{}
+	forwarded := 0
+	counter := Filter(&BLogger{b: &bytes.Buffer{}}, func(Level, string, []Field) bool {
+		forwarded++
+		return true
+	})
+
+	for _, deduper := range []*Deduper{Dedup(NewJSONLoggerWithOptions(WithOutput(buf)), time.Hour, "target"), Dedup(counter, time.Hour, "target")} {
+		deduper.Error("connection refused", Any("target", target{Host: "db1", Port: 5432}))
+		deduper.Error("connection refused", Any("target", target{Host: "db2", Port: 5432}))
+		deduper.Error("connection refused", Any("target", target{Host: "db2", Port: 5432}))
+	}
+	if entries := readEntries(t, buf.Bytes()); len(entries) != 2 {
+		t.Fatalf("expected entries with different structs to be kept apart, got %d", len(entries))
+	}
+	if forwarded != 2 {
+		t.Fatalf("expected other loggers to compare structs too, got %d forwarded", forwarded)
+	}
+}
+
+func TestDedupUsesWrappedLoggerClock(t *testing.T) {
+	buf := &bytes.Buffer{}
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	jl := NewJSONLoggerWithOptions(WithOutput(buf), WithClock(func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}))
+	deduper := Dedup(jl, time.Hour)
+
+	deduper.Warn("disk full")
+	deduper.Warn("disk full")
+	deduper.Flush()
+
+	entries := readEntries(t, buf.Bytes())
+	if len(entries) != 2 {
+		t.Fatalf("expected the entry and its summary, got %d", len(entries))
+	}
+	fields := entries[1].FieldMap()
+	if fields[DedupFirstSeenKey] != "2024-05-01T12:00:01Z" || fields[DedupLastSeenKey] != "2024-05-01T12:00:03Z" {
+		t.Fatalf("expected first_seen and last_seen from the logger's clock, got %v", fields)
+	}
+}
@@ -0,0 +1,85 @@
+package golog
+
+import "reflect"
+
+// deepCopy returns a copy of value that shares no mutable memory reachable
+// through maps, slices, arrays and pointers, so base fields can't change
+// under a concurrent encoder when the caller keeps mutating the original.
+// Structs are copied field by field where the fields are exported;
+// unexported fields, channels and functions are shared. Structures deeper
+// than maxReflectDepth, such as cycles, are shared below that depth.
+func deepCopy(value any) any {
+	switch typedValue := value.(type) {
+	case nil, string, bool, int, int64, float64:
+		return value
+	case []byte:
+		if typedValue == nil {
+			return value
+		}
+		return append([]byte{}, typedValue...)
+	}
+	original := reflect.ValueOf(value)
+	copied := deepCopyValue(original, 0)
+	if !copied.IsValid() || !copied.CanInterface() {
+		return value
+	}
+	return copied.Interface()
+}
+
+func deepCopyValue(value reflect.Value, depth int) reflect.Value {
+	if depth > maxReflectDepth {
+		return value
+	}
+	switch value.Kind() {
+	case reflect.Map:
+		if value.IsNil() {
+			return value
+		}
+		copied := reflect.MakeMapWithSize(value.Type(), value.Len())
+		iter := value.MapRange()
+		for iter.Next() {
+			copied.SetMapIndex(iter.Key(), deepCopyValue(iter.Value(), depth+1))
+		}
+		return copied
+	case reflect.Slice:
+		if value.IsNil() {
+			return value
+		}
+		copied := reflect.MakeSlice(value.Type(), value.Len(), value.Len())
+		for i := 0; i < value.Len(); i++ {
+			copied.Index(i).Set(deepCopyValue(value.Index(i), depth+1))
+		}
+		return copied
+	case reflect.Array:
+		copied := reflect.New(value.Type()).Elem()
+		for i := 0; i < value.Len(); i++ {
+			copied.Index(i).Set(deepCopyValue(value.Index(i), depth+1))
+		}
+		return copied
+	case reflect.Pointer:
+		if value.IsNil() {
+			return value
+		}
+		copied := reflect.New(value.Type().Elem())
+		copied.Elem().Set(deepCopyValue(value.Elem(), depth+1))
+		return copied
+	case reflect.Interface:
+		if value.IsNil() {
+			return value
+		}
+		copied := reflect.New(value.Type()).Elem()
+		copied.Set(deepCopyValue(value.Elem(), depth+1))
+		return copied
+	case reflect.Struct:
+		copied := reflect.New(value.Type()).Elem()
+		copied.Set(value)
+		for i := 0; i < value.NumField(); i++ {
+			if field := copied.Field(i); field.CanSet() {
+				field.Set(deepCopyValue(value.Field(i), depth+1))
+			}
+		}
+		return copied
+	default:
+		return value
+	}
+}
@@ -0,0 +1,57 @@
+package golog
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+)
+
+func TestBaseFieldsAreDeepCopied(t *testing.T) {
+	type owner struct {
+		Name string
+		Tags []string
+	}
+	nested := map[string]any{"region": "eu", "zones": []any{"a", "b"}}
+	tags := []string{"x"}
+	person := &owner{Name: "ops", Tags: tags}
+	fields := map[string]any{"deploy": nested, "owner": person}
+
+	buf := &lockedBuffer{}
+	jl := NewJSONLoggerWithOptions(WithOutput(buf), WithBaseFields(fields), WithBaseField("tags", tags), WithPrettyJSON())
+
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		for range 50 {
+			jl.Info("tick")
+		}
+	})
+	for i := range 50 {
+		nested["region"] = "us"
+		nested["extra"] = i
+		nested["zones"].([]any)[0] = "z"
+		tags[0] = "mutated"
+		person.Name = "dev"
+		fields["late"] = true
+	}
+	wg.Wait()
+
+	out := buf.Bytes()
+	for _, unwanted := range []string{"us", "extra", `"z"`, "mutated", "dev", "late"} {
+		if bytes.Contains(out, []byte(unwanted)) {
+			t.Fatalf("expected base fields to be isolated from %q:\n%s", unwanted, out)
+		}
+	}
+}
+
+func TestDeepCopyKeepsTypes(t *testing.T) {
+	type point struct{ X, y int }
+	original := map[string][]point{"p": {{X: 1, y: 2}}}
+	copied := deepCopy(original).(map[string][]point)
+	original["p"][0].X = 9
+	if copied["p"][0] != (point{X: 1, y: 2}) {
+		t.Fatalf("unexpected copy: %+v", copied)
+	}
+	if deepCopy(nil) != nil || deepCopy("s") != "s" {
+		t.Fatalf("expected scalars to pass through")
+	}
+}
@@ -0,0 +1,151 @@
+package golog
+
+import "strconv"
+
+// Column names with special meaning for DelimitedLogWriter. Any other
+// column name is looked up among the entry's fields.
+const (
+	ColumnTimestamp = "timestamp"
+	ColumnLevel     = "level"
+	ColumnMessage   = "message"
+)
+
+// DelimitedLogWriter writes one delimited row per entry with a fixed column
+// schema, for loading logs into spreadsheets or warehouses that ingest CSV
+// or TSV directly. Missing fields produce empty cells; strings are written
+// as-is and other values as compact JSON. Cells containing the delimiter,
+// quotes or line breaks are quoted following RFC 4180.
+type DelimitedLogWriter struct {
+	// Columns lists the cells of each row, in order.
+	Columns []string
+	// Delimiter separates cells. Defaults to ','.
+	Delimiter byte
+
+	logger *JSONLogger
+}
+
+// NewCSVLogWriter returns a comma separated writer for the given columns.
+func NewCSVLogWriter(columns ...string) *DelimitedLogWriter {
+	return &DelimitedLogWriter{Columns: columns, Delimiter: ','}
+}
+
+// NewTSVLogWriter returns a tab separated writer for the given columns.
+func NewTSVLogWriter(columns ...string) *DelimitedLogWriter {
+	return &DelimitedLogWriter{Columns: columns, Delimiter: '\t'}
+}
+
+func (writer *DelimitedLogWriter) bindLogger(jsonLogger *JSONLogger) {
+	writer.logger = jsonLogger
+}
+
+func (writer *DelimitedLogWriter) delimiter() byte {
+	if writer.Delimiter == 0 {
+		return ','
+	}
+	return writer.Delimiter
+}
+
+// AppendHeader appends the header row (the column names) to dst. Write it
+// once before the first entry when the consumer expects a header.
+func (writer *DelimitedLogWriter) AppendHeader(dst []byte) []byte {
+	delimiter := writer.delimiter()
+	for i, column := range writer.Columns {
+		if i > 0 {
+			dst = append(dst, delimiter)
+		}
+		dst = appendDelimitedCell(dst, column, delimiter)
+	}
+	return append(dst, '\n')
+}
+
+// AppendLog implements LogWriter.
+func (writer *DelimitedLogWriter) AppendLog(dst []byte, entry Entry) []byte {
+	jsonLogger := entry.config(writer.logger)
+	delimiter := writer.delimiter()
+
+	var scratch [64]byte
+	for i, column := range writer.Columns {
+		if i > 0 {
+			dst = append(dst, delimiter)
+		}
+
+		cell := scratch[:0]
+		switch column {
+		case ColumnTimestamp:
+			cell = jsonLogger.appendTimestamp(cell, entry.Time)
+		case ColumnLevel:
+			cell = append(cell, jsonLogger.levelName(entry.Level)...)
+		case ColumnMessage:
+			dst = appendDelimitedCell(dst, entry.Message, delimiter)
+			continue
+		default:
+			cell = writer.appendColumnValue(cell, &jsonLogger.encoder, column, entry.Fields)
+		}
+		dst = appendDelimitedCell(dst, cell, delimiter)
+	}
+	return append(dst, '\n')
+}
+
+// appendColumnValue renders the value for column. The last field with a
+// matching key wins, mirroring JSON output where later keys override earlier
+// ones, so per-call fields take precedence over base fields.
+func (writer *DelimitedLogWriter) appendColumnValue(dst []byte, enc *encoder, column string, fields []Field) []byte {
+	for i := len(fields) - 1; i >= 0; i-- {
+		field := &fields[i]
+		if field.key != column {
+			continue
+		}
+		switch field.kind {
+		case fieldKindStr:
+			return append(dst, enc.stringValue(field.strVal)...)
+		case fieldKindInt:
+			return strconv.AppendInt(dst, field.intVal, 10)
+		case fieldKindAny:
+			return appendDelimitedValue(dst, enc, field.anyVal)
+		default:
+			return enc.appendFieldValue(dst, *field)
+		}
+	}
+	return dst
+}
+
+func appendDelimitedValue(dst []byte, enc *encoder, value any) []byte {
+	switch typedValue := value.(type) {
+	case nil:
+		return dst
+	case string:
+		return append(dst, enc.stringValue(typedValue)...)
+	default:
+		return enc.appendValueOrPlaceholder(dst, value)
+	}
+}
+
+// appendDelimitedCell writes cell, quoting it when it contains the
+// delimiter, a quote, a line break, or leading/trailing spaces.
+func appendDelimitedCell[T string | []byte](dst []byte, cell T, delimiter byte) []byte {
+	needsQuotes := false
+	for i := 0; i < len(cell); i++ {
+		switch cell[i] {
+		case delimiter, '"', '\n', '\r':
+			needsQuotes = true
+		}
+		if needsQuotes {
+			break
+		}
+	}
+	if !needsQuotes && len(cell) > 0 && (cell[0] == ' ' || cell[len(cell)-1] == ' ') {
+		needsQuotes = true
+	}
+	if !needsQuotes {
+		return append(dst, cell...)
+	}
+
+	dst = append(dst, '"')
+	for i := 0; i < len(cell); i++ {
+		if cell[i] == '"' {
+			dst = append(dst, '"')
+		}
+		dst = append(dst, cell[i])
+	}
+	return append(dst, '"')
+}
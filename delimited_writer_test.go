@@ -0,0 +1,75 @@
+package golog
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+)
+
+func TestDelimitedLogWriterCSV(t *testing.T) {
+	writer := NewCSVLogWriter(ColumnLevel, ColumnMessage, "user", "count", "region", "missing", "tags")
+	buf := &bytes.Buffer{}
+	buf.Write(writer.AppendHeader(nil))
+
+	jl := NewJSONLoggerWithOptions(
+		WithOutput(buf),
+		WithLogWriter(writer),
+		WithBaseField("region", "eu, west"),
+	)
+	jl.Info("said \"hi\"", Str("user", "ann"), Int("count", 3), Any("tags", []any{"a", "b"}))
+	jl.Warn("second", Str("user", "old"), Str("user", "new"))
+
+	records, err := csv.NewReader(buf).ReadAll()
+	if err != nil {
+		t.Fatalf("output is not valid CSV: %v\n%s", err, buf.String())
+	}
+	want := [][]string{
+		{"level", "message", "user", "count", "region", "missing", "tags"},
+		{"info", `said "hi"`, "ann", "3", "eu, west", "", `["a","b"]`},
+		{"warn", "second", "new", "", "eu, west", "", ""},
+	}
+	if len(records) != len(want) {
+		t.Fatalf("expected %d rows, got %d: %v", len(want), len(records), records)
+	}
+	for i := range want {
+		if strings.Join(records[i], "|") != strings.Join(want[i], "|") {
+			t.Fatalf("row %d: expected %q, got %q", i, want[i], records[i])
+		}
+	}
+}
+
+func TestDelimitedLogWriterTSVTimestamp(t *testing.T) {
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(
+		WithOutput(buf),
+		WithLogWriter(NewTSVLogWriter(ColumnTimestamp, ColumnMessage)),
+		WithCustomTimeFormat("2006-01-02"),
+	)
+
+	jl.Info("line\nbreak")
+
+	cells := strings.SplitN(strings.TrimSuffix(buf.String(), "\n"), "\t", 2)
+	if len(cells) != 2 || len(cells[0]) != len("2006-01-02") {
+		t.Fatalf("unexpected TSV row: %q", buf.String())
+	}
+	if cells[1] != "\"line\nbreak\"" {
+		t.Fatalf("expected quoted multi-line cell, got %q", cells[1])
+	}
+}
+
+func TestDelimitedLogWriterLevelStrings(t *testing.T) {
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(
+		WithOutput(buf),
+		WithLogWriter(NewCSVLogWriter(ColumnLevel, ColumnMessage)),
+		WithLevelStrings(map[Level]string{WarnLevel: "WARNING", ErrorLevel: "50"}),
+	)
+	jl.Info("default")
+	jl.Warn("renamed")
+	jl.Error("numeric")
+
+	if got, want := buf.String(), "info,default\nWARNING,renamed\n50,numeric\n"; got != want {
+		t.Fatalf("expected the configured level names\n%s\ngot\n%s", want, got)
+	}
+}
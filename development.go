@@ -0,0 +1,83 @@
+package golog
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// WithDevelopmentMode makes the logger panic with a *MisuseError on logging
+// bugs it otherwise tolerates: field values the encoder can't handle,
+// duplicate keys within a call (including the core timestamp, level and
+// message keys), and invalid UTF-8 in the message, keys or string values.
+// Enable it in tests and local runs; production loggers keep writing
+// "<unsupported>" and carrying on. Per-call fields overriding base fields
+// are not reported.
+func WithDevelopmentMode() Option {
+	return func(jsonLogger *JSONLogger) {
+		jsonLogger.development = true
+	}
+}
+
+// MisuseError describes a logging call rejected by WithDevelopmentMode. It
+// is the value passed to panic.
+type MisuseError struct {
+	// Message is the message of the offending entry.
+	Message string
+	// Key is the offending field key, or empty when the message itself is
+	// at fault.
+	Key string
+	// Problem describes what is wrong.
+	Problem string
+	// Err is ErrUnsupportedType for values the encoder can't handle, and
+	// nil otherwise.
+	Err error
+}
+
+func (err *MisuseError) Error() string {
+	if err.Key == "" {
+		return fmt.Sprintf("golog: entry %q: %s", err.Message, err.Problem)
+	}
+	return fmt.Sprintf("golog: entry %q: field %q: %s", err.Message, err.Key, err.Problem)
+}
+
+func (err *MisuseError) Unwrap() error {
+	return err.Err
+}
+
+// checkEntry panics with a *MisuseError when the entry is malformed.
+func (jsonLogger *JSONLogger) checkEntry(message string, fields []Field) {
+	if !utf8.ValidString(message) {
+		panic(&MisuseError{Message: message, Problem: "message is not valid UTF-8"})
+	}
+
+	var scratch [64]byte
+	for i := range fields {
+		field := &fields[i]
+		switch {
+		case !utf8.ValidString(field.key):
+			panic(&MisuseError{Message: message, Key: field.key, Problem: "key is not valid UTF-8"})
+		case field.kind >= fieldKindEntryTime:
+			continue
+		case field.key == ColumnTimestamp || field.key == ColumnLevel || field.key == ColumnMessage:
+			panic(&MisuseError{Message: message, Key: field.key, Problem: "key collides with a core entry key"})
+		case field.kind == fieldKindStr && !utf8.ValidString(field.strVal):
+			panic(&MisuseError{Message: message, Key: field.key, Problem: "value is not valid UTF-8"})
+		}
+		for j := 0; j < i; j++ {
+			if fields[j].key == field.key {
+				panic(&MisuseError{Message: message, Key: field.key, Problem: "duplicate key"})
+			}
+		}
+
+		switch field.kind {
+		case fieldKindFloat:
+			if _, ok := jsonLogger.encoder.appendFloat(scratch[:0], field.fltVal, 64); !ok {
+				panic(&MisuseError{Message: message, Key: field.key, Problem: fmt.Sprintf("non-finite float %v", field.fltVal), Err: ErrUnsupportedType})
+			}
+		case fieldKindAny:
+			if _, ok := jsonLogger.encoder.appendValue(scratch[:0], field.anyVal); !ok {
+				panic(&MisuseError{Message: message, Key: field.key, Problem: fmt.Sprintf("unsupported value of type %T", field.anyVal), Err: ErrUnsupportedType})
+			}
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package golog
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestDevelopmentModePanicsOnMisuse(t *testing.T) {
+	tests := []struct {
+		name        string
+		message     string
+		fields      []Field
+		key         string
+		problem     string
+		unsupported bool
+	}{
+		{name: "unsupported value", message: "m", fields: []Field{Any("ch", make(chan int))}, key: "ch", problem: "chan int", unsupported: true},
+		{name: "duplicate key", message: "m", fields: []Field{Str("id", "a"), Int("id", 1)}, key: "id", problem: "duplicate"},
+		{name: "core key", message: "m", fields: []Field{Str("level", "x")}, key: "level", problem: "core entry key"},
+		{name: "invalid value", message: "m", fields: []Field{Str("name", "a\xffb")}, key: "name", problem: "value is not valid UTF-8"},
+		{name: "invalid key", message: "m", fields: []Field{Str("k\xff", "v")}, key: "k\xff", problem: "key is not valid UTF-8"},
+		{name: "invalid message", message: "bad\xff", problem: "message is not valid UTF-8"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			jl := NewJSONLoggerWithOptions(WithOutput(buf), WithDevelopmentMode())
+
+			defer func() {
+				misuse, ok := recover().(*MisuseError)
+				if !ok {
+					t.Fatalf("expected a *MisuseError panic")
+				}
+				if misuse.Key != tc.key || !strings.Contains(misuse.Error(), tc.problem) {
+					t.Fatalf("unexpected panic: %v", misuse)
+				}
+				if errors.Is(misuse, ErrUnsupportedType) != tc.unsupported {
+					t.Fatalf("unexpected error chain for %v", misuse)
+				}
+				if buf.Len() != 0 {
+					t.Fatalf("expected nothing written, got %q", buf.String())
+				}
+			}()
+			jl.Info(tc.message, tc.fields...)
+		})
+	}
+}
+
+func TestDevelopmentModeFloatPolicy(t *testing.T) {
+	jl := NewJSONLoggerWithOptions(WithOutput(&bytes.Buffer{}), WithDevelopmentMode())
+	jl.Info("nan is null by default", Float64("ratio", math.NaN()))
+
+	jl = NewJSONLoggerWithOptions(WithOutput(&bytes.Buffer{}), WithDevelopmentMode(), WithFloatPolicy(FloatAsError))
+	defer func() {
+		if misuse, ok := recover().(*MisuseError); !ok || misuse.Key != "ratio" {
+			t.Fatalf("expected a panic for the non-finite float, got %v", misuse)
+		}
+	}()
+	jl.Info("nan is an error", Float64("ratio", math.NaN()))
+}
+
+func TestDevelopmentModeAllowsValidEntries(t *testing.T) {
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(WithOutput(buf), WithDevelopmentMode(), WithBaseField("service", "api"))
+
+	jl.Info("ok", Str("service", "override"), Any("point", struct{ X int }{1}), Str("name", "café"))
+	jl.Debug("filtered", Any("ch", make(chan int)))
+
+	if strings.Count(buf.String(), "\n") != 1 {
+		t.Fatalf("expected one entry, got %q", buf.String())
+	}
+}
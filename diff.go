@@ -0,0 +1,151 @@
+package golog
+
+import (
+	"cmp"
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strconv"
+)
+
+// DiffKey is the key of the field built by Diff.
+const DiffKey = "diff"
+
+// diffChange is one changed path in a Diff field.
+type diffChange struct {
+	Path string `json:"path"`
+	Old  any    `json:"old"`
+	New  any    `json:"new"`
+}
+
+var (
+	jsonMarshalerType = reflect.TypeFor[json.Marshaler]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// Diff returns a diff field listing what changed between before and after,
+// for audit-style entries that show exactly what an update did:
+//
+//	jl.Info("deployment updated", golog.Diff(previous, current))
+//	// "diff":[{"path":"spec.replicas","old":2,"new":3},{"path":"labels.env","old":null,"new":"prod"}]
+//
+// Structs are compared field by field under the names the encoder writes
+// them with, honoring json tags; maps key by key, in sorted key order; and
+// slices and arrays element by element, as "items[2]". Missing members and
+// elements are logged as null. Anything else, including times and types
+// with their own JSON or text encoding, is compared as a whole with
+// reflect.DeepEqual, as are values nested deeper than the encoder follows.
+// A path is empty when before and after differ at the top. Equal values log
+// an empty array. The comparison runs when Diff is called, so later changes
+// to before and after don't affect the field.
+func Diff(before, after any) Field {
+	changes := []diffChange{}
+	changes = appendDiff(changes, "", reflect.ValueOf(before), reflect.ValueOf(after), 0)
+	return Any(DiffKey, changes)
+}
+
+// appendDiff appends the changes between before and after under path.
+func appendDiff(changes []diffChange, path string, before, after reflect.Value, depth int) []diffChange {
+	before, after = diffIndirect(before), diffIndirect(after)
+	if depth <= maxReflectDepth && before.IsValid() && after.IsValid() && before.Type() == after.Type() && !isDiffLeaf(before.Type()) {
+		switch before.Kind() {
+		case reflect.Struct:
+			for _, fieldPlan := range planFor(before.Type()).fields {
+				beforeField, beforeOK := fieldByIndex(before, fieldPlan.index)
+				afterField, afterOK := fieldByIndex(after, fieldPlan.index)
+				if !beforeOK {
+					beforeField = reflect.Value{}
+				}
+				if !afterOK {
+					afterField = reflect.Value{}
+				}
+				changes = appendDiff(changes, diffPath(path, fieldPlan.name), beforeField, afterField, depth+1)
+			}
+			return changes
+		case reflect.Map:
+			type mapKey struct {
+				name  string
+				value reflect.Value
+			}
+			keys := make([]mapKey, 0, before.Len()+after.Len())
+			for _, key := range before.MapKeys() {
+				keys = append(keys, mapKey{name: keyString(key.Interface()), value: key})
+			}
+			for _, key := range after.MapKeys() {
+				if !before.MapIndex(key).IsValid() {
+					keys = append(keys, mapKey{name: keyString(key.Interface()), value: key})
+				}
+			}
+			slices.SortFunc(keys, func(a, b mapKey) int {
+				return cmp.Compare(a.name, b.name)
+			})
+			for _, key := range keys {
+				changes = appendDiff(changes, diffPath(path, key.name), before.MapIndex(key.value), after.MapIndex(key.value), depth+1)
+			}
+			return changes
+		case reflect.Slice, reflect.Array:
+			for i := range max(before.Len(), after.Len()) {
+				var beforeElem, afterElem reflect.Value
+				if i < before.Len() {
+					beforeElem = before.Index(i)
+				}
+				if i < after.Len() {
+					afterElem = after.Index(i)
+				}
+				changes = appendDiff(changes, path+"["+strconv.Itoa(i)+"]", beforeElem, afterElem, depth+1)
+			}
+			return changes
+		}
+	}
+
+	beforeValue, afterValue := diffInterface(before), diffInterface(after)
+	if reflect.DeepEqual(beforeValue, afterValue) {
+		return changes
+	}
+	return append(changes, diffChange{Path: path, Old: beforeValue, New: afterValue})
+}
+
+// diffIndirect follows pointers and interfaces, returning the zero Value for
+// nil ones so they compare as missing.
+func diffIndirect(value reflect.Value) reflect.Value {
+	for hops := 0; hops <= maxReflectDepth; hops++ {
+		if !value.IsValid() || (value.Kind() != reflect.Pointer && value.Kind() != reflect.Interface) {
+			return value
+		}
+		if value.IsNil() {
+			return reflect.Value{}
+		}
+		if isDiffLeaf(value.Type()) {
+			return value
+		}
+		value = value.Elem()
+	}
+	return value
+}
+
+// isDiffLeaf reports whether values of valueType are compared as a whole:
+// the types the encoder writes specially and those encoding themselves.
+func isDiffLeaf(valueType reflect.Type) bool {
+	switch valueType {
+	case timeType, durationType, rawMessageType, byteSliceType:
+		return true
+	}
+	return isMarshalerType(valueType)
+}
+
+// diffInterface returns the value to log for one side of a change: nil for
+// a missing or unexported value.
+func diffInterface(value reflect.Value) any {
+	if !value.IsValid() || !value.CanInterface() {
+		return nil
+	}
+	return value.Interface()
+}
+
+func diffPath(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
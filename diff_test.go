@@ -0,0 +1,86 @@
+package golog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDiff(t *testing.T) {
+	type spec struct {
+		Replicas int       `json:"replicas"`
+		Image    string    `json:"image"`
+		Ports    []int     `json:"ports,omitempty"`
+		Secret   string    `json:"-"`
+		Owner    *string   `json:"owner"`
+		Updated  time.Time `json:"updated"`
+	}
+	type deployment struct {
+		Name   string            `json:"name"`
+		Spec   *spec             `json:"spec"`
+		Labels map[string]string `json:"labels"`
+	}
+	owner := "team-a"
+	updated := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	before := deployment{
+		Name:   "api",
+		Spec:   &spec{Replicas: 2, Image: "api:1", Ports: []int{80, 443}, Secret: "a", Updated: updated},
+		Labels: map[string]string{"tier": "web", "old": "x"},
+	}
+	after := deployment{
+		Name:   "api",
+		Spec:   &spec{Replicas: 3, Image: "api:1", Ports: []int{80}, Secret: "b", Owner: &owner, Updated: updated.Add(time.Second)},
+		Labels: map[string]string{"tier": "web", "env": "prod"},
+	}
+
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(WithOutput(buf))
+	jl.Info("deployment updated", Diff(before, after))
+
+	want := `"diff":[` +
+		`{"path":"spec.replicas","old":2,"new":3},` +
+		`{"path":"spec.ports[1]","old":443,"new":null},` +
+		`{"path":"spec.owner","old":null,"new":"team-a"},` +
+		`{"path":"spec.updated","old":"2024-01-02T03:04:05Z","new":"2024-01-02T03:04:06Z"},` +
+		`{"path":"labels.env","old":null,"new":"prod"},` +
+		`{"path":"labels.old","old":"x","new":null}]`
+	if !strings.Contains(buf.String(), want) {
+		t.Fatalf("expected %s in %s", want, buf.String())
+	}
+}
+
+func TestDiffWholeValues(t *testing.T) {
+	tests := []struct {
+		name          string
+		before, after any
+		want          string
+	}{
+		{name: "equal", before: map[string]int{"a": 1}, after: map[string]int{"a": 1}, want: `[]`},
+		{name: "scalars", before: 1, after: 2, want: `[{"path":"","old":1,"new":2}]`},
+		{name: "types", before: 1, after: "1", want: `[{"path":"","old":1,"new":"1"}]`},
+		{name: "nil", before: nil, after: []string{"a"}, want: `[{"path":"","old":null,"new":["a"]}]`},
+		{name: "nested", before: []any{map[string]any{"a": 1}}, after: []any{map[string]any{"a": 2}}, want: `[{"path":"[0].a","old":1,"new":2}]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			enc := encoder{reflectFallback: true}
+			got, ok := enc.appendValue(nil, Diff(tt.before, tt.after).Value())
+			if !ok || string(got) != tt.want {
+				t.Fatalf("got %s (ok=%v), want %s", got, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffCopiesValues(t *testing.T) {
+	before := map[string]int{"n": 1}
+	after := map[string]int{"n": 2}
+	field := Diff(before, after)
+	after["n"] = 1
+	enc := encoder{reflectFallback: true}
+	got, _ := enc.appendValue(nil, field.Value())
+	if string(got) != `[{"path":"n","old":1,"new":2}]` {
+		t.Fatalf("expected the diff to be taken when Diff is called, got %s", got)
+	}
+}
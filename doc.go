@@ -28,7 +28,20 @@
 //	}
 //
 // Use `SetLogger(l Logger)` to install a logger globally that adapter code can
-// depend on.
+// depend on. Request-scoped loggers travel through a context.Context with
+// NewContext and FromContext, which falls back to the global logger.
+//
+// Custom loggers can also implement the optional LeveledLogger
+// (Enabled(Level) bool) and FlushLogger (Flush() error) interfaces. Wrappers
+// such as Filter, AccessLog and NewLoggingRoundTripper type-assert for them,
+// skipping work for disabled levels and forwarding Flush.
+//
+// Dependencies with their own logging interfaces can write into the same
+// stream through NewKeyValueLogger (retryablehttp.LeveledLogger),
+// NewKitLogger (go-kit's log.Logger) and NewHCLogWriter (the Output of a
+// JSON-format hclog logger), without golog importing those libraries.
+// NewZerologWriter and NewZapWriter re-emit the JSON output of zerolog and
+// zap loggers, so mixed codebases share one pipeline during a migration.
 //
 // JSONLogger (usage)
 // The JSON logger writes one JSON object per log call. Each object always
@@ -58,11 +71,59 @@
 //	)
 //
 // Convenience option helpers
-//   - WithLevel(Level)           : set minimum log level (Debug/Info/Warn/Error)
+//   - WithLevel(Level)           : set minimum log level (Debug/Info/Warn/Error/Off)
 //   - WithOutput(io.Writer)      : set writer (stdout, file, buffer)
 //   - WithWriteLock(bool)         : enable/disable output write lock
 //   - WithBaseFields(map[string]any) : add a set of base fields
 //   - WithBaseField(key, value)  : add a single base field
+//   - WithFieldPrefix(prefix)    : namespace field keys, e.g. "app.user_id"
+//   - WithEnvFields(...EnvField) : add base fields from environment variables
+//   - WithEnvironment()          : Kubernetes downward-API and CI/cloud env fields
+//   - WithBuildInfo()            : version, vcs.revision, vcs.time and vcs.modified base fields
+//   - WithLambdaDefaults()       : AWS Lambda tuning: sync writes, request ID, cold start
+//   - WithLevelStrings(map[Level]string) : customize the "level" values
+//   - WithCustomTimeFormat(layout) : timestamp layout; RFC3339Milli and co. are fixed-width and fast
+//   - WithTimestampPrecision(d)  : truncate timestamps, e.g. to milliseconds
+//   - WithTimeLocation(*time.Location) : write timestamps in a zone other than UTC
+//   - WithSequenceNumbers()      : per-logger "seq" counter to spot dropped or reordered lines
+//   - WithGoroutineID()          : debug only: "goroutine" ID parsed from runtime.Stack
+//   - WithClock(func() time.Time) : control entry timestamps in tests
+//   - WithDeterministic()        : fixed timestamps, sorted fields and sequence numbers for golden files; see Golden
+//   - WithEventTimeField(key)    : write WithEntryTime times beside timestamp, not over it
+//   - WithContextExtractor(ContextExtractor) : add fields from ctx in InfoContext & co.
+//   - WithEventSchemaVersion(string) : schema version written with every Event
+//   - WithDurationFormat(DurationFormat) : nanoseconds or "1.5s" strings
+//   - WithFloatPolicy(FloatPolicy) : null, string or unsupported for NaN/±Inf
+//   - WithBigNumberFormat(BigNumberFormat) : big.Int, big.Float and json.Number as numbers or strings
+//   - WithStrictJSON()           : validate UTF-8 and guarantee RFC 8259 output
+//   - WithOmitEmpty()            : drop nil, empty string and zero number fields
+//   - WithMaxFieldLength(n)      : cap string values, marking cuts with a hash and length
+//   - WithMaxDepth(n)            : write objects and arrays nested n levels deep as "<truncated>"
+//   - WithMaxFields(n)           : keep n members per object, array and logging call
+//   - WithScrubbers(...Scrubber) : mask emails, card numbers and bearer tokens in string values
+//   - WithSchema(Schema)         : flag, fix or reject entries breaking a field contract
+//   - WithProtoMarshaler(fn)     : encode protobuf messages with protojson instead of reflection
+//   - WithDevelopmentMode()      : panic on unsupported values, duplicate keys or bad UTF-8
+//   - WithMiddleware(fn)         : drop, rewrite or duplicate entries before they are encoded
+//   - WithErrorHook(fn)          : forward error entries to Sentry, Bugsnag or PagerDuty
+//   - WithTrigger(level, n, d, fn): alert when more than n entries at level are written within d
+//   - WithSink(Sink)             : also deliver entries to a Sink; see OpenSink and RegisterSink
+//   - WithLabelKeys(keys...)     : send low-cardinality fields as Loki stream labels, not in the line
+//   - WithHealthcheckInterval(d) : periodic "logger health" entries with queue and error counts
+//   - WithLevelStats(config)     : per-level counts, error rate and burn rate in Stats and summaries
+//   - WithLogWriter(LogWriter)   : replace the entry formatter
+//   - WithPrettyJSON()           : indented multi-line JSON for local development
+//   - WithConsole()              : human-readable colored lines with TTY and NO_COLOR detection
+//   - WithDevFormat()            : compact "15:04:05.000 ✖ message key=val" lines
+//   - WithEMF(namespace, dims...) : CloudWatch Embedded Metric Format for Count/Gauge
+//   - WithBinaryFormat(BinaryFormat) : length-prefixed CBOR or MessagePack records
+//   - WithCompressedOutput(CompressionCodec) : gzip (or pluggable) output with flush boundaries
+//   - WithAsync(AsyncConfig)     : queue entries and write them in batches; Delivery picks block, drop or spill
+//   - WithWriteWatchdog(WatchdogConfig) : warn about, and optionally drop behind, a hung output
+//   - WithShutdownTimeout(d)     : flush budget for Run once its context is done
+//   - WithLockFreeOutput()       : experimental lock-free queue in front of the output
+//   - WithFlightRecorder(size)   : keep the last entries below the level for DumpRecent
+//   - WithDumpOnError()          : write the flight recorder out before each error
 //
 // Logging calls
 // Pass zero or more typed fields. Each field is merged into the top-level JSON
@@ -74,6 +135,58 @@
 //
 //	jl.Info("user login", Int("user_id", 42), Str("ip", "127.0.0.1"), Bool("success", true))
 //
+// Pre-serialized JSON is embedded verbatim with RawJSON, or streamed from an
+// io.WriterTo with RawJSONFrom, skipping the encoder entirely:
+//
+//	jl.Info("webhook received", RawJSON("payload", body))
+//
+// Fields shared by a whole call tree are pushed once onto the context and
+// picked up by InfoContext and co.:
+//
+//	ctx, pop := PushScope(ctx, Str("job_id", id))
+//	defer pop()
+//
+// HTTPRequest and HTTPResponse log an allowlisted, credential-free view of
+// net/http values, never their bodies:
+//
+//	jl.Warn("upstream failed", HTTPRequest(req), HTTPResponse(resp))
+//
+// Diff lists the paths that changed between two versions of a value, for
+// audit-style update entries:
+//
+//	jl.Info("deployment updated", Diff(previous, current))
+//
+// Err logs an error's message, or the messages of an errors.Join style
+// aggregate as a list:
+//
+//	jl.Error("batch failed", Err(err))
+//
+// Common attributes have well-known keys (KeyUserID, KeyRequestID,
+// KeyDurationMS, ...) and a builder that uses them, so services agree on
+// field names:
+//
+//	jl.Info("checkout completed", Fields{}.User(id).Duration(elapsed)...)
+//
+// Subprocess output is logged line by line, with the command, pid, stream
+// and exit status, by RunCommand; jl.InfoWriter() and jl.ErrorWriter() fit
+// any other io.Writer consumer.
+//
+// Must-log lines bypass level filtering with the ForceLog field:
+//
+//	jl.Info("refund issued", ForceLog(), Str("order_id", id))
+//
+// Backfills and replays set the event time instead of the write time with
+// the WithEntryTime field:
+//
+//	jl.Info("order placed", WithEntryTime(order.CreatedAt))
+//
+// Daemons get the usual runtime controls from jl.HandleSignals(): SIGUSR1
+// and SIGUSR2 make logging more or less verbose and SIGHUP reopens an
+// output opened with OpenReopenableFile after log rotation.
+//
+// A running logger is reconfigured safely with jl.Reconfigure(options...),
+// never by mutating it directly.
+//
 // Concurrency and performance notes
 //   - Writes are protected by an internal mutex so each encoded JSON line is
 //     written atomically. This prevents interleaving when multiple goroutines
@@ -88,8 +201,15 @@
 //
 // Unsupported values
 // If a field value can't be encoded by the fast encoder (for example a channel),
-// golog writes "<unsupported>" for that field value and continues encoding the
-// rest of the log entry.
+// golog writes "<unsupported>" in its place and continues encoding the rest of
+// the log entry. Inside maps, slices and structs only the offending leaf is
+// replaced, and the entry gains an "encode_errors" field listing the paths,
+// e.g. ["payload.items[2]"].
+//
+// Common "almost primitive" types are written in their string form rather
+// than by structure: net.IP and the net/netip addresses, prefixes and
+// address-port pairs, url.URL with its password masked, and time.Month and
+// time.Weekday names.
 //
 // Testing
 // The package includes small tests that demonstrate expected behaviour
@@ -0,0 +1,89 @@
+package golog
+
+import "strconv"
+
+// EMFLogWriter writes metric entries (those written by Count and Gauge) in
+// the AWS CloudWatch Embedded Metric Format, so Lambda and ECS log groups
+// produce CloudWatch metrics without calls to the metrics API. Each metric
+// record gains an "_aws" metadata block and a top-level key named after the
+// metric holding its value; other entries are written as plain JSON.
+//
+//	jl := NewJSONLoggerWithOptions(WithEMF("checkout", "service"))
+//	jl.Count("orders", 1) // "_aws":{...,"Metrics":[{"Name":"orders","Unit":"Count"}]},"orders":1
+type EMFLogWriter struct {
+	// Namespace is the CloudWatch namespace of every metric.
+	Namespace string
+	// Dimensions lists the field keys used as metric dimensions. Keys
+	// missing from an entry are left out of its dimension set. CloudWatch
+	// only accepts string dimension values.
+	Dimensions []string
+
+	logger *JSONLogger
+}
+
+// WithEMF formats metric entries in the CloudWatch Embedded Metric Format
+// under namespace, using the given field keys as dimensions.
+func WithEMF(namespace string, dimensions ...string) Option {
+	return WithLogWriter(&EMFLogWriter{Namespace: namespace, Dimensions: dimensions})
+}
+
+func (writer *EMFLogWriter) bindLogger(jsonLogger *JSONLogger) {
+	writer.logger = jsonLogger
+}
+
+// emfUnit maps a MetricTypeKey value to a CloudWatch unit.
+func emfUnit(metricType string) string {
+	if metricType == MetricTypeCounter {
+		return "Count"
+	}
+	return "None"
+}
+
+// AppendLog implements LogWriter.
+func (writer *EMFLogWriter) AppendLog(dst []byte, entry Entry) []byte {
+	jsonLogger := entry.config(writer.logger)
+	jsonWriter := jsonLogWriter{logger: jsonLogger}
+
+	typeField, isMetric := entry.Field(MetricTypeKey)
+	nameField, hasName := entry.Field(MetricNameKey)
+	valueField, hasValue := entry.Field(MetricValueKey)
+	name, nameIsString := nameField.Value().(string)
+	metricType, typeIsString := typeField.Value().(string)
+	if !isMetric || !hasName || !hasValue || !nameIsString || !typeIsString {
+		return jsonWriter.AppendLog(dst, entry)
+	}
+
+	enc := &jsonLogger.encoder
+	dst = append(dst, `{"_aws":{"Timestamp":`...)
+	dst = strconv.AppendInt(dst, entry.Time.UnixMilli(), 10)
+	dst = append(dst, `,"CloudWatchMetrics":[{"Namespace":`...)
+	dst = enc.appendString(dst, writer.Namespace)
+	dst = append(dst, `,"Dimensions":[[`...)
+	first := true
+	for _, dimension := range writer.Dimensions {
+		if _, ok := entry.Field(dimension); !ok {
+			continue
+		}
+		if !first {
+			dst = append(dst, ',')
+		}
+		first = false
+		dst = enc.appendKey(dst, dimension)
+	}
+	dst = append(dst, `]],"Metrics":[{"Name":`...)
+	dst = enc.appendString(dst, name)
+	dst = append(dst, `,"Unit":`...)
+	dst = enc.appendString(dst, emfUnit(metricType))
+	dst = append(dst, `}]}]},`...)
+
+	// Splice in the regular JSON record without its opening brace, then add
+	// the value under the metric's own name before the closing brace.
+	mark := len(dst)
+	dst = jsonWriter.AppendLog(dst, entry)
+	dst = append(dst[:mark], dst[mark+1:len(dst)-2]...)
+	dst = append(dst, ',')
+	dst = enc.appendString(dst, name)
+	dst = append(dst, ':')
+	dst = enc.appendFieldValue(dst, valueField)
+	return append(dst, '}', '\n')
+}
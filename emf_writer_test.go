@@ -0,0 +1,54 @@
+package golog
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestEMFLogWriterMetricRecords(t *testing.T) {
+	buf := &bytes.Buffer{}
+	clock := time.UnixMilli(1714564800123)
+	jl := NewJSONLoggerWithOptions(
+		WithOutput(buf),
+		WithEMF("checkout", "service", "region"),
+		WithClock(func() time.Time { return clock }),
+		WithBaseField("service", "api"),
+	)
+
+	jl.Count("orders", 2, Str("payment", "card"))
+	jl.Info("not a metric")
+
+	decoder := json.NewDecoder(buf)
+	var metric map[string]any
+	if err := decoder.Decode(&metric); err != nil {
+		t.Fatalf("metric record is not valid JSON: %v", err)
+	}
+	aws := metric["_aws"].(map[string]any)
+	if aws["Timestamp"] != float64(1714564800123) {
+		t.Fatalf("unexpected timestamp: %v", aws["Timestamp"])
+	}
+	directive := aws["CloudWatchMetrics"].([]any)[0].(map[string]any)
+	if directive["Namespace"] != "checkout" {
+		t.Fatalf("unexpected namespace: %v", directive["Namespace"])
+	}
+	if dimensions := directive["Dimensions"].([]any)[0].([]any); len(dimensions) != 1 || dimensions[0] != "service" {
+		t.Fatalf("expected only present dimensions, got %v", dimensions)
+	}
+	definition := directive["Metrics"].([]any)[0].(map[string]any)
+	if definition["Name"] != "orders" || definition["Unit"] != "Count" {
+		t.Fatalf("unexpected metric definition: %v", definition)
+	}
+	if metric["orders"] != float64(2) || metric["service"] != "api" || metric["payment"] != "card" {
+		t.Fatalf("unexpected metric record: %v", metric)
+	}
+
+	var plain map[string]any
+	if err := decoder.Decode(&plain); err != nil {
+		t.Fatalf("plain record is not valid JSON: %v", err)
+	}
+	if _, ok := plain["_aws"]; ok || plain["message"] != "not a metric" {
+		t.Fatalf("expected a plain JSON record, got %v", plain)
+	}
+}
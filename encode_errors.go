@@ -0,0 +1,190 @@
+package golog
+
+import (
+	"reflect"
+	"strconv"
+)
+
+// EncodeErrorsKey lists the paths of values that were written as
+// "<unsupported>", e.g. ["payload.items[2].callback"].
+const EncodeErrorsKey = "encode_errors"
+
+var (
+	anyMapType   = reflect.TypeFor[map[string]any]()
+	anySliceType = reflect.TypeFor[[]any]()
+)
+
+// appendValueLenient encodes value, replacing only the leaves the encoder
+// can't handle with "<unsupported>", and returns their paths below path.
+// Values that encode cleanly take the normal path; the walk runs only after
+// a failure.
+func (enc *encoder) appendValueLenient(dst []byte, value any, path string) ([]byte, []string) {
+	mark := len(dst)
+	encoded, ok := enc.appendValue(dst, value)
+	if ok {
+		return encoded, nil
+	}
+	var paths []string
+	dst = enc.appendLenient(encoded[:mark], reflect.ValueOf(value), path, 0, &paths)
+	return dst, paths
+}
+
+// appendLenient is the slow path of appendValueLenient. Containers are
+// written element by element so a failure stays local to its leaf. Without
+// reflectFallback only map[string]any and []any are walked, matching what
+// the encoder supports. depth counts containers as in appendReflect, so
+// WithMaxDepth and WithMaxFields apply to the walk too.
+func (enc *encoder) appendLenient(dst []byte, value reflect.Value, path string, depth int, paths *[]string) []byte {
+	if value.IsValid() && value.CanInterface() {
+		mark := len(dst)
+		encoded, ok := enc.appendValueAt(dst, value.Interface(), depth)
+		if ok {
+			return encoded
+		}
+		dst = encoded[:mark]
+	}
+	if depth > maxReflectDepth || !value.IsValid() || enc.opaqueType(value.Type()) ||
+		(!enc.reflectFallback && value.Type() != anyMapType && value.Type() != anySliceType) {
+		return enc.appendUnsupported(dst, path, paths)
+	}
+
+	switch value.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		for hops := 0; value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface; hops++ {
+			if hops > maxReflectDepth {
+				return enc.appendUnsupported(dst, path, paths)
+			}
+			if value.IsNil() {
+				return append(dst, "null"...)
+			}
+			value = value.Elem()
+		}
+		return enc.appendLenient(dst, value, path, depth, paths)
+	case reflect.Map:
+		if !isMapKeyType(value.Type().Key()) {
+			return enc.appendUnsupported(dst, path, paths)
+		}
+		dst = append(dst, '{')
+		iter := value.MapRange()
+		written := 0
+		for iter.Next() {
+			if written == enc.maxFields && written > 0 {
+				dst = enc.appendTruncatedMembers(dst, value.Len()-written)
+				break
+			}
+			if written > 0 {
+				dst = append(dst, ',')
+			}
+			written++
+			key, ok := mapKeyString(iter.Key())
+			if !ok {
+				dst = enc.appendString(dst, key)
+				dst = append(dst, ':')
+				dst = enc.appendUnsupported(dst, path, paths)
+				continue
+			}
+			dst = enc.appendString(dst, key)
+			dst = append(dst, ':')
+			dst = enc.appendLenient(dst, iter.Value(), joinKeyPath(path, key), depth+1, paths)
+		}
+		return append(dst, '}')
+	case reflect.Slice, reflect.Array:
+		dst = append(dst, '[')
+		for i := 0; i < value.Len(); i++ {
+			if i == enc.maxFields && i > 0 {
+				dst = appendTruncatedElements(dst)
+				break
+			}
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			dst = enc.appendLenient(dst, value.Index(i), joinIndexPath(path, i), depth+1, paths)
+		}
+		return append(dst, ']')
+	case reflect.Struct:
+		plan := planFor(value.Type())
+		dst = append(dst, '{')
+		written := 0
+		for i := range plan.fields {
+			fieldPlan := &plan.fields[i]
+			fieldValue, ok := fieldByIndex(value, fieldPlan.index)
+			if !ok || (fieldPlan.omitEmpty && isEmptyValue(fieldValue)) {
+				continue
+			}
+			if written == enc.maxFields && written > 0 {
+				dst = enc.appendTruncatedMembers(dst, countStructFields(value, plan.fields[i:]))
+				break
+			}
+			if written > 0 {
+				dst = append(dst, ',')
+			}
+			written++
+			dst = append(dst, fieldPlan.key...)
+			if fieldPlan.quoted {
+				mark := len(dst)
+				if dst, ok = enc.appendStructField(dst, fieldPlan, fieldValue, depth); !ok {
+					dst = enc.appendUnsupported(dst[:mark], joinKeyPath(path, fieldPlan.name), paths)
+				}
+				continue
+			}
+			dst = enc.appendLenient(dst, fieldValue, joinKeyPath(path, fieldPlan.name), depth+1, paths)
+		}
+		return append(dst, '}')
+	default:
+		return enc.appendUnsupported(dst, path, paths)
+	}
+}
+
+// opaqueType reports whether values of valueType are encoded as a whole by
+// appendValue, so a failure can't be narrowed down to a part of them.
+func (enc *encoder) opaqueType(valueType reflect.Type) bool {
+	switch valueType {
+	case timeType, durationType, rawMessageType, rawJSONSourceType, byteSliceType, jsonNumberType, bigIntType, bigFloatType:
+		return true
+	}
+	if isTextValueType(valueType) || isMarshalerType(valueType) {
+		return true
+	}
+	return enc.protoMarshal != nil && valueType.Implements(protoMessageType)
+}
+
+// appendUnsupported writes the placeholder for the value at path.
+func (enc *encoder) appendUnsupported(dst []byte, path string, paths *[]string) []byte {
+	*paths = append(*paths, path)
+	return appendQuoteBytes(dst, "<unsupported>")
+}
+
+func joinKeyPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func joinIndexPath(path string, index int) string {
+	return path + "[" + strconv.Itoa(index) + "]"
+}
+
+// appendEncodeErrors writes the EncodeErrorsKey field for a non-empty
+// list of failed paths.
+func (enc *encoder) appendEncodeErrors(dst []byte, paths []string) []byte {
+	if len(paths) == 0 {
+		return dst
+	}
+	dst = append(dst, ',')
+	dst = enc.appendKey(dst, EncodeErrorsKey)
+	dst = append(dst, ':')
+	return enc.appendPaths(dst, paths)
+}
+
+// appendPaths writes paths as a JSON array of strings.
+func (enc *encoder) appendPaths(dst []byte, paths []string) []byte {
+	dst = append(dst, '[')
+	for i, path := range paths {
+		if i > 0 {
+			dst = append(dst, ',')
+		}
+		dst = enc.appendString(dst, path)
+	}
+	return append(dst, ']')
+}
@@ -0,0 +1,53 @@
+package golog
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestNestedUnsupportedValuesAreIsolated(t *testing.T) {
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(WithOutput(buf))
+
+	type item struct {
+		Name     string `json:"name"`
+		Callback func() `json:"callback"`
+	}
+	jl.Info("payload",
+		Any("payload", map[string]any{"items": []any{1, item{Name: "a"}, make(chan int)}}),
+		Any("fine", []string{"x"}),
+		Any("ch", make(chan int)),
+	)
+
+	line := strings.TrimSpace(buf.String())
+	for _, want := range []string{
+		`"payload":{"items":[1,{"name":"a","callback":"<unsupported>"},"<unsupported>"]}`,
+		`"fine":["x"]`,
+		`"ch":"<unsupported>"`,
+		`"encode_errors":["payload.items[1].callback","payload.items[2]","ch"]}`,
+	} {
+		if !strings.Contains(line, want) {
+			t.Fatalf("expected %s in %s", want, line)
+		}
+	}
+	readEntries(t, buf.Bytes())
+}
+
+func TestEncodeErrorsInOtherWriters(t *testing.T) {
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(WithOutput(buf), WithPrettyJSON(), WithFloatPolicy(FloatAsError), WithBaseField("app", "api"))
+
+	jl.Info("pretty", Float64("ratio", math.NaN()), Any("m", map[string]any{"f": func() {}}))
+
+	if !strings.Contains(buf.String(), `"m": {`) || !strings.Contains(buf.String(), `"encode_errors": ["ratio","m.f"]`) {
+		t.Fatalf("expected encode_errors in pretty output, got %s", buf.String())
+	}
+
+	var encoded bytes.Buffer
+	encoded.Write(AppendValue(nil, map[string]any{"ok": 1, "bad": []any{make(chan int)}}))
+	if !strings.Contains(encoded.String(), `"bad":["<unsupported>"]`) || !strings.Contains(encoded.String(), `"ok":1`) {
+		t.Fatalf("expected AppendValue to isolate the failure, got %s", encoded.String())
+	}
+}
@@ -3,10 +3,12 @@ package golog
 import (
 	"bytes"
 	"encoding/json"
+	"math"
 	"reflect"
 	"strconv"
 	"testing"
 	"time"
+	"unicode/utf8"
 )
 
 func TestFastEncodePrimitives(t *testing.T) {
@@ -70,6 +72,99 @@ func TestFastEncodeMapAndSlice(t *testing.T) {
 	}
 }
 
+func TestFastEncodeConcreteMaps(t *testing.T) {
+	values := []any{
+		map[string]string{"a": "x\n", "b": ""},
+		map[string]int{"a": -1, "b": 2},
+		map[string]int64{"a": math.MaxInt64},
+		map[string]uint64{"a": math.MaxUint64},
+		map[string]float64{"a": 1.5, "b": 0},
+		map[string]bool{"a": true, "b": false},
+		map[string]string(nil),
+		map[string]int{},
+	}
+
+	for _, value := range values {
+		var buf bytes.Buffer
+		if !FastEncode(&buf, value) {
+			t.Fatalf("FastEncode(%T) returned false", value)
+		}
+		want, _ := json.Marshal(value)
+		if !jsonEqual(t, buf.Bytes(), want) {
+			t.Fatalf("FastEncode(%#v): expected %s, got %s", value, want, buf.String())
+		}
+	}
+
+	nonFinite := map[string]float64{"a": math.NaN()}
+	var buf bytes.Buffer
+	if !FastEncode(&buf, nonFinite) || buf.String() != `{"a":null}` {
+		t.Fatalf("expected the float policy to apply to map values, got %s", buf.String())
+	}
+
+	buf.Reset()
+	tags := map[string]string{"env": "prod", "region": "eu-west-1"}
+	allocs := testing.AllocsPerRun(100, func() {
+		buf.Reset()
+		FastEncode(&buf, tags)
+	})
+	if allocs != 0 {
+		t.Fatalf("expected map[string]string to encode without allocating, got %v", allocs)
+	}
+}
+
+func TestFastEncodeConcreteSlices(t *testing.T) {
+	values := []any{
+		[]string{"a", "b\"c"},
+		[]int{-1, 0, 2},
+		[]int64{math.MinInt64},
+		[]uint64{math.MaxUint64},
+		[]float64{0.1, 2},
+		[]bool{true, false},
+		[]string(nil),
+		[]int{},
+		map[string]any{"tags": []string{"blue", "green"}},
+	}
+
+	for _, value := range values {
+		var buf bytes.Buffer
+		if !FastEncode(&buf, value) {
+			t.Fatalf("FastEncode(%T) returned false", value)
+		}
+		want, _ := json.Marshal(value)
+		if buf.String() != string(want) {
+			t.Fatalf("FastEncode(%#v): expected %s, got %s", value, want, buf.String())
+		}
+	}
+
+	enc := encoder{floatPolicy: FloatAsError}
+	if _, ok := enc.appendValue(nil, []float64{1, math.Inf(1)}); ok {
+		t.Fatal("expected the float policy to reject non-finite slice elements")
+	}
+
+	var buf bytes.Buffer
+	payload := map[string]any{"tags": []string{"blue", "green"}, "ids": []int{1, 2}}
+	allocs := testing.AllocsPerRun(100, func() {
+		buf.Reset()
+		FastEncode(&buf, payload)
+	})
+	if allocs != 0 {
+		t.Fatalf("expected concrete slices to encode without allocating, got %v", allocs)
+	}
+}
+
+// jsonEqual compares two JSON documents ignoring object key order.
+func jsonEqual(t *testing.T, got, want []byte) bool {
+	t.Helper()
+	var gotValue, wantValue any
+	if err := json.Unmarshal(got, &gotValue); err != nil {
+		t.Fatalf("invalid JSON %s: %v", got, err)
+	}
+	if err := json.Unmarshal(want, &wantValue); err != nil {
+		t.Fatalf("invalid JSON %s: %v", want, err)
+	}
+	return reflect.DeepEqual(gotValue, wantValue)
+}
+
 func TestFastEncodeUnsupported(t *testing.T) {
 	var buf bytes.Buffer
 	ch := make(chan int)
@@ -119,3 +214,163 @@ func TestFastQuoteControlCharsAndNumericEdgecases(t *testing.T) {
 		t.Fatalf("uint in map encoded mismatch: got %s", buf3.String())
 	}
 }
+
+func TestFastEncodeDurationBytesAndRawMessage(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+		want  string
+	}{
+		{name: "duration", value: 1500 * time.Millisecond, want: `1500000000`},
+		{name: "bytes", value: []byte("hi there"), want: `"aGkgdGhlcmU="`},
+		{name: "nil bytes", value: []byte(nil), want: `null`},
+		{name: "raw message", value: json.RawMessage(`{"a":[1,2]}`), want: `{"a":[1,2]}`},
+		{name: "empty raw message", value: json.RawMessage(nil), want: `null`},
+		{name: "nested", value: map[string]any{"d": time.Second}, want: `{"d":1000000000}`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if !FastEncode(&buf, tc.value) {
+				t.Fatalf("FastEncode returned false")
+			}
+			if buf.String() != tc.want {
+				t.Fatalf("expected %s, got %s", tc.want, buf.String())
+			}
+		})
+	}
+}
+
+func TestEncoderDurationString(t *testing.T) {
+	enc := encoder{durationFormat: DurationString}
+	got, ok := enc.appendValue(nil, []any{90 * time.Second})
+	if !ok {
+		t.Fatalf("appendValue returned false")
+	}
+	if string(got) != `["1m30s"]` {
+		t.Fatalf("unexpected duration string encoding: %s", got)
+	}
+}
+
+func TestEncoderNonFiniteFloatPolicies(t *testing.T) {
+	values := []any{math.NaN(), math.Inf(1), float32(math.Inf(-1)), 1.5}
+	tests := []struct {
+		policy FloatPolicy
+		want   string
+		ok     bool
+	}{
+		{policy: FloatAsNull, want: `[null,null,null,1.5]`, ok: true},
+		{policy: FloatAsString, want: `["NaN","+Inf","-Inf",1.5]`, ok: true},
+		{policy: FloatAsError, ok: false},
+	}
+
+	for _, tc := range tests {
+		enc := encoder{floatPolicy: tc.policy}
+		got, ok := enc.appendValue(nil, values)
+		if ok != tc.ok {
+			t.Fatalf("policy %d: expected ok=%v, got %v", tc.policy, tc.ok, ok)
+		}
+		if !ok {
+			continue
+		}
+		if string(got) != tc.want {
+			t.Fatalf("policy %d: expected %s, got %s", tc.policy, tc.want, got)
+		}
+		if !json.Valid(got) {
+			t.Fatalf("policy %d: produced invalid JSON %s", tc.policy, got)
+		}
+	}
+}
+
+func TestFastEncodeNaNIsValidJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if !FastEncode(&buf, map[string]any{"ratio": math.NaN()}) {
+		t.Fatalf("FastEncode returned false")
+	}
+	if buf.String() != `{"ratio":null}` {
+		t.Fatalf("unexpected encoding: %s", buf.String())
+	}
+}
+
+func TestAppendQuoteStrict(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "ascii", in: "plain \"text\"\n", want: `"plain \"text\"\n"`},
+		{name: "multibyte", in: "héllo 世界", want: `"héllo 世界"`},
+		{name: "invalid utf8", in: "a\xffb\xc3", want: `"a\ufffdb\ufffd"`},
+		{name: "line separators", in: "x\u2028y\u2029z", want: `"x\u2028y\u2029z"`},
+		{name: "control", in: "\x01", want: `"\u0001"`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := appendQuoteStrict(nil, tc.in)
+			if string(got) != tc.want {
+				t.Fatalf("expected %s, got %s", tc.want, got)
+			}
+			if !json.Valid(got) {
+				t.Fatalf("invalid JSON: %s", got)
+			}
+		})
+	}
+}
+
+func TestStrictEncoderRejectsMalformedRawMessage(t *testing.T) {
+	enc := encoder{strict: true}
+	if _, ok := enc.appendValue(nil, json.RawMessage(`{"a":`)); ok {
+		t.Fatalf("expected malformed raw message to be rejected in strict mode")
+	}
+	got, ok := enc.appendValue(nil, map[string]any{"k\xff": json.RawMessage(`[1]`)})
+	if !ok || string(got) != `{"k\ufffd":[1]}` {
+		t.Fatalf("unexpected strict encoding: %s (ok=%v)", got, ok)
+	}
+}
+
+func FuzzAppendQuote(f *testing.F) {
+	for _, seed := range []string{"", "plain", "a\"b\\c\n\t\r", "\x00\x1f\x7f", "héllo 世界", "a\xffb\xc3", "\u2028\u2029", "\xed\xa0\x80"} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, s string) {
+		strict := appendQuoteStrict(nil, s)
+		if !json.Valid(strict) {
+			t.Fatalf("appendQuoteStrict(%q) produced invalid JSON %s", s, strict)
+		}
+		var fromStrict, fromFast string
+		if err := json.Unmarshal(strict, &fromStrict); err != nil {
+			t.Fatalf("unmarshal %s: %v", strict, err)
+		}
+		fast := appendQuoteBytes(nil, s)
+		if err := json.Unmarshal(fast, &fromFast); err != nil {
+			t.Fatalf("appendQuoteBytes(%q) produced undecodable JSON %s: %v", s, fast, err)
+		}
+		if fromFast != fromStrict {
+			t.Fatalf("quoting %q: fast decodes to %q, strict to %q", s, fromFast, fromStrict)
+		}
+		if utf8.ValidString(s) && fromStrict != s {
+			t.Fatalf("round trip of %q gave %q", s, fromStrict)
+		}
+	})
+}
+
+func FuzzFastEncode(f *testing.F) {
+	f.Add("k", "v", int64(7), 0.1)
+	f.Add("\u2028", "\x01\"", int64(math.MaxInt64), math.Inf(-1))
+
+	f.Fuzz(func(t *testing.T, key, s string, i int64, fl float64) {
+		var buf bytes.Buffer
+		value := map[string]any{key: s, "list": []any{i, fl, []string{s}}}
+		if !FastEncode(&buf, value) {
+			t.Fatalf("FastEncode(%#v) returned false", value)
+		}
+		strict := encoder{strict: true}
+		encoded, ok := strict.appendValue(nil, value)
+		if !ok || !json.Valid(encoded) {
+			t.Fatalf("strict encoding of %#v is invalid: %s", value, encoded)
+		}
+	})
+}
@@ -0,0 +1,52 @@
+package golog
+
+import "time"
+
+// Entry is a single log record: the core timestamp, level and message plus
+// the entry's fields in the order they were written. It is the common shape
+// handed to LogWriters, captured by gologtest.Recorder and produced by
+// Reader.
+type Entry struct {
+	Time    time.Time
+	Level   Level
+	Message string
+	Fields  []Field
+
+	// logger is the configuration of the logger that wrote the entry, if
+	// any, for the writers and sinks of this package to format it with.
+	logger *JSONLogger
+}
+
+// config returns the configuration to format entry with: that of the
+// logger that wrote it, else the current one of bound, the logger a writer
+// or sink was installed on, else the defaults.
+func (entry Entry) config(bound *JSONLogger) *JSONLogger {
+	if entry.logger != nil {
+		return entry.logger
+	}
+	if bound != nil {
+		return bound.current()
+	}
+	return unboundWriterConfig
+}
+
+// Field returns the last field with the given key, mirroring JSON output
+// where later keys override earlier ones.
+func (entry Entry) Field(key string) (Field, bool) {
+	for i := len(entry.Fields) - 1; i >= 0; i-- {
+		if entry.Fields[i].key == key {
+			return entry.Fields[i], true
+		}
+	}
+	return Field{}, false
+}
+
+// FieldMap returns the entry's fields as a map of key to Field.Value. Later
+// fields override earlier ones with the same key.
+func (entry Entry) FieldMap() map[string]any {
+	fieldMap := make(map[string]any, len(entry.Fields))
+	for _, field := range entry.Fields {
+		fieldMap[field.key] = field.Value()
+	}
+	return fieldMap
+}
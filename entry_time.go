@@ -0,0 +1,77 @@
+package golog
+
+import "time"
+
+// WithEntryTime returns a field that sets the entry's timestamp to t instead
+// of the time of the call, for replaying historical events, batch ingestion
+// and backfills that must carry event time rather than write time:
+//
+//	jl.Info("order placed", WithEntryTime(order.CreatedAt), Str("order_id", order.ID))
+//
+// The field itself is not written; with WithEventTimeField the time is
+// written under its own key and timestamp keeps the write time. When a call
+// passes several, the last one wins; a zero t keeps the logger's clock.
+// Times must fall between the years 1678 and 2262.
+func WithEntryTime(t time.Time) Field {
+	if t.IsZero() {
+		return Field{key: ColumnTimestamp, kind: fieldKindEntryTime}
+	}
+	return Field{key: ColumnTimestamp, intVal: t.UnixNano(), boolVal: true, kind: fieldKindEntryTime}
+}
+
+// DefaultEventTimeKey is the key WithEventTimeField uses when given an empty
+// key.
+const DefaultEventTimeKey = "event_time"
+
+// WithEventTimeField keeps timestamp as the write time and writes the time
+// passed with WithEntryTime under key instead, formatted like timestamp, so
+// stream processors can compute ingestion lag from the two:
+//
+//	{"timestamp":"2026-03-01T10:00:05Z","level":"info","message":"order placed","event_time":"2026-03-01T09:58:41Z"}
+//
+// An empty key selects DefaultEventTimeKey. Entries without WithEntryTime
+// carry only timestamp.
+func WithEventTimeField(key string) Option {
+	return func(jsonLogger *JSONLogger) {
+		if key == "" {
+			key = DefaultEventTimeKey
+		}
+		jsonLogger.eventTimeKey = key
+	}
+}
+
+// entryTime returns the timestamp for an entry with fields and the fields to
+// write. Without control fields such as WithEntryTime it reads the clock and
+// returns fields unchanged; otherwise it copies the remaining fields.
+func (jsonLogger *JSONLogger) entryTime(fields []Field) (time.Time, []Field) {
+	for i := range fields {
+		if fields[i].kind >= fieldKindEntryTime {
+			return jsonLogger.stripControlFields(fields)
+		}
+	}
+	return jsonLogger.clock(), fields
+}
+
+// stripControlFields applies and removes the control fields. The first slot
+// of remaining is reserved for the WithEventTimeField field.
+func (jsonLogger *JSONLogger) stripControlFields(fields []Field) (time.Time, []Field) {
+	var timestamp time.Time
+	remaining := make([]Field, 1, len(fields))
+	for i := range fields {
+		switch {
+		case fields[i].kind < fieldKindEntryTime:
+			remaining = append(remaining, fields[i])
+		case fields[i].kind == fieldKindEntryTime && fields[i].boolVal:
+			timestamp = time.Unix(0, fields[i].intVal)
+		}
+	}
+	switch {
+	case timestamp.IsZero():
+		return jsonLogger.clock(), remaining[1:]
+	case jsonLogger.eventTimeKey != "":
+		remaining[0] = Str(jsonLogger.eventTimeKey, string(jsonLogger.appendTimestamp(nil, timestamp)))
+		return jsonLogger.clock(), remaining
+	default:
+		return timestamp, remaining[1:]
+	}
+}
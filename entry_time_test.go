@@ -0,0 +1,72 @@
+package golog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithEntryTime(t *testing.T) {
+	buf := &bytes.Buffer{}
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	jl := NewJSONLoggerWithOptions(WithOutput(buf), WithClock(func() time.Time { return now }), WithDevelopmentMode())
+	past := time.Date(2019, 3, 4, 5, 6, 7, 800, time.FixedZone("CET", 3600))
+
+	jl.Info("replayed", WithEntryTime(past), Str("order_id", "o-1"))
+	jl.Info("live", WithEntryTime(time.Time{}))
+	jl.Info("last wins", WithEntryTime(past), WithEntryTime(past.Add(time.Hour)))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	want := []string{
+		`{"timestamp":"2019-03-04T04:06:07.0000008Z","level":"info","message":"replayed","order_id":"o-1"}`,
+		`{"timestamp":"2026-05-01T12:00:00Z","level":"info","message":"live"}`,
+		`{"timestamp":"2019-03-04T05:06:07.0000008Z","level":"info","message":"last wins"}`,
+	}
+	for i := range want {
+		if lines[i] != want[i] {
+			t.Fatalf("line %d:\n got %s\nwant %s", i, lines[i], want[i])
+		}
+	}
+}
+
+func TestEntryTimeReachesCustomWritersAndSinks(t *testing.T) {
+	buf := &bytes.Buffer{}
+	sink := &recordingSink{}
+	jl := NewJSONLoggerWithOptions(WithOutput(buf), WithPrettyJSON(), WithSink(sink))
+	past := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	fields := []Field{Str("a", "b"), WithEntryTime(past)}
+	jl.Info("backfill", fields...)
+
+	if !strings.Contains(buf.String(), `"timestamp": "2020-01-02T03:04:05Z"`) || strings.Count(buf.String(), "timestamp") != 1 {
+		t.Fatalf("unexpected pretty output: %s", buf.String())
+	}
+	entries := sink.entries
+	if len(entries) != 1 || !entries[0].Time.Equal(past) || len(entries[0].Fields) != 1 {
+		t.Fatalf("unexpected sink entry: %+v", entries)
+	}
+	if value := fields[1].Value(); value != past {
+		t.Fatalf("expected Value to return the entry time, got %v", value)
+	}
+}
+
+func TestWithEventTimeField(t *testing.T) {
+	buf := &bytes.Buffer{}
+	now := time.Date(2026, 3, 1, 10, 0, 5, 0, time.UTC)
+	jl := NewJSONLoggerWithOptions(
+		WithOutput(buf),
+		WithClock(func() time.Time { return now }),
+		WithCustomTimeFormat(time.RFC3339),
+		WithEventTimeField(""),
+	)
+
+	jl.Info("order placed", Str("order_id", "o-1"), WithEntryTime(time.Date(2026, 3, 1, 9, 58, 41, 0, time.UTC)))
+	jl.Info("live")
+
+	want := `{"timestamp":"2026-03-01T10:00:05Z","level":"info","message":"order placed","event_time":"2026-03-01T09:58:41Z","order_id":"o-1"}` + "\n" +
+		`{"timestamp":"2026-03-01T10:00:05Z","level":"info","message":"live"}` + "\n"
+	if buf.String() != want {
+		t.Fatalf("unexpected output:\n got %s\nwant %s", buf.String(), want)
+	}
+}
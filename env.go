@@ -0,0 +1,58 @@
+package golog
+
+import "os"
+
+// EnvField maps an environment variable to the base field it populates.
+type EnvField struct {
+	// Env is the environment variable name, e.g. "POD_NAME".
+	Env string
+	// Key is the base field key written to every entry, e.g. "pod_name".
+	Key string
+}
+
+// KubernetesEnvFields covers the variables conventionally exposed through the
+// Kubernetes downward API.
+var KubernetesEnvFields = []EnvField{
+	{Env: "POD_NAME", Key: "pod_name"},
+	{Env: "POD_NAMESPACE", Key: "pod_namespace"},
+	{Env: "POD_IP", Key: "pod_ip"},
+	{Env: "NODE_NAME", Key: "node_name"},
+	{Env: "CONTAINER_NAME", Key: "container_name"},
+}
+
+// CIEnvFields covers common CI and cloud runtime variables.
+var CIEnvFields = []EnvField{
+	{Env: "GITHUB_SHA", Key: "git_commit"},
+	{Env: "GITHUB_RUN_ID", Key: "ci_run_id"},
+	{Env: "CI_COMMIT_SHA", Key: "git_commit"},
+	{Env: "CI_PIPELINE_ID", Key: "ci_run_id"},
+	{Env: "AWS_REGION", Key: "cloud_region"},
+	{Env: "K_SERVICE", Key: "cloud_run_service"},
+	{Env: "K_REVISION", Key: "cloud_run_revision"},
+}
+
+// WithEnvFields adds a base field for every mapping whose environment
+// variable is set and non-empty. Unset variables are skipped, so the same
+// configuration works inside and outside a cluster. When several mappings
+// target the same key, the last one that is set wins.
+//
+//	jl := NewJSONLoggerWithOptions(WithEnvFields(KubernetesEnvFields...))
+func WithEnvFields(mappings ...EnvField) Option {
+	return func(jsonLogger *JSONLogger) {
+		for _, mapping := range mappings {
+			value, ok := os.LookupEnv(mapping.Env)
+			if !ok || value == "" {
+				continue
+			}
+			WithBaseField(mapping.Key, value)(jsonLogger)
+		}
+	}
+}
+
+// WithEnvironment adds base fields from KubernetesEnvFields and CIEnvFields.
+func WithEnvironment() Option {
+	mappings := make([]EnvField, 0, len(KubernetesEnvFields)+len(CIEnvFields))
+	mappings = append(mappings, KubernetesEnvFields...)
+	mappings = append(mappings, CIEnvFields...)
+	return WithEnvFields(mappings...)
+}
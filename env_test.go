@@ -0,0 +1,32 @@
+package golog
+
+import "testing"
+
+func TestWithEnvFieldsAddsSetVariables(t *testing.T) {
+	t.Setenv("POD_NAME", "api-7c9f")
+	t.Setenv("POD_NAMESPACE", "")
+	t.Setenv("GOLOG_TEST_REGION", "eu-west-1")
+
+	jl := NewJSONLoggerWithOptions(
+		WithEnvFields(KubernetesEnvFields...),
+		WithEnvFields(EnvField{Env: "GOLOG_TEST_REGION", Key: "region"}),
+	)
+
+	if jl.baseFields["pod_name"] != "api-7c9f" || jl.baseFields["region"] != "eu-west-1" {
+		t.Fatalf("expected env base fields, got %v", jl.baseFields)
+	}
+	if _, ok := jl.baseFields["pod_namespace"]; ok {
+		t.Fatalf("expected empty variable to be skipped")
+	}
+}
+
+func TestWithEnvironmentCombinesDefaults(t *testing.T) {
+	t.Setenv("NODE_NAME", "node-1")
+	t.Setenv("GITHUB_SHA", "abc123")
+
+	jl := NewJSONLoggerWithOptions(WithEnvironment())
+
+	if jl.baseFields["node_name"] != "node-1" || jl.baseFields["git_commit"] != "abc123" {
+		t.Fatalf("expected kubernetes and CI fields, got %v", jl.baseFields)
+	}
+}
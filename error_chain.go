@@ -0,0 +1,150 @@
+package golog
+
+import (
+	"errors"
+	"reflect"
+	"sync/atomic"
+)
+
+// Keys of the fields written for errors by Err and ErrorE.
+const (
+	ErrorKey  = "error"
+	ErrorsKey = "errors"
+)
+
+// maxCauseDepth bounds how many wrapped errors ErrorE reports so that a
+// cyclic or pathological Unwrap implementation can't stall a log call.
+const maxCauseDepth = 32
+
+// Err returns an error field holding err.Error(), or, when err aggregates
+// several errors, an errors field listing their messages, so log queries can
+// match each cause on its own:
+//
+//	jl.Error("batch failed", Err(errors.Join(errA, errB))) // "errors":["a failed","b failed"]
+//
+// Errors created with errors.Join and other types with an Unwrap() []error,
+// Errors() []error or WrappedErrors() []error method (uber-go/multierr,
+// hashicorp/go-multierror) count as aggregates; nested aggregates are
+// flattened into the one list. A nil err logs a null error field.
+func Err(err error) Field {
+	if err == nil {
+		return Any(ErrorKey, nil)
+	}
+	if messages, ok := errorMessages(err); ok {
+		return Any(ErrorsKey, messages)
+	}
+	return Str(ErrorKey, err.Error())
+}
+
+// appendError writes an error value nested in a field as Err does: its
+// message, or the list of messages of an aggregate.
+func (enc *encoder) appendError(dst []byte, err error) []byte {
+	if messages, ok := errorMessages(err); ok {
+		dst, _ = appendSliceOf(enc, dst, messages, 0, appendStringElement)
+		return dst
+	}
+	return enc.appendStringValue(dst, err.Error())
+}
+
+// errorMessages returns the messages of the errors err aggregates, and
+// false when it isn't an aggregate.
+func errorMessages(err error) ([]string, bool) {
+	children := joinedErrors(err)
+	if len(children) == 0 {
+		return nil, false
+	}
+	messages := make([]string, 0, len(children))
+	var flatten func(children []error, depth int)
+	flatten = func(children []error, depth int) {
+		for _, child := range children {
+			if child == nil {
+				continue
+			}
+			if nested := joinedErrors(child); len(nested) > 0 && depth < maxCauseDepth {
+				flatten(nested, depth+1)
+				continue
+			}
+			messages = append(messages, child.Error())
+		}
+	}
+	flatten(children, 0)
+	return messages, len(messages) > 0
+}
+
+// joinedErrors returns the errors aggregated by err, or nil.
+func joinedErrors(err error) []error {
+	switch multi := err.(type) {
+	case interface{ Unwrap() []error }:
+		return multi.Unwrap()
+	case interface{ Errors() []error }:
+		return multi.Errors()
+	case interface{ WrappedErrors() []error }:
+		return multi.WrappedErrors()
+	}
+	return nil
+}
+
+// ErrorE logs a message at error level together with a structured view of
+// err. The entry carries:
+//   - error: the full err.Error() string, or, for an aggregate of several
+//     errors, errors: their messages, as written by Err
+//   - causes: the messages of every wrapped error, walked depth-first through
+//     errors.Unwrap and the aggregates recognized by Err
+//   - error_type: the Go type of the root cause
+//
+// A nil err logs the message with the given fields only.
+func (jsonLogger *JSONLogger) ErrorE(err error, message string, fields ...Field) {
+	if Level(atomic.LoadInt32((*int32)(&jsonLogger.level))) > ErrorLevel {
+		return
+	}
+	if err == nil {
+		jsonLogger.logFields(ErrorLevel, message, fields)
+		return
+	}
+
+	causes, root := errorCauses(err)
+	errorFields := make([]Field, 0, len(fields)+3)
+	errorFields = append(errorFields,
+		Err(err),
+		Field{key: "causes", anyVal: causes, kind: fieldKindAny},
+		Str("error_type", reflect.TypeOf(root).String()),
+	)
+	errorFields = append(errorFields, fields...)
+	jsonLogger.logFields(ErrorLevel, message, errorFields)
+}
+
+// errorCauses flattens the wrapped errors below err into a list of messages
+// and returns the root cause. For joined errors the root is taken from the
+// first branch.
+func errorCauses(err error) ([]any, error) {
+	causes := make([]any, 0, 4)
+	root := err
+	rootFound := false
+
+	var walk func(current error, depth int)
+	walk = func(current error, depth int) {
+		if depth >= maxCauseDepth {
+			return
+		}
+		children := joinedErrors(current)
+		if children == nil {
+			if next := errors.Unwrap(current); next != nil {
+				children = []error{next}
+			}
+		}
+		if len(children) == 0 && !rootFound {
+			root = current
+			rootFound = true
+		}
+		for _, child := range children {
+			if child == nil {
+				continue
+			}
+			causes = append(causes, child.Error())
+			walk(child, depth+1)
+		}
+	}
+	walk(err, 0)
+
+	return causes, root
+}
@@ -0,0 +1,134 @@
+package golog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+	"testing"
+)
+
+func TestErrorEEmitsCauseChain(t *testing.T) {
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(WithOutput(buf))
+
+	root := &fs.PathError{Op: "open", Path: "/etc/app.yaml", Err: fs.ErrNotExist}
+	err := fmt.Errorf("load config: %w", root)
+
+	jl.ErrorE(err, "startup failed", Str("component", "config"))
+
+	var got map[string]any
+	if jsonErr := json.Unmarshal([]byte(strings.TrimSpace(buf.String())), &got); jsonErr != nil {
+		t.Fatalf("unmarshal: %v -- %s", jsonErr, buf.String())
+	}
+	if got["error"] != err.Error() {
+		t.Fatalf("expected error=%q, got %v", err.Error(), got["error"])
+	}
+	causes, ok := got["causes"].([]any)
+	if !ok || len(causes) != 2 {
+		t.Fatalf("expected two causes, got %#v", got["causes"])
+	}
+	if causes[0] != root.Error() || causes[1] != fs.ErrNotExist.Error() {
+		t.Fatalf("unexpected causes: %#v", causes)
+	}
+	if got["error_type"] != "*errors.errorString" {
+		t.Fatalf("expected root error_type *errors.errorString, got %v", got["error_type"])
+	}
+	if got["component"] != "config" {
+		t.Fatalf("expected component=config, got %v", got["component"])
+	}
+}
+
+func TestErrorEWalksJoinedErrors(t *testing.T) {
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(WithOutput(buf))
+
+	first := errors.New("first")
+	second := fmt.Errorf("second: %w", errors.New("inner"))
+	jl.ErrorE(errors.Join(first, second), "batch failed")
+
+	var got map[string]any
+	if err := json.Unmarshal([]byte(strings.TrimSpace(buf.String())), &got); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	causes, _ := got["causes"].([]any)
+	want := []string{"first", "second: inner", "inner"}
+	if len(causes) != len(want) {
+		t.Fatalf("expected causes %v, got %#v", want, got["causes"])
+	}
+	for i := range want {
+		if causes[i] != want[i] {
+			t.Fatalf("cause %d: expected %q, got %v", i, want[i], causes[i])
+		}
+	}
+}
+
+func TestErrorENilAndFiltered(t *testing.T) {
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(WithOutput(buf))
+
+	jl.ErrorE(nil, "no error")
+	if strings.Contains(buf.String(), "causes") {
+		t.Fatalf("did not expect causes for nil error: %s", buf.String())
+	}
+
+	buf.Reset()
+	quiet := NewJSONLoggerWithOptions(WithOutput(buf), WithLevel(ErrorLevel+1))
+	quiet.ErrorE(errors.New("boom"), "suppressed")
+	if buf.Len() != 0 {
+		t.Fatalf("expected no output above error level, got %s", buf.String())
+	}
+}
+
+// listError mimics uber-go/multierr, which exposes Errors() []error.
+type listError []error
+
+func (list listError) Error() string   { return fmt.Sprint([]error(list)) }
+func (list listError) Errors() []error { return list }
+
+func TestErr(t *testing.T) {
+	nested := errors.Join(errors.New("b"), listError{errors.New("c"), nil})
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{name: "nil", err: nil, want: `"error":null`},
+		{name: "single", err: fmt.Errorf("wrap: %w", errors.New("a")), want: `"error":"wrap: a"`},
+		{name: "joined", err: errors.Join(errors.New("a"), nil, nested), want: `"errors":["a","b","c"]`},
+		{name: "list", err: listError{errors.New("x"), errors.New("y")}, want: `"errors":["x","y"]`},
+		{name: "empty aggregate", err: listError{nil}, want: `"error":"[<nil>]"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			jl := NewJSONLoggerWithOptions(WithOutput(buf))
+			jl.Error("failed", Err(tt.err))
+			if !strings.Contains(buf.String(), tt.want) {
+				t.Fatalf("expected %s in %s", tt.want, buf.String())
+			}
+		})
+	}
+}
+
+func TestErrorEJoinedErrorsField(t *testing.T) {
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(WithOutput(buf))
+	jl.ErrorE(listError{errors.New("disk full"), fmt.Errorf("upload: %w", errors.New("timeout"))}, "sync failed")
+
+	entries := readEntries(t, buf.Bytes())
+	fields := entries[0].FieldMap()
+	messages, ok := fields[ErrorsKey].([]any)
+	if !ok || len(messages) != 2 || messages[0] != "disk full" || messages[1] != "upload: timeout" {
+		t.Fatalf("unexpected errors field: %v", fields)
+	}
+	if _, ok := fields[ErrorKey]; ok {
+		t.Fatalf("expected the errors list instead of the concatenated message: %v", fields)
+	}
+	causes, _ := fields["causes"].([]any)
+	if len(causes) != 3 || causes[2] != "timeout" {
+		t.Fatalf("expected causes to walk Errors() aggregates, got %v", causes)
+	}
+}
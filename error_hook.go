@@ -0,0 +1,82 @@
+package golog
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// WithErrorHook calls hook for every error entry the logger writes, with its
+// message and fields, so errors can be forwarded to Sentry, Bugsnag or
+// PagerDuty without wrapping each Error call site:
+//
+//	golog.WithErrorHook(func(msg string, fields map[string]any) {
+//	    sentry.CaptureMessage(msg) // or build an event from fields
+//	})
+//
+// fields holds the base fields and the call's fields, later keys winning as
+// in the JSON output. The map belongs to hook, but Any values are shared with
+// the caller. The hook runs after the entry has been written and after the
+// middleware chain (see WithMiddleware), whatever the option order: it sees
+// the entry as the middleware passed it on, redactions included, and isn't
+// called for entries a middleware dropped.
+//
+// Values are masked by WithScrubbers and cut by WithMaxFieldLength as in the
+// output, so nothing the output hides reaches a third party. With either
+// option set, values other than strings, numbers, bools, durations and
+// times, such as maps, structs and errors, are passed as a json.RawMessage
+// holding their encoded form.
+//
+// The hook runs on the logging goroutine while the entry is being written,
+// so a slow reporter should hand the entry off rather than block, and the
+// hook must not log through the same logger or call Reconfigure. Several
+// hooks can be added; they run in the order they were added. Entries below
+// error level cost nothing extra.
+func WithErrorHook(hook func(msg string, fields map[string]any)) Option {
+	return func(jsonLogger *JSONLogger) {
+		if hook != nil {
+			jsonLogger.errorHooks = append(jsonLogger.errorHooks, hook)
+		}
+	}
+}
+
+// runErrorHooks calls the WithErrorHook hooks for a written entry at or
+// above error level. fields are those written, control fields already
+// applied.
+func (jsonLogger *JSONLogger) runErrorHooks(message string, fields []Field) {
+	for _, hook := range jsonLogger.errorHooks {
+		hook(message, jsonLogger.fieldMap(fields))
+	}
+}
+
+// fieldMap returns the logger's base fields and fields as a map of key to
+// hookValue.
+func (jsonLogger *JSONLogger) fieldMap(fields []Field) map[string]any {
+	jsonLogger.baseFieldsOnce.Do(jsonLogger.buildBaseFieldsCache)
+	fieldMap := make(map[string]any, len(jsonLogger.baseFieldList)+len(fields))
+	for _, field := range jsonLogger.baseFieldList {
+		fieldMap[field.key] = jsonLogger.encoder.hookValue(field)
+	}
+	for _, field := range fields {
+		fieldMap[field.key] = jsonLogger.encoder.hookValue(field)
+	}
+	return fieldMap
+}
+
+// hookValue returns Field.Value with the scrubbers and the length cap
+// applied. Values that may hold strings deeper down are encoded, since
+// masking them in place would change the caller's data.
+func (enc *encoder) hookValue(field Field) any {
+	value := field.Value()
+	if len(enc.scrubbers) == 0 && enc.maxFieldLength <= 0 {
+		return value
+	}
+	switch typedValue := value.(type) {
+	case string:
+		return enc.stringValue(typedValue)
+	case nil, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64,
+		float32, float64, time.Duration, time.Time:
+		return value
+	default:
+		return json.RawMessage(enc.appendFieldValue(nil, field))
+	}
+}
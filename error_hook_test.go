@@ -0,0 +1,168 @@
+package golog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithErrorHook(t *testing.T) {
+	type report struct {
+		msg    string
+		fields map[string]any
+	}
+	var reports []report
+	var written int
+
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(
+		WithOutput(buf),
+		WithBaseField("service", "api"),
+		WithErrorHook(func(msg string, fields map[string]any) {
+			written = bytes.Count(buf.Bytes(), []byte("\n"))
+			reports = append(reports, report{msg: msg, fields: fields})
+		}),
+		WithErrorHook(nil),
+	)
+	jl.Info("fine")
+	jl.Warn("careful")
+	jl.Error("payment failed", Str("order_id", "o-1"), Int("attempt", 2))
+
+	if len(reports) != 1 {
+		t.Fatalf("expected only the error entry to reach the hook, got %v", reports)
+	}
+	got := reports[0]
+	if got.msg != "payment failed" || got.fields["service"] != "api" || got.fields["order_id"] != "o-1" || got.fields["attempt"] != int64(2) {
+		t.Fatalf("unexpected report: %+v", got)
+	}
+	if written != 3 {
+		t.Fatalf("expected the hook to run after the entry was written, saw %d lines", written)
+	}
+}
+
+func TestWithErrorHookReadsClockOncePerEntry(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 0, 0, time.UTC)
+	buf := &bytes.Buffer{}
+	var hooked map[string]any
+	jl := NewJSONLoggerWithOptions(
+		WithOutput(buf),
+		WithClock(func() time.Time {
+			now = now.Add(time.Second)
+			return now
+		}),
+		WithErrorHook(func(msg string, fields map[string]any) { hooked = fields }),
+	)
+
+	jl.Error("a")
+	jl.Error("b", ForceLog())
+	entries := readEntries(t, buf.Bytes())
+	if step := entries[1].Time.Sub(entries[0].Time); step != time.Second {
+		t.Fatalf("expected consecutive clock readings, got a step of %v", step)
+	}
+	if len(hooked) != 0 {
+		t.Fatalf("expected control fields to be left out, got %v", hooked)
+	}
+}
+
+func TestWithErrorHookEvents(t *testing.T) {
+	calls := 0
+	jl := NewJSONLoggerWithOptions(WithOutput(&bytes.Buffer{}), WithErrorHook(func(string, map[string]any) {
+		calls++
+	}))
+	jl.Event("signup", map[string]any{"plan": "pro"})
+	jl.ErrorE(errors.New("declined"), "charge failed")
+	if calls != 1 {
+		t.Fatalf("expected one hook call for ErrorE and none for events, got %d", calls)
+	}
+}
+
+func TestWithErrorHookAfterMiddleware(t *testing.T) {
+	var reports []map[string]any
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(
+		WithOutput(buf),
+		// Registered first, the hook still sees what the middleware wrote.
+		WithErrorHook(func(msg string, fields map[string]any) {
+			reports = append(reports, fields)
+		}),
+		WithMiddleware(func(next EntryFunc) EntryFunc {
+			return func(entry Entry) {
+				if entry.Message == "noisy" {
+					return
+				}
+				for i, field := range entry.Fields {
+					if field.key == "password" {
+						entry.Fields[i] = Str("password", "[REDACTED]")
+					}
+				}
+				next(entry)
+			}
+		}),
+	)
+
+	jl.Error("noisy", Str("password", "hunter2"))
+	jl.Error("login failed", Str("password", "hunter2"), WithEntryTime(deterministicTime))
+
+	if len(reports) != 1 {
+		t.Fatalf("expected the dropped entry not to reach the hook, got %v", reports)
+	}
+	if reports[0]["password"] != "[REDACTED]" || len(reports[0]) != 1 {
+		t.Fatalf("expected the redacted fields as written, got %v", reports[0])
+	}
+	if entries := readEntries(t, buf.Bytes()); len(entries) != 1 || entries[0].FieldMap()["password"] != "[REDACTED]" {
+		t.Fatalf("unexpected output: %s", buf.Bytes())
+	}
+
+	// The fast path without middleware skips control fields too.
+	reports = nil
+	plain := NewJSONLoggerWithOptions(WithOutput(&bytes.Buffer{}), WithErrorHook(func(msg string, fields map[string]any) {
+		reports = append(reports, fields)
+	}))
+	plain.Error("failed", WithEntryTime(deterministicTime), Int("code", 7))
+	if len(reports) != 1 || len(reports[0]) != 1 || reports[0]["code"] != int64(7) {
+		t.Fatalf("unexpected fields: %v", reports)
+	}
+}
+
+func TestWithErrorHookScrubsValues(t *testing.T) {
+	var reports []map[string]any
+	mask := ScrubberFunc(func(value string) string {
+		return strings.ReplaceAll(value, "hunter2", "***")
+	})
+	jl := NewJSONLoggerWithOptions(
+		WithOutput(&bytes.Buffer{}),
+		WithScrubbers(mask),
+		WithMaxFieldLength(16),
+		WithErrorHook(func(msg string, fields map[string]any) {
+			reports = append(reports, fields)
+		}),
+	)
+	request := map[string]any{"password": "hunter2"}
+	jl.Error("login failed",
+		Str("token", "hunter2"),
+		Str("body", strings.Repeat("x", 32)),
+		Any("request", request),
+		Int("attempt", 3),
+	)
+
+	if len(reports) != 1 {
+		t.Fatalf("expected one report, got %v", reports)
+	}
+	fields := reports[0]
+	if fields["token"] != "***" || fields["attempt"] != int64(3) {
+		t.Fatalf("expected the masked token and the attempt as is: %v", fields)
+	}
+	if body, _ := fields["body"].(string); !strings.HasPrefix(body, strings.Repeat("x", 16)+"...") {
+		t.Fatalf("expected the capped body, got %q", fields["body"])
+	}
+	raw, ok := fields["request"].(json.RawMessage)
+	if !ok || string(raw) != `{"password":"***"}` {
+		t.Fatalf("expected the encoded, masked request, got %#v", fields["request"])
+	}
+	if request["password"] != "hunter2" {
+		t.Fatalf("expected the caller's map to be left alone: %v", request)
+	}
+}
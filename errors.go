@@ -2,4 +2,22 @@ package golog
 
 import "errors"
 
-var errMarshalTypeUnsupported = errors.New("unsupported type for marshal")
+// Sentinel errors returned by the package. Compare with errors.Is, since
+// outputs and hooks may wrap them with more context.
+var (
+	// ErrUnsupportedType is returned by MarshalToBuffer for values it can't
+	// encode, such as channels, functions or maps with float keys.
+	ErrUnsupportedType = errors.New("unsupported type for marshal")
+
+	// ErrQueueFull is returned by queueing outputs that are configured to
+	// reject entries rather than block when their queue is full.
+	ErrQueueFull = errors.New("queue full")
+
+	// ErrWriterClosed is returned by queueing outputs (WithAsync,
+	// WithLockFreeOutput) for writes after Close or Shutdown.
+	ErrWriterClosed = errors.New("writer closed")
+
+	// ErrOutputStalled is returned by Flush, Close and Reopen while an
+	// output guarded by WithWriteWatchdog is stalled in drop mode.
+	ErrOutputStalled = errors.New("output stalled")
+)
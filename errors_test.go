@@ -1,18 +1,30 @@
 package golog
 
-import "testing"
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+)
 
-func TestErrMarshalTypeUnsupportedIsSentinel(t *testing.T) {
-	if errMarshalTypeUnsupported == nil {
-		t.Fatalf("errMarshalTypeUnsupported should not be nil")
-	}
-
-	// Ensure the sentinel value is comparable and not equal to a different error
-	if errMarshalTypeUnsupported == (error)(nil) {
-		t.Fatalf("unexpected nil sentinel")
+func TestSentinelErrorsAreDistinct(t *testing.T) {
+	sentinels := []error{ErrUnsupportedType, ErrQueueFull, ErrWriterClosed}
+	for i, err := range sentinels {
+		if err == nil || err.Error() == "" {
+			t.Fatalf("sentinel %d should be a non-empty error", i)
+		}
+		for j, other := range sentinels {
+			if i != j && errors.Is(err, other) {
+				t.Fatalf("sentinel %q matches %q", err, other)
+			}
+		}
 	}
+}
 
-	if errMarshalTypeUnsupported.Error() == "" {
-		t.Fatalf("expected non-empty error string")
+func TestSentinelErrorsMatchWhenWrapped(t *testing.T) {
+	var buf bytes.Buffer
+	err := fmt.Errorf("encode payload: %w", MarshalToBuffer(&buf, make(chan int)))
+	if !errors.Is(err, ErrUnsupportedType) {
+		t.Fatalf("expected wrapped error to match ErrUnsupportedType, got %v", err)
 	}
 }
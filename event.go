@@ -0,0 +1,79 @@
+package golog
+
+import "sync/atomic"
+
+// Keys added to every Event record.
+const (
+	// EventTypeKey marks the record as an event; its value is EventType.
+	EventTypeKey = "type"
+	// EventSchemaVersionKey carries the schema version of the event
+	// properties, set with WithEventSchemaVersion.
+	EventSchemaVersionKey = "schema_version"
+	// EventType is the value of EventTypeKey on event records.
+	EventType = "event"
+)
+
+// defaultEventSchemaVersion is written when WithEventSchemaVersion is not
+// used.
+const defaultEventSchemaVersion = "1"
+
+// WithEventSchemaVersion sets the schema version written with every Event,
+// so downstream consumers can evolve their parsing as event properties
+// change. Defaults to "1".
+func WithEventSchemaVersion(version string) Option {
+	return func(jsonLogger *JSONLogger) {
+		jsonLogger.eventSchemaVersion = version
+	}
+}
+
+// Event writes a business or analytics event named name. Events share the
+// logging pipeline (output, base fields, LogWriter) but bypass level
+// filtering, and carry "type":"event" and a schema version so they can be
+// routed apart from regular entries downstream. The record's level is info
+// and its message is name; props become fields, sorted by key.
+//
+//	jl.Event("checkout_completed", map[string]any{"order_id": 42, "total": 19.99})
+func (jsonLogger *JSONLogger) Event(name string, props map[string]any) {
+	if jsonLogger.off() {
+		return
+	}
+	scratchPtr := fieldScratchPool.Get().(*[]Field)
+	fields := append((*scratchPtr)[:0],
+		Str(EventTypeKey, EventType),
+		Str(EventSchemaVersionKey, jsonLogger.current().schemaVersion()),
+	)
+	fields = appendMapFields(fields, props)
+
+	jsonLogger.logUnfiltered(name, fields)
+
+	clear(fields)
+	*scratchPtr = fields[:0]
+	fieldScratchPool.Put(scratchPtr)
+}
+
+func (jsonLogger *JSONLogger) schemaVersion() string {
+	if jsonLogger.eventSchemaVersion == "" {
+		return defaultEventSchemaVersion
+	}
+	return jsonLogger.eventSchemaVersion
+}
+
+// off reports whether the logger is set to OffLevel.
+func (jsonLogger *JSONLogger) off() bool {
+	return Level(atomic.LoadInt32((*int32)(&jsonLogger.level))) == OffLevel
+}
+
+// logUnfiltered writes an info entry regardless of the logger's level,
+// unless logging is off. It is the shared path of records that must not be
+// dropped by level filtering, such as events and metrics.
+func (jsonLogger *JSONLogger) logUnfiltered(message string, fields []Field) {
+	if jsonLogger.off() {
+		return
+	}
+	config, inflight := jsonLogger.acquire()
+	defer inflight.release()
+	if config.development {
+		config.checkEntry(message, fields)
+	}
+	config.emit(InfoLevel, message, fields)
+}
@@ -0,0 +1,43 @@
+package golog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestEventBypassesLevelFiltering(t *testing.T) {
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(WithOutput(buf), WithLevel(ErrorLevel), WithBaseField("service", "shop"))
+
+	jl.Info("filtered")
+	jl.Event("checkout_completed", map[string]any{"total": 19.99, "order_id": 42})
+
+	entries := readEntries(t, buf.Bytes())
+	if len(entries) != 1 {
+		t.Fatalf("expected only the event, got %d entries", len(entries))
+	}
+	event := entries[0]
+	if event.Message != "checkout_completed" || event.Level != InfoLevel {
+		t.Fatalf("unexpected event: %+v", event)
+	}
+	fields := event.FieldMap()
+	if fields[EventTypeKey] != EventType || fields[EventSchemaVersionKey] != "1" || fields["service"] != "shop" {
+		t.Fatalf("unexpected event fields: %v", fields)
+	}
+	if !strings.Contains(buf.String(), `"order_id":42,"total":19.99`) {
+		t.Fatalf("expected props sorted by key, got %s", buf.String())
+	}
+}
+
+func TestWithEventSchemaVersion(t *testing.T) {
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(WithOutput(buf), WithEventSchemaVersion("2024-05"))
+
+	jl.Event("signup", nil)
+
+	entries := readEntries(t, buf.Bytes())
+	if version, _ := entries[0].Field(EventSchemaVersionKey); version.Value() != "2024-05" {
+		t.Fatalf("unexpected schema version: %v", version.Value())
+	}
+}
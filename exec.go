@@ -0,0 +1,81 @@
+package golog
+
+import (
+	"errors"
+	"os/exec"
+	"time"
+)
+
+// Field keys written by RunCommand.
+const (
+	CommandKey       = "cmd"
+	CommandPIDKey    = "pid"
+	CommandStreamKey = "stream"
+	ExitCodeKey      = "exit_code"
+)
+
+// defaultCommandWaitDelay is the cmd.WaitDelay RunCommand sets when it is
+// zero.
+const defaultCommandWaitDelay = time.Second
+
+// RunCommand starts cmd, logs every line of its output as an entry and
+// waits for it to exit. Lines from stdout are logged at info level and lines
+// from stderr at warn level, each with the cmd, pid and stream ("stdout" or
+// "stderr") fields. When the command finishes, a "command exited" entry adds
+// exit_code and duration; it is logged at error level, with the error, when
+// the command failed or exited non-zero.
+//
+//	err := RunCommand(jl, exec.CommandContext(ctx, "pg_dump", "-Fc", "app"))
+//
+// cmd.Stdout and cmd.Stderr must be nil. When the command exits, or its
+// context is done, while a process it started still holds the output open,
+// RunCommand waits at most cmd.WaitDelay (one second when zero) for the
+// output to end, then returns exec.ErrWaitDelay. It returns the error from
+// starting or waiting for cmd.
+func RunCommand(l Logger, cmd *exec.Cmd) error {
+	if cmd.Stdout != nil {
+		return errors.New("exec: Stdout already set")
+	}
+	if cmd.Stderr != nil {
+		return errors.New("exec: Stderr already set")
+	}
+	stdout := &messageWriter{logger: l, level: InfoLevel}
+	stderr := &messageWriter{logger: l, level: WarnLevel}
+	cmd.Stdout, cmd.Stderr = stdout, stderr
+	if cmd.WaitDelay == 0 {
+		cmd.WaitDelay = defaultCommandWaitDelay
+	}
+
+	// Output arriving before the pid is known waits for its fields.
+	stdout.mutex.Lock()
+	stderr.mutex.Lock()
+	start := time.Now()
+	err := cmd.Start()
+	var fields []Field
+	if err == nil {
+		fields = []Field{Str(CommandKey, cmd.String()), Int(CommandPIDKey, cmd.Process.Pid)}
+		stdout.fields = append(fields[:len(fields):len(fields)], Str(CommandStreamKey, "stdout"))
+		stderr.fields = append(fields[:len(fields):len(fields)], Str(CommandStreamKey, "stderr"))
+	}
+	stderr.mutex.Unlock()
+	stdout.mutex.Unlock()
+	if err != nil {
+		l.Error("command failed to start", Str(CommandKey, cmd.String()), Str("error", err.Error()))
+		return err
+	}
+
+	err = cmd.Wait()
+	_ = stdout.Flush()
+	_ = stderr.Flush()
+
+	fields = append(fields,
+		Int(ExitCodeKey, cmd.ProcessState.ExitCode()),
+		Duration("duration", time.Since(start)),
+	)
+	if err != nil {
+		l.Error("command exited", append(fields, Str("error", err.Error()))...)
+	} else {
+		l.Info("command exited", fields...)
+	}
+	return err
+}
@@ -0,0 +1,82 @@
+package golog
+
+import (
+	"bytes"
+	"errors"
+	"os/exec"
+	"testing"
+	"time"
+)
+
+func TestRunCommand(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	buf := &lockedBuffer{}
+	jl := NewJSONLoggerWithOptions(WithOutput(buf))
+
+	cmd := exec.Command("sh", "-c", "echo out; echo err >&2; printf tail; exit 3")
+	if err := RunCommand(jl, cmd); err == nil {
+		t.Fatalf("expected the exit status to be returned")
+	}
+
+	entries := readEntries(t, buf.Bytes())
+	byMessage := map[string]Entry{}
+	for _, entry := range entries {
+		byMessage[entry.Message] = entry
+	}
+	out, errLine, tail, exited := byMessage["out"], byMessage["err"], byMessage["tail"], byMessage["command exited"]
+	if out.Level != InfoLevel || out.FieldMap()[CommandStreamKey] != "stdout" {
+		t.Fatalf("unexpected stdout entry: %+v", out)
+	}
+	if errLine.Level != WarnLevel || errLine.FieldMap()[CommandStreamKey] != "stderr" {
+		t.Fatalf("unexpected stderr entry: %+v", errLine)
+	}
+	if tail.FieldMap()[CommandStreamKey] != "stdout" {
+		t.Fatalf("expected the unterminated last line to be logged: %+v", entries)
+	}
+	fields := exited.FieldMap()
+	if exited.Level != ErrorLevel || fields[ExitCodeKey] != int64(3) || fields[CommandPIDKey] != out.FieldMap()[CommandPIDKey] {
+		t.Fatalf("unexpected exit entry: %+v", exited)
+	}
+	if fields[CommandKey] != cmd.String() {
+		t.Fatalf("unexpected cmd field: %v", fields[CommandKey])
+	}
+}
+
+func TestRunCommandStartFailure(t *testing.T) {
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(WithOutput(buf))
+
+	if err := RunCommand(jl, exec.Command("/nonexistent/binary")); err == nil {
+		t.Fatalf("expected a start error")
+	}
+	entries := readEntries(t, buf.Bytes())
+	if len(entries) != 1 || entries[0].Message != "command failed to start" {
+		t.Fatalf("unexpected entries: %+v", entries)
+	}
+}
+
+func TestRunCommandDoesNotWaitForLeftoverProcesses(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	buf := &lockedBuffer{}
+	jl := NewJSONLoggerWithOptions(WithOutput(buf))
+
+	// The background sleep inherits stdout and keeps it open after sh exits.
+	cmd := exec.Command("sh", "-c", "sleep 5 & echo started")
+	cmd.WaitDelay = 50 * time.Millisecond
+	started := time.Now()
+	err := RunCommand(jl, cmd)
+	if !errors.Is(err, exec.ErrWaitDelay) {
+		t.Fatalf("expected the wait delay to end the call, got %v", err)
+	}
+	if elapsed := time.Since(started); elapsed > 3*time.Second {
+		t.Fatalf("expected RunCommand not to wait for the leftover process, took %v", elapsed)
+	}
+	entries := readEntries(t, buf.Bytes())
+	if len(entries) != 2 || entries[0].Message != "started" || entries[1].Message != "command exited" {
+		t.Fatalf("unexpected entries: %+v", entries)
+	}
+}
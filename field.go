@@ -1,6 +1,10 @@
 package golog
 
-import "strconv"
+import (
+	"reflect"
+	"strconv"
+	"time"
+)
 
 // Field is a pre-typed key/value pair that can be logged without a map
 // allocation. Use the constructor helpers (Str, Int, Uint, Float64, Bool,
@@ -15,7 +19,10 @@ type Field struct {
 	uintVal uint64
 	fltVal  float64
 	boolVal bool
-	kind    fieldKind
+	// omitEmpty drops the field from the output when its value is empty.
+	omitEmpty bool
+	anyVal    any
+	kind      fieldKind
 }
 
 type fieldKind uint8
@@ -26,6 +33,15 @@ const (
 	fieldKindUint
 	fieldKindFloat
 	fieldKindBool
+	fieldKindDuration
+	fieldKindAny
+	// Kinds from fieldKindEntryTime on are control fields: they steer how
+	// the entry is logged and are never written themselves.
+
+	// fieldKindEntryTime overrides the entry timestamp; see WithEntryTime.
+	fieldKindEntryTime
+	// fieldKindForceLog bypasses level filtering; see ForceLog.
+	fieldKindForceLog
 )
 
 // Str creates a string Field.
@@ -48,27 +64,164 @@ func Bool(key string, value bool) Field {
 	return Field{key: key, boolVal: value, kind: fieldKindBool}
 }
 
-// appendFieldBytes encodes a Field directly into dst without allocation.
+// Duration creates a time.Duration Field. It is encoded according to the
+// logger's DurationFormat (integer nanoseconds by default).
+func Duration(key string, value time.Duration) Field {
+	return Field{key: key, intVal: int64(value), kind: fieldKindDuration}
+}
+
+// Any creates a Field from an arbitrary value. Values the encoder can't
+// handle are written as "<unsupported>".
+func Any(key string, value any) Field {
+	return Field{key: key, anyVal: value, kind: fieldKindAny}
+}
+
+// Omitempty marks field to be left out of the entry when its value is empty:
+// nil, an empty string, or a zero number or duration.
+//
+//	jl.Info("request", Omitempty(Str("user_id", userID)))
+func Omitempty(field Field) Field {
+	field.omitEmpty = true
+	return field
+}
+
+// isEmpty reports whether the field holds a nil value, an empty string, or a
+// zero number. Bools are never empty: false is a meaningful value.
+func (f Field) isEmpty() bool {
+	switch f.kind {
+	case fieldKindStr:
+		return f.strVal == ""
+	case fieldKindInt, fieldKindDuration:
+		return f.intVal == 0
+	case fieldKindUint:
+		return f.uintVal == 0
+	case fieldKindFloat:
+		return f.fltVal == 0
+	case fieldKindAny:
+		return isEmptyAny(f.anyVal)
+	default:
+		return false
+	}
+}
+
+// isEmptyAny is the omitempty test for untyped values.
+func isEmptyAny(value any) bool {
+	if value == nil {
+		return true
+	}
+	reflectValue := reflect.ValueOf(value)
+	switch reflectValue.Kind() {
+	case reflect.String:
+		return reflectValue.Len() == 0
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return reflectValue.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return reflectValue.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return reflectValue.Float() == 0
+	case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+		return reflectValue.IsNil()
+	default:
+		return false
+	}
+}
+
+// Key returns the field key.
+func (f Field) Key() string {
+	return f.key
+}
+
+// Value returns the field value as the Go type it was constructed with:
+// string, int64, uint64, float64, bool, or the original value for
+// structured fields.
+func (f Field) Value() any {
+	switch f.kind {
+	case fieldKindStr:
+		return f.strVal
+	case fieldKindInt:
+		return f.intVal
+	case fieldKindUint:
+		return f.uintVal
+	case fieldKindFloat:
+		return f.fltVal
+	case fieldKindBool:
+		return f.boolVal
+	case fieldKindDuration:
+		return time.Duration(f.intVal)
+	case fieldKindEntryTime:
+		if !f.boolVal {
+			return time.Time{}
+		}
+		return time.Unix(0, f.intVal).UTC()
+	default:
+		if raw, ok := f.anyVal.(rawJSONSource); ok {
+			return raw.source
+		}
+		return f.anyVal
+	}
+}
+
+// appendFieldBytes encodes a Field with the default encoder.
 func appendFieldBytes(dst []byte, f Field) []byte {
+	return defaultEncoder.appendField(dst, f)
+}
+
+// appendField encodes a Field directly into dst without allocation. Empty
+// fields are skipped when the field or the encoder asks for omitempty.
+func (enc *encoder) appendField(dst []byte, f Field) []byte {
+	dst, _ = enc.appendFieldChecked(dst, f)
+	return dst
+}
+
+// appendFieldChecked is appendField that also returns the paths of values
+// written as "<unsupported>", for the EncodeErrorsKey field.
+func (enc *encoder) appendFieldChecked(dst []byte, f Field) ([]byte, []string) {
+	if (f.omitEmpty || enc.omitEmpty) && f.isEmpty() {
+		return dst, nil
+	}
 	dst = append(dst, ',')
-	dst = appendQuoteBytes(dst, f.key)
+	dst = enc.appendKey(dst, f.key)
 	dst = append(dst, ':')
+	return enc.appendFieldValueChecked(dst, f)
+}
+
+// appendFieldValue encodes only the value of a Field.
+func (enc *encoder) appendFieldValue(dst []byte, f Field) []byte {
+	dst, _ = enc.appendFieldValueChecked(dst, f)
+	return dst
+}
+
+// appendFieldValueChecked is appendFieldValue that also returns the paths of
+// values written as "<unsupported>", starting with the field key.
+func (enc *encoder) appendFieldValueChecked(dst []byte, f Field) ([]byte, []string) {
+	var failed []string
 	switch f.kind {
 	case fieldKindStr:
-		dst = appendQuoteBytes(dst, f.strVal)
+		dst = enc.appendStringValue(dst, f.strVal)
 	case fieldKindInt:
 		dst = strconv.AppendInt(dst, f.intVal, 10)
 	case fieldKindUint:
 		dst = strconv.AppendUint(dst, f.uintVal, 10)
 	case fieldKindFloat:
-		dst = strconv.AppendFloat(dst, f.fltVal, 'g', -1, 64)
+		var ok bool
+		dst, ok = enc.appendFloat(dst, f.fltVal, 64)
+		if !ok {
+			dst = appendQuoteBytes(dst, "<unsupported>")
+			failed = []string{f.key}
+		}
 	case fieldKindBool:
 		if f.boolVal {
 			dst = append(dst, "true"...)
 		} else {
 			dst = append(dst, "false"...)
 		}
+	case fieldKindDuration:
+		dst = enc.appendDuration(dst, time.Duration(f.intVal))
+	case fieldKindAny:
+		dst, failed = enc.appendValueLenient(dst, f.anyVal, f.key)
+	case fieldKindEntryTime, fieldKindForceLog:
+		dst, _ = enc.appendValue(dst, f.Value())
 	}
 
-	return dst
+	return dst, failed
 }
@@ -0,0 +1,42 @@
+package golog
+
+import "strings"
+
+// WithFieldPrefix namespaces every field key with prefix, e.g. "app.", so
+// application fields can't collide with fields that collectors such as
+// Kubernetes add when logs are merged downstream:
+//
+//	{"timestamp":"…","level":"info","message":"saved","app.user_id":42}
+//
+// The core timestamp, level and message keys are left alone. The prefix
+// applies to base fields and to the fields Event and Count add, in the JSON,
+// pretty, console, binary and EMF formats; keys that already start with
+// prefix are not prefixed twice. Sinks and custom LogWriters receive the
+// original keys.
+func WithFieldPrefix(prefix string) Option {
+	return func(jsonLogger *JSONLogger) {
+		jsonLogger.encoder.keyPrefix = prefix
+	}
+}
+
+// prefixedKey returns key with the encoder's prefix. It allocates when a
+// prefix is set; the JSON path uses appendKey instead.
+func (enc *encoder) prefixedKey(key string) string {
+	if enc.keyPrefix == "" || strings.HasPrefix(key, enc.keyPrefix) {
+		return key
+	}
+	return enc.keyPrefix + key
+}
+
+// appendKey quotes a field key, applying the prefix without allocating.
+func (enc *encoder) appendKey(dst []byte, key string) []byte {
+	if enc.keyPrefix == "" || strings.HasPrefix(key, enc.keyPrefix) {
+		return enc.appendString(dst, key)
+	}
+	// Quote the prefix and the key separately, then drop the closing and
+	// opening quotes between them.
+	dst = enc.appendString(dst, enc.keyPrefix)
+	join := len(dst) - 1
+	dst = enc.appendString(dst[:join], key)
+	return append(dst[:join], dst[join+1:]...)
+}
@@ -0,0 +1,53 @@
+package golog
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestWithFieldPrefix(t *testing.T) {
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(
+		WithOutput(buf),
+		WithFieldPrefix("app."),
+		WithBaseField("service", "api"),
+	)
+
+	jl.Info("saved", Int("user_id", 42), Str("app.already", "x"), Str("quote\"d", "y"))
+
+	want := `,"level":"info","message":"saved","app.service":"api","app.user_id":42,"app.already":"x","app.quote\"d":"y"}` + "\n"
+	if !strings.HasSuffix(buf.String(), want) {
+		t.Fatalf("unexpected output:\n got %s\nwant suffix %s", buf.String(), want)
+	}
+	if !strings.HasPrefix(buf.String(), `{"timestamp":"`) {
+		t.Fatalf("expected core keys to stay unprefixed: %s", buf.String())
+	}
+}
+
+func TestFieldPrefixInOtherFormats(t *testing.T) {
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(WithOutput(buf), WithFieldPrefix("app."), WithLogWriter(&ConsoleLogWriter{Color: ColorNever}))
+	jl.Info("saved", Int("user_id", 42))
+	if !strings.Contains(buf.String(), " app.user_id=42") {
+		t.Fatalf("expected prefixed console keys: %q", buf.String())
+	}
+
+	buf.Reset()
+	jl = NewJSONLoggerWithOptions(WithOutput(buf), WithFieldPrefix("app."), WithPrettyJSON())
+	jl.Info("saved", Int("user_id", 42))
+	if !strings.Contains(buf.String(), `"app.user_id": 42`) || !strings.Contains(buf.String(), `"message": "saved"`) {
+		t.Fatalf("expected prefixed pretty keys: %s", buf.String())
+	}
+}
+
+func TestFieldPrefixDoesNotAllocate(t *testing.T) {
+	jl := NewJSONLoggerWithOptions(WithOutput(io.Discard), WithFieldPrefix("app."))
+	allocs := testing.AllocsPerRun(100, func() {
+		jl.Info("hot path", Str("k", "v"), Int("n", 1))
+	})
+	if allocs != 0 {
+		t.Fatalf("expected no allocations, got %v", allocs)
+	}
+}
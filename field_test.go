@@ -1,6 +1,9 @@
 package golog
 
-import "testing"
+import (
+	"testing"
+	"time"
+)
 
 func TestFieldConstructorsAndAppendFieldBytes(t *testing.T) {
 	tests := []struct {
@@ -13,6 +16,9 @@ func TestFieldConstructorsAndAppendFieldBytes(t *testing.T) {
 		{name: "float64", f: Float64("pi", 3.14), want: `,"pi":3.14`},
 		{name: "bool true", f: Bool("ok", true), want: `,"ok":true`},
 		{name: "bool false", f: Bool("ok", false), want: `,"ok":false`},
+		{name: "duration", f: Duration("d", 2*time.Millisecond), want: `,"d":2000000`},
+		{name: "any map", f: Any("m", map[string]any{"a": 1}), want: `,"m":{"a":1}`},
+		{name: "any unsupported", f: Any("c", make(chan int)), want: `,"c":"<unsupported>"`},
 	}
 
 	for _, tc := range tests {
@@ -33,3 +39,56 @@ func TestAppendFieldBytesEscapesKeyAndStringValue(t *testing.T) {
 		t.Fatalf("escaped field mismatch: got %q want %q", got, want)
 	}
 }
+
+func TestFieldKeyAndValue(t *testing.T) {
+	tests := []struct {
+		name string
+		f    Field
+		want any
+	}{
+		{name: "string", f: Str("k", "v"), want: "v"},
+		{name: "int", f: Int("k", 3), want: int64(3)},
+		{name: "float64", f: Float64("k", 1.5), want: 1.5},
+		{name: "bool", f: Bool("k", true), want: true},
+		{name: "duration", f: Duration("k", time.Second), want: time.Second},
+		{name: "any", f: Any("k", "x"), want: "x"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.f.Key() != "k" {
+				t.Fatalf("expected key k, got %q", tc.f.Key())
+			}
+			if tc.f.Value() != tc.want {
+				t.Fatalf("expected value %#v, got %#v", tc.want, tc.f.Value())
+			}
+		})
+	}
+}
+
+func TestOmitemptyField(t *testing.T) {
+	tests := []struct {
+		name string
+		f    Field
+		want string
+	}{
+		{name: "empty string", f: Omitempty(Str("k", "")), want: ``},
+		{name: "set string", f: Omitempty(Str("k", "v")), want: `,"k":"v"`},
+		{name: "zero int", f: Omitempty(Int("k", 0)), want: ``},
+		{name: "zero float", f: Omitempty(Float64("k", 0)), want: ``},
+		{name: "false bool kept", f: Omitempty(Bool("k", false)), want: `,"k":false`},
+		{name: "nil any", f: Omitempty(Any("k", nil)), want: ``},
+		{name: "nil pointer", f: Omitempty(Any("k", (*int)(nil))), want: ``},
+		{name: "zero duration", f: Omitempty(Duration("k", 0)), want: ``},
+		{name: "not marked", f: Str("k", ""), want: `,"k":""`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := string(appendFieldBytes(nil, tc.f))
+			if got != tc.want {
+				t.Fatalf("expected %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package golog
+
+// FilterFunc decides whether an entry should be logged. Return true to keep
+// the entry and false to drop it.
+type FilterFunc func(level Level, message string, fields []Field) bool
+
+// filterLogger drops entries rejected by keep before they reach next.
+type filterLogger struct {
+	next Logger
+	keep FilterFunc
+}
+
+// Filter wraps l so that only entries accepted by keep are forwarded. Use it
+// to drop noisy categories (health checks, specific message prefixes) without
+// touching call sites. A nil keep forwards everything.
+//
+// The fields slice passed to keep is the caller's slice; keep must not retain
+// or modify it.
+func Filter(l Logger, keep FilterFunc) Logger {
+	if keep == nil {
+		return l
+	}
+	return &filterLogger{next: l, keep: keep}
+}
+
+func (filter *filterLogger) Info(message string, fields ...Field) {
+	if filter.keep(InfoLevel, message, fields) {
+		filter.next.Info(message, fields...)
+	}
+}
+
+func (filter *filterLogger) Warn(message string, fields ...Field) {
+	if filter.keep(WarnLevel, message, fields) {
+		filter.next.Warn(message, fields...)
+	}
+}
+
+func (filter *filterLogger) Error(message string, fields ...Field) {
+	if filter.keep(ErrorLevel, message, fields) {
+		filter.next.Error(message, fields...)
+	}
+}
+
+func (filter *filterLogger) Debug(message string, fields ...Field) {
+	if filter.keep(DebugLevel, message, fields) {
+		filter.next.Debug(message, fields...)
+	}
+}
+
+// Enabled reports whether the wrapped logger writes entries at level; keep
+// may still drop them.
+func (filter *filterLogger) Enabled(level Level) bool {
+	return enabled(filter.next, level)
+}
+
+// Flush flushes the wrapped logger.
+func (filter *filterLogger) Flush() error {
+	return flushLogger(filter.next)
+}
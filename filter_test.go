@@ -0,0 +1,90 @@
+package golog
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestFilterDropsRejectedEntries(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l := Filter(&BLogger{b: buf}, func(level Level, message string, fields []Field) bool {
+		if strings.HasPrefix(message, "healthcheck") {
+			return false
+		}
+		for _, f := range fields {
+			if f.Key() == "path" && f.Value() == "/ready" {
+				return false
+			}
+		}
+		return true
+	})
+
+	l.Info("healthcheck ok")
+	l.Info("request", Str("path", "/ready"))
+	l.Info("request", Str("path", "/orders"))
+	l.Warn("slow")
+	l.Error("failed")
+	l.Debug("details")
+
+	want := "I:request\nW:slow\nE:failed\nD:details\n"
+	if buf.String() != want {
+		t.Fatalf("expected %q, got %q", want, buf.String())
+	}
+}
+
+func TestFilterPassesLevel(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l := Filter(&BLogger{b: buf}, func(level Level, _ string, _ []Field) bool {
+		return level >= WarnLevel
+	})
+
+	l.Debug("d")
+	l.Info("i")
+	l.Warn("w")
+	l.Error("e")
+
+	if buf.String() != "W:w\nE:e\n" {
+		t.Fatalf("unexpected output: %q", buf.String())
+	}
+}
+
+func TestFilterNilKeepReturnsLogger(t *testing.T) {
+	inner := &BLogger{b: &bytes.Buffer{}}
+	if Filter(inner, nil) != Logger(inner) {
+		t.Fatalf("expected Filter with nil predicate to return the wrapped logger")
+	}
+}
+
+type flushCountingLogger struct {
+	nopLogger
+	flushes int
+}
+
+func (l *flushCountingLogger) Flush() error {
+	l.flushes++
+	return nil
+}
+
+func TestFilterForwardsEnabledAndFlush(t *testing.T) {
+	jl := NewJSONLoggerWithOptions(WithOutput(io.Discard), WithLevel(WarnLevel))
+	filtered := Filter(jl, func(Level, string, []Field) bool { return true })
+	leveled, ok := filtered.(LeveledLogger)
+	if !ok || leveled.Enabled(InfoLevel) || !leveled.Enabled(ErrorLevel) {
+		t.Fatalf("expected Filter to report the wrapped logger's levels")
+	}
+
+	plain := Filter(&BLogger{b: &bytes.Buffer{}}, func(Level, string, []Field) bool { return true })
+	if !plain.(LeveledLogger).Enabled(DebugLevel) {
+		t.Fatalf("expected loggers without Enabled to count as enabled")
+	}
+
+	counting := &flushCountingLogger{}
+	if err := Filter(counting, func(Level, string, []Field) bool { return true }).(FlushLogger).Flush(); err != nil || counting.flushes != 1 {
+		t.Fatalf("expected Flush to be forwarded, got %d flushes (err=%v)", counting.flushes, err)
+	}
+	if Nop().(LeveledLogger).Enabled(ErrorLevel) {
+		t.Fatalf("expected Nop to report every level disabled")
+	}
+}
@@ -0,0 +1,83 @@
+package golog
+
+import (
+	"io"
+	"sync"
+)
+
+// flightRecorder keeps the most recent entries that were filtered out by the
+// logger's level, already formatted, in a fixed-size ring. Slots are reused so
+// recording doesn't allocate once the ring has warmed up.
+type flightRecorder struct {
+	mutex   sync.Mutex
+	records [][]byte
+	next    int
+	count   int
+}
+
+// WithFlightRecorder keeps the last size entries below the logger's level
+// (typically Debug) in memory instead of dropping them. They are not written
+// until DumpRecent is called, or an error is logged with WithDumpOnError, so
+// production logs stay quiet while the detailed context leading up to a
+// failure is still available. A size of zero or less disables the recorder.
+func WithFlightRecorder(size int) Option {
+	return func(jsonLogger *JSONLogger) {
+		if size <= 0 {
+			jsonLogger.flightRecorder = nil
+			return
+		}
+		jsonLogger.flightRecorder = &flightRecorder{records: make([][]byte, size)}
+	}
+}
+
+// WithDumpOnError writes the flight recorder's entries to the output right
+// before every error entry, oldest first. It has no effect without
+// WithFlightRecorder.
+func WithDumpOnError() Option {
+	return func(jsonLogger *JSONLogger) {
+		jsonLogger.dumpOnError = true
+	}
+}
+
+// DumpRecent writes the entries held by the flight recorder to w, oldest
+// first, and empties the recorder.
+func (jsonLogger *JSONLogger) DumpRecent(w io.Writer) error {
+	recorder := jsonLogger.current().flightRecorder
+	if recorder == nil {
+		return nil
+	}
+	var err error
+	recorder.drain(func(record []byte) {
+		if err == nil {
+			_, err = w.Write(record)
+		}
+	})
+	return err
+}
+
+// record formats an entry into the next ring slot, overwriting the oldest
+// entry once the ring is full.
+func (recorder *flightRecorder) record(jsonLogger *JSONLogger, logLevel Level, message string, fields []Field) {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+
+	recorder.records[recorder.next] = jsonLogger.appendEntry(recorder.records[recorder.next][:0], 0, logLevel, message, fields)
+	recorder.next = (recorder.next + 1) % len(recorder.records)
+	if recorder.count < len(recorder.records) {
+		recorder.count++
+	}
+}
+
+// drain hands every held record to write, oldest first, and empties the
+// ring.
+func (recorder *flightRecorder) drain(write func(record []byte)) {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+
+	size := len(recorder.records)
+	start := (recorder.next - recorder.count + size) % size
+	for i := 0; i < recorder.count; i++ {
+		write(recorder.records[(start+i)%size])
+	}
+	recorder.count = 0
+}
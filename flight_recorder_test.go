@@ -0,0 +1,71 @@
+package golog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestFlightRecorderKeepsLastEntries(t *testing.T) {
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(WithOutput(buf), WithFlightRecorder(3))
+
+	for _, step := range []string{"a", "b", "c", "d", "e"} {
+		jl.Debug("step", Str("step", step))
+	}
+	jl.Info("visible")
+
+	if strings.Contains(buf.String(), `"step"`) {
+		t.Fatalf("expected debug entries to be held back, got %s", buf.String())
+	}
+
+	dump := &bytes.Buffer{}
+	if err := jl.DumpRecent(dump); err != nil {
+		t.Fatalf("dump: %v", err)
+	}
+	var steps []string
+	for _, entry := range readEntries(t, dump.Bytes()) {
+		if entry.Level != DebugLevel {
+			t.Fatalf("unexpected level in dump: %v", entry.Level)
+		}
+		field, _ := entry.Field("step")
+		steps = append(steps, field.Value().(string))
+	}
+	if strings.Join(steps, ",") != "c,d,e" {
+		t.Fatalf("expected the last three entries oldest first, got %v", steps)
+	}
+
+	dump.Reset()
+	if err := jl.DumpRecent(dump); err != nil || dump.Len() != 0 {
+		t.Fatalf("expected dump to empty the recorder, got %q (%v)", dump.String(), err)
+	}
+}
+
+func TestFlightRecorderDumpOnError(t *testing.T) {
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(WithOutput(buf), WithFlightRecorder(8), WithDumpOnError())
+
+	jl.Debug("cache miss")
+	jl.Debug("retrying upstream")
+	jl.Error("request failed")
+	jl.Error("second failure")
+
+	entries := readEntries(t, buf.Bytes())
+	var messages []string
+	for _, entry := range entries {
+		messages = append(messages, entry.Message)
+	}
+	if strings.Join(messages, ",") != "cache miss,retrying upstream,request failed,second failure" {
+		t.Fatalf("unexpected output order: %v", messages)
+	}
+}
+
+func TestFlightRecorderDisabledByDefault(t *testing.T) {
+	jl := NewJSONLoggerWithOptions(WithOutput(&bytes.Buffer{}), WithDumpOnError())
+	jl.Debug("dropped")
+
+	dump := &bytes.Buffer{}
+	if err := jl.DumpRecent(dump); err != nil || dump.Len() != 0 {
+		t.Fatalf("expected nothing to dump, got %q (%v)", dump.String(), err)
+	}
+}
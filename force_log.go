@@ -0,0 +1,23 @@
+package golog
+
+// ForceLog returns a field that makes the entry bypass level filtering, for
+// must-log lines such as security and billing audit records that can't be
+// lost to verbosity settings:
+//
+//	jl.Info("refund issued", ForceLog(), Str("order_id", id))
+//
+// The entry keeps its own level and the field itself is not written. Only
+// OffLevel, which disables logging entirely, still drops it.
+func ForceLog() Field {
+	return Field{kind: fieldKindForceLog}
+}
+
+// forced reports whether fields contain ForceLog.
+func forced(fields []Field) bool {
+	for i := range fields {
+		if fields[i].kind == fieldKindForceLog {
+			return true
+		}
+	}
+	return false
+}
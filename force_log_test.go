@@ -0,0 +1,34 @@
+package golog
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func TestForceLogBypassesLevel(t *testing.T) {
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(WithOutput(buf), WithLevel(ErrorLevel), WithDevelopmentMode())
+
+	jl.Info("dropped")
+	jl.Info("refund issued", ForceLog(), Str("order_id", "o-1"))
+	jl.DebugContext(context.Background(), "login failed", Str("user", "bob"), ForceLog())
+
+	entries := readEntries(t, buf.Bytes())
+	if len(entries) != 2 {
+		t.Fatalf("expected two forced entries, got %+v", entries)
+	}
+	if entries[0].Level != InfoLevel || entries[0].Message != "refund issued" || len(entries[0].Fields) != 1 {
+		t.Fatalf("unexpected forced entry: %+v", entries[0])
+	}
+	if entries[1].Level != DebugLevel || len(entries[1].Fields) != 1 {
+		t.Fatalf("expected the entry to keep its level and drop the marker: %+v", entries[1])
+	}
+
+	buf.Reset()
+	jl.SetLevel(OffLevel)
+	jl.Error("audit", ForceLog())
+	if buf.Len() != 0 {
+		t.Fatalf("expected OffLevel to drop forced entries: %s", buf.String())
+	}
+}
@@ -0,0 +1,140 @@
+package golog
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// GoldenUpdateEnv names the environment variable that makes Golden rewrite
+// golden files instead of comparing against them:
+//
+//	GOLOG_UPDATE_GOLDEN=1 go test ./...
+const GoldenUpdateEnv = "GOLOG_UPDATE_GOLDEN"
+
+// deterministicTime is the timestamp WithDeterministic gives every entry.
+var deterministicTime = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// WithDeterministic makes output reproducible from run to run, for golden
+// files and examples: every entry is timestamped 2000-01-01T00:00:00Z,
+// per-call fields and the members of maps are sorted by key (base fields
+// always are) and entries are numbered from 1 under SequenceKey.
+// WithGoroutineID is turned off. Entries of the same logger must be written
+// from one goroutine for the sequence to be stable. Sorting costs an
+// allocation for each entry whose fields aren't already in order and for
+// each map.
+func WithDeterministic() Option {
+	return func(jsonLogger *JSONLogger) {
+		jsonLogger.clock = func() time.Time { return deterministicTime }
+		jsonLogger.sortFields = true
+		jsonLogger.encoder.sortMapKeys = true
+		jsonLogger.sequenceNumbers = true
+		jsonLogger.sequence.Store(0)
+		jsonLogger.goroutineID = false
+	}
+}
+
+// sortedFields returns fields sorted by key when WithDeterministic is set.
+// Fields with the same key keep their order, so later ones still win.
+func (jsonLogger *JSONLogger) sortedFields(fields []Field) []Field {
+	if !jsonLogger.sortFields || slices.IsSortedFunc(fields, compareFieldKeys) {
+		return fields
+	}
+	sorted := slices.Clone(fields)
+	slices.SortStableFunc(sorted, compareFieldKeys)
+	return sorted
+}
+
+func compareFieldKeys(a, b Field) int {
+	return strings.Compare(a.key, b.key)
+}
+
+// Golden snapshot-tests the entries jsonLogger writes during the test. It
+// switches the logger to WithDeterministic and captures its output; when
+// the test finishes, the output is compared with
+// testdata/<test name>.golden and the test fails on any difference. With
+// GoldenUpdateEnv set the file is written instead:
+//
+//	func TestCheckoutLogs(t *testing.T) {
+//	    jl := golog.NewJSONLogger()
+//	    golog.Golden(t, jl)
+//	    checkout.New(jl).Run()
+//	}
+//
+// Call it before the logger is first used. Subtests get a directory per
+// parent test.
+func Golden(tb testing.TB, jsonLogger *JSONLogger) {
+	tb.Helper()
+	output := &lockedGoldenBuffer{}
+	if err := jsonLogger.Reconfigure(WithOutput(output), WithDeterministic()); err != nil {
+		tb.Fatalf("golog.Golden: %v", err)
+	}
+	path := filepath.Join("testdata", filepath.FromSlash(tb.Name())+".golden")
+	tb.Cleanup(func() {
+		_ = jsonLogger.Flush()
+		checkGolden(tb, path, output.bytes())
+	})
+}
+
+// checkGolden compares got with the golden file at path, or rewrites it
+// when GoldenUpdateEnv is set.
+func checkGolden(tb testing.TB, path string, got []byte) {
+	tb.Helper()
+	if os.Getenv(GoldenUpdateEnv) != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			tb.Fatalf("golog.Golden: %v", err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			tb.Fatalf("golog.Golden: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		tb.Errorf("golog.Golden: %v; run with %s=1 to create it", err, GoldenUpdateEnv)
+		return
+	}
+	if bytes.Equal(got, want) {
+		return
+	}
+	gotLines := strings.Split(string(got), "\n")
+	wantLines := strings.Split(string(want), "\n")
+	line := 0
+	for line < len(gotLines) && line < len(wantLines) && gotLines[line] == wantLines[line] {
+		line++
+	}
+	tb.Errorf("golog.Golden: output differs from %s at line %d:\n got: %s\nwant: %s\nrun with %s=1 to update it",
+		path, line+1, lineAt(gotLines, line), lineAt(wantLines, line), GoldenUpdateEnv)
+}
+
+func lineAt(lines []string, index int) string {
+	if index >= len(lines) {
+		return "<end of output>"
+	}
+	return lines[index]
+}
+
+// lockedGoldenBuffer collects output for Golden. Loggers configured with
+// WithWriteLock(false) may write to it concurrently.
+type lockedGoldenBuffer struct {
+	mutex  sync.Mutex
+	buffer bytes.Buffer
+}
+
+func (buffer *lockedGoldenBuffer) Write(p []byte) (int, error) {
+	buffer.mutex.Lock()
+	defer buffer.mutex.Unlock()
+	return buffer.buffer.Write(p)
+}
+
+func (buffer *lockedGoldenBuffer) bytes() []byte {
+	buffer.mutex.Lock()
+	defer buffer.mutex.Unlock()
+	return bytes.Clone(buffer.buffer.Bytes())
+}
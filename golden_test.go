@@ -0,0 +1,115 @@
+package golog
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWithDeterministic(t *testing.T) {
+	run := func() string {
+		buf := &bytes.Buffer{}
+		jl := NewJSONLoggerWithOptions(WithOutput(buf), WithGoroutineID(), WithDeterministic())
+		jl.Info("first", Str("zeta", "z"), Int("alpha", 1), Str("alpha", "later"))
+		jl.Warn("second")
+		return buf.String()
+	}
+
+	got := run()
+	want := `{"timestamp":"2000-01-01T00:00:00Z","level":"info","message":"first","seq":1,"alpha":1,"alpha":"later","zeta":"z"}` + "\n" +
+		`{"timestamp":"2000-01-01T00:00:00Z","level":"warn","message":"second","seq":2}` + "\n"
+	if got != want {
+		t.Fatalf("expected\n%s\ngot\n%s", want, got)
+	}
+	if again := run(); again != got {
+		t.Fatalf("expected identical output across runs, got\n%s", again)
+	}
+}
+
+func TestGolden(t *testing.T) {
+	t.Chdir(t.TempDir())
+	logEntries := func(tb *fakeTB, message string) {
+		jl := NewJSONLogger()
+		Golden(tb, jl)
+		jl.Info(message, Int("b", 2), Int("a", 1))
+		tb.finish()
+	}
+
+	t.Setenv(GoldenUpdateEnv, "1")
+	update := &fakeTB{}
+	logEntries(update, "created")
+	path := filepath.Join("testdata", "TestParent", "sub.golden")
+	data, err := os.ReadFile(path)
+	if err != nil || !strings.Contains(string(data), `"a":1,"b":2`) || len(update.errors) != 0 {
+		t.Fatalf("expected the golden file to be written, got %q (err=%v, errors=%v)", data, err, update.errors)
+	}
+
+	t.Setenv(GoldenUpdateEnv, "")
+	match := &fakeTB{}
+	logEntries(match, "created")
+	if len(match.errors) != 0 {
+		t.Fatalf("expected matching output to pass, got %v", match.errors)
+	}
+
+	mismatch := &fakeTB{}
+	logEntries(mismatch, "changed")
+	if len(mismatch.errors) != 1 || !strings.Contains(mismatch.errors[0], "line 1") || !strings.Contains(mismatch.errors[0], `"message":"changed"`) {
+		t.Fatalf("expected a mismatch to be reported, got %v", mismatch.errors)
+	}
+
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	missing := &fakeTB{}
+	logEntries(missing, "created")
+	if len(missing.errors) != 1 || !strings.Contains(missing.errors[0], GoldenUpdateEnv) {
+		t.Fatalf("expected a missing golden file to be reported, got %v", missing.errors)
+	}
+}
+
+func TestGoldenStableOrder(t *testing.T) {
+	t.Chdir(t.TempDir())
+	baseFields := map[string]any{"service": "api", "env": "prod", "region": "eu", "version": "1.2", "app": "shop"}
+	logEntries := func(tb *fakeTB) {
+		jl := NewJSONLoggerWithOptions(WithBaseFields(baseFields))
+		Golden(tb, jl)
+		jl.Info("order",
+			Any("cart", map[string]any{"z": 1, "y": map[string]int{"d": 4, "c": 3, "b": 2}, "x": []any{map[string]string{"q": "1", "p": "2"}}}),
+			Any("labels", map[string]bool{"beta": true, "alpha": false, "gamma": true}),
+		)
+		tb.finish()
+	}
+
+	t.Setenv(GoldenUpdateEnv, "1")
+	update := &fakeTB{}
+	logEntries(update)
+	data, err := os.ReadFile(filepath.Join("testdata", "TestParent", "sub.golden"))
+	if err != nil || len(update.errors) != 0 {
+		t.Fatalf("expected the golden file to be written: %v %v", err, update.errors)
+	}
+	want := `"app":"shop","env":"prod","region":"eu","service":"api","version":"1.2",` +
+		`"cart":{"x":[{"p":"2","q":"1"}],"y":{"b":2,"c":3,"d":4},"z":1},"labels":{"alpha":false,"beta":true,"gamma":true}}`
+	if !strings.Contains(string(data), want) {
+		t.Fatalf("expected sorted base fields and map members, got %s", data)
+	}
+
+	t.Setenv(GoldenUpdateEnv, "")
+	for range 20 {
+		match := &fakeTB{}
+		logEntries(match)
+		if len(match.errors) != 0 {
+			t.Fatalf("expected the same output on every run, got %v", match.errors)
+		}
+	}
+}
+
+func TestWithDeterministicMaxFields(t *testing.T) {
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(WithOutput(buf), WithDeterministic(), WithMaxFields(2))
+	jl.Info("capped", Any("m", map[string]any{"d": 4, "a": 1, "c": 3, "b": 2}))
+	if !strings.Contains(buf.String(), `"m":{"a":1,"b":2,`) {
+		t.Fatalf("expected the first keys in order to be kept, got %s", buf.String())
+	}
+}
@@ -0,0 +1,275 @@
+// Package gologecho adapts golog to the echo web framework. It is a module
+// of its own, so golog itself doesn't depend on echo:
+//
+//	e := echo.New()
+//	e.Logger = gologecho.NewLogger(jl)
+//	e.Use(echo.WrapMiddleware(golog.AccessLog(jl)))
+//
+// Echo and handlers calling c.Logger() then write structured entries
+// through jl, and golog.AccessLog logs every request.
+package gologecho
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/KostLabs/golog"
+	"github.com/labstack/gommon/log"
+)
+
+// exit ends the process after a Fatal entry; tests replace it.
+var exit = os.Exit
+
+// Logger implements echo.Logger by writing to a golog.Logger. Messages
+// are formatted as by fmt.Sprint or fmt.Sprintf, and the *j methods log the
+// "message" member of their map as the message and the other members as
+// fields. A non-empty prefix is logged as the golog.KeyComponent field.
+//
+// The level set with SetLevel filters entries before they reach the golog
+// logger, whose own level still applies; log.OFF drops everything. Print
+// logs at info level, Fatal and Panic at error level before exiting or
+// panicking. SetHeader does nothing: formatting is up to the golog logger.
+// It is safe for concurrent use.
+type Logger struct {
+	logger golog.Logger
+	level  atomic.Uint32
+
+	mutex  sync.RWMutex
+	prefix string
+	output io.Writer
+}
+
+// NewLogger returns a Logger writing to l at every level. Its Output is a
+// golog.NewLineWriter logging each line at info level, so echo's standard
+// library logger writes into l as well.
+func NewLogger(l golog.Logger) *Logger {
+	adapter := &Logger{logger: l, output: golog.NewLineWriter(l, golog.InfoLevel)}
+	adapter.level.Store(uint32(log.DEBUG))
+	return adapter
+}
+
+// Output returns the writer set with SetOutput.
+func (adapter *Logger) Output() io.Writer {
+	adapter.mutex.RLock()
+	defer adapter.mutex.RUnlock()
+	return adapter.output
+}
+
+// SetOutput sets the writer Output returns. Entries are still written to
+// the golog logger.
+func (adapter *Logger) SetOutput(w io.Writer) {
+	adapter.mutex.Lock()
+	defer adapter.mutex.Unlock()
+	adapter.output = w
+}
+
+// Prefix returns the prefix logged as the component field.
+func (adapter *Logger) Prefix() string {
+	adapter.mutex.RLock()
+	defer adapter.mutex.RUnlock()
+	return adapter.prefix
+}
+
+// SetPrefix sets the prefix logged as the component field.
+func (adapter *Logger) SetPrefix(p string) {
+	adapter.mutex.Lock()
+	defer adapter.mutex.Unlock()
+	adapter.prefix = p
+}
+
+// Level returns the level set with SetLevel.
+func (adapter *Logger) Level() log.Lvl {
+	return log.Lvl(adapter.level.Load())
+}
+
+// SetLevel sets the minimum level of the entries passed on.
+func (adapter *Logger) SetLevel(v log.Lvl) {
+	adapter.level.Store(uint32(v))
+}
+
+// SetHeader does nothing; see Logger.
+func (adapter *Logger) SetHeader(string) {}
+
+// Print logs the operands at info level.
+func (adapter *Logger) Print(i ...any) { adapter.log(log.INFO, fmt.Sprint(i...), nil) }
+
+// Printf logs the formatted message at info level.
+func (adapter *Logger) Printf(format string, args ...any) {
+	adapter.log(log.INFO, fmt.Sprintf(format, args...), nil)
+}
+
+// Printj logs j at info level.
+func (adapter *Logger) Printj(j log.JSON) { adapter.logJSON(log.INFO, j) }
+
+// Debug logs the operands at debug level.
+func (adapter *Logger) Debug(i ...any) { adapter.log(log.DEBUG, fmt.Sprint(i...), nil) }
+
+// Debugf logs the formatted message at debug level.
+func (adapter *Logger) Debugf(format string, args ...any) {
+	adapter.log(log.DEBUG, fmt.Sprintf(format, args...), nil)
+}
+
+// Debugj logs j at debug level.
+func (adapter *Logger) Debugj(j log.JSON) { adapter.logJSON(log.DEBUG, j) }
+
+// Info logs the operands at info level.
+func (adapter *Logger) Info(i ...any) { adapter.log(log.INFO, fmt.Sprint(i...), nil) }
+
+// Infof logs the formatted message at info level.
+func (adapter *Logger) Infof(format string, args ...any) {
+	adapter.log(log.INFO, fmt.Sprintf(format, args...), nil)
+}
+
+// Infoj logs j at info level.
+func (adapter *Logger) Infoj(j log.JSON) { adapter.logJSON(log.INFO, j) }
+
+// Warn logs the operands at warn level.
+func (adapter *Logger) Warn(i ...any) { adapter.log(log.WARN, fmt.Sprint(i...), nil) }
+
+// Warnf logs the formatted message at warn level.
+func (adapter *Logger) Warnf(format string, args ...any) {
+	adapter.log(log.WARN, fmt.Sprintf(format, args...), nil)
+}
+
+// Warnj logs j at warn level.
+func (adapter *Logger) Warnj(j log.JSON) { adapter.logJSON(log.WARN, j) }
+
+// Error logs the operands at error level.
+func (adapter *Logger) Error(i ...any) { adapter.log(log.ERROR, fmt.Sprint(i...), nil) }
+
+// Errorf logs the formatted message at error level.
+func (adapter *Logger) Errorf(format string, args ...any) {
+	adapter.log(log.ERROR, fmt.Sprintf(format, args...), nil)
+}
+
+// Errorj logs j at error level.
+func (adapter *Logger) Errorj(j log.JSON) { adapter.logJSON(log.ERROR, j) }
+
+// Fatal logs the operands at error level, flushes the logger and exits
+// with status 1.
+func (adapter *Logger) Fatal(i ...any) {
+	adapter.fatal(fmt.Sprint(i...), nil)
+}
+
+// Fatalf is Fatal with a formatted message.
+func (adapter *Logger) Fatalf(format string, args ...any) {
+	adapter.fatal(fmt.Sprintf(format, args...), nil)
+}
+
+// Fatalj is Fatal with a JSON map.
+func (adapter *Logger) Fatalj(j log.JSON) {
+	message, fields := jsonFields(j)
+	adapter.fatal(message, fields)
+}
+
+// Panic logs the operands at error level and panics with the message.
+func (adapter *Logger) Panic(i ...any) {
+	adapter.panic(fmt.Sprint(i...), nil)
+}
+
+// Panicf is Panic with a formatted message.
+func (adapter *Logger) Panicf(format string, args ...any) {
+	adapter.panic(fmt.Sprintf(format, args...), nil)
+}
+
+// Panicj is Panic with a JSON map.
+func (adapter *Logger) Panicj(j log.JSON) {
+	message, fields := jsonFields(j)
+	adapter.panic(message, fields)
+}
+
+func (adapter *Logger) fatal(message string, fields []golog.Field) {
+	adapter.write(log.ERROR, message, fields)
+	if flusher, ok := adapter.logger.(golog.FlushLogger); ok {
+		_ = flusher.Flush()
+	}
+	exit(1)
+}
+
+func (adapter *Logger) panic(message string, fields []golog.Field) {
+	adapter.write(log.ERROR, message, fields)
+	panic(message)
+}
+
+func (adapter *Logger) logJSON(level log.Lvl, j log.JSON) {
+	if !adapter.enabled(level) {
+		return
+	}
+	message, fields := jsonFields(j)
+	adapter.write(level, message, fields)
+}
+
+func (adapter *Logger) log(level log.Lvl, message string, fields []golog.Field) {
+	if !adapter.enabled(level) {
+		return
+	}
+	adapter.write(level, message, fields)
+}
+
+// enabled reports whether the adapter's level and the golog logger both let
+// an entry at level through.
+func (adapter *Logger) enabled(level log.Lvl) bool {
+	configured := adapter.Level()
+	if configured == log.OFF || level < configured {
+		return false
+	}
+	if leveled, ok := adapter.logger.(golog.LeveledLogger); ok {
+		return leveled.Enabled(gologLevel(level))
+	}
+	return true
+}
+
+// write logs an entry without checking the level, adding the prefix.
+func (adapter *Logger) write(level log.Lvl, message string, fields []golog.Field) {
+	if prefix := adapter.Prefix(); prefix != "" {
+		fields = append(fields, golog.Str(golog.KeyComponent, prefix))
+	}
+	switch gologLevel(level) {
+	case golog.DebugLevel:
+		adapter.logger.Debug(message, fields...)
+	case golog.InfoLevel:
+		adapter.logger.Info(message, fields...)
+	case golog.WarnLevel:
+		adapter.logger.Warn(message, fields...)
+	default:
+		adapter.logger.Error(message, fields...)
+	}
+}
+
+// gologLevel maps an echo level to a golog level.
+func gologLevel(level log.Lvl) golog.Level {
+	switch level {
+	case log.DEBUG:
+		return golog.DebugLevel
+	case log.INFO:
+		return golog.InfoLevel
+	case log.WARN:
+		return golog.WarnLevel
+	default:
+		return golog.ErrorLevel
+	}
+}
+
+// jsonFields splits j into its "message" member, formatted with fmt, and
+// the other members as fields sorted by key.
+func jsonFields(j log.JSON) (string, []golog.Field) {
+	var message string
+	if value, ok := j["message"]; ok {
+		message = fmt.Sprint(value)
+	}
+	fields := make([]golog.Field, 0, len(j))
+	for key, value := range j {
+		if key != "message" {
+			fields = append(fields, golog.Any(key, value))
+		}
+	}
+	slices.SortFunc(fields, func(a, b golog.Field) int {
+		return strings.Compare(a.Key(), b.Key())
+	})
+	return message, fields
+}
@@ -0,0 +1,81 @@
+package gologecho
+
+import (
+	"os"
+	"testing"
+
+	"github.com/KostLabs/golog"
+	"github.com/KostLabs/golog/gologtest"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+)
+
+var _ echo.Logger = (*Logger)(nil)
+
+func TestLoggerWritesEntries(t *testing.T) {
+	recorder := gologtest.NewRecorder()
+	adapter := NewLogger(recorder)
+	adapter.SetPrefix("echo")
+	adapter.SetLevel(log.INFO)
+
+	adapter.Debug("filtered")
+	adapter.Infof("listening on %s", ":8080")
+	adapter.Warnj(log.JSON{"message": "slow request", "path": "/users", "ms": 1200})
+	adapter.Error("failed", 42)
+
+	entries := recorder.Entries()
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(entries))
+	}
+	if entries[0].Level != golog.InfoLevel || entries[0].Message != "listening on :8080" {
+		t.Fatalf("unexpected entry: %+v", entries[0])
+	}
+	warn := entries[1].FieldMap()
+	if entries[1].Message != "slow request" || warn["path"] != "/users" || warn["ms"] != 1200 || warn[golog.KeyComponent] != "echo" {
+		t.Fatalf("unexpected entry: %s %v", entries[1].Message, warn)
+	}
+	if entries[2].Level != golog.ErrorLevel || entries[2].Message != "failed42" {
+		t.Fatalf("unexpected entry: %+v", entries[2])
+	}
+
+	adapter.SetLevel(log.OFF)
+	adapter.Error("dropped")
+	if len(recorder.Entries()) != 3 {
+		t.Fatalf("expected log.OFF to drop entries")
+	}
+}
+
+func TestLoggerFatalAndPanic(t *testing.T) {
+	recorder := gologtest.NewRecorder()
+	adapter := NewLogger(recorder)
+
+	var code int
+	exit = func(status int) { code = status }
+	defer func() { exit = os.Exit }()
+	adapter.Fatalf("config %s missing", "db")
+	if code != 1 {
+		t.Fatalf("expected exit status 1, got %d", code)
+	}
+
+	func() {
+		defer func() {
+			if recovered := recover(); recovered != "boom" {
+				t.Fatalf("expected a panic with the message, got %v", recovered)
+			}
+		}()
+		adapter.Panic("boom")
+	}()
+
+	recorder.AssertLogged(t, golog.ErrorLevel, "config db missing")
+	recorder.AssertLogged(t, golog.ErrorLevel, "boom")
+}
+
+func TestOutputLogsLines(t *testing.T) {
+	recorder := gologtest.NewRecorder()
+	adapter := NewLogger(recorder)
+
+	if _, err := adapter.Output().Write([]byte("http: TLS handshake error\n")); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	recorder.AssertLogged(t, golog.InfoLevel, "TLS handshake error")
+}
@@ -0,0 +1,93 @@
+// Package gologgin adapts golog to the gin web framework. It is a module of
+// its own, so golog itself doesn't depend on gin:
+//
+//	r := gin.New()
+//	r.Use(gologgin.AccessLog(jl), gin.Recovery())
+//
+// Gin's own debug output, which only accepts an io.Writer, can be routed
+// through golog.NewLineWriter:
+//
+//	gin.DefaultWriter = golog.NewLineWriter(jl, golog.DebugLevel)
+package gologgin
+
+import (
+	"context"
+	"time"
+
+	"github.com/KostLabs/golog"
+	"github.com/gin-gonic/gin"
+)
+
+// contextLogger is implemented by loggers with ctx-aware methods, such as
+// golog.JSONLogger, so context extractors apply to access log entries.
+type contextLogger interface {
+	InfoContext(ctx context.Context, message string, fields ...golog.Field)
+	WarnContext(ctx context.Context, message string, fields ...golog.Field)
+	ErrorContext(ctx context.Context, message string, fields ...golog.Field)
+}
+
+// AccessLog returns a gin.HandlerFunc that logs one entry per request with
+// the fields of golog.AccessLog — method, path, status, response size,
+// duration, remote address and user agent — plus the matched route and the
+// errors handlers attached with c.Error. 5xx responses are logged at error
+// level, 4xx at warn level and the rest at info level.
+func AccessLog(l golog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		status := c.Writer.Status()
+		if leveled, ok := l.(golog.LeveledLogger); ok && !leveled.Enabled(accessLevel(status)) {
+			return
+		}
+		fields := []golog.Field{
+			golog.Str(golog.KeyMethod, c.Request.Method),
+			golog.Str(golog.KeyPath, c.Request.URL.Path),
+			golog.Str("route", c.FullPath()),
+			golog.Int(golog.KeyStatus, status),
+			golog.Int("bytes", max(c.Writer.Size(), 0)),
+			golog.Duration("duration", time.Since(start)),
+			golog.Str(golog.KeyRemoteAddr, c.Request.RemoteAddr),
+			golog.Str(golog.KeyUserAgent, c.Request.UserAgent()),
+		}
+		if len(c.Errors) > 0 {
+			fields = append(fields, golog.Any("errors", c.Errors.Errors()))
+		}
+		logAccess(c.Request.Context(), l, status, fields)
+	}
+}
+
+// accessLevel is the level AccessLog uses for a response status.
+func accessLevel(status int) golog.Level {
+	switch {
+	case status >= 500:
+		return golog.ErrorLevel
+	case status >= 400:
+		return golog.WarnLevel
+	default:
+		return golog.InfoLevel
+	}
+}
+
+func logAccess(ctx context.Context, l golog.Logger, status int, fields []golog.Field) {
+	const message = "http request"
+	if ctxLogger, ok := l.(contextLogger); ok {
+		switch accessLevel(status) {
+		case golog.ErrorLevel:
+			ctxLogger.ErrorContext(ctx, message, fields...)
+		case golog.WarnLevel:
+			ctxLogger.WarnContext(ctx, message, fields...)
+		default:
+			ctxLogger.InfoContext(ctx, message, fields...)
+		}
+		return
+	}
+	switch accessLevel(status) {
+	case golog.ErrorLevel:
+		l.Error(message, fields...)
+	case golog.WarnLevel:
+		l.Warn(message, fields...)
+	default:
+		l.Info(message, fields...)
+	}
+}
@@ -0,0 +1,62 @@
+package gologgin
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/KostLabs/golog"
+	"github.com/KostLabs/golog/gologtest"
+	"github.com/gin-gonic/gin"
+)
+
+func TestAccessLogLogsRequests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	recorder := gologtest.NewRecorder()
+	router := gin.New()
+	router.Use(AccessLog(recorder))
+	router.GET("/users/:id", func(c *gin.Context) {
+		c.String(http.StatusOK, "hello")
+	})
+	router.GET("/boom", func(c *gin.Context) {
+		_ = c.Error(errors.New("database unavailable"))
+		c.Status(http.StatusInternalServerError)
+	})
+
+	for _, path := range []string{"/users/42", "/missing", "/boom"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	entries := recorder.Entries()
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(entries))
+	}
+	ok := entries[0].FieldMap()
+	if ok[golog.KeyStatus] != int64(200) || ok["bytes"] != int64(5) || ok["route"] != "/users/:id" || ok[golog.KeyPath] != "/users/42" {
+		t.Fatalf("unexpected entry: %v", ok)
+	}
+	if entries[1].Level != golog.WarnLevel || entries[2].Level != golog.ErrorLevel {
+		t.Fatalf("unexpected levels: %v %v", entries[1].Level, entries[2].Level)
+	}
+	if errs, _ := entries[2].Field("errors"); len(errs.Value().([]string)) != 1 {
+		t.Fatalf("expected the handler error, got %v", errs.Value())
+	}
+}
+
+func TestAccessLogSkipsDisabledLevels(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jl := golog.NewJSONLoggerWithOptions(golog.WithLevel(golog.WarnLevel), golog.WithOutput(&failingWriter{t: t}))
+	router := gin.New()
+	router.Use(AccessLog(jl))
+	router.GET("/", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
+
+type failingWriter struct{ t *testing.T }
+
+func (writer *failingWriter) Write(p []byte) (int, error) {
+	writer.t.Fatalf("expected nothing to be written, got %s", p)
+	return len(p), nil
+}
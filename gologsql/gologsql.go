@@ -0,0 +1,355 @@
+// Package gologsql wraps database/sql drivers so every query and exec is
+// logged through a golog.Logger with its SQL text, arguments, duration and
+// error.
+//
+// Wrap a connector and open the database with sql.OpenDB:
+//
+//	connector, _ := pq.NewConnector(dsn)
+//	db := sql.OpenDB(gologsql.WrapConnector(connector, jl))
+//
+// or register a wrapped driver under a new name:
+//
+//	sql.Register("postgres-logged", gologsql.WrapDriver(&pq.Driver{}, jl))
+//
+// Successful statements are logged at debug level, failures at error level.
+// Argument values are redacted by default; see WithRedactor.
+package gologsql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"time"
+
+	"github.com/KostLabs/golog"
+)
+
+// Redactor returns the value logged for a query argument.
+type Redactor func(arg driver.NamedValue) any
+
+// RedactAll replaces every argument with "<redacted>". It is the default.
+func RedactAll(driver.NamedValue) any {
+	return "<redacted>"
+}
+
+// RedactNone logs argument values as they are.
+func RedactNone(arg driver.NamedValue) any {
+	return arg.Value
+}
+
+// Option configures the wrapper.
+type Option func(*config)
+
+// WithRedactor sets how argument values are logged.
+func WithRedactor(redactor Redactor) Option {
+	return func(cfg *config) {
+		if redactor != nil {
+			cfg.redact = redactor
+		}
+	}
+}
+
+// WithSlowThreshold logs successful statements taking at least threshold at
+// warn level instead of debug.
+func WithSlowThreshold(threshold time.Duration) Option {
+	return func(cfg *config) {
+		cfg.slowThreshold = threshold
+	}
+}
+
+type config struct {
+	logger        golog.Logger
+	redact        Redactor
+	slowThreshold time.Duration
+}
+
+func newConfig(l golog.Logger, options []Option) *config {
+	cfg := &config{logger: l, redact: RedactAll}
+	for _, option := range options {
+		option(cfg)
+	}
+	return cfg
+}
+
+// log writes one entry for a finished statement. driver.ErrSkip is not an
+// error from the caller's point of view and is never logged. Nothing is
+// built for entries the logger would drop, so successful statements cost
+// little when debug logging is off.
+func (cfg *config) log(operation, query string, args []driver.NamedValue, start time.Time, err error) {
+	if errors.Is(err, driver.ErrSkip) {
+		return
+	}
+	elapsed := time.Since(start)
+	level := golog.DebugLevel
+	switch {
+	case err != nil:
+		level = golog.ErrorLevel
+	case cfg.slowThreshold > 0 && elapsed >= cfg.slowThreshold:
+		level = golog.WarnLevel
+	}
+	if leveled, ok := cfg.logger.(golog.LeveledLogger); ok && !leveled.Enabled(level) {
+		return
+	}
+
+	loggedArgs := make([]any, len(args))
+	for i, arg := range args {
+		loggedArgs[i] = cfg.redact(arg)
+	}
+	fields := []golog.Field{
+		golog.Str("query", query),
+		golog.Any("args", loggedArgs),
+		golog.Duration("duration", elapsed),
+	}
+
+	switch level {
+	case golog.ErrorLevel:
+		fields = append(fields, golog.Str("error", err.Error()))
+		cfg.logger.Error("sql "+operation, fields...)
+	case golog.WarnLevel:
+		cfg.logger.Warn("sql "+operation, fields...)
+	default:
+		cfg.logger.Debug("sql "+operation, fields...)
+	}
+}
+
+// WrapDriver returns a driver.Driver whose connections log their statements.
+func WrapDriver(d driver.Driver, l golog.Logger, options ...Option) driver.Driver {
+	return &loggingDriver{base: d, cfg: newConfig(l, options)}
+}
+
+// WrapConnector returns a driver.Connector whose connections log their
+// statements. Use it with sql.OpenDB.
+func WrapConnector(c driver.Connector, l golog.Logger, options ...Option) driver.Connector {
+	cfg := newConfig(l, options)
+	return &loggingConnector{base: c, driver: &loggingDriver{base: c.Driver(), cfg: cfg}, cfg: cfg}
+}
+
+type loggingDriver struct {
+	base driver.Driver
+	cfg  *config
+}
+
+func (d *loggingDriver) Open(name string) (driver.Conn, error) {
+	conn, err := d.base.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &loggingConn{base: conn, cfg: d.cfg}, nil
+}
+
+type loggingConnector struct {
+	base   driver.Connector
+	driver *loggingDriver
+	cfg    *config
+}
+
+func (c *loggingConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.base.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &loggingConn{base: conn, cfg: c.cfg}, nil
+}
+
+func (c *loggingConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+// loggingConn forwards to the wrapped connection. Optional interfaces the
+// wrapped connection doesn't implement report driver.ErrSkip (or the
+// equivalent fallback) so database/sql takes its generic path.
+type loggingConn struct {
+	base driver.Conn
+	cfg  *config
+}
+
+func (c *loggingConn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *loggingConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	var stmt driver.Stmt
+	var err error
+	if preparer, ok := c.base.(driver.ConnPrepareContext); ok {
+		stmt, err = preparer.PrepareContext(ctx, query)
+	} else {
+		stmt, err = c.base.Prepare(query)
+	}
+	if err != nil {
+		c.cfg.log("prepare", query, nil, time.Now(), err)
+		return nil, err
+	}
+	logging := &loggingStmt{base: stmt, conn: c, query: query, cfg: c.cfg}
+	if _, ok := stmt.(driver.ColumnConverter); ok {
+		return convertingStmt{logging}, nil
+	}
+	return logging, nil
+}
+
+func (c *loggingConn) Close() error {
+	return c.base.Close()
+}
+
+func (c *loggingConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+// Errors returned by BeginTx for options the wrapped driver can't honor,
+// worded like those of database/sql.
+var (
+	errIsolationLevel = errors.New("gologsql: driver does not support non-default isolation level")
+	errReadOnly       = errors.New("gologsql: driver does not support read-only transactions")
+)
+
+// BeginTx passes opts to drivers implementing driver.ConnBeginTx. Like
+// database/sql, it rejects options other drivers can't honor rather than
+// dropping them.
+func (c *loggingConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if beginner, ok := c.base.(driver.ConnBeginTx); ok {
+		return beginner.BeginTx(ctx, opts)
+	}
+	if opts.Isolation != driver.IsolationLevel(sql.LevelDefault) {
+		return nil, errIsolationLevel
+	}
+	if opts.ReadOnly {
+		return nil, errReadOnly
+	}
+	return c.base.Begin()
+}
+
+func (c *loggingConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.base.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	start := time.Now()
+	rows, err := queryer.QueryContext(ctx, query, args)
+	c.cfg.log("query", query, args, start, err)
+	return rows, err
+}
+
+func (c *loggingConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.base.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	start := time.Now()
+	result, err := execer.ExecContext(ctx, query, args)
+	c.cfg.log("exec", query, args, start, err)
+	return result, err
+}
+
+func (c *loggingConn) Ping(ctx context.Context) error {
+	if pinger, ok := c.base.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *loggingConn) ResetSession(ctx context.Context) error {
+	if resetter, ok := c.base.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *loggingConn) IsValid() bool {
+	if validator, ok := c.base.(driver.Validator); ok {
+		return validator.IsValid()
+	}
+	return true
+}
+
+func (c *loggingConn) CheckNamedValue(value *driver.NamedValue) error {
+	if checker, ok := c.base.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(value)
+	}
+	return driver.ErrSkip
+}
+
+// loggingStmt logs executions of a prepared statement.
+type loggingStmt struct {
+	base  driver.Stmt
+	conn  *loggingConn
+	query string
+	cfg   *config
+}
+
+func (s *loggingStmt) Close() error {
+	return s.base.Close()
+}
+
+func (s *loggingStmt) NumInput() int {
+	return s.base.NumInput()
+}
+
+func (s *loggingStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.ExecContext(context.Background(), namedValues(args))
+}
+
+func (s *loggingStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.QueryContext(context.Background(), namedValues(args))
+}
+
+func (s *loggingStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	start := time.Now()
+	var result driver.Result
+	var err error
+	if execer, ok := s.base.(driver.StmtExecContext); ok {
+		result, err = execer.ExecContext(ctx, args)
+	} else {
+		result, err = s.base.Exec(values(args))
+	}
+	s.cfg.log("exec", s.query, args, start, err)
+	return result, err
+}
+
+func (s *loggingStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	start := time.Now()
+	var rows driver.Rows
+	var err error
+	if queryer, ok := s.base.(driver.StmtQueryContext); ok {
+		rows, err = queryer.QueryContext(ctx, args)
+	} else {
+		rows, err = s.base.Query(values(args))
+	}
+	s.cfg.log("query", s.query, args, start, err)
+	return rows, err
+}
+
+// CheckNamedValue uses the wrapped statement's checker, or else the
+// connection's, the order database/sql would check them in unwrapped.
+func (s *loggingStmt) CheckNamedValue(value *driver.NamedValue) error {
+	if checker, ok := s.base.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(value)
+	}
+	return s.conn.CheckNamedValue(value)
+}
+
+// convertingStmt is a loggingStmt for a statement implementing
+// driver.ColumnConverter. It is a separate type because database/sql skips
+// its default argument checks for any statement that has the method.
+type convertingStmt struct {
+	*loggingStmt
+}
+
+func (s convertingStmt) ColumnConverter(index int) driver.ValueConverter {
+	return s.base.(driver.ColumnConverter).ColumnConverter(index)
+}
+
+func namedValues(args []driver.Value) []driver.NamedValue {
+	named := make([]driver.NamedValue, len(args))
+	for i, value := range args {
+		named[i] = driver.NamedValue{Ordinal: i + 1, Value: value}
+	}
+	return named
+}
+
+func values(args []driver.NamedValue) []driver.Value {
+	plain := make([]driver.Value, len(args))
+	for i, arg := range args {
+		plain[i] = arg.Value
+	}
+	return plain
+}
@@ -0,0 +1,263 @@
+package gologsql
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/KostLabs/golog"
+	"github.com/KostLabs/golog/gologtest"
+)
+
+// fakeConnector hands out fakeConns that understand two statements.
+type fakeConnector struct{}
+
+func (fakeConnector) Connect(context.Context) (driver.Conn, error) { return fakeConn{}, nil }
+func (fakeConnector) Driver() driver.Driver                        { return fakeDriver{} }
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(string) (driver.Conn, error) { return fakeConn{}, nil }
+
+type fakeConn struct{}
+
+func (fakeConn) Prepare(query string) (driver.Stmt, error) { return fakeStmt{query: query}, nil }
+func (fakeConn) Close() error                              { return nil }
+func (fakeConn) Begin() (driver.Tx, error)                 { return nil, errors.New("no transactions") }
+
+func (fakeConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	if query == "BROKEN" {
+		return nil, errors.New("syntax error")
+	}
+	return driver.RowsAffected(1), nil
+}
+
+type fakeStmt struct{ query string }
+
+func (fakeStmt) Close() error                                   { return nil }
+func (fakeStmt) NumInput() int                                  { return -1 }
+func (fakeStmt) Exec([]driver.Value) (driver.Result, error)     { return driver.RowsAffected(0), nil }
+func (stmt fakeStmt) Query([]driver.Value) (driver.Rows, error) { return &fakeRows{}, nil }
+
+type fakeRows struct{ done bool }
+
+func (*fakeRows) Columns() []string { return []string{"n"} }
+func (*fakeRows) Close() error      { return nil }
+func (rows *fakeRows) Next(dest []driver.Value) error {
+	if rows.done {
+		return io.EOF
+	}
+	rows.done = true
+	dest[0] = int64(7)
+	return nil
+}
+
+func TestWrapConnectorLogsStatements(t *testing.T) {
+	recorder := gologtest.NewRecorder()
+	db := sql.OpenDB(WrapConnector(fakeConnector{}, recorder))
+	defer db.Close()
+
+	if _, err := db.Exec("UPDATE users SET name = ? WHERE id = ?", "ada", 1); err != nil {
+		t.Fatalf("exec: %v", err)
+	}
+	if _, err := db.Exec("BROKEN"); err == nil {
+		t.Fatalf("expected exec error")
+	}
+	var n int
+	if err := db.QueryRow("SELECT n FROM t WHERE id = ?", 3).Scan(&n); err != nil || n != 7 {
+		t.Fatalf("query: %v (n=%d)", err, n)
+	}
+
+	exec := recorder.AssertLogged(t, golog.DebugLevel, "sql exec")
+	fields := exec.FieldMap()
+	if fields["query"] != "UPDATE users SET name = ? WHERE id = ?" {
+		t.Fatalf("unexpected query field: %v", fields["query"])
+	}
+	args := fields["args"].([]any)
+	if len(args) != 2 || args[0] != "<redacted>" {
+		t.Fatalf("expected redacted args, got %v", args)
+	}
+
+	failed := recorder.AssertLogged(t, golog.ErrorLevel, "sql exec")
+	if errField, _ := failed.Field("error"); errField.Value() != "syntax error" {
+		t.Fatalf("unexpected error field: %v", errField.Value())
+	}
+
+	query := recorder.AssertLogged(t, golog.DebugLevel, "sql query")
+	if queryField, _ := query.Field("query"); queryField.Value() != "SELECT n FROM t WHERE id = ?" {
+		t.Fatalf("unexpected prepared query field: %v", queryField.Value())
+	}
+}
+
+// registeredDrivers numbers driver names, since sql.Register panics when a
+// name is reused (for example under go test -count).
+var registeredDrivers atomic.Int32
+
+func TestWrapDriverWithOptions(t *testing.T) {
+	recorder := gologtest.NewRecorder()
+	name := fmt.Sprintf("gologsql-test-%d", registeredDrivers.Add(1))
+	sql.Register(name, WrapDriver(fakeDriver{}, recorder, WithRedactor(RedactNone)))
+
+	db, err := sql.Open(name, "")
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec("DELETE FROM t WHERE id = ?", 42); err != nil {
+		t.Fatalf("exec: %v", err)
+	}
+
+	entry := recorder.AssertLogged(t, golog.DebugLevel, "sql exec")
+	if args, _ := entry.Field("args"); args.Value().([]any)[0] != int64(42) {
+		t.Fatalf("expected raw args, got %v", args.Value())
+	}
+}
+
+func TestSlowThresholdLogsAtWarn(t *testing.T) {
+	recorder := gologtest.NewRecorder()
+	cfg := newConfig(recorder, []Option{WithSlowThreshold(time.Second)})
+
+	cfg.log("query", "SELECT 1", nil, time.Now().Add(-2*time.Second), nil)
+	cfg.log("query", "SELECT 2", nil, time.Now(), driver.ErrSkip)
+
+	recorder.AssertLogged(t, golog.WarnLevel, "sql query")
+	if entries := recorder.Entries(); len(entries) != 1 {
+		t.Fatalf("expected driver.ErrSkip not to be logged, got %d entries", len(entries))
+	}
+}
+
+func TestDisabledLevelsSkipRedaction(t *testing.T) {
+	buf := &bytes.Buffer{}
+	jl := golog.NewJSONLoggerWithOptions(golog.WithOutput(buf))
+	var redacted int
+	cfg := newConfig(jl, []Option{WithRedactor(func(arg driver.NamedValue) any {
+		redacted++
+		return arg.Value
+	})})
+	args := []driver.NamedValue{{Ordinal: 1, Value: "ada"}}
+
+	cfg.log("exec", "UPDATE users SET name = ?", args, time.Now(), nil)
+	if redacted != 0 || buf.Len() != 0 {
+		t.Fatalf("expected a statement below the logger's level to be skipped, redacted %d times", redacted)
+	}
+	cfg.log("exec", "UPDATE users SET name = ?", args, time.Now(), errors.New("locked"))
+	if redacted != 1 || buf.Len() == 0 {
+		t.Fatalf("expected the failed statement to be logged")
+	}
+}
+
+// txConn is a fakeConn whose driver supports transaction options.
+type txConn struct {
+	fakeConn
+	opts *driver.TxOptions
+}
+
+func (conn txConn) BeginTx(_ context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	*conn.opts = opts
+	return conn, nil
+}
+
+func (txConn) Commit() error   { return nil }
+func (txConn) Rollback() error { return nil }
+
+func TestBeginTxKeepsOptions(t *testing.T) {
+	var opts driver.TxOptions
+	conn := &loggingConn{base: txConn{opts: &opts}, cfg: newConfig(gologtest.NewRecorder(), nil)}
+	if _, err := conn.BeginTx(context.Background(), driver.TxOptions{Isolation: driver.IsolationLevel(sql.LevelSerializable), ReadOnly: true}); err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+	if opts.Isolation != driver.IsolationLevel(sql.LevelSerializable) || !opts.ReadOnly {
+		t.Fatalf("expected the options to reach the driver, got %+v", opts)
+	}
+
+	db := sql.OpenDB(WrapConnector(fakeConnector{}, gologtest.NewRecorder()))
+	defer db.Close()
+	if _, err := db.BeginTx(context.Background(), &sql.TxOptions{ReadOnly: true}); !errors.Is(err, errReadOnly) {
+		t.Fatalf("expected read-only to be rejected, got %v", err)
+	}
+	if _, err := db.BeginTx(context.Background(), &sql.TxOptions{Isolation: sql.LevelSerializable}); !errors.Is(err, errIsolationLevel) {
+		t.Fatalf("expected the isolation level to be rejected, got %v", err)
+	}
+}
+
+// point is an argument type only the fake drivers below know how to convert.
+type point struct{ x, y int }
+
+func (p point) String() string { return fmt.Sprintf("(%d,%d)", p.x, p.y) }
+
+// checkerConn is a fakeConn that converts point arguments at the connection
+// level, as drivers like pgx and mysql do.
+type checkerConn struct{ fakeConn }
+
+func (checkerConn) CheckNamedValue(value *driver.NamedValue) error {
+	if p, ok := value.Value.(point); ok {
+		value.Value = p.String()
+		return nil
+	}
+	return driver.ErrSkip
+}
+
+// converterConn prepares converterStmts.
+type converterConn struct{ fakeConn }
+
+func (converterConn) Prepare(query string) (driver.Stmt, error) {
+	return converterStmt{fakeStmt{query: query}}, nil
+}
+
+// converterStmt converts point arguments through driver.ColumnConverter.
+type converterStmt struct{ fakeStmt }
+
+func (converterStmt) NumInput() int { return 1 }
+
+func (converterStmt) ColumnConverter(int) driver.ValueConverter {
+	return pointConverter{}
+}
+
+type pointConverter struct{}
+
+func (pointConverter) ConvertValue(value any) (driver.Value, error) {
+	if p, ok := value.(point); ok {
+		return p.String(), nil
+	}
+	return driver.DefaultParameterConverter.ConvertValue(value)
+}
+
+// connConnector hands out a fixed connection.
+type connConnector struct{ conn driver.Conn }
+
+func (c connConnector) Connect(context.Context) (driver.Conn, error) { return c.conn, nil }
+func (connConnector) Driver() driver.Driver                          { return fakeDriver{} }
+
+func TestPreparedStatementsKeepDriverConversions(t *testing.T) {
+	for name, conn := range map[string]driver.Conn{
+		"connection checker": checkerConn{},
+		"column converter":   converterConn{},
+	} {
+		t.Run(name, func(t *testing.T) {
+			recorder := gologtest.NewRecorder()
+			db := sql.OpenDB(WrapConnector(connConnector{conn: conn}, recorder, WithRedactor(RedactNone)))
+			defer db.Close()
+
+			stmt, err := db.Prepare("INSERT INTO shapes VALUES (?)")
+			if err != nil {
+				t.Fatalf("prepare: %v", err)
+			}
+			defer stmt.Close()
+			if _, err := stmt.Exec(point{1, 2}); err != nil {
+				t.Fatalf("exec: %v", err)
+			}
+			entry := recorder.AssertLogged(t, golog.DebugLevel, "sql exec")
+			if args, _ := entry.Field("args"); args.Value().([]any)[0] != "(1,2)" {
+				t.Fatalf("expected the driver's conversion, got %v", args.Value())
+			}
+		})
+	}
+}
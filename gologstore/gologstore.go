@@ -0,0 +1,189 @@
+// Package gologstore keeps log entries in a local SQLite database, so CLI
+// tools and edge agents can search their own logs on the device.
+//
+// The schema and queries are written for SQLite and work with any of its
+// database/sql drivers; other databases aren't supported. golog itself has
+// no driver dependency:
+//
+//	db, _ := sql.Open("sqlite", "agent-logs.db")
+//	store, _ := gologstore.Open(ctx, db, "logs")
+//	jl := golog.NewJSONLoggerWithOptions(golog.WithOutput(store))
+//
+//	entries, _ := store.Query(ctx, gologstore.Query{MinLevel: golog.WarnLevel, Limit: 50})
+//
+// Each entry is stored as its original JSON record next to indexed
+// timestamp, level and message columns. Records written together, such as
+// the batches of golog.WithAsync, are inserted in one transaction.
+package gologstore
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/KostLabs/golog"
+)
+
+var errInvalidTable = errors.New("gologstore: table name must contain only letters, digits and underscores")
+
+// Store is an io.Writer that inserts every record written to it as a row.
+// It is safe for concurrent use.
+type Store struct {
+	db    *sql.DB
+	table string
+}
+
+// Open creates table and its indexes when they don't exist and returns a
+// store writing to it. The logger must use the default RFC 3339 timestamp
+// format so records can be decoded.
+func Open(ctx context.Context, db *sql.DB, table string) (*Store, error) {
+	if !validTableName(table) {
+		return nil, errInvalidTable
+	}
+	statements := []string{
+		"CREATE TABLE IF NOT EXISTS " + table + " (id INTEGER PRIMARY KEY, ts INTEGER NOT NULL, level INTEGER NOT NULL, message TEXT NOT NULL, record TEXT NOT NULL)",
+		"CREATE INDEX IF NOT EXISTS " + table + "_ts ON " + table + " (ts)",
+		"CREATE INDEX IF NOT EXISTS " + table + "_level ON " + table + " (level)",
+		"CREATE INDEX IF NOT EXISTS " + table + "_message ON " + table + " (message)",
+	}
+	for _, statement := range statements {
+		if _, err := db.ExecContext(ctx, statement); err != nil {
+			return nil, err
+		}
+	}
+	return &Store{db: db, table: table}, nil
+}
+
+func validTableName(table string) bool {
+	if table == "" {
+		return false
+	}
+	for _, r := range table {
+		if !(r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// Write decodes each newline-terminated record in p and inserts them in one
+// transaction. Lines that aren't golog records are skipped. If an insert
+// fails the transaction is rolled back and Write returns 0 with the error,
+// so retrying the write can't store a record twice.
+func (store *Store) Write(p []byte) (int, error) {
+	var (
+		records [][]byte
+		entries []golog.Entry
+	)
+	for _, line := range bytes.Split(p, []byte{'\n'}) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		entry, err := golog.ParseEntry(line)
+		if err != nil {
+			continue
+		}
+		records = append(records, line)
+		entries = append(entries, entry)
+	}
+	if err := store.insert(context.Background(), records, entries); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// insert adds the decoded records in one transaction, so a batch costs a
+// single commit and is stored completely or not at all.
+func (store *Store) insert(ctx context.Context, records [][]byte, entries []golog.Entry) error {
+	if len(records) == 0 {
+		return nil
+	}
+	tx, err := store.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	statement := "INSERT INTO " + store.table + " (ts, level, message, record) VALUES (?, ?, ?, ?)"
+	for i, record := range records {
+		entry := entries[i]
+		if _, err := tx.ExecContext(ctx, statement, entry.Time.UnixNano(), int64(entry.Level), entry.Message, string(record)); err != nil {
+			return errors.Join(err, tx.Rollback())
+		}
+	}
+	return tx.Commit()
+}
+
+// Query selects stored entries. Zero fields don't filter.
+type Query struct {
+	// Since and Until bound the entry timestamps, inclusive.
+	Since, Until time.Time
+	// MinLevel keeps entries at or above this level.
+	MinLevel golog.Level
+	// MessageContains keeps entries whose message contains this text.
+	MessageContains string
+	// Limit caps the number of entries returned.
+	Limit int
+	// Newest returns the most recent entries first. By default entries are
+	// returned oldest first.
+	Newest bool
+}
+
+// Query returns the entries matching query.
+func (store *Store) Query(ctx context.Context, query Query) ([]golog.Entry, error) {
+	var (
+		conditions []string
+		args       []any
+	)
+	if !query.Since.IsZero() {
+		conditions = append(conditions, "ts >= ?")
+		args = append(args, query.Since.UnixNano())
+	}
+	if !query.Until.IsZero() {
+		conditions = append(conditions, "ts <= ?")
+		args = append(args, query.Until.UnixNano())
+	}
+	if query.MinLevel > golog.DebugLevel {
+		conditions = append(conditions, "level >= ?")
+		args = append(args, int64(query.MinLevel))
+	}
+	if query.MessageContains != "" {
+		conditions = append(conditions, "instr(message, ?) > 0")
+		args = append(args, query.MessageContains)
+	}
+
+	statement := "SELECT record FROM " + store.table
+	if len(conditions) > 0 {
+		statement += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	if query.Newest {
+		statement += " ORDER BY ts DESC, id DESC"
+	} else {
+		statement += " ORDER BY ts, id"
+	}
+	if query.Limit > 0 {
+		statement += fmt.Sprintf(" LIMIT %d", query.Limit)
+	}
+
+	rows, err := store.db.QueryContext(ctx, statement, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []golog.Entry
+	for rows.Next() {
+		var record string
+		if err := rows.Scan(&record); err != nil {
+			return entries, err
+		}
+		entry, err := golog.ParseEntry([]byte(record))
+		if err != nil {
+			return entries, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
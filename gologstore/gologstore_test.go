@@ -0,0 +1,198 @@
+package gologstore
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/KostLabs/golog"
+)
+
+// fakeDB records executed statements and serves the inserted records back to
+// any SELECT, so tests can check both the SQL and the decoding.
+type fakeDB struct {
+	mutex      sync.Mutex
+	statements []string
+	queryArgs  []driver.NamedValue
+	records    []string
+	// failInsert makes the INSERT with this number, counting from 1, fail.
+	failInsert int
+	inserts    int
+}
+
+func (db *fakeDB) Connect(context.Context) (driver.Conn, error) { return fakeConn{db: db}, nil }
+func (db *fakeDB) Driver() driver.Driver                        { return nil }
+
+type fakeConn struct{ db *fakeDB }
+
+func (fakeConn) Prepare(string) (driver.Stmt, error) { return nil, driver.ErrSkip }
+func (fakeConn) Close() error                        { return nil }
+
+func (conn fakeConn) Begin() (driver.Tx, error) {
+	conn.db.record("BEGIN")
+	return conn, nil
+}
+
+func (conn fakeConn) Commit() error {
+	conn.db.record("COMMIT")
+	return nil
+}
+
+func (conn fakeConn) Rollback() error {
+	conn.db.record("ROLLBACK")
+	return nil
+}
+
+func (db *fakeDB) record(statement string) {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+	db.statements = append(db.statements, statement)
+}
+
+func (conn fakeConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	conn.db.mutex.Lock()
+	defer conn.db.mutex.Unlock()
+	conn.db.statements = append(conn.db.statements, query)
+	if strings.HasPrefix(query, "INSERT") {
+		conn.db.inserts++
+		if conn.db.inserts == conn.db.failInsert {
+			return nil, errors.New("disk I/O error")
+		}
+		conn.db.records = append(conn.db.records, args[3].Value.(string))
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (conn fakeConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	conn.db.mutex.Lock()
+	defer conn.db.mutex.Unlock()
+	conn.db.statements = append(conn.db.statements, query)
+	conn.db.queryArgs = args
+	return &fakeRows{records: append([]string(nil), conn.db.records...)}, nil
+}
+
+type fakeRows struct{ records []string }
+
+func (*fakeRows) Columns() []string { return []string{"record"} }
+func (*fakeRows) Close() error      { return nil }
+func (rows *fakeRows) Next(dest []driver.Value) error {
+	if len(rows.records) == 0 {
+		return io.EOF
+	}
+	dest[0], rows.records = rows.records[0], rows.records[1:]
+	return nil
+}
+
+func TestStoreWritesAndQueriesEntries(t *testing.T) {
+	fake := &fakeDB{}
+	db := sql.OpenDB(fake)
+	defer db.Close()
+
+	store, err := Open(context.Background(), db, "logs")
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	if len(fake.statements) != 4 || !strings.HasPrefix(fake.statements[0], "CREATE TABLE IF NOT EXISTS logs") {
+		t.Fatalf("unexpected schema statements: %v", fake.statements)
+	}
+
+	jl := golog.NewJSONLoggerWithOptions(golog.WithOutput(store))
+	jl.Info("disk check", golog.Int("free_mb", 512))
+	jl.Warn("disk almost full")
+
+	since := time.Unix(100, 0)
+	entries, err := store.Query(context.Background(), Query{Since: since, MinLevel: golog.WarnLevel, MessageContains: "disk", Limit: 10, Newest: true})
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+
+	want := "SELECT record FROM logs WHERE ts >= ? AND level >= ? AND instr(message, ?) > 0 ORDER BY ts DESC, id DESC LIMIT 10"
+	if got := fake.statements[len(fake.statements)-1]; got != want {
+		t.Fatalf("unexpected query:\n%s\nwant:\n%s", got, want)
+	}
+	if len(fake.queryArgs) != 3 || fake.queryArgs[0].Value != since.UnixNano() || fake.queryArgs[1].Value != int64(golog.WarnLevel) {
+		t.Fatalf("unexpected query args: %v", fake.queryArgs)
+	}
+
+	if len(entries) != 2 || entries[0].Message != "disk check" || entries[1].Level != golog.WarnLevel {
+		t.Fatalf("unexpected entries: %+v", entries)
+	}
+	if free, _ := entries[0].Field("free_mb"); free.Value() != int64(512) {
+		t.Fatalf("expected fields to round-trip, got %v", free.Value())
+	}
+}
+
+func TestStoreRejectsInvalidInput(t *testing.T) {
+	db := sql.OpenDB(&fakeDB{})
+	defer db.Close()
+
+	if _, err := Open(context.Background(), db, "logs; DROP TABLE users"); err == nil {
+		t.Fatalf("expected an invalid table name to be rejected")
+	}
+
+	store, err := Open(context.Background(), db, "logs")
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	input := bytes.Repeat([]byte("not json\n"), 2)
+	if n, err := store.Write(input); err != nil || n != len(input) {
+		t.Fatalf("expected undecodable records to be skipped, got %d, %v", n, err)
+	}
+}
+
+func TestStoreInsertsRecordsInOneTransaction(t *testing.T) {
+	fake := &fakeDB{}
+	db := sql.OpenDB(fake)
+	defer db.Close()
+	store, err := Open(context.Background(), db, "logs")
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+
+	buf := &bytes.Buffer{}
+	jl := golog.NewJSONLoggerWithOptions(golog.WithOutput(buf))
+	jl.Info("first")
+	jl.Info("second")
+	buf.WriteString("not json\n")
+	if n, err := store.Write(buf.Bytes()); err != nil || n != buf.Len() {
+		t.Fatalf("expected the undecodable record to be skipped, got %d, %v", n, err)
+	}
+
+	statements := fake.statements[4:]
+	if len(statements) != 4 || statements[0] != "BEGIN" || !strings.HasPrefix(statements[1], "INSERT") || !strings.HasPrefix(statements[2], "INSERT") || statements[3] != "COMMIT" {
+		t.Fatalf("expected both records in one transaction, got %v", statements)
+	}
+	if len(fake.records) != 2 {
+		t.Fatalf("expected the decodable records to be inserted, got %d", len(fake.records))
+	}
+}
+
+func TestStoreRollsBackFailedInserts(t *testing.T) {
+	fake := &fakeDB{failInsert: 2}
+	db := sql.OpenDB(fake)
+	defer db.Close()
+	store, err := Open(context.Background(), db, "logs")
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+
+	buf := &bytes.Buffer{}
+	jl := golog.NewJSONLoggerWithOptions(golog.WithOutput(buf))
+	jl.Info("first")
+	jl.Info("second")
+	if n, err := store.Write(buf.Bytes()); err == nil || n != 0 {
+		t.Fatalf("expected the failed batch to be reported as unwritten, got %d, %v", n, err)
+	}
+
+	statements := fake.statements[4:]
+	if len(statements) != 4 || statements[len(statements)-1] != "ROLLBACK" {
+		t.Fatalf("expected the transaction to be rolled back, got %v", statements)
+	}
+}
@@ -0,0 +1,115 @@
+// Package gologtest provides helpers for asserting on log output in tests.
+//
+// Recorder implements golog.Logger and keeps every entry in memory as a
+// typed struct, so tests can inspect levels, messages and fields directly
+// instead of parsing JSON lines out of a buffer.
+package gologtest
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/KostLabs/golog"
+)
+
+// Entry is a single captured log call.
+type Entry = golog.Entry
+
+// Recorder is an in-memory golog.Logger that captures entries for later
+// inspection. It is safe for concurrent use. The zero value is ready to use.
+type Recorder struct {
+	mutex   sync.Mutex
+	entries []Entry
+}
+
+// NewRecorder returns an empty Recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// Info records a message at info level.
+func (recorder *Recorder) Info(message string, fields ...golog.Field) {
+	recorder.record(golog.InfoLevel, message, fields)
+}
+
+// Warn records a message at warn level.
+func (recorder *Recorder) Warn(message string, fields ...golog.Field) {
+	recorder.record(golog.WarnLevel, message, fields)
+}
+
+// Error records a message at error level.
+func (recorder *Recorder) Error(message string, fields ...golog.Field) {
+	recorder.record(golog.ErrorLevel, message, fields)
+}
+
+// Debug records a message at debug level.
+func (recorder *Recorder) Debug(message string, fields ...golog.Field) {
+	recorder.record(golog.DebugLevel, message, fields)
+}
+
+func (recorder *Recorder) record(level golog.Level, message string, fields []golog.Field) {
+	recorder.mutex.Lock()
+	recorder.entries = append(recorder.entries, Entry{
+		Time:    time.Now().UTC(),
+		Level:   level,
+		Message: message,
+		Fields:  append([]golog.Field(nil), fields...),
+	})
+	recorder.mutex.Unlock()
+}
+
+// Entries returns a copy of all entries recorded so far, oldest first.
+func (recorder *Recorder) Entries() []Entry {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+
+	out := make([]Entry, len(recorder.entries))
+	copy(out, recorder.entries)
+	return out
+}
+
+// Reset discards all recorded entries.
+func (recorder *Recorder) Reset() {
+	recorder.mutex.Lock()
+	recorder.entries = nil
+	recorder.mutex.Unlock()
+}
+
+// Find returns the first entry at level whose message contains
+// messageSubstring.
+func (recorder *Recorder) Find(level golog.Level, messageSubstring string) (Entry, bool) {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+
+	for _, entry := range recorder.entries {
+		if entry.Level == level && strings.Contains(entry.Message, messageSubstring) {
+			return entry, true
+		}
+	}
+	return Entry{}, false
+}
+
+// AssertLogged fails the test unless an entry at level containing
+// messageSubstring was recorded. It returns the matching entry so callers can
+// make further assertions on its fields.
+func (recorder *Recorder) AssertLogged(t testing.TB, level golog.Level, messageSubstring string) Entry {
+	t.Helper()
+
+	entry, ok := recorder.Find(level, messageSubstring)
+	if !ok {
+		t.Fatalf("expected a %s entry containing %q, got %d entries", level, messageSubstring, len(recorder.Entries()))
+	}
+	return entry
+}
+
+// AssertNotLogged fails the test if an entry at level containing
+// messageSubstring was recorded.
+func (recorder *Recorder) AssertNotLogged(t testing.TB, level golog.Level, messageSubstring string) {
+	t.Helper()
+
+	if entry, ok := recorder.Find(level, messageSubstring); ok {
+		t.Fatalf("did not expect a %s entry containing %q, got %q", level, messageSubstring, entry.Message)
+	}
+}
@@ -0,0 +1,59 @@
+package gologtest
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/KostLabs/golog"
+)
+
+func TestRecorderCapturesEntries(t *testing.T) {
+	recorder := NewRecorder()
+
+	recorder.Info("user created", golog.Str("user_id", "u1"), golog.Int("attempt", 2))
+	recorder.Error("payment failed", golog.Bool("retry", true))
+
+	entries := recorder.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].Level != golog.InfoLevel || entries[0].Message != "user created" {
+		t.Fatalf("unexpected first entry: %+v", entries[0])
+	}
+	if fields := entries[0].FieldMap(); fields["user_id"] != "u1" || fields["attempt"] != int64(2) {
+		t.Fatalf("unexpected first entry fields: %#v", entries[0].Fields)
+	}
+	if entries[0].Time.IsZero() {
+		t.Fatalf("expected entry time to be set")
+	}
+
+	entry := recorder.AssertLogged(t, golog.ErrorLevel, "payment")
+	if retry, _ := entry.Field("retry"); retry.Value() != true {
+		t.Fatalf("expected retry=true, got %#v", retry.Value())
+	}
+	recorder.AssertNotLogged(t, golog.WarnLevel, "payment")
+}
+
+func TestRecorderResetAndConcurrentUse(t *testing.T) {
+	recorder := NewRecorder()
+	var logger golog.Logger = recorder
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			logger.Debug("tick")
+		}()
+	}
+	wg.Wait()
+
+	if got := len(recorder.Entries()); got != 8 {
+		t.Fatalf("expected 8 entries, got %d", got)
+	}
+
+	recorder.Reset()
+	if got := len(recorder.Entries()); got != 0 {
+		t.Fatalf("expected no entries after Reset, got %d", got)
+	}
+}
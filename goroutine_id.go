@@ -0,0 +1,53 @@
+package golog
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+)
+
+// GoroutineKey is the field written by WithGoroutineID.
+const GoroutineKey = "goroutine"
+
+// WithGoroutineID stamps every entry with the ID of the goroutine that
+// logged it, for untangling interleaved lines while debugging concurrency
+// issues. It is a debugging aid only: the ID is parsed from runtime.Stack on
+// each call, which costs around a microsecond and an allocation, and Go
+// makes no promises about goroutine IDs beyond uniqueness among live
+// goroutines. Don't build behavior on it.
+func WithGoroutineID() Option {
+	return func(jsonLogger *JSONLogger) {
+		jsonLogger.goroutineID = true
+	}
+}
+
+// currentGoroutineID parses the ID from the "goroutine 42 [running]:"
+// header of the calling goroutine's stack trace. It returns 0 if the format
+// ever changes.
+func currentGoroutineID() uint64 {
+	var stack [64]byte
+	header := stack[:runtime.Stack(stack[:], false)]
+	header, ok := bytes.CutPrefix(header, []byte("goroutine "))
+	if !ok {
+		return 0
+	}
+	var id uint64
+	for _, c := range header {
+		if c < '0' || c > '9' {
+			break
+		}
+		id = id*10 + uint64(c-'0')
+	}
+	return id
+}
+
+// appendGoroutineID writes the `,"goroutine":n` fragment when enabled.
+func (jsonLogger *JSONLogger) appendGoroutineID(dst []byte) []byte {
+	if !jsonLogger.goroutineID {
+		return dst
+	}
+	dst = append(dst, ',')
+	dst = jsonLogger.encoder.appendKey(dst, GoroutineKey)
+	dst = append(dst, ':')
+	return strconv.AppendUint(dst, currentGoroutineID(), 10)
+}
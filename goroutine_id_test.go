@@ -0,0 +1,36 @@
+package golog
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWithGoroutineID(t *testing.T) {
+	buf := &bytes.Buffer{}
+	sink := &recordingSink{}
+	jl := NewJSONLoggerWithOptions(WithOutput(buf), WithGoroutineID(), WithSink(sink))
+
+	jl.Info("here")
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		jl.Info("there")
+	}()
+	<-done
+
+	entries := readEntries(t, buf.Bytes())
+	if len(entries) != 2 {
+		t.Fatalf("expected two entries, got %+v", entries)
+	}
+	here, _ := entries[0].Field(GoroutineKey)
+	there, _ := entries[1].Field(GoroutineKey)
+	if here.Value() != int64(currentGoroutineID()) || here.Value() == int64(0) {
+		t.Fatalf("expected the test goroutine's ID, got %+v", entries[0].Fields)
+	}
+	if there.Value() == here.Value() || there.Value() == int64(0) {
+		t.Fatalf("expected a different goroutine ID, got %v and %v", here.Value(), there.Value())
+	}
+	if field, ok := sink.entries[0].Field(GoroutineKey); !ok || field.Value() != currentGoroutineID() {
+		t.Fatalf("expected sinks to get the goroutine field, got %+v", sink.entries[0].Fields)
+	}
+}
@@ -0,0 +1,96 @@
+package golog
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Keys of the fields written by Healthcheck.
+const (
+	HealthQueuedBytesKey    = "queued_bytes"
+	HealthDroppedEntriesKey = "dropped_entries"
+	HealthWriteErrorsKey    = "write_errors"
+	HealthLastWriteErrorKey = "last_write_error"
+)
+
+// healthMessage is the message of Healthcheck entries.
+const healthMessage = "logger health"
+
+// errorTracker counts write failures and keeps the most recent one. A nil
+// tracker ignores errors.
+type errorTracker struct {
+	count atomic.Uint64
+	last  atomic.Pointer[error]
+}
+
+func (tracker *errorTracker) record(err error) {
+	if tracker == nil {
+		return
+	}
+	tracker.count.Add(1)
+	tracker.last.Store(&err)
+}
+
+func (tracker *errorTracker) lastError() error {
+	if last := tracker.last.Load(); last != nil {
+		return *last
+	}
+	return nil
+}
+
+// Healthcheck writes a "logger health" entry describing the logging
+// pipeline itself: bytes waiting in the async queue, entries dropped, failed
+// writes and the last write error. Like Event, it bypasses level filtering,
+// so pipeline failures stay visible in the logs (or in the sinks that still
+// work).
+func (jsonLogger *JSONLogger) Healthcheck() {
+	if jsonLogger.off() {
+		return
+	}
+	stats := jsonLogger.Stats()
+	fields := [4]Field{
+		Int(HealthQueuedBytesKey, stats.QueuedBytes),
+		{key: HealthDroppedEntriesKey, uintVal: stats.DroppedEntries, kind: fieldKindUint},
+		{key: HealthWriteErrorsKey, uintVal: stats.WriteErrors, kind: fieldKindUint},
+	}
+	count := 3
+	if stats.LastWriteError != nil {
+		fields[3] = Str(HealthLastWriteErrorKey, stats.LastWriteError.Error())
+		count++
+	}
+	jsonLogger.logUnfiltered(healthMessage, fields[:count])
+}
+
+// WithHealthcheckInterval writes a Healthcheck entry every interval until
+// the logger is closed.
+func WithHealthcheckInterval(interval time.Duration) Option {
+	return func(jsonLogger *JSONLogger) {
+		jsonLogger.healthInterval = interval
+	}
+}
+
+func (jsonLogger *JSONLogger) runHealthchecks() {
+	defer close(jsonLogger.healthDone)
+	ticker := time.NewTicker(jsonLogger.healthInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			jsonLogger.Healthcheck()
+		case <-jsonLogger.healthStop:
+			return
+		}
+	}
+}
+
+// stopHealthchecks ends the WithHealthcheckInterval loop, if any, and waits
+// for it to exit.
+func (jsonLogger *JSONLogger) stopHealthchecks() {
+	if jsonLogger.healthStop == nil {
+		return
+	}
+	jsonLogger.healthOnce.Do(func() {
+		close(jsonLogger.healthStop)
+	})
+	<-jsonLogger.healthDone
+}
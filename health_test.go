@@ -0,0 +1,67 @@
+package golog
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// failingWriter rejects every write.
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestHealthcheckReportsWriteErrors(t *testing.T) {
+	sink := &recordingSink{}
+	jl := NewJSONLoggerWithOptions(WithOutput(failingWriter{}), WithSink(sink), WithLevel(ErrorLevel))
+
+	jl.Error("lost")
+	jl.Error("lost again")
+
+	stats := jl.Stats()
+	if stats.WriteErrors != 2 || stats.LastWriteError == nil || stats.LastWriteError.Error() != "disk full" {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+
+	jl.Healthcheck()
+	health := sink.entries[len(sink.entries)-1]
+	fields := health.FieldMap()
+	if health.Message != "logger health" || fields[HealthWriteErrorsKey] != uint64(2) || fields[HealthLastWriteErrorKey] != "disk full" {
+		t.Fatalf("unexpected health entry: %+v", health)
+	}
+	if fields[HealthDroppedEntriesKey] != uint64(0) || fields[HealthQueuedBytesKey] != int64(0) {
+		t.Fatalf("unexpected queue fields: %v", fields)
+	}
+}
+
+func TestWithHealthcheckInterval(t *testing.T) {
+	buf := &lockedBuffer{}
+	jl := NewJSONLoggerWithOptions(WithOutput(buf), WithHealthcheckInterval(5*time.Millisecond))
+
+	deadline := time.Now().Add(2 * time.Second)
+	for len(buf.Bytes()) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("no health entry was written")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if err := jl.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	entries := readEntries(t, buf.Bytes())
+	if entries[0].Message != "logger health" {
+		t.Fatalf("unexpected entry: %+v", entries[0])
+	}
+	if _, ok := entries[0].Field(HealthLastWriteErrorKey); ok {
+		t.Fatalf("expected no last write error on a healthy pipeline")
+	}
+
+	written := len(buf.Bytes())
+	time.Sleep(20 * time.Millisecond)
+	if len(buf.Bytes()) != written {
+		t.Fatalf("expected health entries to stop after Close")
+	}
+}
@@ -0,0 +1,185 @@
+package golog
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// RoundTripperOption configures NewLoggingRoundTripper.
+type RoundTripperOption func(*loggingRoundTripper)
+
+// WithRoundTripperBodies additionally logs request and response bodies, up to
+// maxBytes each, in a separate Debug entry. Bodies are read without being
+// consumed: requests are re-read through GetBody and responses are captured
+// as the caller reads them, so streaming responses aren't held back. The
+// entry is written once maxBytes of the response have been read, or when it
+// ends or is closed.
+func WithRoundTripperBodies(maxBytes int) RoundTripperOption {
+	return func(roundTripper *loggingRoundTripper) {
+		roundTripper.maxBodyBytes = maxBytes
+	}
+}
+
+type retryAttemptKey struct{}
+
+// WithRetryAttempt marks ctx as belonging to the given attempt of a request
+// (1 for the first try). Retry loops set it so the logging round tripper can
+// report an attempt field on every outbound call.
+func WithRetryAttempt(ctx context.Context, attempt int) context.Context {
+	return context.WithValue(ctx, retryAttemptKey{}, attempt)
+}
+
+// loggingRoundTripper logs every outbound request passing through it.
+type loggingRoundTripper struct {
+	base         http.RoundTripper
+	logger       Logger
+	maxBodyBytes int
+}
+
+// NewLoggingRoundTripper wraps base (http.DefaultTransport when nil) so
+// every outbound request is logged with its method, URL (passwords
+// redacted), status, duration and retry attempt. Failed round trips are
+// logged at error level, 5xx responses at warn level and everything else at
+// info level.
+//
+//	client := &http.Client{Transport: NewLoggingRoundTripper(nil, jl)}
+func NewLoggingRoundTripper(base http.RoundTripper, l Logger, options ...RoundTripperOption) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	roundTripper := &loggingRoundTripper{base: base, logger: l}
+	for _, option := range options {
+		option(roundTripper)
+	}
+	return roundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (roundTripper *loggingRoundTripper) RoundTrip(request *http.Request) (*http.Response, error) {
+	start := time.Now()
+	response, err := roundTripper.base.RoundTrip(request)
+	elapsed := time.Since(start)
+
+	if enabled(roundTripper.logger, roundTripLevel(response, err)) {
+		roundTripper.logRequest(request, response, err, elapsed)
+	}
+	if roundTripper.maxBodyBytes > 0 && enabled(roundTripper.logger, DebugLevel) {
+		roundTripper.logBodies(request, response)
+	}
+	return response, err
+}
+
+// roundTripLevel is the level a round trip is logged at.
+func roundTripLevel(response *http.Response, err error) Level {
+	switch {
+	case err != nil:
+		return ErrorLevel
+	case response.StatusCode >= 500:
+		return WarnLevel
+	default:
+		return InfoLevel
+	}
+}
+
+// logRequest writes the entry for a round trip.
+func (roundTripper *loggingRoundTripper) logRequest(request *http.Request, response *http.Response, err error, elapsed time.Duration) {
+	fields := make([]Field, 0, 6)
+	fields = append(fields,
+		Str(KeyMethod, request.Method),
+		Str("url", request.URL.Redacted()),
+		Duration("duration", elapsed),
+	)
+	if attempt, ok := request.Context().Value(retryAttemptKey{}).(int); ok {
+		fields = append(fields, Int("attempt", attempt))
+	}
+
+	switch {
+	case err != nil:
+		fields = append(fields, Str("error", err.Error()))
+		roundTripper.logger.Error("http client request", fields...)
+	case response.StatusCode >= 500:
+		fields = append(fields, Int(KeyStatus, response.StatusCode))
+		roundTripper.logger.Warn("http client request", fields...)
+	default:
+		fields = append(fields, Int(KeyStatus, response.StatusCode))
+		roundTripper.logger.Info("http client request", fields...)
+	}
+}
+
+// logBodies writes a Debug entry holding the leading bytes of the request
+// and response bodies, once the caller has read those of the response.
+func (roundTripper *loggingRoundTripper) logBodies(request *http.Request, response *http.Response) {
+	fields := []Field{Str(KeyMethod, request.Method), Str("url", request.URL.Redacted())}
+
+	if request.GetBody != nil {
+		if body, err := request.GetBody(); err == nil {
+			prefix, _ := io.ReadAll(io.LimitReader(body, int64(roundTripper.maxBodyBytes)))
+			_ = body.Close()
+			fields = append(fields, Str("request_body", string(prefix)))
+		}
+	}
+
+	if response == nil || response.Body == nil {
+		roundTripper.logger.Debug("http client body", fields...)
+		return
+	}
+	response.Body = &loggedBody{
+		ReadCloser: response.Body,
+		limit:      roundTripper.maxBodyBytes,
+		log: func(prefix []byte, complete bool) {
+			if complete {
+				fields = append(fields, Str("response_body", string(prefix)))
+			}
+			roundTripper.logger.Debug("http client body", fields...)
+		},
+	}
+}
+
+// loggedBody passes a response body through to the caller, keeping its
+// leading bytes up to limit. It calls log once: when the limit is reached,
+// the body ends or fails, or it is closed. complete is false when reading
+// failed.
+type loggedBody struct {
+	io.ReadCloser
+	limit int
+
+	// mutex guards the fields below, as Close may run concurrently with
+	// Read to abort it.
+	mutex  sync.Mutex
+	prefix []byte
+	log    func(prefix []byte, complete bool)
+}
+
+func (body *loggedBody) Read(p []byte) (int, error) {
+	n, err := body.ReadCloser.Read(p)
+	body.mutex.Lock()
+	defer body.mutex.Unlock()
+	if body.log == nil {
+		return n, err
+	}
+	body.prefix = append(body.prefix, p[:min(n, body.limit-len(body.prefix))]...)
+	if err != nil || len(body.prefix) >= body.limit {
+		body.finish(err == nil || errors.Is(err, io.EOF))
+	}
+	return n, err
+}
+
+func (body *loggedBody) Close() error {
+	body.mutex.Lock()
+	if body.log != nil {
+		body.finish(true)
+	}
+	body.mutex.Unlock()
+	return body.ReadCloser.Close()
+}
+
+// finish logs the prefix. The caller holds the mutex.
+func (body *loggedBody) finish(complete bool) {
+	body.log(body.prefix, complete)
+	body.log = nil
+	body.prefix = nil
+}
@@ -0,0 +1,139 @@
+package golog
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Keys of the fields built by HTTPRequest and HTTPResponse.
+const (
+	HTTPRequestKey  = "http_request"
+	HTTPResponseKey = "http_response"
+)
+
+// HTTPHeaderAllowlist lists the headers HTTPRequest and HTTPResponse copy.
+// Replace or extend it at startup to log more; credentials (Authorization,
+// Proxy-Authorization, Cookie and Set-Cookie) are never logged even when
+// listed.
+var HTTPHeaderAllowlist = []string{
+	"Accept",
+	"Content-Encoding",
+	"Content-Type",
+	"Traceparent",
+	"User-Agent",
+	"X-Forwarded-For",
+	"X-Request-Id",
+}
+
+// maxHTTPHeaderValue caps each logged header value, in bytes.
+const maxHTTPHeaderValue = 256
+
+// httpRequestValue is the logged view of an *http.Request.
+type httpRequestValue struct {
+	Method        string            `json:"method"`
+	URL           string            `json:"url"`
+	Proto         string            `json:"proto,omitempty"`
+	RemoteAddr    string            `json:"remote_addr,omitempty"`
+	ContentLength int64             `json:"content_length,omitempty"`
+	Headers       map[string]string `json:"headers,omitempty"`
+}
+
+// httpResponseValue is the logged view of an *http.Response.
+type httpResponseValue struct {
+	Status        int               `json:"status"`
+	Proto         string            `json:"proto,omitempty"`
+	ContentLength int64             `json:"content_length,omitempty"`
+	Headers       map[string]string `json:"headers,omitempty"`
+}
+
+// HTTPRequest returns an http_request field describing request as an
+// object: method, URL, protocol, remote address, content length and the
+// headers in HTTPHeaderAllowlist. The body is never read, and the URL is
+// logged without its query string or user info, which often carry tokens.
+// A nil request logs null.
+//
+//	jl.Info("proxying", HTTPRequest(r))
+func HTTPRequest(request *http.Request) Field {
+	if request == nil {
+		return Any(HTTPRequestKey, nil)
+	}
+	value := httpRequestValue{
+		Method:        request.Method,
+		Proto:         request.Proto,
+		RemoteAddr:    request.RemoteAddr,
+		ContentLength: request.ContentLength,
+		Headers:       allowedHeaders(request.Header),
+	}
+	if request.URL != nil {
+		value.URL = safeURL(request)
+	}
+	return Any(HTTPRequestKey, value)
+}
+
+// HTTPResponse returns an http_response field describing response as an
+// object: status code, protocol, content length and the headers in
+// HTTPHeaderAllowlist. The body is never read. A nil response logs null.
+func HTTPResponse(response *http.Response) Field {
+	if response == nil {
+		return Any(HTTPResponseKey, nil)
+	}
+	return Any(HTTPResponseKey, httpResponseValue{
+		Status:        response.StatusCode,
+		Proto:         response.Proto,
+		ContentLength: response.ContentLength,
+		Headers:       allowedHeaders(response.Header),
+	})
+}
+
+// safeURL renders the request URL without user info, query or fragment.
+// Server requests carry only a path, so the scheme and Host header fill in
+// the rest.
+func safeURL(request *http.Request) string {
+	target := *request.URL
+	target.User = nil
+	target.RawQuery = ""
+	target.ForceQuery = false
+	target.Fragment = ""
+	target.RawFragment = ""
+	if target.Host == "" && request.Host != "" {
+		target.Host = request.Host
+		target.Scheme = "http"
+		if request.TLS != nil {
+			target.Scheme = "https"
+		}
+	}
+	return target.String()
+}
+
+// allowedHeaders copies the allowlisted, non-credential headers of header,
+// joining repeated values with ", " and capping their length.
+func allowedHeaders(header http.Header) map[string]string {
+	var headers map[string]string
+	for _, name := range HTTPHeaderAllowlist {
+		name = http.CanonicalHeaderKey(name)
+		if isCredentialHeader(name) {
+			continue
+		}
+		values := header[name]
+		if len(values) == 0 {
+			continue
+		}
+		value := strings.Join(values, ", ")
+		if len(value) > maxHTTPHeaderValue {
+			value = value[:maxHTTPHeaderValue]
+		}
+		if headers == nil {
+			headers = make(map[string]string, len(HTTPHeaderAllowlist))
+		}
+		headers[name] = value
+	}
+	return headers
+}
+
+func isCredentialHeader(name string) bool {
+	switch name {
+	case "Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie":
+		return true
+	}
+	return false
+}
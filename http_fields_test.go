@@ -0,0 +1,65 @@
+package golog
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHTTPRequestField(t *testing.T) {
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(WithOutput(buf))
+	request := httptest.NewRequest(http.MethodPost, "/orders?token=secret#top", strings.NewReader("{}"))
+	request.Host = "api.example.com"
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Authorization", "Bearer abc")
+	request.Header.Set("Cookie", "session=abc")
+	request.Header.Add("X-Forwarded-For", "10.0.0.1")
+	request.Header.Add("X-Forwarded-For", "10.0.0.2")
+
+	jl.Info("incoming", HTTPRequest(request))
+
+	line := buf.String()
+	want := `"http_request":{"method":"POST","url":"http://api.example.com/orders","proto":"HTTP/1.1","remote_addr":"192.0.2.1:1234","content_length":2,"headers":{`
+	if !strings.Contains(line, want) {
+		t.Fatalf("expected %s in %s", want, line)
+	}
+	for _, leaked := range []string{"secret", "Bearer", "session", "top"} {
+		if strings.Contains(line, leaked) {
+			t.Fatalf("expected %q to stay out of the log: %s", leaked, line)
+		}
+	}
+	if !strings.Contains(line, `"X-Forwarded-For":"10.0.0.1, 10.0.0.2"`) || !strings.Contains(line, `"Content-Type":"application/json"`) {
+		t.Fatalf("expected allowlisted headers: %s", line)
+	}
+}
+
+func TestHTTPResponseField(t *testing.T) {
+	original := HTTPHeaderAllowlist
+	HTTPHeaderAllowlist = append([]string{"set-cookie", "retry-after"}, original...)
+	defer func() { HTTPHeaderAllowlist = original }()
+
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(WithOutput(buf))
+	response := &http.Response{
+		StatusCode:    http.StatusServiceUnavailable,
+		Proto:         "HTTP/2.0",
+		ContentLength: -1,
+		Header: http.Header{
+			"Retry-After": {"30"},
+			"Set-Cookie":  {"session=abc"},
+		},
+	}
+
+	jl.Warn("upstream", HTTPResponse(response))
+
+	want := `"http_response":{"status":503,"proto":"HTTP/2.0","content_length":-1,"headers":{"Retry-After":"30"}}`
+	if !strings.Contains(buf.String(), want) {
+		t.Fatalf("expected %s in %s", want, buf.String())
+	}
+	if HTTPResponse(nil).Value() != nil || HTTPRequest(nil).Value() != nil {
+		t.Fatal("expected nil requests and responses to log null")
+	}
+}
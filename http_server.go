@@ -0,0 +1,134 @@
+package golog
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/http"
+	"time"
+)
+
+// contextLogger is implemented by loggers with ctx-aware methods, such as
+// JSONLogger. AccessLog uses them so context extractors (request IDs, trace
+// IDs) apply to access log entries.
+type contextLogger interface {
+	InfoContext(ctx context.Context, message string, fields ...Field)
+	WarnContext(ctx context.Context, message string, fields ...Field)
+	ErrorContext(ctx context.Context, message string, fields ...Field)
+}
+
+// AccessLog returns net/http middleware that logs one entry per request with
+// its method, path, status, response size, duration, remote address and user
+// agent. 5xx responses are logged at error level, 4xx at warn level and the
+// rest at info level.
+//
+// Routers built on net/http use it directly; echo accepts it through
+// echo.WrapMiddleware:
+//
+//	http.ListenAndServe(":8080", AccessLog(jl)(mux))
+//	e.Use(echo.WrapMiddleware(AccessLog(jl)))
+//
+// The gologgin module provides the same access log as a gin.HandlerFunc,
+// and gologecho an echo.Logger implementation.
+func AccessLog(l Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(recorder, r)
+			if !enabled(l, accessLevel(recorder.status)) {
+				return
+			}
+
+			fields := []Field{
+				Str(KeyMethod, r.Method),
+				Str(KeyPath, r.URL.Path),
+				Int(KeyStatus, recorder.status),
+				Int("bytes", recorder.bytes),
+				Duration("duration", time.Since(start)),
+				Str(KeyRemoteAddr, r.RemoteAddr),
+				Str(KeyUserAgent, r.UserAgent()),
+			}
+			logAccess(r.Context(), l, recorder.status, fields)
+		})
+	}
+}
+
+// accessLevel is the level AccessLog uses for a response status.
+func accessLevel(status int) Level {
+	switch {
+	case status >= 500:
+		return ErrorLevel
+	case status >= 400:
+		return WarnLevel
+	default:
+		return InfoLevel
+	}
+}
+
+func logAccess(ctx context.Context, l Logger, status int, fields []Field) {
+	const message = "http request"
+	if ctxLogger, ok := l.(contextLogger); ok {
+		switch {
+		case status >= 500:
+			ctxLogger.ErrorContext(ctx, message, fields...)
+		case status >= 400:
+			ctxLogger.WarnContext(ctx, message, fields...)
+		default:
+			ctxLogger.InfoContext(ctx, message, fields...)
+		}
+		return
+	}
+	switch {
+	case status >= 500:
+		l.Error(message, fields...)
+	case status >= 400:
+		l.Warn(message, fields...)
+	default:
+		l.Info(message, fields...)
+	}
+}
+
+// statusRecorder captures the status code and body size written by a
+// handler.
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	bytes       int
+	wroteHeader bool
+}
+
+func (recorder *statusRecorder) WriteHeader(status int) {
+	if !recorder.wroteHeader {
+		recorder.status = status
+		recorder.wroteHeader = true
+	}
+	recorder.ResponseWriter.WriteHeader(status)
+}
+
+func (recorder *statusRecorder) Write(p []byte) (int, error) {
+	recorder.wroteHeader = true
+	n, err := recorder.ResponseWriter.Write(p)
+	recorder.bytes += n
+	return n, err
+}
+
+// Flush implements http.Flusher for streaming handlers such as server-sent
+// events. It does nothing when the underlying writer can't flush.
+func (recorder *statusRecorder) Flush() {
+	recorder.wroteHeader = true
+	_ = http.NewResponseController(recorder.ResponseWriter).Flush()
+}
+
+// Hijack implements http.Hijacker for handlers taking over the connection,
+// such as WebSocket upgrades. It returns http.ErrNotSupported when the
+// underlying writer can't hijack.
+func (recorder *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return http.NewResponseController(recorder.ResponseWriter).Hijack()
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer for
+// flushing and hijacking.
+func (recorder *statusRecorder) Unwrap() http.ResponseWriter {
+	return recorder.ResponseWriter
+}
@@ -0,0 +1,133 @@
+package golog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAccessLogLogsRequests(t *testing.T) {
+	buf := &bytes.Buffer{}
+	type routeKey struct{}
+	jl := NewJSONLoggerWithOptions(
+		WithOutput(buf),
+		WithContextExtractor(func(ctx context.Context, fields []Field) []Field {
+			if route, ok := ctx.Value(routeKey{}).(string); ok {
+				fields = append(fields, Str("route", route))
+			}
+			return fields
+		}),
+	)
+
+	handler := AccessLog(jl)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/missing":
+			http.NotFound(w, r)
+		case "/boom":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			_, _ = io.WriteString(w, "hello")
+		}
+	}))
+
+	for _, path := range []string{"/ok", "/missing", "/boom"} {
+		request := httptest.NewRequest(http.MethodGet, path, nil)
+		request = request.WithContext(context.WithValue(request.Context(), routeKey{}, path))
+		handler.ServeHTTP(httptest.NewRecorder(), request)
+	}
+
+	entries := readEntries(t, buf.Bytes())
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(entries))
+	}
+	ok := entries[0].FieldMap()
+	if ok["status"] != int64(200) || ok["bytes"] != int64(5) || ok["method"] != "GET" || ok["route"] != "/ok" {
+		t.Fatalf("unexpected entry: %v", ok)
+	}
+	if entries[1].Level != WarnLevel || entries[2].Level != ErrorLevel {
+		t.Fatalf("unexpected levels: %v %v", entries[1].Level, entries[2].Level)
+	}
+}
+
+func TestAccessLogWithPlainLogger(t *testing.T) {
+	var got []Level
+	l := Filter(&BLogger{b: &bytes.Buffer{}}, func(level Level, message string, fields []Field) bool {
+		got = append(got, level)
+		return false
+	})
+
+	handler := AccessLog(l)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if len(got) != 1 || got[0] != WarnLevel {
+		t.Fatalf("expected a single warn entry, got %v", got)
+	}
+}
+
+func TestAccessLogSkipsDisabledLevels(t *testing.T) {
+	calls := 0
+	jl := NewJSONLoggerWithOptions(WithOutput(io.Discard), WithLevel(WarnLevel))
+	l := Filter(jl, func(level Level, message string, fields []Field) bool {
+		calls++
+		return true
+	})
+
+	handler := AccessLog(l)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	if calls != 0 {
+		t.Fatalf("expected the disabled info entry to be skipped before building fields")
+	}
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/missing", nil))
+	if calls != 1 {
+		t.Fatalf("expected the warn entry to be logged, got %d calls", calls)
+	}
+}
+
+func TestAccessLogKeepsFlusherAndHijacker(t *testing.T) {
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(WithOutput(buf))
+
+	streamed := AccessLog(jl)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "data: 1\n\n")
+		w.(http.Flusher).Flush()
+		if _, _, err := w.(http.Hijacker).Hijack(); !errors.Is(err, http.ErrNotSupported) {
+			t.Errorf("expected the recorder not to hijack, got %v", err)
+		}
+	}))
+	recorder := httptest.NewRecorder()
+	streamed.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/events", nil))
+	if !recorder.Flushed {
+		t.Fatal("expected the flush to reach the underlying writer")
+	}
+
+	hijacked := httptest.NewServer(AccessLog(jl)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, rw, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Errorf("hijack: %v", err)
+			return
+		}
+		defer conn.Close()
+		_, _ = rw.WriteString("HTTP/1.1 200 OK\r\nContent-Length: 3\r\nConnection: close\r\n\r\nraw")
+		_ = rw.Flush()
+	})))
+	defer hijacked.Close()
+	response, err := http.Get(hijacked.URL)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	body, _ := io.ReadAll(response.Body)
+	response.Body.Close()
+	if string(body) != "raw" {
+		t.Fatalf("expected the hijacked connection's response, got %q", body)
+	}
+}
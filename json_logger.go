@@ -3,41 +3,24 @@ package golog
 import (
 	"io"
 	"os"
+	"slices"
 	"sync"
 	"sync/atomic"
 	"time"
 )
 
-// Level represents a logging severity threshold.
-//
-// Higher values mean higher severity.
-// A logger configured with a given level writes entries whose level is
-// greater than or equal to that configured level.
-type Level int32
-
-const (
-	// DebugLevel enables debug, info, warn, and error logs.
-	DebugLevel Level = 0 + iota
-	// InfoLevel enables info, warn, and error logs.
-	InfoLevel
-	// WarnLevel enables warn and error logs.
-	WarnLevel
-	// ErrorLevel enables only error logs.
-	ErrorLevel
-)
-
 // JSONLogger is a small, fast, concurrent-safe JSON logger implementation.
 // Create one with NewJSONLogger or NewJSONLoggerWithOptions. Use the Option
 // helpers to customize level, output and base fields.
 type JSONLogger struct {
+	// loggerState is shared by every configuration of the logger; the
+	// other fields make up one configuration. See Reconfigure.
+	*loggerState
 	output     io.Writer
 	baseFields map[string]any
-	// level is stored as int32 and accessed atomically so concurrent SetLevel
-	// calls are safe without a mutex, and the field is still comparable as Level
-	// in internal tests via a direct cast.
-	level      Level
-	mutex      sync.Mutex
-	bufferPool sync.Pool
+	// mutex serializes writes to output. Configurations with the same
+	// output share it.
+	mutex *sync.Mutex
 	// lockWrites protects output writes for concurrency-safe ordering by default.
 	// You can disable it with WithWriteLock(false) for maximum throughput when
 	// writing to a thread-safe sink.
@@ -45,10 +28,113 @@ type JSONLogger struct {
 	// timeFormat controls how timestamps are rendered. Defaults to
 	// time.RFC3339Nano but can be changed with WithCustomTimeFormat.
 	timeFormat string
+	// eventTimeKey, when set, carries WithEntryTime times instead of
+	// timestamp; see WithEventTimeField.
+	eventTimeKey string
+	// fixedTimeLayout marks a timeFormat with a fast appender writing
+	// fixedTimeDigits fractional digits; see fixedTimeLayouts.
+	fixedTimeLayout bool
+	fixedTimeDigits int
+	// timePrecision truncates entry timestamps; zero or a nanosecond keeps
+	// them whole.
+	timePrecision time.Duration
+	// sequenceNumbers stamps written entries with the next sequence value;
+	// see WithSequenceNumbers.
+	sequenceNumbers bool
+	// goroutineID stamps entries with the logging goroutine's ID; see
+	// WithGoroutineID.
+	goroutineID bool
+	// sortFields writes per-call fields sorted by key; see WithDeterministic.
+	sortFields bool
+	// middlewares are the WithMiddleware functions in registration order,
+	// and middleware the chain composed from them, or nil.
+	middlewares []func(next EntryFunc) EntryFunc
+	middleware  EntryFunc
+	// errorHooks are called for written error entries; see WithErrorHook.
+	errorHooks []func(msg string, fields map[string]any)
+	// timeLocation is the zone of entry timestamps; nil means UTC.
+	timeLocation *time.Location
+	// levelValues holds the pre-encoded JSON value written for each level,
+	// e.g. `"info"`. Override with WithLevelStrings.
+	levelValues [ErrorLevel + 1][]byte
+	// levelNames holds the WithLevelStrings names, for writers that don't
+	// write JSON. Empty entries use Level.String.
+	levelNames [ErrorLevel + 1]string
 	// baseFieldsCache holds a pre-encoded JSON fragment of all base fields,
 	// e.g. `,"service":"api","version":"1.0"`. Built once on first log call.
 	baseFieldsCache []byte
-	baseFieldsOnce  sync.Once
+	// baseFieldList holds the base fields as Fields sorted by key, for
+	// LogWriters that receive them through Entry.Fields.
+	baseFieldList  []Field
+	baseFieldsOnce *sync.Once
+	// encoder holds the value-encoding policy (duration format, etc.).
+	encoder encoder
+	// writer formats each entry. Defaults to the built-in JSON writer.
+	writer LogWriter
+	// clock supplies entry timestamps. Defaults to time.Now; override with
+	// WithClock.
+	clock func() time.Time
+	// contextExtractors add fields from the context passed to the
+	// ctx-aware methods. Register them with WithContextExtractor.
+	contextExtractors []ContextExtractor
+	// flightRecorder, when set, keeps recent entries below the level in
+	// memory. dumpOnError writes them out before each error entry.
+	flightRecorder *flightRecorder
+	dumpOnError    bool
+	// async is the queueing writer installed by WithAsync, if any.
+	async *asyncWriter
+	// watchdog is the output guard installed by WithWriteWatchdog, if any.
+	watchdog *watchdogWriter
+	// development panics on malformed entries; see WithDevelopmentMode.
+	development bool
+	// schema validates leveled entries; see WithSchema.
+	schema *schemaValidator
+	// eventSchemaVersion is written with every Event. Empty means "1".
+	eventSchemaVersion string
+	// sinks receive every written entry alongside the output.
+	sinks []Sink
+	// labelKeys are the field keys indexing sinks send as labels; see
+	// WithLabelKeys.
+	labelKeys []string
+	// healthInterval, when positive, writes a Healthcheck entry that often
+	// until Close.
+	healthInterval time.Duration
+	// levelStats counts written entries per level; see WithLevelStats.
+	levelStats *levelStats
+	// shutdownTimeout bounds the shutdown Run performs.
+	shutdownTimeout time.Duration
+	// inflight counts the entries being written with this configuration, so
+	// Reconfigure can wait for the writes to an output it replaces.
+	inflight *inflightCount
+}
+
+// loggerState is the part of a JSONLogger that outlives Reconfigure: the
+// level, the counters and the current configuration.
+type loggerState struct {
+	// level is stored as int32 and accessed atomically so concurrent SetLevel
+	// calls are safe without a mutex, and the field is still comparable as Level
+	// in internal tests via a direct cast.
+	level      Level
+	bufferPool sync.Pool
+	sequence   atomic.Uint64
+	// rateLimits holds the *rateLimitState of every Once/Every key.
+	rateLimits sync.Map
+	// levelOverride tracks a pending WithTemporaryLevel revert.
+	levelOverride levelOverride
+	// writeErrors tracks failed writes to the output and sinks.
+	writeErrors errorTracker
+	// healthStop ends the WithHealthcheckInterval loop, which closes
+	// healthDone.
+	healthStop chan struct{}
+	healthDone chan struct{}
+	healthOnce sync.Once
+	// stopped is set once Run begins shutting down; later entries go to
+	// os.Stderr.
+	stopped atomic.Bool
+	// config is the configuration entries are written with. configMutex
+	// serializes Reconfigure, which publishes a new one.
+	config      atomic.Pointer[JSONLogger]
+	configMutex sync.Mutex
 }
 
 // Option configures the JSONLogger.
@@ -60,19 +146,34 @@ type Option func(*JSONLogger)
 //   - No base fields
 func NewJSONLogger() *JSONLogger {
 	l := &JSONLogger{
+		loggerState: &loggerState{
+			level: InfoLevel,
+			bufferPool: sync.Pool{
+				New: func() any {
+					// Pre-allocate a reusable byte slice for the hot path.
+					slice := make([]byte, 0, 512)
+					return &slice
+				},
+			},
+		},
 		output:     os.Stdout,
 		baseFields: make(map[string]any),
-		level:      InfoLevel,
+		mutex:      new(sync.Mutex),
 		lockWrites: true,
 		timeFormat: time.RFC3339Nano,
-		bufferPool: sync.Pool{
-			New: func() any {
-				// Pre-allocate a reusable byte slice for the hot path.
-				slice := make([]byte, 0, 512)
-				return &slice
-			},
+		clock:      time.Now,
+		encoder:    encoder{reflectFallback: true},
+		levelValues: [ErrorLevel + 1][]byte{
+			DebugLevel: appendQuoteBytes(nil, DebugLevel.String()),
+			InfoLevel:  appendQuoteBytes(nil, InfoLevel.String()),
+			WarnLevel:  appendQuoteBytes(nil, WarnLevel.String()),
+			ErrorLevel: appendQuoteBytes(nil, ErrorLevel.String()),
 		},
+		baseFieldsOnce: new(sync.Once),
+		inflight:       new(inflightCount),
 	}
+	l.writer = jsonLogWriter{logger: l}
+	l.config.Store(l)
 	return l
 }
 
@@ -84,12 +185,22 @@ func NewJSONLoggerWithOptions(options ...Option) *JSONLogger {
 	for _, option := range options {
 		option(jsonLogger)
 	}
+	if jsonLogger.healthInterval > 0 {
+		jsonLogger.healthStop = make(chan struct{})
+		jsonLogger.healthDone = make(chan struct{})
+		go jsonLogger.runHealthchecks()
+	}
+	if stats := jsonLogger.levelStats; stats != nil && stats.config.SummaryInterval > 0 {
+		stats.stop = make(chan struct{})
+		stats.done = make(chan struct{})
+		go jsonLogger.runLevelSummaries()
+	}
 
 	return jsonLogger
 }
 
 // WithLevel sets the minimum level for the logger. Logs with lower severity
-// than the configured level are dropped.
+// than the configured level are dropped; OffLevel drops everything.
 func WithLevel(logLevel Level) Option {
 	return func(jsonLogger *JSONLogger) { atomic.StoreInt32((*int32)(&jsonLogger.level), int32(logLevel)) }
 }
@@ -116,29 +227,41 @@ func WithWriteLock(enabled bool) Option {
 }
 
 // WithBaseFields adds the provided fields to the logger's base fields. These
-// fields are included in every emitted log entry.
+// fields are included in every emitted log entry. The map and the values
+// are deep copied, so mutating them afterwards doesn't affect the logger.
 func WithBaseFields(fields map[string]any) Option {
 	return func(jsonLogger *JSONLogger) {
 		for key, value := range fields {
-			jsonLogger.baseFields[key] = value
+			jsonLogger.baseFields[key] = deepCopy(value)
 		}
 		// Reset cache so it will be rebuilt on next log call.
-		jsonLogger.baseFieldsOnce = sync.Once{}
+		jsonLogger.baseFieldsOnce = new(sync.Once)
 	}
 }
 
 // WithBaseField adds a single base field key/value that will be included in
-// every log entry.
+// every log entry. Like WithBaseFields it keeps a deep copy of value.
 func WithBaseField(key string, value any) Option {
 	return func(jsonLogger *JSONLogger) {
-		jsonLogger.baseFields[key] = value
+		jsonLogger.baseFields[key] = deepCopy(value)
 		// Reset cache so it will be rebuilt on next log call.
-		jsonLogger.baseFieldsOnce = sync.Once{}
+		jsonLogger.baseFieldsOnce = new(sync.Once)
 	}
 }
 
+// Fixed-width RFC 3339 layouts for WithCustomTimeFormat. Unlike
+// time.RFC3339Nano they keep trailing zeros, so every timestamp has the same
+// length.
+const (
+	RFC3339Milli = "2006-01-02T15:04:05.000Z07:00"
+	RFC3339Micro = "2006-01-02T15:04:05.000000Z07:00"
+)
+
 // WithCustomTimeFormat sets a custom time format for the timestamp field.
-// If not set, the logger uses RFC3339Nano.
+// If not set, the logger uses RFC3339Nano. time.RFC3339, RFC3339Milli,
+// RFC3339Micro and their nanosecond counterpart are formatted by a
+// hand-written appender as fast as the default; other layouts go through
+// time.Time.AppendFormat.
 func WithCustomTimeFormat(timeFormat string) Option {
 	return func(jsonLogger *JSONLogger) {
 		if timeFormat == "" {
@@ -146,7 +269,143 @@ func WithCustomTimeFormat(timeFormat string) Option {
 		}
 
 		jsonLogger.timeFormat = timeFormat
+		jsonLogger.fixedTimeDigits, jsonLogger.fixedTimeLayout = fixedTimeLayouts[timeFormat]
+	}
+}
+
+// WithTimeLocation writes entry timestamps in location instead of UTC, for
+// log schemas that require local or business time. With the default
+// RFC 3339 format the offset is included, e.g.
+// "2026-03-01T09:30:00.123+01:00". Time values inside fields stay UTC. A
+// nil location or time.UTC restores the default.
+func WithTimeLocation(location *time.Location) Option {
+	return func(jsonLogger *JSONLogger) {
+		if location == time.UTC {
+			location = nil
+		}
+		jsonLogger.timeLocation = location
+	}
+}
+
+// WithTimestampPrecision truncates entry timestamps to a multiple of
+// precision, e.g. time.Millisecond for systems such as BigQuery that reject
+// nanosecond timestamps. With the default format trailing zeros are dropped,
+// so lines also get shorter; combine with WithCustomTimeFormat(RFC3339Milli)
+// for fixed-width timestamps. Zero or negative values restore full precision.
+func WithTimestampPrecision(precision time.Duration) Option {
+	return func(jsonLogger *JSONLogger) {
+		if precision < time.Nanosecond {
+			precision = 0
+		}
+		jsonLogger.timePrecision = precision
+	}
+}
+
+// WithClock sets the function used to timestamp entries, so tests and replay
+// tools can produce deterministic output. A nil clock restores time.Now.
+func WithClock(clock func() time.Time) Option {
+	return func(jsonLogger *JSONLogger) {
+		if clock == nil {
+			clock = time.Now
+		}
+		jsonLogger.clock = clock
+	}
+}
+
+// WithDurationFormat sets how time.Duration values are encoded: as integer
+// nanoseconds (DurationNanos, the default) or as a human readable string
+// (DurationString).
+func WithDurationFormat(format DurationFormat) Option {
+	return func(jsonLogger *JSONLogger) {
+		jsonLogger.encoder.durationFormat = format
+	}
+}
+
+// WithFloatPolicy sets how NaN and ±Inf float values are encoded. The default,
+// FloatAsNull, writes null so a single bad metric can't produce invalid JSON.
+func WithFloatPolicy(policy FloatPolicy) Option {
+	return func(jsonLogger *JSONLogger) {
+		jsonLogger.encoder.floatPolicy = policy
+	}
+}
+
+// WithStrictJSON guarantees RFC 8259-valid output: strings are validated as
+// UTF-8 (invalid sequences become U+FFFD) and U+2028/U+2029 are escaped for
+// consumers that embed lines in JavaScript. Pure ASCII strings keep the fast
+// path, so the cost is only paid for non-ASCII content.
+func WithStrictJSON() Option {
+	return func(jsonLogger *JSONLogger) {
+		jsonLogger.encoder.strict = true
+	}
+}
+
+// WithOmitEmpty drops base and per-call fields whose value is nil, an empty
+// string, or a zero number, shrinking entries with sparsely populated fields.
+// Use Omitempty to opt in for individual fields instead.
+func WithOmitEmpty() Option {
+	return func(jsonLogger *JSONLogger) {
+		jsonLogger.encoder.omitEmpty = true
+	}
+}
+
+// WithLevelStrings overrides the value written to the "level" field for the
+// given levels, e.g. {WarnLevel: "WARNING", ErrorLevel: "ERR"}. Levels that are
+// not present in the map keep their default lowercase names. Values that are
+// valid JSON numbers (e.g. "4") are written unquoted to support numeric
+// severity schemas.
+func WithLevelStrings(levelStrings map[Level]string) Option {
+	return func(jsonLogger *JSONLogger) {
+		for level, levelString := range levelStrings {
+			if level < DebugLevel || level > ErrorLevel {
+				continue
+			}
+			jsonLogger.levelNames[level] = levelString
+			if isJSONNumber(levelString) {
+				jsonLogger.levelValues[level] = []byte(levelString)
+			} else {
+				jsonLogger.levelValues[level] = appendQuoteStrict(nil, levelString)
+			}
+		}
+	}
+}
+
+// isJSONNumber reports whether s is a valid JSON number literal.
+func isJSONNumber(s string) bool {
+	if s == "" {
+		return false
 	}
+	i := 0
+	if s[i] == '-' {
+		i++
+	}
+	digits := func() int {
+		start := i
+		for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+			i++
+		}
+		return i - start
+	}
+	if i < len(s) && s[i] == '0' {
+		i++
+	} else if digits() == 0 {
+		return false
+	}
+	if i < len(s) && s[i] == '.' {
+		i++
+		if digits() == 0 {
+			return false
+		}
+	}
+	if i < len(s) && (s[i] == 'e' || s[i] == 'E') {
+		i++
+		if i < len(s) && (s[i] == '+' || s[i] == '-') {
+			i++
+		}
+		if digits() == 0 {
+			return false
+		}
+	}
+	return i == len(s)
 }
 
 // buildBaseFieldsCache pre-encodes all base fields into a reusable []byte fragment.
@@ -154,90 +413,231 @@ func WithCustomTimeFormat(timeFormat string) Option {
 func (jsonLogger *JSONLogger) buildBaseFieldsCache() {
 	if len(jsonLogger.baseFields) == 0 {
 		jsonLogger.baseFieldsCache = nil
+		jsonLogger.baseFieldList = nil
 		return
 	}
+
+	keys := make([]string, 0, len(jsonLogger.baseFields))
+	for fieldKey := range jsonLogger.baseFields {
+		keys = append(keys, fieldKey)
+	}
+	slices.Sort(keys)
+	fieldList := make([]Field, len(keys))
+	for i, fieldKey := range keys {
+		fieldList[i] = Any(fieldKey, jsonLogger.baseFields[fieldKey])
+	}
+	jsonLogger.baseFieldList = fieldList
+
 	cache := make([]byte, 0, 128)
-	for fieldKey, fieldValue := range jsonLogger.baseFields {
+	for _, fieldKey := range keys {
+		fieldValue := jsonLogger.baseFields[fieldKey]
+		if jsonLogger.encoder.omitEmpty && isEmptyAny(fieldValue) {
+			continue
+		}
 		cache = append(cache, ',')
-		cache = appendQuoteBytes(cache, fieldKey)
+		cache = jsonLogger.encoder.appendKey(cache, fieldKey)
 		cache = append(cache, ':')
-		var ok bool
-		cache, ok = appendValueBytes(cache, fieldValue)
-		if !ok {
-			cache = appendQuoteBytes(cache, "<unsupported>")
-		}
+		cache = jsonLogger.encoder.appendValueOrPlaceholder(cache, fieldValue)
 	}
 	jsonLogger.baseFieldsCache = cache
 }
 
-// logFields writes a JSON entry using typed Field values.
-func (jsonLogger *JSONLogger) logFields(logLevel Level, levelString, message string, fields []Field) {
-	if Level(atomic.LoadInt32((*int32)(&jsonLogger.level))) > logLevel {
-		return
+// appendTimestamp renders t in UTC, or the WithTimeLocation zone, using the
+// configured time format.
+func (jsonLogger *JSONLogger) appendTimestamp(dst []byte, t time.Time) []byte {
+	if jsonLogger.timePrecision > time.Nanosecond {
+		t = t.Truncate(jsonLogger.timePrecision)
+	}
+	if jsonLogger.timeLocation != nil {
+		return t.In(jsonLogger.timeLocation).AppendFormat(dst, jsonLogger.timeFormat)
+	}
+	t = t.UTC()
+	if jsonLogger.timeFormat == time.RFC3339Nano {
+		return appendRFC3339NanoUTC(dst, t)
+	}
+	if jsonLogger.fixedTimeLayout {
+		return appendRFC3339FixedUTC(dst, t, jsonLogger.fixedTimeDigits)
+	}
+	return t.AppendFormat(dst, jsonLogger.timeFormat)
+}
+
+// levelName returns the name set with WithLevelStrings for logLevel, or
+// its default name.
+func (jsonLogger *JSONLogger) levelName(logLevel Level) string {
+	if logLevel >= DebugLevel && logLevel <= ErrorLevel && jsonLogger.levelNames[logLevel] != "" {
+		return jsonLogger.levelNames[logLevel]
+	}
+	return logLevel.String()
+}
+
+// levelValue returns the encoded "level" value for logLevel.
+func (jsonLogger *JSONLogger) levelValue(logLevel Level) []byte {
+	if logLevel < DebugLevel || logLevel > ErrorLevel {
+		return appendQuoteBytes(nil, logLevel.String())
 	}
+	return jsonLogger.levelValues[logLevel]
+}
+
+// fieldScratchPool holds reusable []Field slices for appendWithCustomWriter.
+var fieldScratchPool = sync.Pool{
+	New: func() any {
+		scratch := make([]Field, 0, 16)
+		return &scratch
+	},
+}
 
+// appendWithCustomWriter calls a user supplied LogWriter. The base and
+// per-call fields are copied into a pooled slice first: handing the caller's
+// variadic slice to an interface method would force it onto the heap on every
+// call, even for loggers using the default writer.
+func (jsonLogger *JSONLogger) appendWithCustomWriter(dst []byte, seq uint64, timestamp time.Time, logLevel Level, message string, fields []Field) []byte {
 	jsonLogger.baseFieldsOnce.Do(jsonLogger.buildBaseFieldsCache)
 
-	bufPtr := jsonLogger.bufferPool.Get().(*[]byte)
-	buffer := (*bufPtr)[:0]
+	scratchPtr := fieldScratchPool.Get().(*[]Field)
+	scratch := jsonLogger.appendGeneratedFields((*scratchPtr)[:0], seq)
+	scratch = append(scratch, jsonLogger.baseFieldList...)
+	scratch = append(scratch, fields...)
+
+	dst = jsonLogger.writer.AppendLog(dst, Entry{
+		Time:    timestamp,
+		Level:   logLevel,
+		Message: message,
+		Fields:  scratch,
+		logger:  jsonLogger,
+	})
+
+	clear(scratch)
+	*scratchPtr = scratch[:0]
+	fieldScratchPool.Put(scratchPtr)
+	return dst
+}
+
+// logFields formats an entry with the current configuration's LogWriter and
+// writes it.
+func (jsonLogger *JSONLogger) logFields(logLevel Level, message string, fields []Field) {
+	configured := Level(atomic.LoadInt32((*int32)(&jsonLogger.level)))
+	if configured > logLevel && (configured == OffLevel || !forced(fields)) {
+		if config := jsonLogger.current(); config.flightRecorder != nil && configured != OffLevel {
+			config.flightRecorder.record(config, logLevel, message, fields)
+		}
+		return
+	}
+	config, inflight := jsonLogger.acquire()
+	defer inflight.release()
+	if config.development {
+		config.checkEntry(message, fields)
+	}
+	if config.schema != nil {
+		var ok bool
+		if fields, ok = config.schema.validate(config, fields); !ok {
+			return
+		}
+	}
+	if logLevel >= ErrorLevel && config.dumpOnError && config.flightRecorder != nil {
+		config.flightRecorder.drain(config.writeOutput)
+	}
+
+	config.emit(logLevel, message, fields)
+}
 
-	timeFormat := jsonLogger.timeFormat
+// emit writes an entry that passed filtering to the output and the sinks,
+// through the middleware chain when one is configured.
+func (jsonLogger *JSONLogger) emit(logLevel Level, message string, fields []Field) {
+	if jsonLogger.middleware != nil {
+		jsonLogger.runMiddleware(logLevel, message, fields)
+		return
+	}
 
-	buffer = append(buffer, '{')
-	buffer = append(buffer, `"timestamp":"`...)
-	var tsBuf [64]byte
-	now := time.Now().UTC()
-	if timeFormat == time.RFC3339Nano {
-		buffer = append(buffer, appendRFC3339NanoUTC(tsBuf[:0], now)...)
-	} else {
-		buffer = now.AppendFormat(buffer, timeFormat)
+	timestamp, fields := jsonLogger.entryTime(fields)
+	if jsonLogger.levelStats != nil {
+		jsonLogger.levelStats.record(timestamp, logLevel)
 	}
-	buffer = append(buffer, '"')
-	buffer = append(buffer, `,"level":"`...)
-	buffer = append(buffer, levelString...)
-	buffer = append(buffer, '"')
-	buffer = append(buffer, `,"message":`...)
-	buffer = appendQuoteBytes(buffer, message)
+	bufPtr := jsonLogger.bufferPool.Get().(*[]byte)
+	buffer, seq := jsonLogger.writeNumbered((*bufPtr)[:0], timestamp, logLevel, message, fields)
+
+	*bufPtr = buffer[:0]
+	jsonLogger.bufferPool.Put(bufPtr)
 
-	if jsonLogger.baseFieldsCache != nil {
-		buffer = append(buffer, jsonLogger.baseFieldsCache...)
+	if len(jsonLogger.sinks) > 0 && !jsonLogger.stopped.Load() {
+		jsonLogger.writeSinksAt(seq, timestamp, logLevel, message, fields)
+	}
+	if len(jsonLogger.errorHooks) > 0 && logLevel >= ErrorLevel {
+		jsonLogger.runErrorHooks(message, fields)
 	}
+}
+
+// appendEntry formats an entry with the configured LogWriter into dst. A
+// non-zero seq is written as the SequenceKey field.
+func (jsonLogger *JSONLogger) appendEntry(dst []byte, seq uint64, logLevel Level, message string, fields []Field) []byte {
+	timestamp, fields := jsonLogger.entryTime(fields)
+	return jsonLogger.appendEntryAt(dst, seq, timestamp, logLevel, message, fields)
+}
 
-	for i := range fields {
-		buffer = appendFieldBytes(buffer, fields[i])
+// appendEntryAt is appendEntry for fields whose control fields have already
+// been applied.
+func (jsonLogger *JSONLogger) appendEntryAt(dst []byte, seq uint64, timestamp time.Time, logLevel Level, message string, fields []Field) []byte {
+	// Calling the default writer directly instead of through the interface
+	// lets the compiler prove fields doesn't escape, keeping the common case
+	// allocation free.
+	fields = jsonLogger.sortedFields(jsonLogger.encoder.limitFields(fields))
+	if writer, ok := jsonLogger.writer.(jsonLogWriter); ok {
+		return writer.appendEntry(dst, seq, timestamp, logLevel, message, fields)
 	}
+	return jsonLogger.appendWithCustomWriter(dst, seq, timestamp, logLevel, message, fields)
+}
 
-	buffer = append(buffer, '}', '\n')
+// writeNumbered formats an entry into dst and writes it, returning the
+// record and its seq. With sequence numbers the seq is taken, and the entry
+// formatted and written, under the write lock, so concurrent entries reach
+// the output in seq order. Without the write lock the seq is taken
+// atomically, leaving outputs that serialize writes themselves, such as the
+// write watchdog, free to drop entries instead of queuing behind a stalled
+// write.
+func (jsonLogger *JSONLogger) writeNumbered(dst []byte, timestamp time.Time, logLevel Level, message string, fields []Field) ([]byte, uint64) {
+	if !jsonLogger.sequenceNumbers {
+		dst = jsonLogger.appendEntryAt(dst, 0, timestamp, logLevel, message, fields)
+		jsonLogger.writeOutput(dst)
+		return dst, 0
+	}
+	if !jsonLogger.lockWrites {
+		seq := jsonLogger.sequence.Add(1)
+		dst = jsonLogger.appendEntryAt(dst, seq, timestamp, logLevel, message, fields)
+		jsonLogger.writeRecord(dst)
+		return dst, seq
+	}
+	jsonLogger.mutex.Lock()
+	defer jsonLogger.mutex.Unlock()
+	seq := jsonLogger.sequence.Add(1)
+	dst = jsonLogger.appendEntryAt(dst, seq, timestamp, logLevel, message, fields)
+	jsonLogger.writeRecord(dst)
+	return dst, seq
+}
 
+// writeOutput writes a formatted record, holding the write lock if enabled.
+func (jsonLogger *JSONLogger) writeOutput(record []byte) {
 	if jsonLogger.lockWrites {
 		jsonLogger.mutex.Lock()
-		_, _ = jsonLogger.output.Write(buffer)
-		jsonLogger.mutex.Unlock()
-	} else {
-		_, _ = jsonLogger.output.Write(buffer)
+		defer jsonLogger.mutex.Unlock()
 	}
+	jsonLogger.writeRecord(record)
+}
 
-	*bufPtr = buffer[:0]
-	jsonLogger.bufferPool.Put(bufPtr)
+// writeRecord writes a formatted record. The caller holds the write lock if
+// it is enabled.
+func (jsonLogger *JSONLogger) writeRecord(record []byte) {
+	if jsonLogger.stopped.Load() {
+		jsonLogger.writeStopped(record)
+		return
+	}
+	if _, err := jsonLogger.output.Write(record); err != nil {
+		jsonLogger.writeErrors.record(err)
+	}
 }
 
 func appendRFC3339NanoUTC(dst []byte, t time.Time) []byte {
-	year, month, day := t.Date()
-	hour, minute, sec := t.Clock()
+	dst = timestampSecondCache.appendSecond(dst, t)
 	nsec := t.Nanosecond()
 
-	dst = appendFourDigitNumber(dst, year)
-	dst = append(dst, '-')
-	dst = appendTwoDigitNumber(dst, int(month))
-	dst = append(dst, '-')
-	dst = appendTwoDigitNumber(dst, day)
-	dst = append(dst, 'T')
-	dst = appendTwoDigitNumber(dst, hour)
-	dst = append(dst, ':')
-	dst = appendTwoDigitNumber(dst, minute)
-	dst = append(dst, ':')
-	dst = appendTwoDigitNumber(dst, sec)
-
 	if nsec != 0 {
 		dst = append(dst, '.')
 		start := len(dst)
@@ -2,10 +2,13 @@ package golog
 
 import (
 	"bytes"
+	"encoding/json"
+	"math"
 	"os"
 	"strings"
 	"testing"
 	"time"
+	"unicode/utf8"
 )
 
 func TestNewJSONLoggerWithDefaults(t *testing.T) {
@@ -151,6 +154,85 @@ func TestWithCustomTimeFormatEmpty(t *testing.T) {
 	}
 }
 
+func TestWithClockMakesOutputDeterministic(t *testing.T) {
+	// Given
+	buf := &bytes.Buffer{}
+	fixed := time.Date(2024, 5, 6, 7, 8, 9, 0, time.FixedZone("CEST", 2*60*60))
+	jl := NewJSONLoggerWithOptions(
+		WithOutput(buf),
+		WithClock(func() time.Time { return fixed }),
+	)
+
+	// When
+	jl.Info("tick", Int("n", 1))
+
+	// Then
+	want := `{"timestamp":"2024-05-06T05:08:09Z","level":"info","message":"tick","n":1}` + "\n"
+	if buf.String() != want {
+		t.Errorf("expected %q, got %q", want, buf.String())
+	}
+
+	// A nil clock falls back to time.Now.
+	WithClock(nil)(jl)
+	if jl.clock().Equal(fixed) {
+		t.Errorf("expected nil clock to restore time.Now")
+	}
+}
+
+func TestWithTimeLocation(t *testing.T) {
+	// Given
+	buf := &bytes.Buffer{}
+	fixed := time.Date(2024, 5, 6, 7, 8, 9, 120000000, time.UTC)
+	tokyo := time.FixedZone("JST", 9*60*60)
+	jl := NewJSONLoggerWithOptions(
+		WithOutput(buf),
+		WithClock(func() time.Time { return fixed }),
+		WithTimeLocation(tokyo),
+	)
+
+	// When
+	jl.Info("tick")
+	WithTimeLocation(time.UTC)(jl)
+	jl.Info("tock")
+
+	// Then
+	want := `{"timestamp":"2024-05-06T16:08:09.12+09:00","level":"info","message":"tick"}` + "\n" +
+		`{"timestamp":"2024-05-06T07:08:09.12Z","level":"info","message":"tock"}` + "\n"
+	if buf.String() != want {
+		t.Errorf("expected %q, got %q", want, buf.String())
+	}
+	entries := readEntries(t, buf.Bytes())
+	if !entries[0].Time.Equal(fixed) {
+		t.Errorf("expected the zoned timestamp to parse back to the same instant, got %v", entries[0].Time)
+	}
+}
+
+func TestWithTimestampPrecision(t *testing.T) {
+	// Given
+	buf := &bytes.Buffer{}
+	fixed := time.Date(2024, 5, 6, 7, 8, 9, 123456789, time.UTC)
+	jl := NewJSONLoggerWithOptions(
+		WithOutput(buf),
+		WithClock(func() time.Time { return fixed }),
+		WithTimestampPrecision(time.Millisecond),
+	)
+
+	// When
+	jl.Info("default format")
+	WithCustomTimeFormat(RFC3339Micro)(jl)
+	jl.Info("fixed width")
+	WithTimestampPrecision(0)(jl)
+	jl.Info("full precision")
+
+	// Then
+	want := `{"timestamp":"2024-05-06T07:08:09.123Z","level":"info","message":"default format"}` + "\n" +
+		`{"timestamp":"2024-05-06T07:08:09.123000Z","level":"info","message":"fixed width"}` + "\n" +
+		`{"timestamp":"2024-05-06T07:08:09.123456Z","level":"info","message":"full precision"}` + "\n"
+	if buf.String() != want {
+		t.Errorf("expected %q, got %q", want, buf.String())
+	}
+}
+
 func TestJSONLoggerIntegration(t *testing.T) {
 	// Given
 	buf := &bytes.Buffer{}
@@ -178,3 +260,129 @@ func TestJSONLoggerIntegration(t *testing.T) {
 		t.Errorf("expected output to contain level field, got %s", output)
 	}
 }
+
+func TestWithLevelStringsOverridesLevelValues(t *testing.T) {
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(
+		WithLevel(DebugLevel),
+		WithOutput(buf),
+		WithLevelStrings(map[Level]string{
+			WarnLevel:  "WARNING",
+			ErrorLevel: "3",
+		}),
+	)
+
+	jl.Info("info")
+	jl.Warn("warn")
+	jl.Error("error")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 lines, got %d: %s", len(lines), buf.String())
+	}
+	want := []any{"info", "WARNING", float64(3)}
+	for i, line := range lines {
+		var got map[string]any
+		if err := json.Unmarshal([]byte(line), &got); err != nil {
+			t.Fatalf("unmarshal line %d: %v -- %s", i, err, line)
+		}
+		if got["level"] != want[i] {
+			t.Fatalf("line %d: expected level %#v, got %#v", i, want[i], got["level"])
+		}
+	}
+}
+
+func TestIsJSONNumber(t *testing.T) {
+	valid := []string{"0", "3", "-1", "10.5", "1e3", "2.5E-2"}
+	invalid := []string{"", "-", "01", "1.", ".5", "1e", "WARN", "3 "}
+	for _, s := range valid {
+		if !isJSONNumber(s) {
+			t.Fatalf("expected %q to be a JSON number", s)
+		}
+	}
+	for _, s := range invalid {
+		if isJSONNumber(s) {
+			t.Fatalf("did not expect %q to be a JSON number", s)
+		}
+	}
+}
+
+func TestWithDurationFormat(t *testing.T) {
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(
+		WithOutput(buf),
+		WithDurationFormat(DurationString),
+		WithBaseField("timeout", 5*time.Second),
+	)
+
+	jl.Info("request", Duration("latency", 1500*time.Millisecond))
+
+	var got map[string]any
+	if err := json.Unmarshal([]byte(strings.TrimSpace(buf.String())), &got); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if got["latency"] != "1.5s" || got["timeout"] != "5s" {
+		t.Fatalf("expected string durations, got latency=%v timeout=%v", got["latency"], got["timeout"])
+	}
+}
+
+func TestWithFloatPolicyKeepsLinesValid(t *testing.T) {
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(WithOutput(buf), WithFloatPolicy(FloatAsError))
+
+	jl.Info("metric", Float64("ratio", math.NaN()), Float64("ok", 0.5))
+
+	var got map[string]any
+	if err := json.Unmarshal([]byte(strings.TrimSpace(buf.String())), &got); err != nil {
+		t.Fatalf("output is not valid JSON: %v -- %s", err, buf.String())
+	}
+	if got["ratio"] != "<unsupported>" || got["ok"] != 0.5 {
+		t.Fatalf("unexpected fields: %v", got)
+	}
+}
+
+func TestWithStrictJSONProducesValidUTF8(t *testing.T) {
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(WithOutput(buf), WithStrictJSON())
+
+	jl.Info("bad \xfe bytes", Str("path\xff", "line\u2028sep"))
+
+	line := strings.TrimSpace(buf.String())
+	if !utf8.ValidString(line) {
+		t.Fatalf("expected valid UTF-8 output, got %q", line)
+	}
+	if !strings.Contains(line, `"line\u2028sep"`) {
+		t.Fatalf("expected U+2028 to be escaped, got %s", line)
+	}
+	var got map[string]any
+	if err := json.Unmarshal([]byte(line), &got); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if got["message"] != "bad \ufffd bytes" {
+		t.Fatalf("unexpected message: %q", got["message"])
+	}
+}
+
+func TestWithOmitEmptyDropsEmptyFields(t *testing.T) {
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(
+		WithOutput(buf),
+		WithOmitEmpty(),
+		WithBaseFields(map[string]any{"region": "", "service": "api"}),
+	)
+
+	jl.Info("sparse", Str("user", ""), Int("count", 0), Bool("cached", false), Str("path", "/"))
+
+	var got map[string]any
+	if err := json.Unmarshal([]byte(strings.TrimSpace(buf.String())), &got); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	for _, key := range []string{"region", "user", "count"} {
+		if _, present := got[key]; present {
+			t.Fatalf("expected %q to be omitted, got %v", key, got)
+		}
+	}
+	if got["service"] != "api" || got["path"] != "/" || got["cached"] != false {
+		t.Fatalf("expected non-empty fields to remain, got %v", got)
+	}
+}
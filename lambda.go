@@ -0,0 +1,91 @@
+package golog
+
+import (
+	"context"
+	"os"
+	"sync"
+)
+
+// Field keys written by WithLambdaDefaults.
+const (
+	LambdaRequestIDKey = "aws_request_id"
+	LambdaColdStartKey = "cold_start"
+	LambdaTraceIDKey   = "xray_trace_id"
+)
+
+// lambdaTimeFormat is the millisecond UTC layout used by WithLambdaDefaults.
+const lambdaTimeFormat = "2006-01-02T15:04:05.000Z"
+
+// LambdaEnvFields covers the variables the AWS Lambda runtime sets for every
+// function.
+var LambdaEnvFields = []EnvField{
+	{Env: "AWS_LAMBDA_FUNCTION_NAME", Key: "function_name"},
+	{Env: "AWS_LAMBDA_FUNCTION_VERSION", Key: "function_version"},
+	{Env: "AWS_LAMBDA_FUNCTION_MEMORY_SIZE", Key: "function_memory_mb"},
+	{Env: "AWS_REGION", Key: "cloud_region"},
+}
+
+type lambdaContextKey struct{}
+
+// NewLambdaContext returns a copy of ctx carrying the invocation's request
+// ID. golog has no dependency on the Lambda SDK, so handlers copy the ID
+// across once per invocation:
+//
+//	func handler(ctx context.Context, event Event) error {
+//	    lc, _ := lambdacontext.FromContext(ctx)
+//	    ctx = golog.NewLambdaContext(ctx, lc.AwsRequestID)
+//	    jl.InfoContext(ctx, "handling event")
+//	    ...
+//	}
+func NewLambdaContext(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, lambdaContextKey{}, requestID)
+}
+
+// WithLambdaDefaults tunes the logger for AWS Lambda and other short-lived
+// serverless executions:
+//   - writes go straight to the output under the write lock, so nothing is
+//     lost when the execution environment is frozen after the handler
+//     returns (do not combine it with WithAsync or WithCompressedOutput);
+//   - timestamps use the compact millisecond layout
+//     "2006-01-02T15:04:05.000Z";
+//   - LambdaEnvFields become base fields;
+//   - entries logged through the ctx-aware methods with a NewLambdaContext
+//     context carry aws_request_id, cold_start (true only during the first
+//     invocation handled by the process) and xray_trace_id when the runtime
+//     sets _X_AMZN_TRACE_ID.
+func WithLambdaDefaults() Option {
+	return func(jsonLogger *JSONLogger) {
+		jsonLogger.lockWrites = true
+		WithCustomTimeFormat(lambdaTimeFormat)(jsonLogger)
+		WithEnvFields(LambdaEnvFields...)(jsonLogger)
+		WithContextExtractor(newLambdaExtractor())(jsonLogger)
+	}
+}
+
+// newLambdaExtractor returns an extractor that remembers the first request
+// ID it sees to tell the cold start invocation apart.
+func newLambdaExtractor() ContextExtractor {
+	var (
+		mutex          sync.Mutex
+		firstRequestID string
+	)
+	return func(ctx context.Context, fields []Field) []Field {
+		requestID, ok := ctx.Value(lambdaContextKey{}).(string)
+		if !ok || requestID == "" {
+			return fields
+		}
+
+		mutex.Lock()
+		if firstRequestID == "" {
+			firstRequestID = requestID
+		}
+		coldStart := requestID == firstRequestID
+		mutex.Unlock()
+
+		fields = append(fields, Str(LambdaRequestIDKey, requestID), Bool(LambdaColdStartKey, coldStart))
+		if traceID := os.Getenv("_X_AMZN_TRACE_ID"); traceID != "" {
+			fields = append(fields, Str(LambdaTraceIDKey, traceID))
+		}
+		return fields
+	}
+}
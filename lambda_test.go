@@ -0,0 +1,43 @@
+package golog
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestWithLambdaDefaults(t *testing.T) {
+	t.Setenv("AWS_LAMBDA_FUNCTION_NAME", "orders")
+	t.Setenv("_X_AMZN_TRACE_ID", "Root=1-abc")
+
+	buf := &bytes.Buffer{}
+	clock := time.Date(2024, 5, 1, 12, 0, 0, 123456789, time.UTC)
+	jl := NewJSONLoggerWithOptions(WithOutput(buf), WithLambdaDefaults(), WithClock(func() time.Time { return clock }))
+
+	jl.InfoContext(NewLambdaContext(context.Background(), "req-1"), "first")
+	jl.InfoContext(NewLambdaContext(context.Background(), "req-2"), "second")
+	jl.InfoContext(context.Background(), "outside an invocation")
+
+	entries := readEntries(t, buf.Bytes())
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(entries))
+	}
+	if !bytes.Contains(buf.Bytes(), []byte(`"timestamp":"2024-05-01T12:00:00.123Z"`)) {
+		t.Fatalf("expected compact timestamps, got %s", buf.String())
+	}
+
+	first, second := entries[0].FieldMap(), entries[1].FieldMap()
+	if first[LambdaRequestIDKey] != "req-1" || first[LambdaColdStartKey] != true || first[LambdaTraceIDKey] != "Root=1-abc" {
+		t.Fatalf("unexpected first invocation fields: %v", first)
+	}
+	if second[LambdaRequestIDKey] != "req-2" || second[LambdaColdStartKey] != false {
+		t.Fatalf("unexpected second invocation fields: %v", second)
+	}
+	if first["function_name"] != "orders" {
+		t.Fatalf("expected function_name base field, got %v", first)
+	}
+	if _, ok := entries[2].Field(LambdaRequestIDKey); ok {
+		t.Fatalf("expected no request ID outside an invocation")
+	}
+}
@@ -0,0 +1,84 @@
+package golog
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Level represents a logging severity threshold.
+//
+// Higher values mean higher severity.
+// A logger configured with a given level writes entries whose level is
+// greater than or equal to that configured level.
+type Level int32
+
+const (
+	// DebugLevel enables debug, info, warn, and error logs.
+	DebugLevel Level = 0 + iota
+	// InfoLevel enables info, warn, and error logs.
+	InfoLevel
+	// WarnLevel enables warn and error logs.
+	WarnLevel
+	// ErrorLevel enables only error logs.
+	ErrorLevel
+	// OffLevel disables logging entirely, including events, metrics and the
+	// flight recorder. Entries are dropped before any encoding. It is a
+	// threshold only; nothing is logged at OffLevel.
+	OffLevel
+)
+
+// String returns the lowercase name of the level ("debug", "info", "warn",
+// "error", "off"). Unknown values render as "Level(n)".
+func (level Level) String() string {
+	switch level {
+	case DebugLevel:
+		return "debug"
+	case InfoLevel:
+		return "info"
+	case WarnLevel:
+		return "warn"
+	case ErrorLevel:
+		return "error"
+	case OffLevel:
+		return "off"
+	default:
+		return "Level(" + strconv.Itoa(int(level)) + ")"
+	}
+}
+
+// ParseLevel converts a level name into a Level. Matching is
+// case-insensitive and surrounding whitespace is ignored; "warning" is
+// accepted as an alias for "warn", and "disabled" and "none" for "off".
+func ParseLevel(text string) (Level, error) {
+	switch strings.ToLower(strings.TrimSpace(text)) {
+	case "debug":
+		return DebugLevel, nil
+	case "info":
+		return InfoLevel, nil
+	case "warn", "warning":
+		return WarnLevel, nil
+	case "error":
+		return ErrorLevel, nil
+	case "off", "disabled", "none":
+		return OffLevel, nil
+	default:
+		return InfoLevel, fmt.Errorf("unknown level %q", text)
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (level Level) MarshalText() ([]byte, error) {
+	return []byte(level.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, so a Level can be read
+// directly from JSON/YAML config, flags or environment parsers.
+func (level *Level) UnmarshalText(text []byte) error {
+	parsed, err := ParseLevel(string(text))
+	if err != nil {
+		return err
+	}
+	*level = parsed
+	return nil
+}
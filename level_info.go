@@ -0,0 +1,42 @@
+package golog
+
+// LevelMetadata describes how a level is rendered, so custom LogWriter and
+// Sink implementations can match the package's own writers.
+type LevelMetadata struct {
+	Level Level
+	// Name is the lowercase name written by the JSON writer, as returned by
+	// Level.String: "debug", "info", "warn" or "error".
+	Name string
+	// Label is the upper-case name padded to five characters, as written by
+	// ConsoleLogWriter: "DEBUG", "INFO ", "WARN " or "ERROR".
+	Label string
+	// Glyph is the symbol ConsoleLogWriter writes with Glyphs set.
+	Glyph string
+	// Color is the ANSI SGR sequence DefaultTheme uses for the level.
+	Color string
+	// Severity is the OpenTelemetry severity number: 5, 9, 13 or 17.
+	Severity int
+	// SyslogPriority is the RFC 5424 severity: 7 (debug), 6
+	// (informational), 4 (warning) or 3 (error).
+	SyslogPriority int
+}
+
+// levelMetadata holds the metadata of every level a logger writes.
+var levelMetadata = [ErrorLevel + 1]LevelMetadata{
+	DebugLevel: {Level: DebugLevel, Name: "debug", Label: "DEBUG", Glyph: "·", Color: "\x1b[35m", Severity: 5, SyslogPriority: 7},
+	InfoLevel:  {Level: InfoLevel, Name: "info", Label: "INFO ", Glyph: "•", Color: "\x1b[32m", Severity: 9, SyslogPriority: 6},
+	WarnLevel:  {Level: WarnLevel, Name: "warn", Label: "WARN ", Glyph: "▲", Color: "\x1b[33m", Severity: 13, SyslogPriority: 4},
+	ErrorLevel: {Level: ErrorLevel, Name: "error", Label: "ERROR", Glyph: "✖", Color: "\x1b[1;31m", Severity: 17, SyslogPriority: 3},
+}
+
+// LevelInfo returns the metadata of level. OffLevel and unknown levels only
+// have a Level and a Name, since no entry is written at them.
+//
+//	meta := golog.LevelInfo(entry.Level)
+//	fmt.Fprintf(w, "<%d>%s %s\n", 8+meta.SyslogPriority, meta.Label, entry.Message)
+func LevelInfo(level Level) LevelMetadata {
+	if level < DebugLevel || level > ErrorLevel {
+		return LevelMetadata{Level: level, Name: level.String()}
+	}
+	return levelMetadata[level]
+}
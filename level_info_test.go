@@ -0,0 +1,39 @@
+package golog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestLevelInfo(t *testing.T) {
+	theme := DefaultTheme()
+	for level := DebugLevel; level <= ErrorLevel; level++ {
+		meta := LevelInfo(level)
+		if meta.Level != level || meta.Name != level.String() || meta.Color != theme.levelColor(level) {
+			t.Fatalf("inconsistent metadata for %v: %+v", level, meta)
+		}
+		if len(meta.Label) != 5 || strings.TrimSpace(meta.Label) != strings.ToUpper(meta.Name) {
+			t.Fatalf("unexpected label %q for %v", meta.Label, level)
+		}
+	}
+	if meta := LevelInfo(WarnLevel); meta.Severity != 13 || meta.SyslogPriority != 4 || meta.Glyph != "▲" {
+		t.Fatalf("unexpected warn metadata: %+v", meta)
+	}
+	if meta := LevelInfo(OffLevel); meta != (LevelMetadata{Level: OffLevel, Name: "off"}) {
+		t.Fatalf("unexpected off metadata: %+v", meta)
+	}
+	if meta := LevelInfo(Level(9)); meta.Name != "Level(9)" || meta.Label != "" {
+		t.Fatalf("unexpected metadata for an unknown level: %+v", meta)
+	}
+}
+
+func TestLevelInfoMatchesConsoleWriter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(WithOutput(buf), WithLogWriter(&ConsoleLogWriter{Color: ColorAlways, Glyphs: true}))
+	jl.Error("boom")
+	meta := LevelInfo(ErrorLevel)
+	if !strings.Contains(buf.String(), meta.Color+meta.Glyph+ansiReset) {
+		t.Fatalf("expected the console writer to use the level metadata: %q", buf.String())
+	}
+}
@@ -0,0 +1,93 @@
+package golog
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// levelOverride tracks the temporary level set by WithTemporaryLevel.
+// Overrides don't stack: a new one replaces the pending one and keeps the
+// original level as the one to restore.
+type levelOverride struct {
+	mutex      sync.Mutex
+	active     bool
+	original   Level
+	generation uint64
+	timer      *time.Timer
+}
+
+// SetLevel changes the minimum level at runtime. It is safe to call
+// concurrently with logging and cancels any pending temporary override.
+func (jsonLogger *JSONLogger) SetLevel(logLevel Level) {
+	override := &jsonLogger.levelOverride
+	override.mutex.Lock()
+	defer override.mutex.Unlock()
+
+	override.cancelLocked()
+	atomic.StoreInt32((*int32)(&jsonLogger.level), int32(logLevel))
+}
+
+// Level returns the current minimum level.
+func (jsonLogger *JSONLogger) Level() Level {
+	return Level(atomic.LoadInt32((*int32)(&jsonLogger.level)))
+}
+
+// Enabled reports whether entries at logLevel pass the current minimum
+// level, or are kept by the flight recorder when they don't. It implements
+// LeveledLogger; entries carrying ForceLog are written even when it
+// reports false.
+func (jsonLogger *JSONLogger) Enabled(logLevel Level) bool {
+	configured := jsonLogger.Level()
+	return configured != OffLevel && (configured <= logLevel || jsonLogger.current().flightRecorder != nil)
+}
+
+// WithTemporaryLevel switches the logger to logLevel for duration and then
+// reverts to the level that was active before, so verbose logging enabled
+// during an incident can't be forgotten. The returned function reverts early;
+// calling it after the override ended, or after it was replaced by another
+// override or a SetLevel call, does nothing.
+//
+//	restore := jl.WithTemporaryLevel(DebugLevel, 5*time.Minute)
+//	defer restore()
+func (jsonLogger *JSONLogger) WithTemporaryLevel(logLevel Level, duration time.Duration) (restore func()) {
+	override := &jsonLogger.levelOverride
+	override.mutex.Lock()
+	defer override.mutex.Unlock()
+
+	if !override.active {
+		override.original = jsonLogger.Level()
+	}
+	override.cancelLocked()
+	override.active = true
+	override.generation++
+	generation := override.generation
+
+	atomic.StoreInt32((*int32)(&jsonLogger.level), int32(logLevel))
+	revert := func() { jsonLogger.endTemporaryLevel(generation) }
+	override.timer = time.AfterFunc(duration, revert)
+	return revert
+}
+
+// endTemporaryLevel restores the original level if the override identified
+// by generation is still the active one.
+func (jsonLogger *JSONLogger) endTemporaryLevel(generation uint64) {
+	override := &jsonLogger.levelOverride
+	override.mutex.Lock()
+	defer override.mutex.Unlock()
+
+	if !override.active || override.generation != generation {
+		return
+	}
+	atomic.StoreInt32((*int32)(&jsonLogger.level), int32(override.original))
+	override.cancelLocked()
+}
+
+// cancelLocked stops the pending revert and marks no override as active.
+func (override *levelOverride) cancelLocked() {
+	if override.timer != nil {
+		override.timer.Stop()
+		override.timer = nil
+	}
+	override.active = false
+}
@@ -0,0 +1,69 @@
+package golog
+
+import (
+	"io"
+	"testing"
+	"time"
+)
+
+func TestSetLevelAndLevel(t *testing.T) {
+	jl := NewJSONLoggerWithOptions(WithOutput(io.Discard))
+	jl.SetLevel(ErrorLevel)
+	if jl.Level() != ErrorLevel {
+		t.Fatalf("expected error level, got %v", jl.Level())
+	}
+}
+
+func TestWithTemporaryLevelRevertsAfterDuration(t *testing.T) {
+	jl := NewJSONLoggerWithOptions(WithOutput(io.Discard), WithLevel(WarnLevel))
+
+	jl.WithTemporaryLevel(DebugLevel, 20*time.Millisecond)
+	if jl.Level() != DebugLevel {
+		t.Fatalf("expected debug level during override, got %v", jl.Level())
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for jl.Level() != WarnLevel {
+		if time.Now().After(deadline) {
+			t.Fatalf("level was not reverted, still %v", jl.Level())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestWithTemporaryLevelRestoreAndReplace(t *testing.T) {
+	jl := NewJSONLoggerWithOptions(WithOutput(io.Discard))
+
+	first := jl.WithTemporaryLevel(WarnLevel, time.Hour)
+	second := jl.WithTemporaryLevel(DebugLevel, time.Hour)
+
+	first()
+	if jl.Level() != DebugLevel {
+		t.Fatalf("expected a replaced override's restore to be a no-op, got %v", jl.Level())
+	}
+	second()
+	if jl.Level() != InfoLevel {
+		t.Fatalf("expected the original level to be restored, got %v", jl.Level())
+	}
+
+	restore := jl.WithTemporaryLevel(DebugLevel, time.Hour)
+	jl.SetLevel(ErrorLevel)
+	restore()
+	if jl.Level() != ErrorLevel {
+		t.Fatalf("expected SetLevel to cancel the override, got %v", jl.Level())
+	}
+}
+
+func TestJSONLoggerEnabled(t *testing.T) {
+	jl := NewJSONLoggerWithOptions(WithOutput(io.Discard), WithLevel(WarnLevel))
+	if jl.Enabled(InfoLevel) || !jl.Enabled(WarnLevel) || !jl.Enabled(ErrorLevel) {
+		t.Fatalf("unexpected Enabled results at warn level")
+	}
+	jl.SetLevel(OffLevel)
+	if jl.Enabled(ErrorLevel) {
+		t.Fatalf("expected nothing to be enabled when logging is off")
+	}
+
+	var _ LeveledLogger = jl
+	var _ FlushLogger = jl
+}
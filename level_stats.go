@@ -0,0 +1,208 @@
+package golog
+
+import (
+	"sync"
+	"time"
+)
+
+// Keys of the fields written by LevelSummary, besides one count per level
+// named after the level ("debug", "info", "warn", "error").
+const (
+	LevelSummaryWindowKey    = "window"
+	LevelSummaryErrorRateKey = "error_rate"
+	LevelSummaryBurnRateKey  = "burn_rate"
+)
+
+// levelSummaryMessage is the message of LevelSummary entries.
+const levelSummaryMessage = "log level summary"
+
+const (
+	defaultLevelStatsWindow = time.Minute
+	// levelStatsBuckets is the number of buckets the window is split into;
+	// counts expire one bucket at a time.
+	levelStatsBuckets = 12
+)
+
+// LevelCounts holds a number of entries per level, indexed by Level.
+type LevelCounts [ErrorLevel + 1]uint64
+
+// Total returns the number of entries at every level.
+func (counts LevelCounts) Total() uint64 {
+	var total uint64
+	for _, count := range counts {
+		total += count
+	}
+	return total
+}
+
+// ErrorRate returns the fraction of entries at error level, or 0 when there
+// are none.
+func (counts LevelCounts) ErrorRate() float64 {
+	total := counts.Total()
+	if total == 0 {
+		return 0
+	}
+	return float64(counts[ErrorLevel]) / float64(total)
+}
+
+// LevelStatsConfig tunes WithLevelStats. Zero fields use the defaults.
+type LevelStatsConfig struct {
+	// Window is the sliding window covered by Stats.RecentLevels and the
+	// summaries. Counts expire in twelfths of it. Defaults to 1m.
+	Window time.Duration
+	// SummaryInterval, when positive, writes a LevelSummary entry that
+	// often until Close.
+	SummaryInterval time.Duration
+	// ErrorBudget is the fraction of entries allowed at error level, such
+	// as 0.001 for a 99.9% objective. When positive, summaries carry the
+	// burn rate: the window's error rate divided by the budget, so 1 spends
+	// the budget exactly and 10 spends it ten times too fast.
+	ErrorBudget float64
+}
+
+// WithLevelStats counts the entries written at each level, in total and
+// within a sliding window, so dashboards built from logs alone can show
+// error-rate trends:
+//
+//	golog.WithLevelStats(golog.LevelStatsConfig{
+//	    Window:          5 * time.Minute,
+//	    SummaryInterval: time.Minute,
+//	    ErrorBudget:     0.001,
+//	})
+//
+// The counts are reported by Stats and, every SummaryInterval, in a "log
+// level summary" entry with these fields:
+//
+//	"window":300000000000,"debug":0,"info":1830,"warn":12,"error":4,"error_rate":0.00217,"burn_rate":2.17
+//
+// Entries are counted when written, after level filtering and middleware,
+// at their timestamp, so those backdated with WithEntryTime may fall
+// outside the window; events, metrics and the summaries themselves count
+// as info. Like WithHealthcheckInterval, it belongs at construction only.
+func WithLevelStats(config LevelStatsConfig) Option {
+	return func(jsonLogger *JSONLogger) {
+		if config.Window <= 0 {
+			config.Window = defaultLevelStatsWindow
+		}
+		jsonLogger.levelStats = &levelStats{
+			config: config,
+			width:  max(config.Window/levelStatsBuckets, 1),
+		}
+	}
+}
+
+// levelStats counts entries per level in total and in a ring of buckets,
+// each covering width of the window.
+type levelStats struct {
+	config LevelStatsConfig
+	width  time.Duration
+
+	mutex   sync.Mutex
+	total   LevelCounts
+	buckets [levelStatsBuckets]levelStatsBucket
+
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+// levelStatsBucket holds the counts of the bucket with the given index,
+// the number of widths since the Unix epoch.
+type levelStatsBucket struct {
+	index  int64
+	counts LevelCounts
+}
+
+// record counts an entry at logLevel written at now.
+func (stats *levelStats) record(now time.Time, logLevel Level) {
+	if logLevel < DebugLevel || logLevel > ErrorLevel {
+		return
+	}
+	index := now.UnixNano() / int64(stats.width)
+	slot := index % levelStatsBuckets
+	if slot < 0 {
+		slot += levelStatsBuckets
+	}
+	bucket := &stats.buckets[slot]
+
+	stats.mutex.Lock()
+	defer stats.mutex.Unlock()
+	if bucket.index != index {
+		*bucket = levelStatsBucket{index: index}
+	}
+	bucket.counts[logLevel]++
+	stats.total[logLevel]++
+}
+
+// snapshot returns the total counts and those within the window ending at
+// now.
+func (stats *levelStats) snapshot(now time.Time) (total, recent LevelCounts) {
+	index := now.UnixNano() / int64(stats.width)
+
+	stats.mutex.Lock()
+	defer stats.mutex.Unlock()
+	for _, bucket := range stats.buckets {
+		if bucket.index > index-levelStatsBuckets && bucket.index <= index {
+			for level, count := range bucket.counts {
+				recent[level] += count
+			}
+		}
+	}
+	return stats.total, recent
+}
+
+// LevelSummary writes a "log level summary" entry with the number of
+// entries written at each level within the WithLevelStats window, their
+// error rate and, with an ErrorBudget, the burn rate. Like Healthcheck, it
+// bypasses level filtering. It does nothing without WithLevelStats.
+func (jsonLogger *JSONLogger) LevelSummary() {
+	stats := jsonLogger.levelStats
+	if stats == nil || jsonLogger.off() {
+		return
+	}
+	_, recent := stats.snapshot(jsonLogger.clock())
+
+	var fields [ErrorLevel + 4]Field
+	fields[0] = Duration(LevelSummaryWindowKey, stats.config.Window)
+	count := 1
+	for level, levelCount := range recent {
+		fields[count] = Field{key: Level(level).String(), uintVal: levelCount, kind: fieldKindUint}
+		count++
+	}
+	errorRate := recent.ErrorRate()
+	fields[count] = Float64(LevelSummaryErrorRateKey, errorRate)
+	count++
+	if stats.config.ErrorBudget > 0 {
+		fields[count] = Float64(LevelSummaryBurnRateKey, errorRate/stats.config.ErrorBudget)
+		count++
+	}
+	jsonLogger.logUnfiltered(levelSummaryMessage, fields[:count])
+}
+
+func (jsonLogger *JSONLogger) runLevelSummaries() {
+	stats := jsonLogger.levelStats
+	defer close(stats.done)
+	ticker := time.NewTicker(stats.config.SummaryInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			jsonLogger.LevelSummary()
+		case <-stats.stop:
+			return
+		}
+	}
+}
+
+// stopLevelSummaries ends the SummaryInterval loop, if any, and waits for it
+// to exit.
+func (jsonLogger *JSONLogger) stopLevelSummaries() {
+	stats := jsonLogger.levelStats
+	if stats == nil || stats.stop == nil {
+		return
+	}
+	stats.once.Do(func() {
+		close(stats.stop)
+	})
+	<-stats.done
+}
@@ -0,0 +1,149 @@
+package golog
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestWithLevelStats(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 0, 0, time.UTC)
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(
+		WithOutput(buf),
+		WithClock(func() time.Time { return now }),
+		WithLevelStats(LevelStatsConfig{Window: 12 * time.Second, ErrorBudget: 0.1}),
+	)
+
+	jl.Debug("filtered")
+	jl.Info("a")
+	jl.Error("b")
+	now = now.Add(10 * time.Second)
+	jl.Warn("c")
+	jl.Error("d")
+
+	stats := jl.Stats()
+	if stats.Levels != (LevelCounts{InfoLevel: 1, WarnLevel: 1, ErrorLevel: 2}) || stats.RecentLevels != stats.Levels {
+		t.Fatalf("unexpected counts: %+v", stats)
+	}
+
+	now = now.Add(5 * time.Second)
+	stats = jl.Stats()
+	if stats.RecentLevels != (LevelCounts{WarnLevel: 1, ErrorLevel: 1}) || stats.Levels.Total() != 4 {
+		t.Fatalf("expected the first entries to leave the window, got %+v", stats)
+	}
+	if rate := stats.RecentLevels.ErrorRate(); rate != 0.5 {
+		t.Fatalf("unexpected error rate %v", rate)
+	}
+
+	buf.Reset()
+	jl.LevelSummary()
+	entries := readEntries(t, buf.Bytes())
+	summary := entries[0]
+	fields := summary.FieldMap()
+	if summary.Level != InfoLevel || summary.Message != "log level summary" || fields[LevelSummaryWindowKey] != int64(12*time.Second) ||
+		fields["debug"] != int64(0) || fields["warn"] != int64(1) || fields["error"] != int64(1) ||
+		fields[LevelSummaryErrorRateKey] != 0.5 || fields[LevelSummaryBurnRateKey] != int64(5) {
+		t.Fatalf("unexpected summary: %+v %v", summary, fields)
+	}
+	if jl.Stats().Levels[InfoLevel] != 2 {
+		t.Fatalf("expected the summary to be counted as info")
+	}
+}
+
+func TestWithLevelStatsReadsClockOncePerEntry(t *testing.T) {
+	for _, middleware := range []bool{false, true} {
+		now := time.Date(2024, 1, 2, 3, 4, 0, 0, time.UTC)
+		buf := &bytes.Buffer{}
+		options := []Option{
+			WithOutput(buf),
+			WithClock(func() time.Time {
+				now = now.Add(time.Second)
+				return now
+			}),
+			WithLevelStats(LevelStatsConfig{}),
+		}
+		if middleware {
+			options = append(options, WithMiddleware(func(next EntryFunc) EntryFunc { return next }))
+		}
+		jl := NewJSONLoggerWithOptions(options...)
+
+		jl.Info("a")
+		jl.Info("b")
+		entries := readEntries(t, buf.Bytes())
+		if step := entries[1].Time.Sub(entries[0].Time); step != time.Second {
+			t.Fatalf("middleware %v: expected consecutive clock readings, got a step of %v", middleware, step)
+		}
+	}
+}
+
+func TestWithLevelStatsMiddleware(t *testing.T) {
+	jl := NewJSONLoggerWithOptions(
+		WithOutput(io.Discard),
+		WithLevelStats(LevelStatsConfig{}),
+		WithMiddleware(func(next EntryFunc) EntryFunc {
+			return func(entry Entry) {
+				if entry.Message != "dropped" {
+					next(entry)
+				}
+			}
+		}),
+	)
+	jl.Error("dropped")
+	jl.Error("kept")
+	if levels := jl.Stats().Levels; levels != (LevelCounts{ErrorLevel: 1}) {
+		t.Fatalf("expected only written entries to be counted, got %v", levels)
+	}
+}
+
+func TestWithLevelStatsSummaryInterval(t *testing.T) {
+	buf := &lockedBuffer{}
+	jl := NewJSONLoggerWithOptions(WithOutput(buf), WithLevelStats(LevelStatsConfig{SummaryInterval: 5 * time.Millisecond}))
+
+	deadline := time.Now().Add(2 * time.Second)
+	for len(buf.Bytes()) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("no summary entry was written")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if err := jl.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	entries := readEntries(t, buf.Bytes())
+	if entries[0].Message != "log level summary" {
+		t.Fatalf("unexpected entry: %+v", entries[0])
+	}
+	if _, ok := entries[0].Field(LevelSummaryBurnRateKey); ok {
+		t.Fatalf("expected no burn rate without an error budget")
+	}
+
+	written := len(buf.Bytes())
+	time.Sleep(20 * time.Millisecond)
+	if len(buf.Bytes()) != written {
+		t.Fatalf("expected summaries to stop after Close")
+	}
+}
+
+func TestLevelSummaryWithoutLevelStats(t *testing.T) {
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(WithOutput(buf))
+	jl.LevelSummary()
+	if buf.Len() != 0 || jl.Stats().Levels.Total() != 0 {
+		t.Fatalf("expected nothing without WithLevelStats, got %s", buf.Bytes())
+	}
+}
+
+func TestLevelStatsAllocations(t *testing.T) {
+	if raceEnabled {
+		t.Skip("sync.Pool drops items under the race detector")
+	}
+	jl := NewJSONLoggerWithOptions(WithOutput(io.Discard), WithLevelStats(LevelStatsConfig{}))
+	allocs := testing.AllocsPerRun(100, func() {
+		jl.Info("msg", Int("n", 1))
+	})
+	if allocs != 0 {
+		t.Fatalf("expected counting not to allocate, got %v", allocs)
+	}
+}
@@ -0,0 +1,99 @@
+package golog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestLevelStringAndParseRoundTrip(t *testing.T) {
+	for _, level := range []Level{DebugLevel, InfoLevel, WarnLevel, ErrorLevel, OffLevel} {
+		parsed, err := ParseLevel(level.String())
+		if err != nil {
+			t.Fatalf("ParseLevel(%q) error: %v", level.String(), err)
+		}
+		if parsed != level {
+			t.Fatalf("round trip mismatch: got %v want %v", parsed, level)
+		}
+	}
+
+	if got := Level(42).String(); got != "Level(42)" {
+		t.Fatalf("unexpected unknown level string: %q", got)
+	}
+}
+
+func TestParseLevelAliasesAndErrors(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Level
+	}{
+		{in: "WARN", want: WarnLevel},
+		{in: " warning ", want: WarnLevel},
+		{in: "Debug", want: DebugLevel},
+		{in: "disabled", want: OffLevel},
+		{in: "NONE", want: OffLevel},
+	}
+	for _, tc := range tests {
+		got, err := ParseLevel(tc.in)
+		if err != nil || got != tc.want {
+			t.Fatalf("ParseLevel(%q) = %v, %v; want %v", tc.in, got, err, tc.want)
+		}
+	}
+
+	if _, err := ParseLevel("verbose"); err == nil {
+		t.Fatalf("expected error for unknown level")
+	}
+}
+
+func TestLevelTextMarshaling(t *testing.T) {
+	type config struct {
+		Level Level `json:"level"`
+	}
+
+	out, err := json.Marshal(config{Level: WarnLevel})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if string(out) != `{"level":"warn"}` {
+		t.Fatalf("unexpected marshaled config: %s", out)
+	}
+
+	var cfg config
+	if err := json.Unmarshal([]byte(`{"level":"error"}`), &cfg); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if cfg.Level != ErrorLevel {
+		t.Fatalf("expected ErrorLevel, got %v", cfg.Level)
+	}
+
+	if err := json.Unmarshal([]byte(`{"level":"loud"}`), &cfg); err == nil {
+		t.Fatalf("expected unmarshal error for unknown level")
+	}
+}
+
+func TestOffLevelDisablesEverything(t *testing.T) {
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(WithOutput(buf), WithLevel(OffLevel), WithFlightRecorder(8))
+
+	jl.Error("dropped")
+	jl.ErrorE(errors.New("boom"), "dropped")
+	jl.Event("signup", map[string]any{"plan": "pro"})
+	jl.Count("requests", 1)
+	jl.Healthcheck()
+
+	if buf.Len() != 0 {
+		t.Fatalf("expected no output, got %s", buf.String())
+	}
+	recent := &bytes.Buffer{}
+	if err := jl.DumpRecent(recent); err != nil || recent.Len() != 0 {
+		t.Fatalf("expected the flight recorder to stay empty: %q, %v", recent.String(), err)
+	}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		jl.Error("dropped", Str("k", "v"))
+	})
+	if allocs != 0 {
+		t.Fatalf("expected no allocations, got %v", allocs)
+	}
+}
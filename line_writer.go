@@ -0,0 +1,153 @@
+package golog
+
+import (
+	"bytes"
+	"io"
+	"sync"
+	"unicode/utf8"
+)
+
+// maxLineLength caps the lines a messageWriter logs: longer ones, complete
+// or not, are logged in pieces of this many bytes.
+const maxLineLength = 64 << 10
+
+// messageWriter logs every line written to it as one entry.
+type messageWriter struct {
+	logger Logger
+	level  Level
+	// fields are added to every entry.
+	fields []Field
+	// decode, when set, parses structured lines into an entry. Lines it
+	// rejects are logged as the message at level.
+	decode func(line []byte, fallback Level) (Level, string, []Field, bool)
+
+	mutex   sync.Mutex
+	partial []byte
+}
+
+// NewLineWriter returns an io.Writer that logs each line written to it as
+// the message of an entry at level. Incomplete lines are held until their
+// newline arrives, or until Flush or Close logs them; lines longer than
+// 64 KiB are logged in pieces, so nothing is held beyond that.
+//
+// It lets libraries and frameworks that only accept an io.Writer feed the
+// structured stream:
+//
+//	log.SetOutput(NewLineWriter(jl, WarnLevel))           // standard library log
+//	gin.DefaultWriter = NewLineWriter(jl, InfoLevel)      // gin
+//	e.Logger.SetOutput(NewLineWriter(jl, InfoLevel))      // echo
+//	app.Use(logger.New(logger.Config{Output: NewLineWriter(jl, InfoLevel)})) // fiber
+func NewLineWriter(l Logger, level Level) io.WriteCloser {
+	return &messageWriter{logger: l, level: level}
+}
+
+// DebugWriter returns an io.Writer that logs each line written to it at
+// debug level. See NewLineWriter.
+func (jsonLogger *JSONLogger) DebugWriter() io.WriteCloser {
+	return NewLineWriter(jsonLogger, DebugLevel)
+}
+
+// InfoWriter returns an io.Writer that logs each line written to it at info
+// level. See NewLineWriter.
+func (jsonLogger *JSONLogger) InfoWriter() io.WriteCloser {
+	return NewLineWriter(jsonLogger, InfoLevel)
+}
+
+// WarnWriter returns an io.Writer that logs each line written to it at warn
+// level. See NewLineWriter.
+func (jsonLogger *JSONLogger) WarnWriter() io.WriteCloser {
+	return NewLineWriter(jsonLogger, WarnLevel)
+}
+
+// ErrorWriter returns an io.Writer that logs each line written to it at
+// error level. Paired with InfoWriter it routes a subprocess's output by
+// stream:
+//
+//	cmd.Stdout = jl.InfoWriter()
+//	cmd.Stderr = jl.ErrorWriter()
+func (jsonLogger *JSONLogger) ErrorWriter() io.WriteCloser {
+	return NewLineWriter(jsonLogger, ErrorLevel)
+}
+
+// Write logs every complete line in p.
+func (writer *messageWriter) Write(p []byte) (int, error) {
+	writer.mutex.Lock()
+	defer writer.mutex.Unlock()
+
+	data := p
+	for len(data) > 0 {
+		room := maxLineLength - len(writer.partial)
+		newline := bytes.IndexByte(data[:min(len(data), room+1)], '\n')
+		if newline >= 0 {
+			line := data[:newline]
+			if len(writer.partial) > 0 {
+				line = append(writer.partial, line...)
+				writer.partial = writer.partial[:0]
+			}
+			line = bytes.TrimSuffix(line, []byte{'\r'})
+			if len(line) > 0 {
+				writer.logLine(line)
+			}
+			data = data[newline+1:]
+			continue
+		}
+		if len(data) <= room {
+			writer.partial = append(writer.partial, data...)
+			break
+		}
+		// The line is too long for one entry: log what fits, up to the last
+		// complete UTF-8 sequence, and carry on with the rest.
+		writer.partial = append(writer.partial, data[:room]...)
+		data = data[room:]
+		cut := completeRunes(writer.partial)
+		writer.logLine(writer.partial[:cut])
+		writer.partial = append(writer.partial[:0], writer.partial[cut:]...)
+	}
+	return len(p), nil
+}
+
+// Flush logs a held incomplete line, for streams that end without a final
+// newline.
+func (writer *messageWriter) Flush() error {
+	writer.mutex.Lock()
+	defer writer.mutex.Unlock()
+
+	line := bytes.TrimSuffix(writer.partial, []byte{'\r'})
+	if len(line) > 0 {
+		writer.logLine(line)
+	}
+	writer.partial = writer.partial[:0]
+	return nil
+}
+
+// Close logs a held incomplete line like Flush. The writer stays usable.
+func (writer *messageWriter) Close() error {
+	return writer.Flush()
+}
+
+// completeRunes returns the length of the longest prefix of p that doesn't
+// end inside a UTF-8 sequence.
+func completeRunes(p []byte) int {
+	for i := len(p) - 1; i > 0 && i >= len(p)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(p[i]) {
+			if utf8.FullRune(p[i:]) {
+				return len(p)
+			}
+			return i
+		}
+	}
+	return len(p)
+}
+
+// logLine logs one complete, non-empty line.
+func (writer *messageWriter) logLine(line []byte) {
+	if writer.decode != nil {
+		if level, message, fields, ok := writer.decode(line, writer.level); ok {
+			logAt(writer.logger, level, message, append(fields, writer.fields...))
+			return
+		}
+	}
+	if enabled(writer.logger, writer.level) {
+		logAt(writer.logger, writer.level, string(line), writer.fields)
+	}
+}
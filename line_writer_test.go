@@ -0,0 +1,96 @@
+package golog
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestLineWriterLogsEachLine(t *testing.T) {
+	buf := &bytes.Buffer{}
+	writer := NewLineWriter(NewJSONLoggerWithOptions(WithOutput(buf)), WarnLevel)
+
+	_, _ = writer.Write([]byte("first line\nsecond "))
+	_, _ = writer.Write([]byte("line\r\n\nthird"))
+
+	entries := readEntries(t, buf.Bytes())
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 complete lines, got %d", len(entries))
+	}
+	if entries[0].Message != "first line" || entries[1].Message != "second line" || entries[1].Level != WarnLevel {
+		t.Fatalf("unexpected entries: %+v", entries)
+	}
+}
+
+func TestLineWriterCloseLogsTrailingLine(t *testing.T) {
+	buf := &bytes.Buffer{}
+	writer := NewLineWriter(NewJSONLoggerWithOptions(WithOutput(buf)), InfoLevel)
+
+	_, _ = writer.Write([]byte("done\nno newline"))
+	if err := writer.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	entries := readEntries(t, buf.Bytes())
+	if len(entries) != 2 || entries[1].Message != "no newline" {
+		t.Fatalf("expected the trailing line to be logged, got %+v", entries)
+	}
+}
+
+func TestLineWriterSplitsLongLines(t *testing.T) {
+	buf := &bytes.Buffer{}
+	writer := NewLineWriter(NewJSONLoggerWithOptions(WithOutput(buf)), InfoLevel)
+
+	// The cut falls inside the three-byte "€", which must stay whole.
+	long := strings.Repeat("a", maxLineLength-1) + "€" + strings.Repeat("b", 10)
+	for i := 0; i < len(long); i += 1000 {
+		_, _ = writer.Write([]byte(long[i:min(i+1000, len(long))]))
+	}
+	if len(buf.Bytes()) == 0 {
+		t.Fatal("expected the first piece to be logged before the newline")
+	}
+	_, _ = writer.Write([]byte("\n"))
+
+	entries := readEntries(t, buf.Bytes())
+	if len(entries) != 2 || entries[0].Message+entries[1].Message != long {
+		t.Fatalf("expected the line in two pieces, got %d entries", len(entries))
+	}
+	if len(entries[0].Message) != maxLineLength-1 || !utf8.ValidString(entries[1].Message) {
+		t.Fatalf("expected the cut before the split character, got %d bytes", len(entries[0].Message))
+	}
+}
+
+func TestLineWriterWithStandardLogger(t *testing.T) {
+	buf := &bytes.Buffer{}
+	std := log.New(NewLineWriter(NewJSONLoggerWithOptions(WithOutput(buf)), InfoLevel), "legacy: ", 0)
+
+	std.Printf("cache warmed in %dms", 12)
+
+	entries := readEntries(t, buf.Bytes())
+	if len(entries) != 1 || entries[0].Message != "legacy: cache warmed in 12ms" {
+		t.Fatalf("unexpected entries: %+v", entries)
+	}
+}
+
+func TestLeveledWriters(t *testing.T) {
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(WithOutput(buf), WithLevel(DebugLevel))
+
+	_, _ = jl.DebugWriter().Write([]byte("d\n"))
+	_, _ = jl.InfoWriter().Write([]byte("i\n"))
+	_, _ = jl.WarnWriter().Write([]byte("w\n"))
+	_, _ = jl.ErrorWriter().Write([]byte("e\n"))
+
+	entries := readEntries(t, buf.Bytes())
+	want := []Level{DebugLevel, InfoLevel, WarnLevel, ErrorLevel}
+	if len(entries) != len(want) {
+		t.Fatalf("expected %d entries, got %d", len(want), len(entries))
+	}
+	for i, level := range want {
+		if entries[i].Level != level {
+			t.Fatalf("entry %d: expected %v, got %v", i, level, entries[i].Level)
+		}
+	}
+}
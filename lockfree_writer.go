@@ -0,0 +1,247 @@
+package golog
+
+import (
+	"errors"
+	"io"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// WithLockFreeOutput is an experimental output mode for very hot multi-core
+// workloads. Entries are still encoded in per-P pooled buffers, but instead
+// of serializing on the output lock, each goroutine pushes its finished line
+// onto a lock-free multi-producer single-consumer queue. A single consumer
+// goroutine drains the queue and writes whatever it collected in one Write
+// call.
+//
+// The queue is unbounded, so a stalled output grows memory rather than
+// blocking callers; prefer WithAsync unless profiles show contention on the
+// write lock. Wraps the output configured so far; call Close to drain the
+// queue and stop the consumer.
+func WithLockFreeOutput() Option {
+	return func(jsonLogger *JSONLogger) {
+		jsonLogger.output = newLockFreeWriter(jsonLogger.output, &jsonLogger.writeErrors)
+		jsonLogger.lockWrites = false
+	}
+}
+
+// mpscNode is one queued line.
+type mpscNode struct {
+	next   atomic.Pointer[mpscNode]
+	record []byte
+}
+
+// mpscQueue is an intrusive Vyukov multi-producer single-consumer queue.
+// push is wait-free; pop must only be called from the consumer.
+type mpscQueue struct {
+	head *mpscNode
+	tail atomic.Pointer[mpscNode]
+	stub mpscNode
+}
+
+func (queue *mpscQueue) init() {
+	queue.head = &queue.stub
+	queue.tail.Store(&queue.stub)
+}
+
+func (queue *mpscQueue) push(node *mpscNode) {
+	node.next.Store(nil)
+	previous := queue.tail.Swap(node)
+	previous.next.Store(node)
+}
+
+// pop returns the oldest node, or nil when the queue is empty or a producer
+// is between its two steps of push (the caller retries later).
+func (queue *mpscQueue) pop() *mpscNode {
+	head := queue.head
+	next := head.next.Load()
+	if head == &queue.stub {
+		if next == nil {
+			return nil
+		}
+		queue.head = next
+		head = next
+		next = next.next.Load()
+	}
+	if next != nil {
+		queue.head = next
+		return head
+	}
+	if head != queue.tail.Load() {
+		return nil
+	}
+	queue.push(&queue.stub)
+	next = head.next.Load()
+	if next != nil {
+		queue.head = next
+		return head
+	}
+	return nil
+}
+
+var mpscNodePool = sync.Pool{
+	New: func() any { return &mpscNode{} },
+}
+
+// lockFreeWriter hands writes to a consumer goroutine through an mpscQueue.
+type lockFreeWriter struct {
+	output io.Writer
+	errors *errorTracker
+	queue  mpscQueue
+	// enqueued and dequeued count lines pushed and popped since creation.
+	enqueued atomic.Int64
+	dequeued atomic.Int64
+	closed   atomic.Bool
+
+	wake    chan struct{}
+	flushes chan chan error
+	stop    chan struct{}
+	done    chan struct{}
+	batch   []byte
+}
+
+func newLockFreeWriter(output io.Writer, errors *errorTracker) *lockFreeWriter {
+	writer := &lockFreeWriter{
+		output:  output,
+		errors:  errors,
+		wake:    make(chan struct{}, 1),
+		flushes: make(chan chan error),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	writer.queue.init()
+	go writer.run()
+	return writer
+}
+
+// Write queues a copy of p without taking a lock.
+func (writer *lockFreeWriter) Write(p []byte) (int, error) {
+	if writer.closed.Load() {
+		return 0, ErrWriterClosed
+	}
+	node := mpscNodePool.Get().(*mpscNode)
+	node.record = append(node.record[:0], p...)
+	writer.enqueued.Add(1)
+	writer.queue.push(node)
+
+	select {
+	case writer.wake <- struct{}{}:
+	default:
+	}
+	return len(p), nil
+}
+
+func (writer *lockFreeWriter) run() {
+	defer close(writer.done)
+	for {
+		select {
+		case <-writer.wake:
+			_ = writer.drain()
+		case reply := <-writer.flushes:
+			reply <- writer.drain()
+		case <-writer.stop:
+			return
+		}
+	}
+}
+
+// drain writes everything queued so far in one call. Only the consumer
+// goroutine calls it.
+func (writer *lockFreeWriter) drain() error {
+	writer.batch = writer.batch[:0]
+	for writer.dequeued.Load() < writer.enqueued.Load() {
+		node := writer.queue.pop()
+		if node == nil {
+			// A producer is mid-push; its node appears momentarily.
+			runtime.Gosched()
+			continue
+		}
+		writer.batch = append(writer.batch, node.record...)
+		writer.dequeued.Add(1)
+		mpscNodePool.Put(node)
+		if len(writer.batch) >= defaultAsyncMaxBatchBytes {
+			break
+		}
+	}
+	if len(writer.batch) == 0 {
+		return nil
+	}
+	_, err := writer.output.Write(writer.batch)
+	if err != nil {
+		writer.errors.record(err)
+	}
+	if writer.dequeued.Load() < writer.enqueued.Load() {
+		select {
+		case writer.wake <- struct{}{}:
+		default:
+		}
+	}
+	return err
+}
+
+// Flush waits until every line queued before the call is written, then
+// flushes the output if it buffers data.
+func (writer *lockFreeWriter) Flush() error {
+	err := writer.writeQueued()
+	if errors.Is(err, ErrWriterClosed) {
+		return err
+	}
+	if flusher, ok := writer.output.(interface{ Flush() error }); ok {
+		if flushErr := flusher.Flush(); err == nil {
+			err = flushErr
+		}
+	}
+	return err
+}
+
+// writeQueued waits until every line queued before the call is written.
+func (writer *lockFreeWriter) writeQueued() error {
+	target := writer.enqueued.Load()
+	var err error
+	for writer.dequeued.Load() < target {
+		reply := make(chan error)
+		select {
+		case writer.flushes <- reply:
+			if drainErr := <-reply; err == nil {
+				err = drainErr
+			}
+		case <-writer.done:
+			return ErrWriterClosed
+		}
+	}
+	return err
+}
+
+// Unwrap returns the output the consumer writes to.
+func (writer *lockFreeWriter) Unwrap() io.Writer {
+	return writer.output
+}
+
+// closeWrapper drains the queue and stops the consumer, leaving the output
+// open.
+func (writer *lockFreeWriter) closeWrapper() error {
+	if writer.closed.Swap(true) {
+		return nil
+	}
+	err := writer.writeQueued()
+	close(writer.stop)
+	<-writer.done
+	return err
+}
+
+// Close drains the queue, stops the consumer and closes the output
+// (standard streams excepted). Lines written concurrently with Close may be
+// dropped. It is safe to call more than once.
+func (writer *lockFreeWriter) Close() error {
+	if writer.closed.Swap(true) {
+		return nil
+	}
+	err := writer.Flush()
+	close(writer.stop)
+	<-writer.done
+	if closeErr := closeOutput(writer.output); err == nil {
+		err = closeErr
+	}
+	return err
+}
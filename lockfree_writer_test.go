@@ -0,0 +1,72 @@
+package golog
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestMPSCQueueOrder(t *testing.T) {
+	var queue mpscQueue
+	queue.init()
+	if queue.pop() != nil {
+		t.Fatalf("expected an empty queue")
+	}
+
+	nodes := []*mpscNode{{record: []byte("a")}, {record: []byte("b")}, {record: []byte("c")}}
+	for _, node := range nodes {
+		queue.push(node)
+	}
+	for _, want := range []string{"a", "b", "c"} {
+		node := queue.pop()
+		if node == nil || string(node.record) != want {
+			t.Fatalf("expected %q, got %v", want, node)
+		}
+	}
+	if queue.pop() != nil {
+		t.Fatalf("expected the queue to be drained")
+	}
+}
+
+func TestWithLockFreeOutputConcurrentWriters(t *testing.T) {
+	output := &countingWriter{}
+	jl := NewJSONLoggerWithOptions(WithOutput(output), WithLockFreeOutput())
+
+	var wg sync.WaitGroup
+	for worker := 0; worker < 8; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				jl.Info("hot", Int("worker", worker), Int("i", i))
+			}
+		}()
+	}
+	wg.Wait()
+	if err := jl.Flush(); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+
+	_, data := output.snapshot()
+	entries := readEntries(t, data)
+	if len(entries) != 1600 {
+		t.Fatalf("expected 1600 entries, got %d", len(entries))
+	}
+	// Lines from one goroutine keep their relative order.
+	last := make(map[int64]int64)
+	for _, entry := range entries {
+		worker, _ := entry.Field("worker")
+		i, _ := entry.Field("i")
+		if previous, ok := last[worker.Value().(int64)]; ok && i.Value().(int64) <= previous {
+			t.Fatalf("worker %v: line %v after %v", worker.Value(), i.Value(), previous)
+		}
+		last[worker.Value().(int64)] = i.Value().(int64)
+	}
+
+	if err := jl.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if _, err := jl.output.Write([]byte("late\n")); !errors.Is(err, ErrWriterClosed) {
+		t.Fatalf("expected ErrWriterClosed after close, got %v", err)
+	}
+}
@@ -0,0 +1,256 @@
+package golog
+
+import "time"
+
+// LogWriter formats log entries. A JSONLogger hands every entry that passes
+// level filtering to its LogWriter, which appends the complete encoded record
+// (including any trailing newline) to dst and returns the extended slice. The
+// logger then writes the result to its output.
+//
+// entry.Fields holds the logger's base fields, sorted by key, followed by the
+// per-call fields. The slice is reused after AppendLog returns, so writers
+// must not retain it. Install a writer with WithLogWriter; the default emits
+// one compact JSON object per line.
+type LogWriter interface {
+	AppendLog(dst []byte, entry Entry) []byte
+}
+
+// loggerBinder is implemented by writers in this package that reuse the
+// owning logger's configuration (encoding policy, level strings and time
+// format). WithLogWriter binds them to the logger they are installed on.
+type loggerBinder interface {
+	bindLogger(jsonLogger *JSONLogger)
+}
+
+// WithLogWriter sets the LogWriter used to format entries.
+func WithLogWriter(writer LogWriter) Option {
+	return func(jsonLogger *JSONLogger) {
+		if writer == nil {
+			writer = jsonLogWriter{logger: jsonLogger}
+		}
+		if binder, ok := writer.(loggerBinder); ok {
+			binder.bindLogger(jsonLogger)
+		}
+		jsonLogger.writer = writer
+	}
+}
+
+// WithPrettyJSON formats entries as indented, multi-line JSON objects. It is
+// meant for local development; use the default compact writer in production.
+func WithPrettyJSON() Option {
+	return WithLogWriter(&PrettyJSONLogWriter{})
+}
+
+// jsonLogWriter is the default compact NDJSON writer. It reads the owning
+// logger's pre-encoded base fields and level values directly, which keeps the
+// hot path allocation free.
+type jsonLogWriter struct {
+	logger *JSONLogger
+}
+
+// AppendLog implements LogWriter. The logger itself bypasses it and calls
+// appendEntry with only the per-call fields.
+func (writer jsonLogWriter) AppendLog(dst []byte, entry Entry) []byte {
+	writer.logger = entry.config(writer.logger)
+	dst = writer.appendHeader(dst, entry.Time, entry.Level, entry.Message)
+	return writer.appendFields(dst, entry.Fields)
+}
+
+// appendEntry writes an entry using the logger's pre-encoded base fields
+// followed by fields.
+func (writer jsonLogWriter) appendEntry(dst []byte, seq uint64, timestamp time.Time, level Level, message string, fields []Field) []byte {
+	dst = writer.appendHeader(dst, timestamp, level, message)
+	dst = writer.logger.appendSequence(dst, seq)
+	return writer.appendBody(dst, fields)
+}
+
+// appendBody writes what follows the core keys and seq: the goroutine ID,
+// the pre-encoded base fields and fields.
+func (writer jsonLogWriter) appendBody(dst []byte, fields []Field) []byte {
+	jsonLogger := writer.logger
+	jsonLogger.baseFieldsOnce.Do(jsonLogger.buildBaseFieldsCache)
+
+	dst = jsonLogger.appendGoroutineID(dst)
+	if jsonLogger.baseFieldsCache != nil {
+		dst = append(dst, jsonLogger.baseFieldsCache...)
+	}
+
+	return writer.appendFields(dst, fields)
+}
+
+// appendFields writes fields and closes the entry, listing values that
+// couldn't be encoded under EncodeErrorsKey.
+func (writer jsonLogWriter) appendFields(dst []byte, fields []Field) []byte {
+	enc := &writer.logger.encoder
+	var encodeErrors, failed []string
+	for i := range fields {
+		dst, failed = enc.appendFieldChecked(dst, fields[i])
+		encodeErrors = append(encodeErrors, failed...)
+	}
+	dst = enc.appendEncodeErrors(dst, encodeErrors)
+	return append(dst, '}', '\n')
+}
+
+// appendHeader opens the JSON object and writes the core keys.
+func (writer jsonLogWriter) appendHeader(dst []byte, timestamp time.Time, level Level, message string) []byte {
+	jsonLogger := writer.logger
+	dst = append(dst, `{"timestamp":"`...)
+	dst = jsonLogger.appendTimestamp(dst, timestamp)
+	dst = append(dst, `","level":`...)
+	dst = append(dst, jsonLogger.levelValue(level)...)
+	dst = append(dst, `,"message":`...)
+	return jsonLogger.encoder.appendString(dst, message)
+}
+
+// PrettyJSONLogWriter writes each entry as an indented JSON object with one
+// top-level field per line. Nested objects and arrays are indented too, up
+// to MaxDepth levels.
+type PrettyJSONLogWriter struct {
+	// Indent is written once per nesting level. Defaults to two spaces.
+	Indent string
+	// MaxDepth limits how many levels of nested objects and arrays are
+	// expanded; deeper values are written compactly on one line. Zero
+	// expands every level and a negative value keeps all nested values
+	// compact.
+	MaxDepth int
+
+	logger *JSONLogger
+}
+
+func (writer *PrettyJSONLogWriter) bindLogger(jsonLogger *JSONLogger) {
+	writer.logger = jsonLogger
+}
+
+// AppendLog implements LogWriter.
+func (writer *PrettyJSONLogWriter) AppendLog(dst []byte, entry Entry) []byte {
+	jsonLogger := entry.config(writer.logger)
+	enc := &jsonLogger.encoder
+	indent := writer.Indent
+	if indent == "" {
+		indent = "  "
+	}
+
+	dst = append(dst, "{\n"...)
+	dst = append(dst, indent...)
+	dst = append(dst, `"timestamp": "`...)
+	dst = jsonLogger.appendTimestamp(dst, entry.Time)
+	dst = append(dst, `",`+"\n"...)
+	dst = append(dst, indent...)
+	dst = append(dst, `"level": `...)
+	dst = append(dst, jsonLogger.levelValue(entry.Level)...)
+	dst = append(dst, ",\n"...)
+	dst = append(dst, indent...)
+	dst = append(dst, `"message": `...)
+	dst = enc.appendString(dst, entry.Message)
+
+	var encodeErrors, failed []string
+	fields := entry.Fields
+	for i := range fields {
+		if (fields[i].omitEmpty || enc.omitEmpty) && fields[i].isEmpty() {
+			continue
+		}
+		dst = append(dst, ",\n"...)
+		dst = append(dst, indent...)
+		dst = enc.appendKey(dst, fields[i].key)
+		dst = append(dst, ": "...)
+		mark := len(dst)
+		dst, failed = enc.appendFieldValueChecked(dst, fields[i])
+		encodeErrors = append(encodeErrors, failed...)
+		if writer.MaxDepth >= 0 && len(dst) > mark && (dst[mark] == '{' || dst[mark] == '[') {
+			compact := AcquireBuffer()
+			*compact = append(*compact, dst[mark:]...)
+			dst = appendIndentedJSON(dst[:mark], *compact, indent, writer.MaxDepth)
+			ReleaseBuffer(compact)
+		}
+	}
+	if len(encodeErrors) > 0 {
+		dst = append(dst, ",\n"...)
+		dst = append(dst, indent...)
+		dst = enc.appendKey(dst, EncodeErrorsKey)
+		dst = append(dst, ": "...)
+		dst = enc.appendPaths(dst, encodeErrors)
+	}
+
+	return append(dst, "\n}\n"...)
+}
+
+// appendIndentedJSON re-indents the compact JSON value of a top-level field.
+// Nested lines start with one indent per level, counting the top-level field
+// itself as level one. Containers nested deeper than maxDepth (when
+// positive) and empty containers are copied as they are.
+func appendIndentedJSON(dst, compact []byte, indent string, maxDepth int) []byte {
+	nesting := 0
+	// compactFrom is the nesting level of the container being copied
+	// compactly, or zero while expanding.
+	compactFrom := 0
+	inString := false
+	for i := 0; i < len(compact); i++ {
+		c := compact[i]
+		if inString {
+			dst = append(dst, c)
+			if c == '\\' && i+1 < len(compact) {
+				i++
+				dst = append(dst, compact[i])
+			} else if c == '"' {
+				inString = false
+			}
+			continue
+		}
+		if compactFrom > 0 {
+			dst = append(dst, c)
+			switch c {
+			case '"':
+				inString = true
+			case '{', '[':
+				nesting++
+			case '}', ']':
+				nesting--
+				if nesting < compactFrom {
+					compactFrom = 0
+				}
+			}
+			continue
+		}
+
+		switch c {
+		case '"':
+			inString = true
+			dst = append(dst, c)
+		case '{', '[':
+			nesting++
+			dst = append(dst, c)
+			if maxDepth > 0 && nesting > maxDepth {
+				compactFrom = nesting
+			} else if i+1 < len(compact) && compact[i+1] != '}' && compact[i+1] != ']' {
+				dst = appendIndentedLine(dst, indent, nesting+1)
+			}
+		case '}', ']':
+			nesting--
+			if compact[i-1] != '{' && compact[i-1] != '[' {
+				dst = appendIndentedLine(dst, indent, nesting+1)
+			}
+			dst = append(dst, c)
+		case ',':
+			dst = append(dst, ',')
+			dst = appendIndentedLine(dst, indent, nesting+1)
+		case ':':
+			dst = append(dst, ": "...)
+		default:
+			dst = append(dst, c)
+		}
+	}
+	return dst
+}
+
+// appendIndentedLine starts a new line indented level times.
+func appendIndentedLine(dst []byte, indent string, level int) []byte {
+	dst = append(dst, '\n')
+	for range level {
+		dst = append(dst, indent...)
+	}
+	return dst
+}
+
+// unboundWriterConfig supplies the default configuration to writers that are
+// used without being installed on a logger.
+var unboundWriterConfig = NewJSONLogger()
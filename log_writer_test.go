@@ -0,0 +1,176 @@
+package golog
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+)
+
+// lineWriter is a minimal custom LogWriter producing "level|message|k=v" lines.
+type lineWriter struct{}
+
+func (lineWriter) AppendLog(dst []byte, entry Entry) []byte {
+	dst = append(dst, entry.Level.String()...)
+	dst = append(dst, '|')
+	dst = append(dst, entry.Message...)
+	for _, f := range entry.Fields {
+		dst = append(dst, '|')
+		dst = append(dst, f.Key()...)
+		dst = append(dst, '=')
+		dst = append(dst, f.Value().(string)...)
+	}
+	return append(dst, '\n')
+}
+
+func TestWithLogWriterFormatsEveryEntry(t *testing.T) {
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(
+		WithOutput(buf),
+		WithLogWriter(lineWriter{}),
+	)
+
+	jl.Info("hello", Str("k", "v"))
+	jl.Debug("filtered")
+	jl.Error("boom")
+
+	want := "info|hello|k=v\nerror|boom\n"
+	if buf.String() != want {
+		t.Fatalf("expected %q, got %q", want, buf.String())
+	}
+}
+
+// entryCapture records the entries handed to a LogWriter.
+type entryCapture struct {
+	entries []Entry
+}
+
+func (capture *entryCapture) AppendLog(dst []byte, entry Entry) []byte {
+	entry.Fields = append([]Field(nil), entry.Fields...)
+	capture.entries = append(capture.entries, entry)
+	return dst
+}
+
+func TestLogWriterReceivesEntry(t *testing.T) {
+	capture := &entryCapture{}
+	jl := NewJSONLoggerWithOptions(
+		WithOutput(io.Discard),
+		WithLogWriter(capture),
+		WithBaseFields(map[string]any{"zone": "eu", "app": "api"}),
+	)
+
+	jl.Warn("slow", Str("app", "override"), Int("ms", 30))
+
+	if len(capture.entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(capture.entries))
+	}
+	entry := capture.entries[0]
+	if entry.Level != WarnLevel || entry.Message != "slow" || entry.Time.IsZero() {
+		t.Fatalf("unexpected entry: %+v", entry)
+	}
+	var keys []string
+	for _, field := range entry.Fields {
+		keys = append(keys, field.Key())
+	}
+	if strings.Join(keys, ",") != "app,zone,app,ms" {
+		t.Fatalf("expected sorted base fields before call fields, got %v", keys)
+	}
+	if field, _ := entry.Field("app"); field.Value() != "override" {
+		t.Fatalf("expected per-call field to win, got %v", field.Value())
+	}
+}
+
+func TestPrettyJSONWriter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(
+		WithOutput(buf),
+		WithPrettyJSON(),
+		WithBaseField("service", "api"),
+		WithLevelStrings(map[Level]string{InfoLevel: "INFO"}),
+	)
+
+	jl.Info("started", Int("port", 8080), Any("tags", []any{"a", "b"}))
+
+	out := buf.String()
+	if !strings.Contains(out, "\n  \"port\": 8080") {
+		t.Fatalf("expected indented top-level fields, got:\n%s", out)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("pretty output is not valid JSON: %v\n%s", err, out)
+	}
+	if got["level"] != "INFO" || got["service"] != "api" || got["message"] != "started" {
+		t.Fatalf("unexpected pretty entry: %v", got)
+	}
+}
+
+func TestPrettyJSONWriterIndentsNestedValues(t *testing.T) {
+	type user struct {
+		Name  string   `json:"name"`
+		Roles []string `json:"roles"`
+	}
+	value := struct {
+		Empty map[string]any `json:"empty"`
+		User  user           `json:"user"`
+	}{Empty: map[string]any{}, User: user{Name: "a,b:{c}", Roles: []string{"admin"}}}
+
+	tests := []struct {
+		name     string
+		maxDepth int
+		want     string
+	}{
+		{
+			name: "all levels",
+			want: `  "data": {
+    "empty": {},
+    "user": {
+      "name": "a,b:{c}",
+      "roles": [
+        "admin"
+      ]
+    }
+  }`,
+		},
+		{
+			name:     "depth one",
+			maxDepth: 1,
+			want: `  "data": {
+    "empty": {},
+    "user": {"name":"a,b:{c}","roles":["admin"]}
+  }`,
+		},
+		{
+			name:     "compact",
+			maxDepth: -1,
+			want:     `  "data": {"empty":{},"user":{"name":"a,b:{c}","roles":["admin"]}}`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			jl := NewJSONLoggerWithOptions(WithOutput(buf), WithLogWriter(&PrettyJSONLogWriter{MaxDepth: tc.maxDepth}))
+
+			jl.Info("nested", Any("data", value))
+
+			if !strings.Contains(buf.String(), tc.want+"\n}") {
+				t.Fatalf("expected:\n%s\ngot:\n%s", tc.want, buf.String())
+			}
+			if !json.Valid(buf.Bytes()) {
+				t.Fatalf("pretty output is not valid JSON:\n%s", buf.String())
+			}
+		})
+	}
+}
+
+func TestDefaultWriterDoesNotAllocate(t *testing.T) {
+	jl := NewJSONLoggerWithOptions(WithOutput(io.Discard))
+
+	allocs := testing.AllocsPerRun(100, func() {
+		jl.Info("hot path", Str("k", "v"), Int("n", 1), Bool("ok", true))
+	})
+	if allocs != 0 {
+		t.Fatalf("expected zero allocations with the default writer, got %v", allocs)
+	}
+}
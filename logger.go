@@ -1,5 +1,7 @@
 package golog
 
+import "context"
+
 // Logger is the minimal typed logging interface used by this package.
 //
 // It mirrors common leveled methods and accepts zero or more typed Field
@@ -11,6 +13,39 @@ type Logger interface {
 	Debug(message string, fields ...Field)
 }
 
+// LeveledLogger is implemented by loggers that can report up front whether
+// an entry at level would be written. Wrappers in this package, such as
+// AccessLog and NewLoggingRoundTripper, type-assert for it to skip building
+// fields that would be dropped; loggers without it are assumed to write
+// every level.
+type LeveledLogger interface {
+	Enabled(level Level) bool
+}
+
+// FlushLogger is implemented by loggers that buffer entries and can write
+// them out on demand, such as JSONLogger with WithAsync or a buffered
+// output. Wrappers forward Flush to the logger they wrap.
+type FlushLogger interface {
+	Flush() error
+}
+
+// enabled reports whether l writes entries at level, assuming it does when
+// l doesn't implement LeveledLogger.
+func enabled(l Logger, level Level) bool {
+	if leveled, ok := l.(LeveledLogger); ok {
+		return leveled.Enabled(level)
+	}
+	return true
+}
+
+// flushLogger flushes l if it implements FlushLogger.
+func flushLogger(l Logger) error {
+	if flusher, ok := l.(FlushLogger); ok {
+		return flusher.Flush()
+	}
+	return nil
+}
+
 // logger is the package-level logger used by helper functions.
 // Install a custom logger with SetLogger.
 var logger Logger = NewJSONLogger()
@@ -20,6 +55,30 @@ func SetLogger(l Logger) {
 	logger = l
 }
 
+type loggerContextKey struct{}
+
+// NewContext returns a copy of ctx carrying l, so request-scoped loggers can
+// travel down a call stack without extra parameters. Retrieve it with
+// FromContext.
+func NewContext(ctx context.Context, l Logger) context.Context {
+	return context.WithValue(ctx, loggerContextKey{}, l)
+}
+
+// FromContext returns the Logger stored in ctx by NewContext. Without one it
+// returns the package-level logger, or Nop when SetLogger(nil) removed it,
+// so the result is always safe to call.
+func FromContext(ctx context.Context) Logger {
+	if ctx != nil {
+		if l, ok := ctx.Value(loggerContextKey{}).(Logger); ok && l != nil {
+			return l
+		}
+	}
+	if logger == nil {
+		return Nop()
+	}
+	return logger
+}
+
 // Info logs a message at info level via the installed package-level logger.
 // If no logger is installed, the call is a no-op.
 func Info(message string, fields ...Field) {
@@ -58,20 +117,20 @@ func Debug(message string, fields ...Field) {
 
 // Info logs a message at info level with optional typed fields.
 func (jsonLogger *JSONLogger) Info(message string, fields ...Field) {
-	jsonLogger.logFields(InfoLevel, "info", message, fields)
+	jsonLogger.logFields(InfoLevel, message, fields)
 }
 
 // Warn logs a message at warn level with optional typed fields.
 func (jsonLogger *JSONLogger) Warn(message string, fields ...Field) {
-	jsonLogger.logFields(WarnLevel, "warn", message, fields)
+	jsonLogger.logFields(WarnLevel, message, fields)
 }
 
 // Error logs a message at error level with optional typed fields.
 func (jsonLogger *JSONLogger) Error(message string, fields ...Field) {
-	jsonLogger.logFields(ErrorLevel, "error", message, fields)
+	jsonLogger.logFields(ErrorLevel, message, fields)
 }
 
 // Debug logs a message at debug level with optional typed fields.
 func (jsonLogger *JSONLogger) Debug(message string, fields ...Field) {
-	jsonLogger.logFields(DebugLevel, "debug", message, fields)
+	jsonLogger.logFields(DebugLevel, message, fields)
 }
@@ -2,6 +2,7 @@ package golog
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"strings"
 	"testing"
@@ -228,6 +229,29 @@ func TestSetLoggerForwards(t *testing.T) {
 	}
 }
 
+func TestLoggerContext(t *testing.T) {
+	prev := logger
+	defer SetLogger(prev)
+
+	global := &BLogger{b: &bytes.Buffer{}}
+	SetLogger(global)
+	if FromContext(context.Background()) != Logger(global) {
+		t.Fatalf("expected the global logger without a stored one")
+	}
+
+	scoped := &BLogger{b: &bytes.Buffer{}}
+	ctx := NewContext(context.Background(), scoped)
+	FromContext(ctx).Info("request")
+	if scoped.b.String() != "I:request\n" || global.b.Len() != 0 {
+		t.Fatalf("expected the stored logger to be used: scoped=%q global=%q", scoped.b.String(), global.b.String())
+	}
+
+	SetLogger(nil)
+	if _, ok := FromContext(context.Background()).(nopLogger); !ok {
+		t.Fatalf("expected Nop without any logger")
+	}
+}
+
 // collectLevelsFromBuffer parses newline-delimited JSON log lines from buf and
 // returns a set of the `level` field values found.
 //
@@ -0,0 +1,335 @@
+package golog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LokiLevelLabel is the stream label LokiSink sets to the entry's level.
+const LokiLevelLabel = "level"
+
+const (
+	defaultLokiBatchSize = 500
+	defaultLokiTimeout   = 10 * time.Second
+	lokiPushPath         = "/loki/api/v1/push"
+	// defaultLokiBacklog is MaxBuffered in batches.
+	defaultLokiBacklog = 10
+)
+
+// errLokiBacklog is returned by LokiSink.Write for an entry dropped while a
+// push is in flight.
+var errLokiBacklog = errors.New("loki push in flight: buffer full, entry dropped")
+
+// WithLabelKeys marks the fields with the given keys as labels: attributes
+// with few distinct values, such as service, env or region, that indexing
+// sinks like LokiSink send as stream labels rather than in the log line.
+// Every other field stays in the line, so high-cardinality values such as
+// user or request IDs never multiply the index. Outputs and sinks that
+// don't index are unaffected.
+//
+//	jl := NewJSONLoggerWithOptions(
+//	    WithBaseFields(map[string]any{"service": "api", "env": "prod"}),
+//	    WithLabelKeys("service", "env"),
+//	    WithSink(NewLokiSink(LokiConfig{URL: "http://loki:3100"})),
+//	)
+func WithLabelKeys(keys ...string) Option {
+	return func(jsonLogger *JSONLogger) {
+		jsonLogger.labelKeys = append(jsonLogger.labelKeys, keys...)
+	}
+}
+
+// LokiConfig configures a LokiSink. Zero fields use the defaults.
+type LokiConfig struct {
+	// URL is the base URL of Loki, such as "http://loki:3100". Entries are
+	// pushed to its /loki/api/v1/push endpoint.
+	URL string
+	// Tenant, when set, is sent as the X-Scope-OrgID header of a
+	// multi-tenant Loki.
+	Tenant string
+	// Labels are added to every stream, such as {"job": "api"}.
+	Labels map[string]string
+	// BatchSize is the number of entries buffered before they are pushed.
+	// Defaults to 500.
+	BatchSize int
+	// MaxBuffered is the number of entries buffered while a push is in
+	// flight. Entries beyond it are dropped. Defaults to 10 × BatchSize.
+	MaxBuffered int
+	// FlushInterval, when positive, also pushes buffered entries that often
+	// until Close.
+	FlushInterval time.Duration
+	// Client sends the pushes. Defaults to a client with a 10s timeout.
+	Client *http.Client
+}
+
+// LokiSink pushes entries to Grafana Loki. Each entry goes to the stream
+// identified by the configured Labels, its level under LokiLevelLabel and
+// the fields named by WithLabelKeys; the rest of the entry is the log line,
+// as compact JSON following the logger's configuration. Label names that
+// Loki doesn't accept have their invalid characters replaced with '_'.
+//
+// Entries are buffered and pushed in batches, one push at a time: in the
+// background once BatchSize accumulate, so logging never waits for Loki, and
+// from Flush, Close and the FlushInterval loop. While a push is in flight,
+// Write keeps buffering up to MaxBuffered entries and drops the rest. A
+// failed push drops its batch and is reported by Flush or Close when they
+// made it, or in Stats.WriteErrors for background pushes. Label values are
+// scrubbed and capped like any string value. It is safe for concurrent use.
+type LokiSink struct {
+	config LokiConfig
+	// endpoint is the push URL.
+	endpoint string
+
+	mutex   sync.Mutex
+	logger  *JSONLogger
+	streams []*lokiStream
+	// index maps the encoded label set of each stream to its position in
+	// streams.
+	index   map[string]int
+	pending int
+	line    []byte
+	// pushing is closed when the push in flight ends; nil when none is.
+	pushing chan struct{}
+
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+// lokiStream is one stream of a push request.
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+// NewLokiSink returns a sink pushing to the Loki at config.URL. Install it
+// with WithSink and call Close, or Close on the logger, to push the last
+// entries.
+func NewLokiSink(config LokiConfig) *LokiSink {
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaultLokiBatchSize
+	}
+	if config.MaxBuffered <= 0 {
+		config.MaxBuffered = defaultLokiBacklog * config.BatchSize
+	}
+	if config.Client == nil {
+		config.Client = &http.Client{Timeout: defaultLokiTimeout}
+	}
+	sink := &LokiSink{
+		config:   config,
+		endpoint: strings.TrimSuffix(config.URL, "/") + lokiPushPath,
+		index:    make(map[string]int),
+	}
+	if config.FlushInterval > 0 {
+		sink.stop = make(chan struct{})
+		sink.done = make(chan struct{})
+		go sink.run()
+	}
+	return sink
+}
+
+func (sink *LokiSink) bindLogger(jsonLogger *JSONLogger) {
+	sink.mutex.Lock()
+	defer sink.mutex.Unlock()
+	sink.logger = jsonLogger
+}
+
+// Write implements Sink.
+func (sink *LokiSink) Write(entry Entry) error {
+	sink.mutex.Lock()
+	defer sink.mutex.Unlock()
+	if sink.pushing != nil && sink.pending >= sink.config.MaxBuffered {
+		return errLokiBacklog
+	}
+
+	jsonLogger := entry.config(sink.logger)
+	labels := make(map[string]string, len(sink.config.Labels)+len(jsonLogger.labelKeys)+1)
+	for name, value := range sink.config.Labels {
+		labels[lokiLabelName(name)] = value
+	}
+	labels[LokiLevelLabel] = entry.Level.String()
+
+	lineFields := entry.Fields
+	if len(jsonLogger.labelKeys) > 0 {
+		lineFields = make([]Field, 0, len(entry.Fields))
+		for _, field := range entry.Fields {
+			if !slices.Contains(jsonLogger.labelKeys, field.key) {
+				lineFields = append(lineFields, field)
+				continue
+			}
+			value, ok := field.Value().(string)
+			if !ok {
+				value = fmt.Sprint(field.Value())
+			}
+			labels[lokiLabelName(field.key)] = jsonLogger.encoder.stringValue(value)
+		}
+	}
+
+	line := jsonLogWriter{logger: jsonLogger}.AppendLog(sink.line[:0], Entry{Time: entry.Time, Level: entry.Level, Message: entry.Message, Fields: lineFields, logger: jsonLogger})
+	sink.line = line
+	value := [2]string{strconv.FormatInt(entry.Time.UnixNano(), 10), string(bytes.TrimSuffix(line, []byte{'\n'}))}
+
+	key := lokiStreamKey(labels)
+	if i, ok := sink.index[key]; ok {
+		sink.streams[i].Values = append(sink.streams[i].Values, value)
+	} else {
+		sink.index[key] = len(sink.streams)
+		sink.streams = append(sink.streams, &lokiStream{Stream: labels, Values: [][2]string{value}})
+	}
+	sink.pending++
+	if sink.pending < sink.config.BatchSize || sink.pushing != nil {
+		return nil
+	}
+	streams, done := sink.take()
+	go func() {
+		if err := sink.push(streams, done); err != nil {
+			sink.recordError(err)
+		}
+	}()
+	return nil
+}
+
+// Flush waits for the push in flight, if any, then pushes the buffered
+// entries.
+func (sink *LokiSink) Flush() error {
+	sink.mutex.Lock()
+	for sink.pushing != nil {
+		pushing := sink.pushing
+		sink.mutex.Unlock()
+		<-pushing
+		sink.mutex.Lock()
+	}
+	streams, done := sink.take()
+	sink.mutex.Unlock()
+	return sink.push(streams, done)
+}
+
+// Close stops the FlushInterval loop, if any, and pushes the buffered
+// entries.
+func (sink *LokiSink) Close() error {
+	if sink.stop != nil {
+		sink.once.Do(func() {
+			close(sink.stop)
+		})
+		<-sink.done
+	}
+	return sink.Flush()
+}
+
+// take removes the buffered streams for a push and marks the push as in
+// flight until push closes done. The caller holds the mutex.
+func (sink *LokiSink) take() ([]*lokiStream, chan struct{}) {
+	streams := sink.streams
+	sink.streams = nil
+	clear(sink.index)
+	sink.pending = 0
+	done := make(chan struct{})
+	sink.pushing = done
+	return streams, done
+}
+
+// push sends streams in one request, then ends the push started by take.
+// The streams are dropped whether or not Loki accepted them.
+func (sink *LokiSink) push(streams []*lokiStream, done chan struct{}) error {
+	defer func() {
+		sink.mutex.Lock()
+		sink.pushing = nil
+		sink.mutex.Unlock()
+		close(done)
+	}()
+	if len(streams) == 0 {
+		return nil
+	}
+	body, err := json.Marshal(struct {
+		Streams []*lokiStream `json:"streams"`
+	}{Streams: streams})
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequest(http.MethodPost, sink.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	if sink.config.Tenant != "" {
+		request.Header.Set("X-Scope-OrgID", sink.config.Tenant)
+	}
+	response, err := sink.config.Client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, response.Body)
+	if response.StatusCode/100 != 2 {
+		return fmt.Errorf("loki push: %s", response.Status)
+	}
+	return nil
+}
+
+// recordError counts the failure of a background push in the bound
+// logger's Stats.WriteErrors.
+func (sink *LokiSink) recordError(err error) {
+	sink.mutex.Lock()
+	jsonLogger := sink.logger
+	sink.mutex.Unlock()
+	if jsonLogger != nil {
+		jsonLogger.writeErrors.record(err)
+	}
+}
+
+func (sink *LokiSink) run() {
+	defer close(sink.done)
+	ticker := time.NewTicker(sink.config.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := sink.Flush(); err != nil {
+				sink.recordError(err)
+			}
+		case <-sink.stop:
+			return
+		}
+	}
+}
+
+// lokiStreamKey encodes a label set as a map key.
+func lokiStreamKey(labels map[string]string) string {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	var key strings.Builder
+	for _, name := range names {
+		key.WriteString(name)
+		key.WriteByte(0)
+		key.WriteString(labels[name])
+		key.WriteByte(0)
+	}
+	return key.String()
+}
+
+// lokiLabelName replaces the characters Loki doesn't accept in label names,
+// which must match [a-zA-Z_][a-zA-Z0-9_]*, with '_'.
+func lokiLabelName(name string) string {
+	if name == "" {
+		return "_"
+	}
+	label := []byte(name)
+	for i, c := range label {
+		if c != '_' && (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') && (i == 0 || c < '0' || c > '9') {
+			label[i] = '_'
+		}
+	}
+	return string(label)
+}
@@ -0,0 +1,265 @@
+package golog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// lokiServer records the push requests it receives.
+type lokiServer struct {
+	*httptest.Server
+	mutex   sync.Mutex
+	pushes  []lokiPush
+	tenants []string
+	status  int
+}
+
+type lokiPush struct {
+	Streams []lokiStream `json:"streams"`
+}
+
+func newLokiServer(t *testing.T) *lokiServer {
+	server := &lokiServer{status: http.StatusNoContent}
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != lokiPushPath || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("unexpected request: %s %s", r.URL.Path, r.Header.Get("Content-Type"))
+		}
+		var push lokiPush
+		if err := json.NewDecoder(r.Body).Decode(&push); err != nil {
+			t.Errorf("decode push: %v", err)
+		}
+		server.mutex.Lock()
+		server.pushes = append(server.pushes, push)
+		server.tenants = append(server.tenants, r.Header.Get("X-Scope-OrgID"))
+		status := server.status
+		server.mutex.Unlock()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func (server *lokiServer) received() []lokiPush {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	return append([]lokiPush(nil), server.pushes...)
+}
+
+func TestLokiSinkSendsLabelKeysAsStreamLabels(t *testing.T) {
+	server := newLokiServer(t)
+	sink := NewLokiSink(LokiConfig{URL: server.URL + "/", Tenant: "team-a", Labels: map[string]string{"job": "api"}})
+	jl := NewJSONLoggerWithOptions(
+		WithOutput(&bytes.Buffer{}),
+		WithBaseFields(map[string]any{"service": "checkout", "env": "prod"}),
+		WithLabelKeys("service", "env"),
+		WithSink(sink),
+	)
+
+	jl.Info("paid", Str(KeyUserID, "u-1"), Int("amount", 5))
+	jl.Info("paid", Str(KeyUserID, "u-2"), Int("amount", 7))
+	jl.Error("declined", Str(KeyUserID, "u-3"))
+	if err := jl.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	pushes := server.received()
+	if len(pushes) != 1 || server.tenants[0] != "team-a" {
+		t.Fatalf("expected one push for team-a, got %d %v", len(pushes), server.tenants)
+	}
+	streams := pushes[0].Streams
+	if len(streams) != 2 {
+		t.Fatalf("expected one stream per level, got %+v", streams)
+	}
+	info := streams[0]
+	want := map[string]string{"job": "api", "service": "checkout", "env": "prod", LokiLevelLabel: "info"}
+	if len(info.Stream) != len(want) {
+		t.Fatalf("unexpected labels: %v", info.Stream)
+	}
+	for name, value := range want {
+		if info.Stream[name] != value {
+			t.Fatalf("unexpected labels: %v", info.Stream)
+		}
+	}
+	if len(info.Values) != 2 || streams[1].Stream[LokiLevelLabel] != "error" || len(streams[1].Values) != 1 {
+		t.Fatalf("unexpected streams: %+v", streams)
+	}
+
+	line := info.Values[0][1]
+	if strings.Contains(line, "checkout") || strings.Contains(line, "prod") || strings.HasSuffix(line, "\n") {
+		t.Fatalf("expected labels to be left out of the line: %q", line)
+	}
+	var fields map[string]any
+	if err := json.Unmarshal([]byte(line), &fields); err != nil {
+		t.Fatalf("line is not JSON: %q", line)
+	}
+	if fields["message"] != "paid" || fields[KeyUserID] != "u-1" || fields["amount"] != float64(5) {
+		t.Fatalf("unexpected line: %q", line)
+	}
+}
+
+func TestLokiSinkTimestampsAndLabelNames(t *testing.T) {
+	server := newLokiServer(t)
+	sink := NewLokiSink(LokiConfig{URL: server.URL})
+	timestamp := time.Date(2024, 5, 1, 12, 0, 0, 42, time.UTC)
+
+	err := sink.Write(Entry{Time: timestamp, Level: WarnLevel, Message: "slow", Fields: []Field{Str("k8s.pod", "api-1")}})
+	if err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := sink.Flush(); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+
+	streams := server.received()[0].Streams
+	if streams[0].Values[0][0] != "1714564800000000042" {
+		t.Fatalf("expected the entry time in nanoseconds, got %q", streams[0].Values[0][0])
+	}
+	if !strings.Contains(streams[0].Values[0][1], `"k8s.pod":"api-1"`) {
+		t.Fatalf("expected fields to stay in the line without label keys: %q", streams[0].Values[0][1])
+	}
+	if got := lokiLabelName("k8s.pod-name"); got != "k8s_pod_name" {
+		t.Fatalf("unexpected label name %q", got)
+	}
+	if got := lokiLabelName("1st"); got != "_st" {
+		t.Fatalf("unexpected label name %q", got)
+	}
+}
+
+func TestLokiSinkLabelValuesAreStrings(t *testing.T) {
+	server := newLokiServer(t)
+	jl := NewJSONLoggerWithOptions(
+		WithOutput(&bytes.Buffer{}),
+		WithLabelKeys("region.id", "shard"),
+		WithSink(NewLokiSink(LokiConfig{URL: server.URL})),
+	)
+
+	jl.Info("started", Str("region.id", "eu-1"), Int("shard", 3))
+	if err := jl.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	labels := server.received()[0].Streams[0].Stream
+	if labels["region_id"] != "eu-1" || labels["shard"] != "3" {
+		t.Fatalf("unexpected labels: %v", labels)
+	}
+}
+
+func TestLokiSinkBatchesAndReportsFailures(t *testing.T) {
+	server := newLokiServer(t)
+	sink := NewLokiSink(LokiConfig{URL: server.URL, BatchSize: 2})
+	entry := Entry{Time: time.Now(), Level: InfoLevel, Message: "tick"}
+
+	if err := sink.Write(entry); err != nil || len(server.received()) != 0 {
+		t.Fatalf("expected the first entry to be buffered: %v", err)
+	}
+	if err := sink.Write(entry); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := sink.Flush(); err != nil || len(server.received()) != 1 {
+		t.Fatalf("expected the full batch to be pushed and nothing left: %v", err)
+	}
+
+	server.mutex.Lock()
+	server.status = http.StatusTooManyRequests
+	server.mutex.Unlock()
+	if err := sink.Write(entry); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := sink.Close(); err == nil || !strings.Contains(err.Error(), "429") {
+		t.Fatalf("expected the rejected push to be reported, got %v", err)
+	}
+}
+
+func TestLokiSinkPushesInTheBackground(t *testing.T) {
+	var pushes atomic.Int32
+	gate := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-gate
+		pushes.Add(1)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	sink := NewLokiSink(LokiConfig{URL: server.URL, BatchSize: 1, MaxBuffered: 2})
+	entry := Entry{Time: time.Now(), Level: InfoLevel, Message: "tick"}
+
+	done := make(chan error, 1)
+	go func() {
+		for range 3 {
+			if err := sink.Write(entry); err != nil {
+				done <- err
+				return
+			}
+		}
+		done <- sink.Write(entry)
+	}()
+	select {
+	case err := <-done:
+		if !errors.Is(err, errLokiBacklog) {
+			t.Fatalf("expected the entry beyond MaxBuffered to be dropped, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected Write not to wait for the push")
+	}
+
+	close(gate)
+	if err := sink.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if got := pushes.Load(); got != 2 {
+		t.Fatalf("expected the full batch and the buffered entries to be pushed, got %d pushes", got)
+	}
+}
+
+func TestLokiSinkScrubsLabelValues(t *testing.T) {
+	server := newLokiServer(t)
+	jl := NewJSONLoggerWithOptions(
+		WithOutput(&bytes.Buffer{}),
+		WithScrubbers(ScrubberFunc(func(value string) string {
+			return strings.ReplaceAll(value, "s3cret", "***")
+		})),
+		WithMaxFieldLength(8),
+		WithLabelKeys("tenant", "zone"),
+		WithSink(NewLokiSink(LokiConfig{URL: server.URL})),
+	)
+
+	jl.Info("started", Str("tenant", "s3cret"), Str("zone", "eu-central-1a"))
+	if err := jl.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	labels := server.received()[0].Streams[0].Stream
+	if labels["tenant"] != "***" || !strings.HasPrefix(labels["zone"], "eu-centr... [sha256:") {
+		t.Fatalf("expected scrubbed and capped label values: %v", labels)
+	}
+}
+
+func TestLokiSinkFlushInterval(t *testing.T) {
+	server := newLokiServer(t)
+	jl := NewJSONLoggerWithOptions(
+		WithOutput(&bytes.Buffer{}),
+		WithSink(NewLokiSink(LokiConfig{URL: server.URL, FlushInterval: 10 * time.Millisecond})),
+	)
+
+	jl.Info("background")
+	deadline := time.Now().Add(2 * time.Second)
+	for len(server.received()) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("expected the flush loop to push the entry")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if err := jl.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if len(server.received()) != 1 {
+		t.Fatalf("expected nothing left to push on Close, got %d pushes", len(server.received()))
+	}
+}
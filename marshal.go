@@ -3,113 +3,21 @@ package golog
 import (
 	"bytes"
 	"reflect"
-	"strconv"
-	"time"
 )
 
-// MarshalToBuffer attempts to encode arbitrary values using reflection into
-// the provided buffer. It returns an error if it encounters an unsupported
-// type (e.g., chan, func, complex) that we don't want to attempt to encode.
+// MarshalToBuffer encodes v as JSON into buf using the same reflection
+// encoder as log fields. It follows encoding/json for the common subset:
+// `json` tag names, "-", omitempty and string options, flattening of
+// untagged embedded structs, base64 []byte, RFC 3339 times, json.RawMessage
+// and map keys that are strings, integers or encoding.TextMarshalers. It
+// returns ErrUnsupportedType if it encounters a value it won't encode, such
+// as a chan, func, complex number or a map with float keys; buf then holds
+// the output written up to the failure.
 func MarshalToBuffer(buf *bytes.Buffer, v any) error {
-	return marshalValue(buf, reflect.ValueOf(v))
-}
-
-func marshalValue(buf *bytes.Buffer, reflectValue reflect.Value) error {
-	if !reflectValue.IsValid() {
-		buf.WriteString("null")
-		return nil
-	}
-
-	for reflectValue.Kind() == reflect.Interface || reflectValue.Kind() == reflect.Pointer {
-		if reflectValue.IsNil() {
-			buf.WriteString("null")
-			return nil
-		}
-		reflectValue = reflectValue.Elem()
-	}
-
-	switch reflectValue.Kind() {
-	case reflect.String:
-		fastQuote(buf, reflectValue.String())
-		return nil
-	case reflect.Bool:
-		if reflectValue.Bool() {
-			buf.WriteString("true")
-		} else {
-			buf.WriteString("false")
-		}
-		return nil
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		fastFormatInt(buf, reflectValue.Int())
-		return nil
-	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
-		fastFormatUint(buf, reflectValue.Uint())
-		return nil
-	case reflect.Float32, reflect.Float64:
-		buf.WriteString(strconv.FormatFloat(reflectValue.Float(), 'g', -1, 64))
-		return nil
-	case reflect.Map:
-		if reflectValue.Type().Key().Kind() != reflect.String {
-			return errMarshalTypeUnsupported
-		}
-		buf.WriteByte('{')
-		keys := reflectValue.MapKeys()
-		// MapKeys is nondeterministic order; keep original behavior and
-		// write in whatever order reflect returns to avoid extra allocs.
-		for i, k := range keys {
-			if i > 0 {
-				buf.WriteByte(',')
-			}
-			fastQuote(buf, k.String())
-			buf.WriteByte(':')
-			if err := marshalValue(buf, reflectValue.MapIndex(k)); err != nil {
-				return err
-			}
-		}
-		buf.WriteByte('}')
-		return nil
-	case reflect.Slice, reflect.Array:
-		buf.WriteByte('[')
-		for i := 0; i < reflectValue.Len(); i++ {
-			if i > 0 {
-				buf.WriteByte(',')
-			}
-			if err := marshalValue(buf, reflectValue.Index(i)); err != nil {
-				return err
-			}
-		}
-		buf.WriteByte(']')
-		return nil
-	case reflect.Struct:
-		if reflectValue.Type() == reflect.TypeOf(time.Time{}) {
-			t := reflectValue.Interface().(time.Time)
-			buf.WriteByte('"')
-			var tsBuf [64]byte
-			buf.Write(t.UTC().AppendFormat(tsBuf[:0], time.RFC3339Nano))
-			buf.WriteByte('"')
-			return nil
-		}
-		buf.WriteByte('{')
-		reflectionType := reflectValue.Type()
-		firstElement := true
-		for i := 0; i < reflectionType.NumField(); i++ {
-			field := reflectionType.Field(i)
-			if field.PkgPath != "" {
-				continue
-			}
-			if !firstElement {
-				buf.WriteByte(',')
-			}
-			fastQuote(buf, field.Name)
-			buf.WriteByte(':')
-			if err := marshalValue(buf, reflectValue.Field(i)); err != nil {
-				return err
-			}
-			firstElement = false
-		}
-		buf.WriteByte('}')
-		return nil
-	default:
-		return errMarshalTypeUnsupported
+	encoded, ok := reflectEncoder.appendReflect(buf.AvailableBuffer(), reflect.ValueOf(v), 0)
+	buf.Write(encoded)
+	if !ok {
+		return ErrUnsupportedType
 	}
+	return nil
 }
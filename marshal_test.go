@@ -3,9 +3,13 @@ package golog
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
+	"math"
+	"net/netip"
 	"reflect"
 	"testing"
 	"time"
+	"unicode/utf8"
 )
 
 func TestMarshalPrimitivesAndTime(t *testing.T) {
@@ -80,15 +84,47 @@ func TestMarshalMapSliceStruct(t *testing.T) {
 func TestMarshalUnsupportedTypes(t *testing.T) {
 	var buf bytes.Buffer
 	// func is unsupported
-	if err := MarshalToBuffer(&buf, func() {}); err != errMarshalTypeUnsupported {
-		t.Fatalf("expected errMarshalTypeUnsupported for func, got: %v", err)
+	if err := MarshalToBuffer(&buf, func() {}); !errors.Is(err, ErrUnsupportedType) {
+		t.Fatalf("expected ErrUnsupportedType for func, got: %v", err)
 	}
 
 	buf.Reset()
-	// map with non-string key is unsupported
-	m := map[int]string{1: "a"}
-	if err := MarshalToBuffer(&buf, m); err != errMarshalTypeUnsupported {
-		t.Fatalf("expected errMarshalTypeUnsupported for map[int]string, got: %v", err)
+	// map with a key that is neither a string, an integer nor a TextMarshaler is unsupported
+	if err := MarshalToBuffer(&buf, map[float64]string{1: "a"}); !errors.Is(err, ErrUnsupportedType) {
+		t.Fatalf("expected ErrUnsupportedType for map[float64]string, got: %v", err)
+	}
+}
+
+type textKey struct{ name string }
+
+func (k textKey) MarshalText() ([]byte, error) { return []byte("key-" + k.name), nil }
+
+func TestMarshalMapKeys(t *testing.T) {
+	cases := []any{
+		map[int]string{-1: "a", 2: "b"},
+		map[uint8]string{7: "a"},
+		map[textKey]int{{"a"}: 1, {"b"}: 2},
+		map[netip.Addr]bool{netip.MustParseAddr("10.0.0.1"): true},
+	}
+	for _, value := range cases {
+		var buf bytes.Buffer
+		if err := MarshalToBuffer(&buf, value); err != nil {
+			t.Fatalf("marshal %#v: %v", value, err)
+		}
+		want, err := json.Marshal(value)
+		if err != nil {
+			t.Fatalf("encoding/json %#v: %v", value, err)
+		}
+		var got, expected any
+		if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+			t.Fatalf("unmarshal %s: %v", buf.String(), err)
+		}
+		if err := json.Unmarshal(want, &expected); err != nil {
+			t.Fatalf("unmarshal %s: %v", want, err)
+		}
+		if !reflect.DeepEqual(got, expected) {
+			t.Fatalf("got %s, want %s", buf.String(), want)
+		}
 	}
 }
 
@@ -140,3 +176,112 @@ func TestMarshalNilPointerAndInterfaceAndArray(t *testing.T) {
 		t.Fatalf("expected A=5, got %v", pm["A"])
 	}
 }
+
+func TestMarshalNonFiniteFloatWritesNull(t *testing.T) {
+	var buf bytes.Buffer
+	if err := MarshalToBuffer(&buf, []float64{math.Inf(1), 2}); err != nil {
+		t.Fatalf("MarshalToBuffer error: %v", err)
+	}
+	if buf.String() != "[null,2]" {
+		t.Fatalf("unexpected encoding: %s", buf.String())
+	}
+}
+
+func TestMarshalMatchesEncodingJSONTags(t *testing.T) {
+	type Audit struct {
+		CreatedBy string `json:"created_by"`
+		Version   int    `json:"version,string"`
+	}
+	type Base struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+	type Order struct {
+		Kind string `json:"kind"`
+		Base
+		*Audit
+		Name     string            `json:"order_name"`
+		Note     string            `json:"note,omitempty"`
+		Tags     []string          `json:"tags,omitempty"`
+		Secret   string            `json:"-"`
+		Dash     string            `json:"-,"`
+		Total    float64           `json:"total,string"`
+		Paid     bool              `json:",string"`
+		Ref      *int              `json:"ref,string"`
+		Label    string            `json:"label,string"`
+		Payload  []byte            `json:"payload"`
+		Raw      json.RawMessage   `json:"raw"`
+		Extra    map[string]string `json:"extra,omitempty"`
+		internal string
+	}
+	ref := 7
+	orders := []Order{
+		{
+			Kind: "web", Base: Base{ID: 1, Name: "shadowed"}, Audit: &Audit{CreatedBy: "ann", Version: 3},
+			Name: "o-1", Tags: []string{"a"}, Secret: "s", Dash: "d", Total: 9.5, Paid: true, Ref: &ref,
+			Label: `say "hi"`, Payload: []byte("hi"), Raw: json.RawMessage(`{"a":1}`), internal: "x",
+		},
+		{Kind: "empty", Raw: json.RawMessage(`null`)},
+	}
+
+	for _, order := range orders {
+		var buf bytes.Buffer
+		if err := MarshalToBuffer(&buf, order); err != nil {
+			t.Fatalf("MarshalToBuffer error: %v", err)
+		}
+		want, err := json.Marshal(order)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if buf.String() != string(want) {
+			t.Fatalf("expected %s, got %s", want, buf.String())
+		}
+	}
+}
+
+func FuzzMarshalToBuffer(f *testing.F) {
+	f.Add("name", int64(1), 2.5, true, []byte("b"))
+	f.Add("<&>\u2028\x00", int64(-1), 0.0, false, []byte(nil))
+
+	type inner struct {
+		Tags []string `json:"tags,omitempty"`
+	}
+	type payload struct {
+		Name   string  `json:"name"`
+		Count  int64   `json:"count,string"`
+		Ratio  float64 `json:"ratio,omitempty"`
+		On     bool    `json:"on,string"`
+		Raw    []byte  `json:"raw"`
+		Skip   string  `json:"-"`
+		Ptr    *inner  `json:"ptr,omitempty"`
+		Nested inner
+		inner
+	}
+
+	f.Fuzz(func(t *testing.T, s string, i int64, fl float64, b bool, raw []byte) {
+		if math.IsNaN(fl) || math.IsInf(fl, 0) || !utf8.ValidString(s) {
+			return
+		}
+		value := payload{Name: s, Count: i, Ratio: fl, On: b, Raw: raw, Skip: s, Nested: inner{Tags: []string{s}}}
+		if b {
+			value.Ptr = &inner{Tags: []string{s, s}}
+		}
+
+		var buf bytes.Buffer
+		if err := MarshalToBuffer(&buf, value); err != nil {
+			t.Fatalf("MarshalToBuffer: %v", err)
+		}
+		want, err := json.Marshal(value)
+		if err != nil {
+			t.Fatalf("json.Marshal: %v", err)
+		}
+		var gotValue, wantValue any
+		if err := json.Unmarshal(buf.Bytes(), &gotValue); err != nil {
+			t.Fatalf("invalid JSON %s: %v", buf.Bytes(), err)
+		}
+		_ = json.Unmarshal(want, &wantValue)
+		if !reflect.DeepEqual(gotValue, wantValue) {
+			t.Fatalf("MarshalToBuffer disagrees with encoding/json:\n got %s\nwant %s", buf.Bytes(), want)
+		}
+	})
+}
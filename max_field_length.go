@@ -0,0 +1,73 @@
+package golog
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"unicode/utf8"
+)
+
+// WithMaxFieldLength caps string values at n bytes. Longer values are cut
+// at a UTF-8 boundary and marked with a short hash and the original length,
+// so entries stay searchable and identical payloads can still be matched:
+//
+//	"payload":"{\"items\":[{\"id\":1... [sha256:9f86d081, len=18234]"
+//
+// The cap applies to string values anywhere in an entry, including inside
+// maps and structs, in every output format; keys and the message are not
+// capped. Zero or a negative n disables it.
+func WithMaxFieldLength(n int) Option {
+	return func(jsonLogger *JSONLogger) {
+		jsonLogger.encoder.maxFieldLength = n
+	}
+}
+
+// stringValue applies the scrubbers and the length cap to a string value.
+// Every LogWriter passes string values through it, or through
+// appendStringValue, so masking and capping don't depend on the output
+// format.
+func (enc *encoder) stringValue(value string) string {
+	if len(enc.scrubbers) > 0 {
+		value = enc.scrub(value)
+	}
+	if enc.maxFieldLength <= 0 || len(value) <= enc.maxFieldLength {
+		return value
+	}
+	cut := enc.truncationCut(value)
+	return string(appendTruncationMarker([]byte(value[:cut]), value))
+}
+
+// appendStringValue quotes a string value, applying the scrubbers and the
+// length cap.
+func (enc *encoder) appendStringValue(dst []byte, value string) []byte {
+	if len(enc.scrubbers) > 0 {
+		value = enc.scrub(value)
+	}
+	if enc.maxFieldLength <= 0 || len(value) <= enc.maxFieldLength {
+		return enc.appendString(dst, value)
+	}
+	dst = enc.appendString(dst, value[:enc.truncationCut(value)])
+	dst = appendTruncationMarker(dst[:len(dst)-1], value)
+	return append(dst, '"')
+}
+
+// truncationCut returns where value is cut: at maxFieldLength bytes or the
+// UTF-8 boundary before it.
+func (enc *encoder) truncationCut(value string) int {
+	cut := enc.maxFieldLength
+	for cut > 0 && !utf8.RuneStart(value[cut]) {
+		cut--
+	}
+	return cut
+}
+
+// appendTruncationMarker appends the marker written after a cut value: a
+// short hash and the length of the whole value.
+func appendTruncationMarker(dst []byte, value string) []byte {
+	sum := sha256.Sum256([]byte(value))
+	dst = append(dst, "... [sha256:"...)
+	dst = hex.AppendEncode(dst, sum[:4])
+	dst = append(dst, ", len="...)
+	dst = strconv.AppendInt(dst, int64(len(value)), 10)
+	return append(dst, ']')
+}
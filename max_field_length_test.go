@@ -0,0 +1,79 @@
+package golog
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestWithMaxFieldLength(t *testing.T) {
+	long := strings.Repeat("x", 100)
+	sum := sha256.Sum256([]byte(long))
+	marker := "... [sha256:" + hex.EncodeToString(sum[:4]) + ", len=100]"
+
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(WithOutput(buf), WithMaxFieldLength(10))
+
+	jl.Info(long,
+		Str("payload", long),
+		Str("short", "fits"),
+		Any("nested", map[string]any{"body": long}),
+		Any("struct", struct{ Body string }{Body: long}),
+	)
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("output is not valid JSON: %v\n%s", err, buf.String())
+	}
+	want := strings.Repeat("x", 10) + marker
+	if entry["payload"] != want {
+		t.Fatalf("unexpected truncated value: %v", entry["payload"])
+	}
+	if entry["short"] != "fits" || entry["message"] != long {
+		t.Fatalf("expected short values and the message to be untouched: %v", entry)
+	}
+	if entry["nested"].(map[string]any)["body"] != want || entry["struct"].(map[string]any)["Body"] != want {
+		t.Fatalf("expected nested strings to be capped: %v", entry)
+	}
+}
+
+func TestMaxFieldLengthCutsAtRuneBoundary(t *testing.T) {
+	enc := encoder{maxFieldLength: 4}
+	got := string(enc.appendStringValue(nil, "aéééé"))
+	if !strings.HasPrefix(got, `"aé... [sha256:`) || !strings.HasSuffix(got, `, len=9]"`) {
+		t.Fatalf("unexpected truncation: %s", got)
+	}
+}
+
+func TestMaxFieldLengthEveryWriter(t *testing.T) {
+	long := strings.Repeat("a", 50)
+	writers := []struct {
+		name   string
+		option Option
+	}{
+		{name: "json", option: func(*JSONLogger) {}},
+		{name: "pretty", option: WithPrettyJSON()},
+		{name: "console", option: WithConsole()},
+		{name: "csv", option: WithLogWriter(NewCSVLogWriter(ColumnMessage, "payload", "nested"))},
+		{name: "cbor", option: WithBinaryFormat(BinaryCBOR)},
+		{name: "msgpack", option: WithBinaryFormat(BinaryMessagePack)},
+	}
+	for _, tc := range writers {
+		t.Run(tc.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			jl := NewJSONLoggerWithOptions(WithOutput(buf), WithMaxFieldLength(10), tc.option)
+			jl.Info("upload", Str("payload", long), Any("nested", map[string]any{"body": long}))
+
+			out := buf.String()
+			if strings.Contains(out, long[:11]) {
+				t.Fatalf("expected long values to be capped, got %q", out)
+			}
+			if strings.Count(out, "aaaaaaaaaa... [sha256:") != 2 || strings.Count(out, ", len=50]") != 2 {
+				t.Fatalf("expected 2 truncation markers, got %q", out)
+			}
+		})
+	}
+}
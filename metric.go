@@ -0,0 +1,49 @@
+package golog
+
+// Keys of the standardized metric fields written by Count and Gauge. Log
+// based metric pipelines (Loki recording rules, CloudWatch metric filters)
+// can extract series from them without a second instrumentation library.
+const (
+	MetricNameKey  = "metric_name"
+	MetricValueKey = "metric_value"
+	MetricTypeKey  = "metric_type"
+)
+
+// Values of MetricTypeKey.
+const (
+	MetricTypeCounter = "counter"
+	MetricTypeGauge   = "gauge"
+)
+
+// metricMessage is the message of every metric record.
+const metricMessage = "metric"
+
+// Count records that the counter name increased by delta. Like Event, the
+// record bypasses level filtering; fields become dimensions of the sample.
+//
+//	jl.Count("cache_miss", 1, Str("cache", "sessions"))
+func (jsonLogger *JSONLogger) Count(name string, delta int, fields ...Field) {
+	jsonLogger.logMetric(name, Int(MetricValueKey, delta), MetricTypeCounter, fields)
+}
+
+// Gauge records the current value of the gauge name.
+//
+//	jl.Gauge("queue_depth", float64(len(queue)))
+func (jsonLogger *JSONLogger) Gauge(name string, value float64, fields ...Field) {
+	jsonLogger.logMetric(name, Float64(MetricValueKey, value), MetricTypeGauge, fields)
+}
+
+func (jsonLogger *JSONLogger) logMetric(name string, value Field, metricType string, fields []Field) {
+	if jsonLogger.off() {
+		return
+	}
+	scratchPtr := fieldScratchPool.Get().(*[]Field)
+	scratch := append((*scratchPtr)[:0], Str(MetricNameKey, name), value, Str(MetricTypeKey, metricType))
+	scratch = append(scratch, fields...)
+
+	jsonLogger.logUnfiltered(metricMessage, scratch)
+
+	clear(scratch)
+	*scratchPtr = scratch[:0]
+	fieldScratchPool.Put(scratchPtr)
+}
@@ -0,0 +1,26 @@
+package golog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestCountAndGaugeFields(t *testing.T) {
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(WithOutput(buf), WithLevel(ErrorLevel))
+
+	jl.Count("cache_miss", 1, Str("cache", "sessions"))
+	jl.Gauge("queue_depth", 12.5)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected metrics to bypass level filtering, got %q", buf.String())
+	}
+	if !strings.Contains(lines[0], `"message":"metric","metric_name":"cache_miss","metric_value":1,"metric_type":"counter","cache":"sessions"`) {
+		t.Fatalf("unexpected counter record: %s", lines[0])
+	}
+	if !strings.Contains(lines[1], `"metric_name":"queue_depth","metric_value":12.5,"metric_type":"gauge"`) {
+		t.Fatalf("unexpected gauge record: %s", lines[1])
+	}
+}
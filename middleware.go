@@ -0,0 +1,84 @@
+package golog
+
+import "slices"
+
+// EntryFunc handles an entry on its way to the output and sinks.
+type EntryFunc func(entry Entry)
+
+// WithMiddleware adds middleware to the path every entry takes after level
+// filtering and before it is encoded. middleware receives the next EntryFunc
+// and returns one that can drop the entry by not calling next, change its
+// time, level, message or fields, or call next more than once to duplicate
+// it, so redaction, sampling and enrichment rules can be written once for
+// every call site:
+//
+//	golog.WithMiddleware(func(next golog.EntryFunc) golog.EntryFunc {
+//	    return func(entry golog.Entry) {
+//	        if entry.Message == "health check" {
+//	            return
+//	        }
+//	        entry.Fields = append(entry.Fields, golog.Str("region", region))
+//	        next(entry)
+//	    }
+//	})
+//
+// Middleware added first runs first. Entry.Fields holds the per-call fields,
+// with WithEntryTime already applied to Time; base fields are added when the
+// entry is encoded. The slice may be modified but is reused once the call
+// returns, so copy it to keep it. Every entry passed on gets its own
+// sequence number. Middleware runs while the entry is being written: it
+// must not log through the same logger or call Reconfigure. Events and metrics pass through it; AuditLogger entries and
+// flight recorder dumps don't. Using middleware costs no allocations beyond
+// those of the middleware itself.
+func WithMiddleware(middleware func(next EntryFunc) EntryFunc) Option {
+	return func(jsonLogger *JSONLogger) {
+		if middleware == nil {
+			return
+		}
+		jsonLogger.middlewares = append(jsonLogger.middlewares, middleware)
+		// Entries carry the configuration they are written with, which
+		// may be newer than the one the chain was built on.
+		next := EntryFunc(func(entry Entry) {
+			entry.config(jsonLogger).writeEntry(entry)
+		})
+		for _, outer := range slices.Backward(jsonLogger.middlewares) {
+			next = outer(next)
+		}
+		jsonLogger.middleware = next
+	}
+}
+
+// runMiddleware passes an entry through the middleware chain. The fields
+// are copied into a pooled slice so the caller's variadic slice stays on the
+// stack.
+func (jsonLogger *JSONLogger) runMiddleware(logLevel Level, message string, fields []Field) {
+	timestamp, fields := jsonLogger.entryTime(fields)
+	scratchPtr := fieldScratchPool.Get().(*[]Field)
+	scratch := append((*scratchPtr)[:0], fields...)
+
+	jsonLogger.middleware(Entry{Time: timestamp, Level: logLevel, Message: message, Fields: scratch, logger: jsonLogger})
+
+	clear(scratch)
+	*scratchPtr = scratch[:0]
+	fieldScratchPool.Put(scratchPtr)
+}
+
+// writeEntry is the end of the middleware chain: it writes entry to the
+// output and the sinks.
+func (jsonLogger *JSONLogger) writeEntry(entry Entry) {
+	if jsonLogger.levelStats != nil {
+		jsonLogger.levelStats.record(entry.Time, entry.Level)
+	}
+	bufPtr := jsonLogger.bufferPool.Get().(*[]byte)
+	buffer, seq := jsonLogger.writeNumbered((*bufPtr)[:0], entry.Time, entry.Level, entry.Message, entry.Fields)
+
+	*bufPtr = buffer[:0]
+	jsonLogger.bufferPool.Put(bufPtr)
+
+	if len(jsonLogger.sinks) > 0 && !jsonLogger.stopped.Load() {
+		jsonLogger.writeSinksAt(seq, entry.Time, entry.Level, entry.Message, entry.Fields)
+	}
+	if len(jsonLogger.errorHooks) > 0 && entry.Level >= ErrorLevel {
+		jsonLogger.runErrorHooks(entry.Message, entry.Fields)
+	}
+}
@@ -0,0 +1,118 @@
+package golog
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithMiddleware(t *testing.T) {
+	var order []string
+	trace := func(name string) func(next EntryFunc) EntryFunc {
+		return func(next EntryFunc) EntryFunc {
+			return func(entry Entry) {
+				order = append(order, name)
+				next(entry)
+			}
+		}
+	}
+	dropHealth := func(next EntryFunc) EntryFunc {
+		return func(entry Entry) {
+			if entry.Message != "health check" {
+				next(entry)
+			}
+		}
+	}
+	enrich := func(next EntryFunc) EntryFunc {
+		return func(entry Entry) {
+			entry.Fields = append(entry.Fields, Str("region", "eu"))
+			if entry.Level == ErrorLevel {
+				entry.Message = strings.ToUpper(entry.Message)
+			}
+			next(entry)
+		}
+	}
+	duplicateErrors := func(next EntryFunc) EntryFunc {
+		return func(entry Entry) {
+			next(entry)
+			if entry.Level == ErrorLevel {
+				entry.Fields = append(entry.Fields[:len(entry.Fields):len(entry.Fields)], Bool("copy", true))
+				next(entry)
+			}
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	sink := &recordingSink{}
+	jl := NewJSONLoggerWithOptions(
+		WithOutput(buf),
+		WithSink(sink),
+		WithSequenceNumbers(),
+		WithMiddleware(trace("outer")),
+		WithMiddleware(trace("inner")),
+		WithMiddleware(dropHealth),
+		WithMiddleware(enrich),
+		WithMiddleware(duplicateErrors),
+		WithMiddleware(nil),
+	)
+	jl.Info("health check")
+	jl.Info("request", Int("status", 200))
+	jl.Error("failed")
+
+	if strings.Join(order[:2], ",") != "outer,inner" {
+		t.Fatalf("expected middleware to run in registration order, got %v", order)
+	}
+	entries := readEntries(t, buf.Bytes())
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d:\n%s", len(entries), buf.String())
+	}
+	request := entries[0].FieldMap()
+	if entries[0].Message != "request" || request["status"] != int64(200) || request["region"] != "eu" || request[SequenceKey] != int64(1) {
+		t.Fatalf("unexpected enriched entry: %v", request)
+	}
+	if entries[1].Message != "FAILED" || entries[2].FieldMap()["copy"] != true || entries[2].FieldMap()[SequenceKey] != int64(3) {
+		t.Fatalf("expected the error entry to be modified and duplicated, got %+v", entries[1:])
+	}
+	if len(sink.entries) != 3 || sink.entries[2].Message != "FAILED" {
+		t.Fatalf("expected sinks to receive the middleware's entries, got %+v", sink.entries)
+	}
+}
+
+func TestWithMiddlewareKeepsEntryTime(t *testing.T) {
+	buf := &bytes.Buffer{}
+	var seen Entry
+	jl := NewJSONLoggerWithOptions(WithOutput(buf), WithMiddleware(func(next EntryFunc) EntryFunc {
+		return func(entry Entry) {
+			seen = entry
+			next(entry)
+		}
+	}))
+	at := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	jl.Info("replayed", WithEntryTime(at), Str("k", "v"))
+
+	if !seen.Time.Equal(at) || len(seen.Fields) != 1 {
+		t.Fatalf("expected the entry time to be applied before middleware, got %v %v", seen.Time, seen.Fields)
+	}
+	if entries := readEntries(t, buf.Bytes()); !entries[0].Time.Equal(at) {
+		t.Fatalf("expected the entry time to be written, got %v", entries[0].Time)
+	}
+}
+
+func TestWithMiddlewareAllocations(t *testing.T) {
+	if raceEnabled {
+		t.Skip("sync.Pool drops items under the race detector")
+	}
+	jl := NewJSONLoggerWithOptions(WithOutput(io.Discard), WithMiddleware(func(next EntryFunc) EntryFunc {
+		return next
+	}), WithMiddleware(func(next EntryFunc) EntryFunc {
+		return func(entry Entry) { next(entry) }
+	}))
+	allocs := testing.AllocsPerRun(100, func() {
+		jl.Info("msg", Int("n", 1), Str("s", "x"))
+	})
+	if allocs != 0 {
+		t.Fatalf("expected pass-through middleware not to allocate, got %v", allocs)
+	}
+}
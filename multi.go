@@ -0,0 +1,64 @@
+package golog
+
+import "errors"
+
+// multiLogger forwards every call to each of its loggers in order.
+type multiLogger []Logger
+
+// Multi returns a Logger that forwards each call to all of the given loggers,
+// in the order they were passed. Nil loggers are skipped. This fans out at the
+// Logger level, so each destination keeps its own level, format and output.
+func Multi(loggers ...Logger) Logger {
+	fanOut := make(multiLogger, 0, len(loggers))
+	for _, l := range loggers {
+		if l != nil {
+			fanOut = append(fanOut, l)
+		}
+	}
+	return fanOut
+}
+
+func (multi multiLogger) Info(message string, fields ...Field) {
+	for _, l := range multi {
+		l.Info(message, fields...)
+	}
+}
+
+func (multi multiLogger) Warn(message string, fields ...Field) {
+	for _, l := range multi {
+		l.Warn(message, fields...)
+	}
+}
+
+func (multi multiLogger) Error(message string, fields ...Field) {
+	for _, l := range multi {
+		l.Error(message, fields...)
+	}
+}
+
+func (multi multiLogger) Debug(message string, fields ...Field) {
+	for _, l := range multi {
+		l.Debug(message, fields...)
+	}
+}
+
+// Enabled reports whether any of the loggers writes entries at level.
+func (multi multiLogger) Enabled(level Level) bool {
+	for _, l := range multi {
+		if enabled(l, level) {
+			return true
+		}
+	}
+	return false
+}
+
+// Flush flushes every logger, returning their errors joined.
+func (multi multiLogger) Flush() error {
+	var errs []error
+	for _, l := range multi {
+		if err := flushLogger(l); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
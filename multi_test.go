@@ -0,0 +1,90 @@
+package golog
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMultiForwardsToAllLoggers(t *testing.T) {
+	first := &bytes.Buffer{}
+	second := &bytes.Buffer{}
+	jsonBuf := &bytes.Buffer{}
+
+	l := Multi(
+		&BLogger{b: first},
+		nil,
+		&BLogger{b: second},
+		NewJSONLoggerWithOptions(WithOutput(jsonBuf), WithLevel(WarnLevel)),
+	)
+
+	l.Info("one")
+	l.Warn("two")
+	l.Error("three")
+	l.Debug("four")
+
+	for _, buf := range []*bytes.Buffer{first, second} {
+		want := "I:one\nW:two\nE:three\nD:four\n"
+		if buf.String() != want {
+			t.Fatalf("expected %q, got %q", want, buf.String())
+		}
+	}
+
+	levels := collectLevelsFromBuffer(jsonBuf)
+	if len(levels) != 2 {
+		t.Fatalf("expected JSON logger to keep its own level filtering, got %v", levels)
+	}
+	if strings.Contains(jsonBuf.String(), `"one"`) {
+		t.Fatalf("did not expect info entry in JSON output: %s", jsonBuf.String())
+	}
+}
+
+// failingFlushLogger fails every Flush.
+type failingFlushLogger struct {
+	nopLogger
+	err error
+}
+
+func (l failingFlushLogger) Flush() error {
+	return l.err
+}
+
+func TestMultiEnabled(t *testing.T) {
+	warn := NewJSONLoggerWithOptions(WithOutput(&bytes.Buffer{}), WithLevel(WarnLevel))
+	errorOnly := NewJSONLoggerWithOptions(WithOutput(&bytes.Buffer{}), WithLevel(ErrorLevel))
+
+	l := Multi(warn, errorOnly)
+	if enabled(l, InfoLevel) || !enabled(l, WarnLevel) || !enabled(l, ErrorLevel) {
+		t.Fatalf("expected Multi to be enabled for the levels any logger writes")
+	}
+	if enabled(Multi(Nop(), Nop()), ErrorLevel) {
+		t.Fatalf("expected Multi of disabled loggers to be disabled")
+	}
+	if !enabled(Multi(warn, &BLogger{b: &bytes.Buffer{}}), DebugLevel) {
+		t.Fatalf("expected loggers without Enabled to count as enabled")
+	}
+}
+
+func TestMultiFlush(t *testing.T) {
+	output := &countingWriter{}
+	async := NewJSONLoggerWithOptions(WithOutput(output), WithAsync(AsyncConfig{MaxLatency: time.Hour}))
+	defer async.Close()
+	counting := &flushCountingLogger{}
+
+	l := Multi(async, counting, &BLogger{b: &bytes.Buffer{}})
+	l.Info("queued")
+	if err := flushLogger(l); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	if _, data := output.snapshot(); len(readEntries(t, data)) != 1 || counting.flushes != 1 {
+		t.Fatalf("expected Flush to reach every logger, got %q and %d flushes", data, counting.flushes)
+	}
+
+	first, second := errors.New("first"), errors.New("second")
+	err := flushLogger(Multi(failingFlushLogger{err: first}, counting, failingFlushLogger{err: second}))
+	if !errors.Is(err, first) || !errors.Is(err, second) || counting.flushes != 2 {
+		t.Fatalf("expected every logger flushed and the errors joined, got %v", err)
+	}
+}
@@ -0,0 +1,19 @@
+package golog
+
+// nopLogger is a Logger that discards every call without inspecting its
+// fields.
+type nopLogger struct{}
+
+// Nop returns a Logger whose methods do nothing. It is a safe default for
+// libraries that accept an optional Logger, and a useful baseline when
+// benchmarking the cost of call sites themselves.
+func Nop() Logger {
+	return nopLogger{}
+}
+
+func (nopLogger) Info(string, ...Field)  {}
+func (nopLogger) Warn(string, ...Field)  {}
+func (nopLogger) Error(string, ...Field) {}
+func (nopLogger) Debug(string, ...Field) {}
+func (nopLogger) Enabled(Level) bool     { return false }
+func (nopLogger) Flush() error           { return nil }
@@ -0,0 +1,27 @@
+package golog
+
+import "testing"
+
+func TestNopLoggerDiscards(t *testing.T) {
+	l := Nop()
+	l.Info("info", Str("k", "v"))
+	l.Warn("warn")
+	l.Error("error", Int("code", 500))
+	l.Debug("debug")
+
+	allocs := testing.AllocsPerRun(100, func() {
+		l.Info("hot path", Str("k", "v"), Int("n", 1))
+	})
+	if allocs != 0 {
+		t.Fatalf("expected zero allocations, got %v", allocs)
+	}
+}
+
+func TestSetLoggerNop(t *testing.T) {
+	prev := logger
+	defer SetLogger(prev)
+
+	SetLogger(Nop())
+	Info("dropped")
+	Error("dropped")
+}
@@ -0,0 +1,5 @@
+//go:build !race
+
+package golog
+
+const raceEnabled = false
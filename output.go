@@ -0,0 +1,72 @@
+package golog
+
+import (
+	"io"
+	"os"
+)
+
+// Flush flushes the output if it buffers data (it has a Flush() error
+// method, like CompressedWriter, the async writer or bufio.Writer), then
+// flushes every sink.
+func (jsonLogger *JSONLogger) Flush() error {
+	return jsonLogger.current().flush()
+}
+
+// flush is Flush for one configuration.
+func (jsonLogger *JSONLogger) flush() error {
+	var err error
+	if flusher, ok := jsonLogger.output.(interface{ Flush() error }); ok {
+		jsonLogger.mutex.Lock()
+		err = flusher.Flush()
+		jsonLogger.mutex.Unlock()
+	}
+	for _, sink := range jsonLogger.sinks {
+		if flushErr := sink.Flush(); err == nil {
+			err = flushErr
+		}
+	}
+	return err
+}
+
+// Close flushes the output and closes it if it implements io.Closer, then
+// closes every sink. The process's standard output and error streams are
+// never closed.
+func (jsonLogger *JSONLogger) Close() error {
+	jsonLogger.stopHealthchecks()
+	jsonLogger.stopLevelSummaries()
+	// Reconfigure must not swap the output being closed.
+	jsonLogger.configMutex.Lock()
+	defer jsonLogger.configMutex.Unlock()
+	config := jsonLogger.current()
+	err := config.flush()
+	// Retire the configuration in use for an identical copy so the entries
+	// still being written with it can drain, then hold the write lock of
+	// the output, which after Reconfigure isn't the root's and which the
+	// copy shares.
+	jsonLogger.config.Store(config.clone())
+	config.inflight.wait()
+	config.mutex.Lock()
+	closeErr := closeOutput(config.output)
+	config.mutex.Unlock()
+	if err == nil {
+		err = closeErr
+	}
+	for _, sink := range config.sinks {
+		if closeErr := sink.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// closeOutput closes w if it implements io.Closer, leaving the standard
+// streams open.
+func closeOutput(w io.Writer) error {
+	if w == os.Stdout || w == os.Stderr {
+		return nil
+	}
+	if closer, ok := w.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package golog
+
+import (
+	"bytes"
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+type closeRecorder struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (recorder *closeRecorder) Close() error {
+	recorder.closed = true
+	return nil
+}
+
+func TestJSONLoggerCloseClosesOutput(t *testing.T) {
+	output := &closeRecorder{}
+	jl := NewJSONLoggerWithOptions(WithOutput(output))
+
+	if err := jl.Flush(); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	if err := jl.Close(); err != nil || !output.closed {
+		t.Fatalf("expected output to be closed, err=%v", err)
+	}
+}
+
+// closingWriter is an unsynchronized writer that fails after Close, so the
+// race detector reports a Close that isn't serialized with writes.
+type closingWriter struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (writer *closingWriter) Write(p []byte) (int, error) {
+	if writer.closed {
+		return 0, os.ErrClosed
+	}
+	return writer.Buffer.Write(p)
+}
+
+func (writer *closingWriter) Close() error {
+	writer.closed = true
+	return nil
+}
+
+func TestJSONLoggerCloseWaitsForWritesAfterReconfigure(t *testing.T) {
+	output := &closingWriter{}
+	jl := NewJSONLoggerWithOptions(WithOutput(&bytes.Buffer{}))
+	if err := jl.Reconfigure(WithOutput(output)); err != nil {
+		t.Fatalf("reconfigure: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Go(func() {
+			for range 200 {
+				jl.Info("tick")
+			}
+		})
+	}
+	if err := jl.Close(); err != nil || !output.closed {
+		t.Fatalf("expected output to be closed, err=%v", err)
+	}
+	wg.Wait()
+}
+
+func TestJSONLoggerCloseDoesNotWaitForLaterWrites(t *testing.T) {
+	jl := NewJSONLoggerWithOptions(WithOutput(&lockedBuffer{}), WithWriteLock(false))
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Go(func() {
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+					jl.Info("tick")
+				}
+			}
+		})
+	}
+	closed := make(chan error, 1)
+	go func() { closed <- jl.Close() }()
+	select {
+	case err := <-closed:
+		if err != nil {
+			t.Fatalf("close: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected Close not to wait for entries begun after it")
+	}
+	close(stop)
+	wg.Wait()
+}
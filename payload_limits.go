@@ -0,0 +1,74 @@
+package golog
+
+import "strconv"
+
+const (
+	// TruncatedMarker replaces containers nested deeper than WithMaxDepth
+	// allows and ends arrays cut short by WithMaxFields.
+	TruncatedMarker = "<truncated>"
+	// TruncatedKey ends objects cut short by WithMaxFields and carries the
+	// number of members that were dropped, e.g. {"a":1,"_truncated":41}.
+	TruncatedKey = "_truncated"
+)
+
+// WithMaxDepth limits how deeply objects and arrays are nested inside a
+// field value. A container at depth n or deeper is written as
+// "<truncated>" instead of being encoded, so a runaway or self-similar
+// payload costs neither encoder CPU nor index mappings downstream. With
+// WithMaxDepth(1) a map field keeps its own members but any object or array
+// inside it is truncated. Pointers and interfaces don't count as levels.
+// Zero or a negative n disables the limit.
+//
+// The limit applies to the JSON writers; WithBinaryFormat keeps its own
+// fixed recursion bound.
+func WithMaxDepth(n int) Option {
+	return func(jsonLogger *JSONLogger) {
+		jsonLogger.encoder.maxDepth = n
+	}
+}
+
+// WithMaxFields limits how many members an object and how many elements an
+// array may have. Objects keep their first n members and get a TruncatedKey
+// member counting the rest; arrays keep their first n elements followed by
+// "<truncated>". Map iteration order is random, so which map members survive
+// varies between entries.
+//
+// The limit also caps the fields passed to a single logging call: fields
+// beyond the first n are dropped and counted under TruncatedKey, which costs
+// one allocation for that entry. Fields from WithBaseFields are not
+// counted. Zero or a negative n disables the limit.
+func WithMaxFields(n int) Option {
+	return func(jsonLogger *JSONLogger) {
+		jsonLogger.encoder.maxFields = n
+	}
+}
+
+// depthExceeded reports whether a container at depth must be truncated.
+func (enc *encoder) depthExceeded(depth int) bool {
+	return enc.maxDepth > 0 && depth >= enc.maxDepth
+}
+
+// appendTruncatedMembers closes off an object that still has dropped
+// members to write.
+func (enc *encoder) appendTruncatedMembers(dst []byte, dropped int) []byte {
+	dst = append(dst, ',')
+	dst = enc.appendString(dst, TruncatedKey)
+	dst = append(dst, ':')
+	return strconv.AppendInt(dst, int64(dropped), 10)
+}
+
+// appendTruncatedElements closes off an array that had elements dropped.
+func appendTruncatedElements(dst []byte) []byte {
+	dst = append(dst, ',')
+	return appendQuoteBytes(dst, TruncatedMarker)
+}
+
+// limitFields applies WithMaxFields to the fields of one logging call.
+func (enc *encoder) limitFields(fields []Field) []Field {
+	if enc.maxFields <= 0 || len(fields) <= enc.maxFields {
+		return fields
+	}
+	limited := make([]Field, enc.maxFields, enc.maxFields+1)
+	copy(limited, fields)
+	return append(limited, Int(TruncatedKey, len(fields)-enc.maxFields))
+}
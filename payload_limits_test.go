@@ -0,0 +1,157 @@
+package golog
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestWithMaxDepth(t *testing.T) {
+	type inner struct {
+		Tags []string
+	}
+	type outer struct {
+		Name  string
+		Inner *inner
+	}
+
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(WithOutput(buf), WithMaxDepth(2))
+	jl.Info("deep",
+		Any("map", map[string]any{"a": map[string]any{"b": map[string]any{"c": 1}}}),
+		Any("slice", []any{[]any{[]int{1}}, 2}),
+		Any("struct", outer{Name: "x", Inner: &inner{Tags: []string{"t"}}}),
+		Any("tags", map[string][]string{"k": {"v"}}),
+		Str("plain", "kept"),
+	)
+
+	out := buf.String()
+	for _, want := range []string{
+		`"map":{"a":{"b":"<truncated>"}}`,
+		`"slice":[["<truncated>"],2]`,
+		`"struct":{"Name":"x","Inner":{"Tags":"<truncated>"}}`,
+		`"tags":{"k":["v"]}`,
+		`"plain":"kept"`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %s in %s", want, out)
+		}
+	}
+	if strings.Contains(out, EncodeErrorsKey) {
+		t.Fatalf("truncation must not be reported as an encode error: %s", out)
+	}
+}
+
+func TestWithMaxDepthOne(t *testing.T) {
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(WithOutput(buf), WithMaxDepth(1))
+	jl.Info("shallow", Any("payload", map[string]any{"id": 1, "items": []any{1}}))
+
+	entries := decodeLines(t, buf.Bytes())
+	payload := entries[0]["payload"].(map[string]any)
+	if payload["id"] != float64(1) || payload["items"] != TruncatedMarker {
+		t.Fatalf("unexpected payload: %v", payload)
+	}
+}
+
+func TestWithMaxFields(t *testing.T) {
+	type wide struct{ A, B, C, D int }
+
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(WithOutput(buf), WithMaxFields(2))
+	jl.Info("wide",
+		Any("map", map[string]int{"a": 1, "b": 2, "c": 3, "d": 4, "e": 5}),
+		Any("slice", []int{1, 2, 3, 4}),
+		Any("short", []any{1, 2}),
+	)
+	jl.Info("struct", Any("struct", wide{1, 2, 3, 4}), Any("short", []any{1, 2}))
+
+	entries := decodeLines(t, buf.Bytes())
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	first := entries[0]
+	mapField := first["map"].(map[string]any)
+	if len(mapField) != 3 || mapField[TruncatedKey] != float64(3) {
+		t.Fatalf("expected 2 members and a count of 3 dropped, got %v", mapField)
+	}
+	slice := first["slice"].([]any)
+	if len(slice) != 3 || slice[0] != float64(1) || slice[1] != float64(2) || slice[2] != TruncatedMarker {
+		t.Fatalf("unexpected truncated slice: %v", slice)
+	}
+	if _, ok := first["short"]; ok || first[TruncatedKey] != float64(1) {
+		t.Fatalf("expected the third call field to be dropped and counted: %v", first)
+	}
+
+	structField := entries[1]["struct"].(map[string]any)
+	if structField["A"] != float64(1) || structField["B"] != float64(2) || structField[TruncatedKey] != float64(2) || len(structField) != 3 {
+		t.Fatalf("unexpected truncated struct: %v", structField)
+	}
+	if short := entries[1]["short"].([]any); len(short) != 2 {
+		t.Fatalf("expected a slice within the limit to be untouched: %v", short)
+	}
+}
+
+func TestWithMaxFieldsLenient(t *testing.T) {
+	enc := encoder{reflectFallback: true, maxFields: 1}
+	got, paths := enc.appendValueLenient(nil, []any{func() {}, 1, 2}, "items")
+	if string(got) != `["<unsupported>","<truncated>"]` {
+		t.Fatalf("unexpected encoding: %s", got)
+	}
+	if len(paths) != 1 || paths[0] != "items[0]" {
+		t.Fatalf("unexpected paths: %v", paths)
+	}
+}
+
+func TestMaxDepthCyclicValue(t *testing.T) {
+	type node struct {
+		Next *node
+	}
+	cyclic := &node{}
+	cyclic.Next = cyclic
+
+	enc := encoder{reflectFallback: true, maxDepth: 3}
+	got, ok := enc.appendValue(nil, cyclic)
+	if !ok || string(got) != `{"Next":{"Next":{"Next":"<truncated>"}}}` {
+		t.Fatalf("expected the cycle to be cut at the depth limit, got %s (ok=%v)", got, ok)
+	}
+}
+
+func TestMaxFieldsSinks(t *testing.T) {
+	sink := &recordingSink{}
+	jl := NewJSONLoggerWithOptions(WithOutput(&bytes.Buffer{}), WithSink(sink), WithMaxFields(1))
+	jl.Info("call", Int("a", 1), Int("b", 2), Int("c", 3))
+
+	fields := sink.entries[0].Fields
+	last := fields[len(fields)-1]
+	if last.key != TruncatedKey || last.intVal != 2 {
+		t.Fatalf("expected sinks to see the truncated fields, got %v", fields)
+	}
+}
+
+func TestPayloadLimitsDisabledAllocations(t *testing.T) {
+	jl := NewJSONLoggerWithOptions(WithOutput(io.Discard), WithMaxDepth(4), WithMaxFields(8))
+	payload := map[string]any{"tags": []string{"a", "b"}, "n": 1}
+	allocs := testing.AllocsPerRun(100, func() {
+		jl.Info("msg", Any("payload", payload), Int("n", 1))
+	})
+	if allocs != 0 {
+		t.Fatalf("expected limits within bounds not to allocate, got %v", allocs)
+	}
+}
+
+// decodeLines decodes newline-delimited JSON entries.
+func decodeLines(t *testing.T, data []byte) []map[string]any {
+	t.Helper()
+	var entries []map[string]any
+	for line := range bytes.Lines(data) {
+		var entry map[string]any
+		if err := json.Unmarshal(line, &entry); err != nil {
+			t.Fatalf("invalid JSON line %s: %v", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
@@ -0,0 +1,52 @@
+package golog
+
+import (
+	"reflect"
+)
+
+// ProtoMessage matches generated protobuf message types, which all carry
+// the ProtoMessage marker method. It lets golog recognize messages without
+// depending on the protobuf module.
+type ProtoMessage interface {
+	ProtoMessage()
+}
+
+var protoMessageType = reflect.TypeFor[ProtoMessage]()
+
+// WithProtoMarshaler encodes field values that are protobuf messages, at any
+// depth, with marshal instead of the reflection encoder, which doesn't know
+// about oneofs, well-known types or JSON field names. Wire it to protojson:
+//
+//	jl := NewJSONLoggerWithOptions(WithProtoMarshaler(func(m ProtoMessage) ([]byte, error) {
+//	    return protojson.Marshal(m.(proto.Message))
+//	}))
+//	jl.Info("order received", Any("order", order))
+//
+// The result is compacted onto one line; a marshal error or invalid JSON
+// writes "<unsupported>". A nil marshal restores the default.
+func WithProtoMarshaler(marshal func(ProtoMessage) ([]byte, error)) Option {
+	return func(jsonLogger *JSONLogger) {
+		jsonLogger.encoder.protoMarshal = marshal
+	}
+}
+
+// appendProto appends message encoded by the configured marshaler.
+func (enc *encoder) appendProto(dst []byte, message ProtoMessage) ([]byte, bool) {
+	encoded, err := enc.protoMarshal(message)
+	if err != nil {
+		return dst, false
+	}
+	return appendCompactJSON(dst, encoded)
+}
+
+// protoValue reports whether value holds a non-nil protobuf message the
+// marshaler should encode.
+func (enc *encoder) protoValue(value reflect.Value) (ProtoMessage, bool) {
+	if enc.protoMarshal == nil || !value.CanInterface() || !value.Type().Implements(protoMessageType) {
+		return nil, false
+	}
+	if value.Kind() == reflect.Pointer && value.IsNil() {
+		return nil, false
+	}
+	return value.Interface().(ProtoMessage), true
+}
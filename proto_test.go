@@ -0,0 +1,52 @@
+package golog
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// fakeOrder stands in for a generated protobuf message.
+type fakeOrder struct {
+	ID    string
+	Total int
+}
+
+func (*fakeOrder) ProtoMessage() {}
+
+func fakeProtoJSON(message ProtoMessage) ([]byte, error) {
+	order := message.(*fakeOrder)
+	if order.ID == "" {
+		return nil, errors.New("missing id")
+	}
+	// protojson output isn't stable and may contain extra whitespace.
+	return []byte(`{"orderId": "` + order.ID + `",  "total": ` + strings.Repeat("9", order.Total) + "}\n"), nil
+}
+
+func TestWithProtoMarshaler(t *testing.T) {
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(WithOutput(buf), WithProtoMarshaler(fakeProtoJSON))
+
+	var missing *fakeOrder
+	jl.Info("orders",
+		Any("order", &fakeOrder{ID: "o-1", Total: 2}),
+		Any("nested", map[string]any{"items": []*fakeOrder{{ID: "o-2", Total: 1}}}),
+		Any("wrapped", struct{ Order *fakeOrder }{&fakeOrder{ID: "o-3", Total: 1}}),
+		Any("missing", missing),
+		Any("broken", &fakeOrder{}),
+	)
+
+	want := `"order":{"orderId":"o-1","total":99},"nested":{"items":[{"orderId":"o-2","total":9}]},` +
+		`"wrapped":{"Order":{"orderId":"o-3","total":9}},"missing":null,"broken":"<unsupported>","encode_errors":["broken"]}`
+	if !strings.HasSuffix(strings.TrimSpace(buf.String()), want) {
+		t.Fatalf("expected %s, got %s", want, buf.String())
+	}
+
+	buf.Reset()
+	WithProtoMarshaler(nil)(jl)
+	jl.Info("orders", Any("order", &fakeOrder{ID: "o-1", Total: 2}))
+	if !strings.Contains(buf.String(), `"order":{"ID":"o-1","Total":2}`) {
+		t.Fatalf("expected the reflection encoder without a marshaler, got %s", buf.String())
+	}
+}
@@ -0,0 +1,147 @@
+package golog
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Publisher sends a message to a pub/sub subject or topic. data is only
+// valid until Publish returns: a Publisher that sends later must copy it.
+// *nats.Conn satisfies it directly; MQTT clients plug in with a small
+// adapter:
+//
+//	type mqttPublisher struct{ client mqtt.Client }
+//
+//	func (p mqttPublisher) Publish(topic string, data []byte) error {
+//	    token := p.client.Publish(topic, 1, false, data)
+//	    token.Wait()
+//	    return token.Error()
+//	}
+type Publisher interface {
+	Publish(topic string, data []byte) error
+}
+
+// PublishWriter is an output that publishes every record as one message,
+// without the trailing newline. Install it with WithOutput; it accepts
+// several records per Write, so it also works behind WithAsync. A JSON
+// record is one message even when it spans lines, as written by
+// PrettyJSONLogWriter; records in other formats are one line each.
+//
+//	jl := NewJSONLoggerWithOptions(WithOutput(&PublishWriter{
+//	    Publisher: natsConn,
+//	    Topic:     "logs.other",
+//	    Route:     TopicPerLevel("logs."),
+//	}))
+type PublishWriter struct {
+	// Publisher delivers the messages.
+	Publisher Publisher
+	// Topic is used for every record when Route is nil, and for records
+	// Route returns "" for.
+	Topic string
+	// Route picks the topic of a record from its decoded level and fields.
+	// Records are only decoded when Route is set. Entry.Time is not filled
+	// in.
+	Route func(entry Entry) string
+}
+
+// TopicPerLevel routes records to prefix followed by the level name, e.g.
+// "logs.error".
+func TopicPerLevel(prefix string) func(entry Entry) string {
+	return func(entry Entry) string {
+		return prefix + entry.Level.String()
+	}
+}
+
+// TopicPerField routes records to prefix followed by the value of the field
+// key, e.g. "devices.sensor-7" for TopicPerField("device", "devices.").
+// Records without the field go to the writer's Topic.
+func TopicPerField(key, prefix string) func(entry Entry) string {
+	return func(entry Entry) string {
+		field, ok := entry.Field(key)
+		if !ok {
+			return ""
+		}
+		if value, ok := field.Value().(string); ok {
+			return prefix + value
+		}
+		return prefix + fmt.Sprint(field.Value())
+	}
+}
+
+// Write publishes each record in p. Every record is attempted; the first
+// error is returned.
+func (writer *PublishWriter) Write(p []byte) (int, error) {
+	var firstErr error
+	for rest := p; len(rest) > 0; {
+		var record []byte
+		record, rest = nextRecord(rest)
+		if len(record) == 0 {
+			continue
+		}
+		if err := writer.Publisher.Publish(writer.topic(record), record); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	if firstErr != nil {
+		return 0, firstErr
+	}
+	return len(p), nil
+}
+
+// nextRecord splits the first record off p, without its trailing newline:
+// the JSON object p starts with, whatever newlines it holds, or else the
+// first line.
+func nextRecord(p []byte) (record, rest []byte) {
+	if end := objectEnd(p); end > 0 {
+		record, rest = p[:end], p[end:]
+		if len(rest) > 0 && rest[0] == '\n' {
+			rest = rest[1:]
+		}
+		return record, rest
+	}
+	if i := bytes.IndexByte(p, '\n'); i >= 0 {
+		return p[:i], p[i+1:]
+	}
+	return p, nil
+}
+
+// objectEnd returns the length of the JSON object at the start of p, or -1
+// when p doesn't start with a complete one.
+func objectEnd(p []byte) int {
+	if len(p) == 0 || p[0] != '{' {
+		return -1
+	}
+	depth, inString, escaped := 0, false, false
+	for i, c := range p {
+		switch {
+		case escaped:
+			escaped = false
+		case inString:
+			escaped = c == '\\'
+			inString = c != '"'
+		case c == '"':
+			inString = true
+		case c == '{' || c == '[':
+			depth++
+		case c == '}' || c == ']':
+			if depth--; depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return -1
+}
+
+func (writer *PublishWriter) topic(record []byte) string {
+	if writer.Route == nil {
+		return writer.Topic
+	}
+	entry, err := parseEntry(record, "")
+	if err != nil {
+		return writer.Topic
+	}
+	if topic := writer.Route(entry); topic != "" {
+		return topic
+	}
+	return writer.Topic
+}
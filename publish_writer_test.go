@@ -0,0 +1,100 @@
+package golog
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// publication is one message received by recordingPublisher.
+type publication struct {
+	topic string
+	data  string
+}
+
+type recordingPublisher struct {
+	published []publication
+	err       error
+}
+
+func (publisher *recordingPublisher) Publish(topic string, data []byte) error {
+	publisher.published = append(publisher.published, publication{topic: topic, data: string(data)})
+	return publisher.err
+}
+
+func TestPublishWriterRoutesRecords(t *testing.T) {
+	tests := []struct {
+		name   string
+		route  func(Entry) string
+		topics []string
+	}{
+		{name: "static", topics: []string{"logs", "logs", "logs"}},
+		{name: "per level", route: TopicPerLevel("logs."), topics: []string{"logs.info", "logs.error", "logs.info"}},
+		{name: "per field", route: TopicPerField("device", "devices."), topics: []string{"devices.sensor-7", "devices.42", "logs"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			publisher := &recordingPublisher{}
+			jl := NewJSONLoggerWithOptions(
+				WithOutput(&PublishWriter{Publisher: publisher, Topic: "logs", Route: tc.route}),
+				WithCustomTimeFormat("15:04:05"),
+			)
+
+			jl.Info("reading", Str("device", "sensor-7"))
+			jl.Error("offline", Int("device", 42))
+			jl.Info("no device")
+
+			if len(publisher.published) != len(tc.topics) {
+				t.Fatalf("expected %d messages, got %d", len(tc.topics), len(publisher.published))
+			}
+			for i, want := range tc.topics {
+				if got := publisher.published[i]; got.topic != want || got.data[len(got.data)-1] != '}' {
+					t.Fatalf("message %d: expected topic %q and no newline, got %+v", i, want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestPublishWriterSplitsBatches(t *testing.T) {
+	publisher := &recordingPublisher{err: errors.New("broker down")}
+	writer := &PublishWriter{Publisher: publisher, Topic: "logs"}
+
+	n, err := writer.Write([]byte("{\"a\":1}\n{\"b\":2}\n"))
+	if err == nil || n != 0 {
+		t.Fatalf("expected the publish error, got n=%d err=%v", n, err)
+	}
+	if len(publisher.published) != 2 || publisher.published[1].data != `{"b":2}` {
+		t.Fatalf("expected every record to be attempted, got %+v", publisher.published)
+	}
+}
+
+func TestPublishWriterKeepsMultiLineRecords(t *testing.T) {
+	publisher := &recordingPublisher{}
+	jl := NewJSONLoggerWithOptions(
+		WithOutput(&PublishWriter{Publisher: publisher, Topic: "logs", Route: TopicPerLevel("logs.")}),
+		WithPrettyJSON(),
+	)
+
+	jl.Warn("low disk", Str("path", "/var/{x}\"y\""), RawJSON("usage", []byte(`{"free":[1,2]}`)))
+	jl.Info("ok")
+
+	if len(publisher.published) != 2 {
+		t.Fatalf("expected one message per record, got %+v", publisher.published)
+	}
+	first := publisher.published[0]
+	if first.topic != "logs.warn" || !strings.Contains(first.data, "\n") || !json.Valid([]byte(first.data)) {
+		t.Fatalf("expected the whole pretty record in one message, got %+v", first)
+	}
+
+	publisher.published = nil
+	writer := &PublishWriter{Publisher: publisher, Topic: "logs"}
+	if _, err := writer.Write([]byte("INF started\nWRN {slow\n")); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if len(publisher.published) != 2 || publisher.published[1].data != "WRN {slow" {
+		t.Fatalf("expected other formats to be split into lines, got %+v", publisher.published)
+	}
+}
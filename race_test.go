@@ -0,0 +1,7 @@
+//go:build race
+
+package golog
+
+// raceEnabled reports whether the race detector is on. It makes sync.Pool
+// drop items at random, so allocation tests of pooled paths skip under it.
+const raceEnabled = true
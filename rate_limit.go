@@ -0,0 +1,128 @@
+package golog
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// SuppressedKey is the field carrying how many entries an Every logger
+// dropped since the previous one it wrote.
+const SuppressedKey = "suppressed"
+
+// rateLimitState tracks one Once/Every key.
+type rateLimitState struct {
+	mutex      sync.Mutex
+	emitted    bool
+	lastEmit   time.Time
+	suppressed int
+}
+
+// allow reports whether an entry may be written at now, and how many entries
+// were suppressed since the last one that was. A negative interval means the
+// key is written only once.
+func (state *rateLimitState) allow(now time.Time, interval time.Duration) (int, bool) {
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+
+	if state.emitted && (interval < 0 || now.Sub(state.lastEmit) < interval) {
+		state.suppressed++
+		return 0, false
+	}
+	suppressed := state.suppressed
+	state.emitted = true
+	state.lastEmit = now
+	state.suppressed = 0
+	return suppressed, true
+}
+
+// rateLimitedLogger writes through its parent at most once per interval for
+// its key.
+type rateLimitedLogger struct {
+	logger   *JSONLogger
+	state    *rateLimitState
+	interval time.Duration
+}
+
+// Once returns a Logger that writes only the first entry for key; later
+// entries with the same key are dropped for the lifetime of jsonLogger.
+//
+//	jl.Once("config-fallback").Warn("using default config")
+//
+// Keys are kept for the lifetime of the logger, so use a fixed set of keys
+// rather than values derived from request data.
+func (jsonLogger *JSONLogger) Once(key string) Logger {
+	return rateLimitedLogger{logger: jsonLogger, state: jsonLogger.rateLimitState(key), interval: -1}
+}
+
+// Every returns a Logger that writes at most one entry per interval for key.
+// The first entry after a quiet period carries a suppressed field counting
+// the entries dropped since the previous one, so reconnect storms turn into
+// periodic summaries:
+//
+//	jl.Every("db-reconnect", time.Minute).Error("reconnect failed", Str("error", err.Error()))
+//
+// As with Once, keys are kept for the lifetime of the logger. Entries
+// carrying ForceLog are always written, and neither Once nor Every counts
+// them.
+func (jsonLogger *JSONLogger) Every(key string, interval time.Duration) Logger {
+	if interval < 0 {
+		interval = 0
+	}
+	return rateLimitedLogger{logger: jsonLogger, state: jsonLogger.rateLimitState(key), interval: interval}
+}
+
+func (jsonLogger *JSONLogger) rateLimitState(key string) *rateLimitState {
+	if state, ok := jsonLogger.rateLimits.Load(key); ok {
+		return state.(*rateLimitState)
+	}
+	state, _ := jsonLogger.rateLimits.LoadOrStore(key, &rateLimitState{})
+	return state.(*rateLimitState)
+}
+
+func (limited rateLimitedLogger) Info(message string, fields ...Field) {
+	limited.log(InfoLevel, message, fields)
+}
+
+func (limited rateLimitedLogger) Warn(message string, fields ...Field) {
+	limited.log(WarnLevel, message, fields)
+}
+
+func (limited rateLimitedLogger) Error(message string, fields ...Field) {
+	limited.log(ErrorLevel, message, fields)
+}
+
+func (limited rateLimitedLogger) Debug(message string, fields ...Field) {
+	limited.log(DebugLevel, message, fields)
+}
+
+func (limited rateLimitedLogger) Enabled(logLevel Level) bool {
+	return limited.logger.Enabled(logLevel)
+}
+
+func (limited rateLimitedLogger) Flush() error {
+	return limited.logger.Flush()
+}
+
+func (limited rateLimitedLogger) log(logLevel Level, message string, fields []Field) {
+	jsonLogger := limited.logger
+	// ForceLog bypasses rate limiting as it does level filtering, without
+	// using up the key's slot.
+	if forced(fields) {
+		jsonLogger.logFields(logLevel, message, fields)
+		return
+	}
+	// Entries the level would drop must not use up the key's slot.
+	if Level(atomic.LoadInt32((*int32)(&jsonLogger.level))) > logLevel {
+		return
+	}
+
+	suppressed, ok := limited.state.allow(jsonLogger.clock(), limited.interval)
+	if !ok {
+		return
+	}
+	if suppressed > 0 {
+		fields = append(fields[:len(fields):len(fields)], Int(SuppressedKey, suppressed))
+	}
+	jsonLogger.logFields(logLevel, message, fields)
+}
@@ -0,0 +1,71 @@
+package golog
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestOnceWritesFirstEntryOnly(t *testing.T) {
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(WithOutput(buf))
+
+	jl.Once("fallback").Debug("below level")
+	for i := 0; i < 3; i++ {
+		jl.Once("fallback").Warn("using default config", Int("i", i))
+	}
+	jl.Once("other").Warn("different key")
+
+	entries := readEntries(t, buf.Bytes())
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	if field, _ := entries[0].Field("i"); field.Value() != int64(0) {
+		t.Fatalf("expected the first call to be written, got %v", field.Value())
+	}
+	if entries[1].Message != "different key" {
+		t.Fatalf("unexpected second entry: %+v", entries[1])
+	}
+}
+
+func TestEveryReportsSuppressedCount(t *testing.T) {
+	buf := &bytes.Buffer{}
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	jl := NewJSONLoggerWithOptions(WithOutput(buf), WithClock(func() time.Time { return now }))
+
+	for i := 0; i < 5; i++ {
+		jl.Every("reconnect", time.Minute).Error("reconnect failed")
+		now = now.Add(10 * time.Second)
+	}
+	now = now.Add(time.Minute)
+	jl.Every("reconnect", time.Minute).Error("reconnect failed")
+
+	entries := readEntries(t, buf.Bytes())
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	if _, ok := entries[0].Field(SuppressedKey); ok {
+		t.Fatalf("expected no suppressed count on the first entry")
+	}
+	if field, _ := entries[1].Field(SuppressedKey); field.Value() != int64(4) {
+		t.Fatalf("expected 4 suppressed entries, got %v", field.Value())
+	}
+}
+
+func TestRateLimitedLoggersWriteForcedEntries(t *testing.T) {
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(WithOutput(buf), WithLevel(WarnLevel))
+
+	jl.Once("refund").Info("refund issued", ForceLog())
+	jl.Once("refund").Info("refund issued", ForceLog())
+	jl.Every("refund", time.Hour).Debug("refund issued", ForceLog())
+	jl.Once("refund").Warn("first unforced")
+
+	entries := readEntries(t, buf.Bytes())
+	if len(entries) != 4 || entries[3].Message != "first unforced" {
+		t.Fatalf("expected forced entries to bypass rate limiting, got %d entries", len(entries))
+	}
+	if _, ok := entries[3].Field(SuppressedKey); ok {
+		t.Fatalf("expected forced entries not to count as suppressed")
+	}
+}
@@ -0,0 +1,54 @@
+package golog
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// RawJSON creates a Field whose value is already-encoded JSON, such as a
+// request body or protobuf-rendered JSON. The bytes are written without
+// escaping or re-encoding, only compacted so the entry stays on one line, and
+// are not copied: value must not be modified until the call returns. An empty
+// value is written as null and a malformed one as "<unsupported>".
+func RawJSON(key string, value []byte) Field {
+	return Field{key: key, anyVal: json.RawMessage(value), kind: fieldKindAny}
+}
+
+// RawJSONFrom creates a Field whose value is the JSON that source writes,
+// letting large documents stream straight into the entry buffer without an
+// intermediate copy. The output is handled like RawJSON; if WriteTo returns
+// an error the value is written as "<unsupported>". WriteTo runs once per
+// encoding of the entry (more than once under Multi), so source must produce
+// the same output each time.
+func RawJSONFrom(key string, source io.WriterTo) Field {
+	return Field{key: key, anyVal: rawJSONSource{source: source}, kind: fieldKindAny}
+}
+
+// rawJSONSource marks an io.WriterTo whose output is pre-encoded JSON, so the
+// encoders can tell it apart from values that merely implement io.WriterTo.
+type rawJSONSource struct {
+	source io.WriterTo
+}
+
+// appendWriter is an io.Writer appending to a byte slice.
+type appendWriter struct {
+	buf []byte
+}
+
+func (writer *appendWriter) Write(p []byte) (int, error) {
+	writer.buf = append(writer.buf, p...)
+	return len(p), nil
+}
+
+// appendTo appends the source's output to dst. It reports false when WriteTo
+// fails, leaving dst unchanged.
+func (raw rawJSONSource) appendTo(dst []byte) ([]byte, bool) {
+	if raw.source == nil {
+		return dst, false
+	}
+	writer := appendWriter{buf: dst}
+	if _, err := raw.source.WriteTo(&writer); err != nil {
+		return dst, false
+	}
+	return writer.buf, true
+}
@@ -0,0 +1,109 @@
+package golog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+// jsonSource writes a fixed document, or fails with err.
+type jsonSource struct {
+	document string
+	err      error
+}
+
+func (source jsonSource) WriteTo(w io.Writer) (int64, error) {
+	if source.err != nil {
+		return 0, source.err
+	}
+	n, err := io.WriteString(w, source.document)
+	return int64(n), err
+}
+
+func TestRawJSONFieldsAreWritten(t *testing.T) {
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(WithOutput(buf))
+
+	jl.Info("raw",
+		RawJSON("body", []byte(`{"user":"aé","tags":[1,2]}`)),
+		RawJSON("empty", nil),
+		RawJSONFrom("streamed", jsonSource{document: `[true,null]`}),
+		RawJSONFrom("failed", jsonSource{err: errors.New("boom")}),
+	)
+
+	line := buf.String()
+	for _, want := range []string{
+		`"body":{"user":"aé","tags":[1,2]}`,
+		`"empty":null`,
+		`"streamed":[true,null]`,
+		`"failed":"<unsupported>"`,
+	} {
+		if !strings.Contains(line, want) {
+			t.Fatalf("expected %s in %s", want, line)
+		}
+	}
+	if !json.Valid(bytes.TrimSpace(buf.Bytes())) {
+		t.Fatalf("entry is not valid JSON: %s", line)
+	}
+}
+
+func TestRawJSONRejectsMalformedInput(t *testing.T) {
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(WithOutput(buf))
+
+	jl.Info("raw",
+		RawJSON("bad", []byte(`{"a":`)),
+		RawJSON("injected", []byte(`1,"admin":true`)),
+		RawJSONFrom("also_bad", jsonSource{document: `[1,`}),
+	)
+
+	line := buf.String()
+	for _, want := range []string{`"bad":"<unsupported>"`, `"injected":"<unsupported>"`, `"also_bad":"<unsupported>"`} {
+		if !strings.Contains(line, want) {
+			t.Fatalf("expected malformed raw values to be replaced, got %s", line)
+		}
+	}
+	if strings.Contains(line, `"admin"`) {
+		t.Fatalf("expected no injected key, got %s", line)
+	}
+}
+
+func TestRawJSONIsCompacted(t *testing.T) {
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(WithOutput(buf))
+
+	jl.Info("raw",
+		RawJSON("body", []byte("{\n  \"a\": 1,\n  \"b\": \"x y\"\n}")),
+		RawJSONFrom("streamed", jsonSource{document: "[\n\t1,\n\t2\n]\n"}),
+	)
+
+	if lines := strings.Count(buf.String(), "\n"); lines != 1 {
+		t.Fatalf("expected a single line, got %d: %s", lines, buf.String())
+	}
+	line := buf.String()
+	if !strings.Contains(line, `"body":{"a":1,"b":"x y"}`) || !strings.Contains(line, `"streamed":[1,2]`) {
+		t.Fatalf("expected compacted raw values, got %s", line)
+	}
+}
+
+func TestRawJSONFromValueReturnsSource(t *testing.T) {
+	source := jsonSource{document: `{}`}
+	if got := RawJSONFrom("doc", source).Value(); got != source {
+		t.Fatalf("expected Value to return the source, got %#v", got)
+	}
+}
+
+func TestRawJSONBinaryFormat(t *testing.T) {
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(WithOutput(buf), WithBinaryFormat(BinaryCBOR))
+
+	jl.Info("raw", RawJSONFrom("doc", jsonSource{document: `{"a":1}`}))
+
+	records := decodeBinaryRecords(t, buf.Bytes(), decodeCBOR)
+	if records[0]["doc"] != `{"a":1}` {
+		t.Fatalf("expected raw JSON as a string, got %#v", records[0]["doc"])
+	}
+}
@@ -0,0 +1,186 @@
+package golog
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+var errEntryNotObject = errors.New("log line is not a JSON object")
+
+// ParseEntry decodes a single golog NDJSON line into an Entry. The
+// timestamp must use RFC 3339 (the default format) and the level must be one
+// ParseLevel understands, other than off, or the number of a Level from
+// DebugLevel to ErrorLevel. All other keys become Fields in the order they
+// appear: strings as Str, integral numbers as Int64 values, other numbers as
+// Float64, booleans as Bool, and null, objects and arrays as Any holding the
+// encoding/json representation.
+func ParseEntry(line []byte) (Entry, error) {
+	return parseEntry(line, time.RFC3339Nano)
+}
+
+// parseEntry is ParseEntry with a custom timestamp layout. An empty layout
+// leaves Entry.Time unset, for callers that only route on level and fields.
+func parseEntry(line []byte, timeFormat string) (Entry, error) {
+	var entry Entry
+
+	decoder := json.NewDecoder(bytes.NewReader(line))
+	decoder.UseNumber()
+
+	token, err := decoder.Token()
+	if err != nil {
+		return entry, err
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '{' {
+		return entry, errEntryNotObject
+	}
+
+	for decoder.More() {
+		token, err = decoder.Token()
+		if err != nil {
+			return entry, err
+		}
+		key := token.(string)
+
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			return entry, err
+		}
+
+		switch key {
+		case "timestamp":
+			if timeFormat == "" {
+				continue
+			}
+			var timestamp string
+			if err := json.Unmarshal(raw, &timestamp); err != nil {
+				return entry, fmt.Errorf("timestamp: %w", err)
+			}
+			if entry.Time, err = time.Parse(timeFormat, timestamp); err != nil {
+				return entry, fmt.Errorf("timestamp: %w", err)
+			}
+		case "level":
+			if err := json.Unmarshal(raw, &entry.Level); err != nil {
+				return entry, fmt.Errorf("level: %w", err)
+			}
+			if entry.Level < DebugLevel || entry.Level > ErrorLevel {
+				return entry, fmt.Errorf("level: no entries are written at %s", raw)
+			}
+		case "message":
+			if err := json.Unmarshal(raw, &entry.Message); err != nil {
+				return entry, fmt.Errorf("message: %w", err)
+			}
+		default:
+			field, err := parseField(key, raw)
+			if err != nil {
+				return entry, fmt.Errorf("field %q: %w", key, err)
+			}
+			entry.Fields = append(entry.Fields, field)
+		}
+	}
+
+	if _, err := decoder.Token(); err != nil {
+		return entry, err
+	}
+	return entry, nil
+}
+
+// parseField converts a raw JSON value into the closest typed Field.
+func parseField(key string, raw json.RawMessage) (Field, error) {
+	switch raw[0] {
+	case '"':
+		var value string
+		err := json.Unmarshal(raw, &value)
+		return Str(key, value), err
+	case 't', 'f':
+		var value bool
+		err := json.Unmarshal(raw, &value)
+		return Bool(key, value), err
+	case '{', '[', 'n':
+		var value any
+		err := json.Unmarshal(raw, &value)
+		return Any(key, value), err
+	default:
+		if integer, err := strconv.ParseInt(string(raw), 10, 64); err == nil {
+			return Field{key: key, intVal: integer, kind: fieldKindInt}, nil
+		}
+		value, err := strconv.ParseFloat(string(raw), 64)
+		return Float64(key, value), err
+	}
+}
+
+// Reader reads golog NDJSON output entry by entry:
+//
+//	reader := NewReader(file)
+//	for reader.Next() {
+//	    entry := reader.Entry()
+//	    ...
+//	}
+//	if err := reader.Err(); err != nil { ... }
+//
+// Blank lines are skipped. Reading stops at the first malformed line.
+type Reader struct {
+	// TimeFormat is the layout used to parse the timestamp field. Set it
+	// when the logger was configured with WithCustomTimeFormat. Defaults to
+	// time.RFC3339Nano.
+	TimeFormat string
+
+	scanner *bufio.Scanner
+	entry   Entry
+	line    int
+	err     error
+}
+
+// maxReaderLineSize caps the size of a single NDJSON line accepted by Reader.
+const maxReaderLineSize = 64 << 20
+
+// NewReader returns a Reader that decodes entries from r.
+func NewReader(r io.Reader) *Reader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxReaderLineSize)
+	return &Reader{scanner: scanner}
+}
+
+// Next advances to the next entry. It returns false at the end of the input
+// or on the first error, which is then reported by Err.
+func (reader *Reader) Next() bool {
+	if reader.err != nil {
+		return false
+	}
+	timeFormat := reader.TimeFormat
+	if timeFormat == "" {
+		timeFormat = time.RFC3339Nano
+	}
+
+	for reader.scanner.Scan() {
+		reader.line++
+		line := bytes.TrimSpace(reader.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		entry, err := parseEntry(line, timeFormat)
+		if err != nil {
+			reader.err = fmt.Errorf("line %d: %w", reader.line, err)
+			return false
+		}
+		reader.entry = entry
+		return true
+	}
+	reader.err = reader.scanner.Err()
+	return false
+}
+
+// Entry returns the entry decoded by the last successful call to Next.
+func (reader *Reader) Entry() Entry {
+	return reader.entry
+}
+
+// Err returns the first error encountered while reading, if any.
+func (reader *Reader) Err() error {
+	return reader.err
+}
@@ -0,0 +1,123 @@
+package golog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseEntryRoundTrip(t *testing.T) {
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(WithOutput(buf), WithLevel(DebugLevel), WithBaseField("service", "api"))
+
+	before := time.Now().UTC()
+	jl.Warn("disk low",
+		Str("path", "/var"),
+		Int("free", 42),
+		Float64("ratio", 0.5),
+		Bool("ok", false),
+		Any("tags", []any{"a", "b"}),
+		Any("missing", nil),
+	)
+
+	entry, err := ParseEntry(bytes.TrimSpace(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("ParseEntry failed: %v", err)
+	}
+	if entry.Level != WarnLevel || entry.Message != "disk low" {
+		t.Fatalf("unexpected core fields: %+v", entry)
+	}
+	if entry.Time.Before(before.Add(-time.Second)) || entry.Time.After(time.Now().Add(time.Second)) {
+		t.Fatalf("unexpected timestamp: %v", entry.Time)
+	}
+
+	fields := entry.FieldMap()
+	if fields["service"] != "api" || fields["path"] != "/var" || fields["free"] != int64(42) {
+		t.Fatalf("unexpected fields: %v", fields)
+	}
+	if fields["ratio"] != 0.5 || fields["ok"] != false || fields["missing"] != nil {
+		t.Fatalf("unexpected fields: %v", fields)
+	}
+	if tags, ok := fields["tags"].([]any); !ok || len(tags) != 2 || tags[1] != "b" {
+		t.Fatalf("unexpected tags: %#v", fields["tags"])
+	}
+	if got := entry.Fields[0].Key(); got != "service" {
+		t.Fatalf("expected fields in output order, first was %q", got)
+	}
+}
+
+func TestParseEntryErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+	}{
+		{name: "not json", line: "hello"},
+		{name: "array", line: `["a"]`},
+		{name: "bad timestamp", line: `{"timestamp":"yesterday","level":"info","message":"x"}`},
+		{name: "bad level", line: `{"level":"loud","message":"x"}`},
+		{name: "numeric level out of range", line: `{"level":7,"message":"x"}`},
+		{name: "negative level", line: `{"level":-1,"message":"x"}`},
+		{name: "off level", line: `{"level":"off","message":"x"}`},
+		{name: "truncated", line: `{"level":"info"`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := ParseEntry([]byte(tc.line)); err == nil {
+				t.Fatalf("expected error for %q", tc.line)
+			}
+		})
+	}
+}
+
+func TestEntryFieldLastWins(t *testing.T) {
+	entry := Entry{Fields: []Field{Str("k", "first"), Str("k", "second")}}
+
+	field, ok := entry.Field("k")
+	if !ok || field.Value() != "second" {
+		t.Fatalf("expected last field to win, got %v", field.Value())
+	}
+	if _, ok := entry.Field("absent"); ok {
+		t.Fatalf("expected missing field")
+	}
+}
+
+func TestReader(t *testing.T) {
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(WithOutput(buf), WithLevel(DebugLevel))
+	jl.Info("one")
+	buf.WriteString("\n")
+	jl.Error("two", Int("n", 2))
+
+	reader := NewReader(buf)
+	var messages []string
+	for reader.Next() {
+		messages = append(messages, reader.Entry().Message)
+	}
+	if err := reader.Err(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(messages, ",") != "one,two" {
+		t.Fatalf("unexpected messages: %v", messages)
+	}
+}
+
+func TestReaderCustomTimeFormatAndErrors(t *testing.T) {
+	input := `{"timestamp":"2024-01-02 03:04:05","level":"info","message":"ok"}` + "\n" + "garbage\n"
+
+	reader := NewReader(strings.NewReader(input))
+	reader.TimeFormat = time.DateTime
+	if !reader.Next() {
+		t.Fatalf("expected first entry, err: %v", reader.Err())
+	}
+	if want := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC); !reader.Entry().Time.Equal(want) {
+		t.Fatalf("unexpected time: %v", reader.Entry().Time)
+	}
+	if reader.Next() {
+		t.Fatalf("expected malformed line to stop the reader")
+	}
+	if err := reader.Err(); err == nil || !strings.HasPrefix(err.Error(), "line 2:") {
+		t.Fatalf("expected line-numbered error, got %v", err)
+	}
+}
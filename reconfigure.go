@@ -0,0 +1,142 @@
+package golog
+
+import (
+	"maps"
+	"math/rand/v2"
+	"runtime"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Reconfigure applies options to a running logger, for changing the output,
+// format, base fields or encoding policy without restarting:
+//
+//	jl.Reconfigure(WithOutput(newFile), WithBaseField("config_version", 7))
+//
+// The options run on a copy of the current configuration, which then
+// replaces it in one step: each entry is written with either the old or the
+// new configuration, never a mix, and logging calls don't wait for
+// Reconfigure or for each other's writes. When the output is replaced,
+// Reconfigure waits for the entries still being written to the previous
+// one, flushes it and returns its flush error; closing it is up to the
+// caller.
+//
+// Options that start background work or keep counts — WithAsync,
+// WithLockFreeOutput, WithWriteWatchdog, WithFlightRecorder,
+// WithHealthcheckInterval and WithLevelStats — and WithClock belong at
+// construction only. Reconfigure must not run concurrently with Shutdown.
+func (jsonLogger *JSONLogger) Reconfigure(options ...Option) error {
+	jsonLogger.configMutex.Lock()
+	defer jsonLogger.configMutex.Unlock()
+
+	previous := jsonLogger.current()
+	next := previous.clone()
+	for _, option := range options {
+		if option != nil {
+			option(next)
+		}
+	}
+	replaced := next.output != previous.output
+	if replaced {
+		// A stalled write to the previous output must not hold up the new
+		// one.
+		next.mutex = new(sync.Mutex)
+	}
+	jsonLogger.config.Store(next)
+	if !replaced {
+		return nil
+	}
+
+	previous.inflight.wait()
+	if flusher, ok := previous.output.(interface{ Flush() error }); ok {
+		return flusher.Flush()
+	}
+	return nil
+}
+
+// clone returns a copy of the configuration for Reconfigure to change.
+// Maps and slices are copied or clipped so options don't write to those of
+// the configuration in use.
+func (jsonLogger *JSONLogger) clone() *JSONLogger {
+	next := *jsonLogger
+	next.baseFields = maps.Clone(jsonLogger.baseFields)
+	next.contextExtractors = slices.Clip(next.contextExtractors)
+	next.middlewares = slices.Clip(next.middlewares)
+	next.errorHooks = slices.Clip(next.errorHooks)
+	next.sinks = slices.Clip(next.sinks)
+	next.labelKeys = slices.Clip(next.labelKeys)
+	next.encoder.scrubbers = slices.Clip(next.encoder.scrubbers)
+	if _, ok := next.writer.(jsonLogWriter); ok {
+		next.writer = jsonLogWriter{logger: &next}
+	}
+	// Encoding options change how the cached base fields look.
+	next.baseFieldsOnce = new(sync.Once)
+	next.inflight = new(inflightCount)
+	return &next
+}
+
+// current returns the configuration new entries are written with.
+func (jsonLogger *JSONLogger) current() *JSONLogger {
+	return jsonLogger.config.Load()
+}
+
+// acquire returns the current configuration, counted as in use until the
+// returned stripe is released so that a Reconfigure replacing its output
+// waits for the entry.
+func (jsonLogger *JSONLogger) acquire() (*JSONLogger, *inflightStripe) {
+	for {
+		config := jsonLogger.config.Load()
+		stripe := config.inflight.enter()
+		if jsonLogger.config.Load() == config {
+			return config, stripe
+		}
+		// Reconfigure replaced it meanwhile.
+		stripe.release()
+	}
+}
+
+// inflightStripes is the number of counters an inflightCount is spread over.
+const inflightStripes = 16
+
+// inflightCount counts the entries being written with a configuration. The
+// count is spread over stripes picked at random, so logging goroutines on
+// different cores mostly update different cache lines rather than one
+// shared lock word.
+type inflightCount struct {
+	stripes [inflightStripes]inflightStripe
+}
+
+// inflightStripe is one counter of an inflightCount, padded to a cache line.
+type inflightStripe struct {
+	count atomic.Int64
+	_     [56]byte
+}
+
+// enter counts an entry on a random stripe and returns it for release.
+func (inflight *inflightCount) enter() *inflightStripe {
+	stripe := &inflight.stripes[rand.Uint32()%inflightStripes]
+	stripe.count.Add(1)
+	return stripe
+}
+
+// release ends a use of the configuration begun by acquire.
+func (stripe *inflightStripe) release() {
+	stripe.count.Add(-1)
+}
+
+// wait returns once the entries being written with a configuration that is
+// no longer current have finished. A configuration that is still current
+// may never drain.
+func (inflight *inflightCount) wait() {
+	for i := range inflight.stripes {
+		for spins := 0; inflight.stripes[i].count.Load() != 0; spins++ {
+			if spins < 100 {
+				runtime.Gosched()
+			} else {
+				time.Sleep(100 * time.Microsecond)
+			}
+		}
+	}
+}
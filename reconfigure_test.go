@@ -0,0 +1,137 @@
+package golog
+
+import (
+	"bufio"
+	"bytes"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestReconfigure(t *testing.T) {
+	first := &lockedBuffer{}
+	second := &lockedBuffer{}
+	jl := NewJSONLoggerWithOptions(WithOutput(first), WithBaseField("version", 1))
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Go(func() {
+			for range 200 {
+				jl.Info("tick", Int("n", 1))
+			}
+		})
+	}
+	if err := jl.Reconfigure(WithOutput(second), WithBaseField("version", 2), WithFieldPrefix("app.")); err != nil {
+		t.Fatalf("reconfigure: %v", err)
+	}
+	wg.Wait()
+	jl.Info("after")
+
+	total := 0
+	for _, entry := range readEntries(t, first.Bytes()) {
+		fields := entry.FieldMap()
+		if fields["version"] != int64(1) || fields["n"] != int64(1) {
+			t.Fatalf("expected the old configuration before the switch: %v", fields)
+		}
+		total++
+	}
+	entries := readEntries(t, second.Bytes())
+	for _, entry := range entries {
+		fields := entry.FieldMap()
+		if fields["app.version"] != int64(2) {
+			t.Fatalf("expected the new configuration after the switch: %v", fields)
+		}
+		total++
+	}
+	if total != 801 || entries[len(entries)-1].Message != "after" {
+		t.Fatalf("expected every entry exactly once, got %d", total)
+	}
+}
+
+func TestReconfigureFlushesReplacedOutput(t *testing.T) {
+	buf := &bytes.Buffer{}
+	buffered := bufio.NewWriter(buf)
+	jl := NewJSONLoggerWithOptions(WithOutput(buffered))
+
+	jl.Info("buffered")
+	if err := jl.Reconfigure(WithOutput(&bytes.Buffer{})); err != nil {
+		t.Fatalf("reconfigure: %v", err)
+	}
+	if len(readEntries(t, buf.Bytes())) != 1 {
+		t.Fatalf("expected the previous output to be flushed: %q", buf.String())
+	}
+}
+
+func TestReconfigureDoesNotWaitForStalledWrites(t *testing.T) {
+	stalled := newGateWriter()
+	stalled.block()
+	jl := NewJSONLoggerWithOptions(WithOutput(stalled))
+	initial := jl.current()
+
+	go jl.Info("stuck")
+	<-stalled.entered
+	reconfigured := make(chan error, 1)
+	second := &lockedBuffer{}
+	go func() {
+		reconfigured <- jl.Reconfigure(WithOutput(second))
+	}()
+	for jl.current() == initial {
+		time.Sleep(time.Millisecond)
+	}
+
+	logged := make(chan struct{})
+	go func() {
+		defer close(logged)
+		jl.Info("after")
+	}()
+	select {
+	case <-logged:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected logging not to wait for the stalled write")
+	}
+	select {
+	case err := <-reconfigured:
+		t.Fatalf("expected Reconfigure to wait for the write to the previous output, got %v", err)
+	default:
+	}
+
+	stalled.release()
+	if err := <-reconfigured; err != nil {
+		t.Fatalf("reconfigure: %v", err)
+	}
+	if entries := readEntries(t, second.Bytes()); len(entries) != 1 || entries[0].Message != "after" {
+		t.Fatalf("expected the new output to get the entry: %q", second.Bytes())
+	}
+	if entries := readEntries(t, stalled.Bytes()); len(entries) != 1 || entries[0].Message != "stuck" {
+		t.Fatalf("expected the stalled entry to finish: %q", stalled.Bytes())
+	}
+}
+
+func TestReconfigureReachesSinksAndMiddleware(t *testing.T) {
+	sinkOutput := &bytes.Buffer{}
+	output := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(
+		WithOutput(&bytes.Buffer{}),
+		WithSink(NewWriterSink(sinkOutput, nil)),
+		WithMiddleware(func(next EntryFunc) EntryFunc {
+			return func(entry Entry) {
+				entry.Fields = append(entry.Fields, Str("stage", "middleware"))
+				next(entry)
+			}
+		}),
+	)
+
+	if err := jl.Reconfigure(WithOutput(output), WithFieldPrefix("app.")); err != nil {
+		t.Fatalf("reconfigure: %v", err)
+	}
+	jl.Info("routed")
+
+	entries := readEntries(t, output.Bytes())
+	if len(entries) != 1 || entries[0].FieldMap()["app.stage"] != "middleware" {
+		t.Fatalf("expected the middleware to write with the new configuration: %q", output.String())
+	}
+	entries = readEntries(t, sinkOutput.Bytes())
+	if len(entries) != 1 || entries[0].FieldMap()["app.stage"] != "middleware" {
+		t.Fatalf("expected the sink to format with the new configuration: %q", sinkOutput.String())
+	}
+}
@@ -0,0 +1,109 @@
+package golog
+
+import (
+	"errors"
+	"os"
+	"sync"
+)
+
+// Reopener is implemented by outputs and sinks that can reopen their
+// underlying file, so log rotation tools can move the file away and have
+// the logger continue in a fresh one.
+type Reopener interface {
+	Reopen() error
+}
+
+// Reopen reopens the logger's output and every sink that implements
+// Reopener; outputs wrapped by WithAsync, WithLockFreeOutput or
+// WithWriteWatchdog are reached through the wrapper. Others are left
+// alone. HandleSignals calls it on SIGHUP.
+func (jsonLogger *JSONLogger) Reopen() error {
+	config := jsonLogger.current()
+	err := reopenOutput(config.output)
+	for _, sink := range config.sinks {
+		err = errors.Join(err, reopenOutput(sink))
+	}
+	return err
+}
+
+func reopenOutput(w any) error {
+	if reopener, ok := w.(Reopener); ok {
+		return reopener.Reopen()
+	}
+	return nil
+}
+
+// Reopen reopens the wrapped output.
+func (writer *asyncWriter) Reopen() error {
+	return reopenOutput(writer.output)
+}
+
+// Reopen reopens the wrapped output.
+func (writer *lockFreeWriter) Reopen() error {
+	return reopenOutput(writer.output)
+}
+
+// ReopenableFile is an append-only log file that can be reopened by path
+// after an external tool such as logrotate renamed it. It is safe for
+// concurrent use.
+type ReopenableFile struct {
+	path  string
+	mutex sync.RWMutex
+	file  *os.File
+}
+
+// OpenReopenableFile opens path for appending, creating it if needed.
+//
+//	file, err := OpenReopenableFile("/var/log/app.log")
+//	jl := NewJSONLoggerWithOptions(WithOutput(file))
+//	stop := jl.HandleSignals() // SIGHUP reopens the file
+func OpenReopenableFile(path string) (*ReopenableFile, error) {
+	file, err := openLogFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return &ReopenableFile{path: path, file: file}, nil
+}
+
+func openLogFile(path string) (*os.File, error) {
+	return os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+}
+
+// Write appends p to the current file.
+func (file *ReopenableFile) Write(p []byte) (int, error) {
+	file.mutex.RLock()
+	defer file.mutex.RUnlock()
+	if file.file == nil {
+		return 0, ErrWriterClosed
+	}
+	return file.file.Write(p)
+}
+
+// Reopen opens the path again and switches writes to the new file once
+// in-flight writes finish. On error the old file stays in use.
+func (file *ReopenableFile) Reopen() error {
+	file.mutex.Lock()
+	defer file.mutex.Unlock()
+	if file.file == nil {
+		return ErrWriterClosed
+	}
+	next, err := openLogFile(file.path)
+	if err != nil {
+		return err
+	}
+	previous := file.file
+	file.file = next
+	return previous.Close()
+}
+
+// Close closes the current file. Later writes fail with ErrWriterClosed.
+func (file *ReopenableFile) Close() error {
+	file.mutex.Lock()
+	defer file.mutex.Unlock()
+	if file.file == nil {
+		return nil
+	}
+	err := file.file.Close()
+	file.file = nil
+	return err
+}
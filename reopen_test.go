@@ -0,0 +1,52 @@
+package golog
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReopenableFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	file, err := OpenReopenableFile(path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	jl := NewJSONLoggerWithOptions(WithOutput(file), WithAsync(AsyncConfig{}))
+
+	jl.Info("before rotation")
+	if err := jl.Flush(); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	rotated := filepath.Join(dir, "app.log.1")
+	if err := os.Rename(path, rotated); err != nil {
+		t.Fatalf("rename: %v", err)
+	}
+	if err := jl.Reopen(); err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	jl.Info("after rotation")
+	if err := jl.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	for name, want := range map[string]string{rotated: "before rotation", path: "after rotation"} {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatalf("read %s: %v", name, err)
+		}
+		entries := readEntries(t, data)
+		if len(entries) != 1 || entries[0].Message != want {
+			t.Fatalf("%s: unexpected entries %+v", name, entries)
+		}
+	}
+
+	if _, err := file.Write([]byte("late\n")); !errors.Is(err, ErrWriterClosed) {
+		t.Fatalf("expected ErrWriterClosed after Close, got %v", err)
+	}
+	if err := file.Reopen(); !errors.Is(err, ErrWriterClosed) {
+		t.Fatalf("expected Reopen after Close to fail, got %v", err)
+	}
+}
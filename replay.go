@@ -0,0 +1,125 @@
+package golog
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+)
+
+// ReplayOption configures Replay.
+type ReplayOption func(*replayer)
+
+// WithReplayRate limits Replay to perSecond entries per second, spaced
+// evenly, so a backfill doesn't overwhelm the sink's backend. Zero or a
+// negative rate, the default, replays as fast as the sink accepts entries.
+func WithReplayRate(perSecond float64) ReplayOption {
+	return func(replayer *replayer) {
+		if perSecond > 0 {
+			replayer.interval = time.Duration(float64(time.Second) / perSecond)
+		}
+	}
+}
+
+// WithReplayWindow replays only the entries timestamped in [from, to), such
+// as the span of a shipper outage. A zero from or to leaves that side open.
+func WithReplayWindow(from, to time.Time) ReplayOption {
+	return func(replayer *replayer) {
+		replayer.from = from
+		replayer.to = to
+	}
+}
+
+// WithReplayTimeFormat sets the timestamp layout of the input, for logs
+// written with WithCustomTimeFormat. Defaults to time.RFC3339Nano.
+func WithReplayTimeFormat(layout string) ReplayOption {
+	return func(replayer *replayer) {
+		replayer.timeFormat = layout
+	}
+}
+
+// WithReplayContext stops Replay when ctx is done, returning ctx.Err().
+func WithReplayContext(ctx context.Context) ReplayOption {
+	return func(replayer *replayer) {
+		replayer.ctx = ctx
+	}
+}
+
+type replayer struct {
+	ctx        context.Context
+	interval   time.Duration
+	from, to   time.Time
+	timeFormat string
+}
+
+// Replay reads golog NDJSON from r, as written to a file or stdout, and
+// writes every entry to sink with its original timestamp, level, message and
+// fields, then flushes the sink. It backfills a log backend after an outage
+// of the shipper:
+//
+//	file, _ := os.Open("/var/log/api.log")
+//	n, err := golog.Replay(file, lokiSink,
+//	    golog.WithReplayWindow(outageStart, outageEnd),
+//	    golog.WithReplayRate(200))
+//
+// Replay returns the number of entries written to sink. It stops at the
+// first malformed line or failed write and returns the error with the
+// entries written before it. Entries outside the window aren't counted, so
+// the count is not a line offset: to resume, replay again with a window
+// starting at the time of the last entry written, which writes that entry,
+// and any sharing its timestamp, again. The sink is left open for the
+// caller to close.
+func Replay(r io.Reader, sink Sink, options ...ReplayOption) (int, error) {
+	replayer := &replayer{ctx: context.Background()}
+	for _, option := range options {
+		option(replayer)
+	}
+
+	reader := NewReader(r)
+	reader.TimeFormat = replayer.timeFormat
+	written := 0
+	var next time.Time
+	for reader.Next() {
+		entry := reader.Entry()
+		if (!replayer.from.IsZero() && entry.Time.Before(replayer.from)) || (!replayer.to.IsZero() && !entry.Time.Before(replayer.to)) {
+			continue
+		}
+		if err := replayer.wait(&next); err != nil {
+			return written, err
+		}
+		if err := sink.Write(entry); err != nil {
+			return written, fmt.Errorf("replay entry %d: %w", written+1, err)
+		}
+		written++
+	}
+	if err := reader.Err(); err != nil {
+		return written, err
+	}
+	return written, sink.Flush()
+}
+
+// wait blocks until next, the time the next entry may be written under the
+// rate limit, and advances next by one interval.
+func (replayer *replayer) wait(next *time.Time) error {
+	if err := replayer.ctx.Err(); err != nil {
+		return err
+	}
+	if replayer.interval <= 0 {
+		return nil
+	}
+	now := time.Now()
+	if next.IsZero() || next.Before(now) {
+		*next = now
+	}
+	if delay := next.Sub(now); delay > 0 {
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-replayer.ctx.Done():
+			timer.Stop()
+			return replayer.ctx.Err()
+		}
+	}
+	*next = next.Add(replayer.interval)
+	return nil
+}
@@ -0,0 +1,123 @@
+package golog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// replayInput writes one entry per minute starting at base.
+func replayInput(t *testing.T, base time.Time, count int) *bytes.Buffer {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(WithOutput(buf), WithBaseField("service", "api"))
+	for i := range count {
+		jl.Warn("retrying", WithEntryTime(base.Add(time.Duration(i)*time.Minute)), Int("attempt", i))
+	}
+	buf.WriteString("\n")
+	return buf
+}
+
+func TestReplayPreservesEntries(t *testing.T) {
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	sink := &recordingSink{}
+
+	n, err := Replay(replayInput(t, base, 3), sink)
+	if err != nil || n != 3 || len(sink.entries) != 3 {
+		t.Fatalf("expected 3 entries replayed, got %d %v", n, err)
+	}
+	entry := sink.entries[2]
+	if !entry.Time.Equal(base.Add(2*time.Minute)) || entry.Level != WarnLevel || entry.Message != "retrying" {
+		t.Fatalf("expected the original timestamp, level and message: %+v", entry)
+	}
+	fields := entry.FieldMap()
+	if fields["service"] != "api" || fields["attempt"] != int64(2) {
+		t.Fatalf("unexpected fields: %v", fields)
+	}
+	if sink.flushes != 1 || sink.closed {
+		t.Fatalf("expected the sink to be flushed and left open: %+v", sink)
+	}
+}
+
+func TestReplayWindow(t *testing.T) {
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	sink := &recordingSink{}
+
+	n, err := Replay(replayInput(t, base, 5), sink, WithReplayWindow(base.Add(time.Minute), base.Add(3*time.Minute)))
+	if err != nil || n != 2 {
+		t.Fatalf("expected 2 entries in the window, got %d %v", n, err)
+	}
+	if sink.entries[0].FieldMap()["attempt"] != int64(1) || sink.entries[1].FieldMap()["attempt"] != int64(2) {
+		t.Fatalf("unexpected entries: %+v", sink.entries)
+	}
+
+	sink = &recordingSink{}
+	if n, err := Replay(replayInput(t, base, 5), sink, WithReplayWindow(base.Add(3*time.Minute), time.Time{})); err != nil || n != 2 {
+		t.Fatalf("expected an open end to replay the rest, got %d %v", n, err)
+	}
+}
+
+func TestReplayRate(t *testing.T) {
+	base := time.Now()
+	start := time.Now()
+	n, err := Replay(replayInput(t, base, 5), &recordingSink{}, WithReplayRate(100))
+	if err != nil || n != 5 {
+		t.Fatalf("replay: %d %v", n, err)
+	}
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Fatalf("expected 5 entries at 100/s to take 40ms, took %v", elapsed)
+	}
+}
+
+func TestReplayContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	n, err := Replay(replayInput(t, time.Now(), 3), &recordingSink{}, WithReplayContext(ctx), WithReplayRate(1))
+	if !errors.Is(err, context.Canceled) || n != 0 {
+		t.Fatalf("expected a canceled replay, got %d %v", n, err)
+	}
+}
+
+func TestReplayStopsAtErrors(t *testing.T) {
+	input := replayInput(t, time.Now(), 2)
+	input.WriteString("{not json\n")
+	sink := &recordingSink{}
+	n, err := Replay(input, sink)
+	if err == nil || !strings.Contains(err.Error(), "line 4") || n != 2 || sink.flushes != 0 {
+		t.Fatalf("expected a malformed line to stop the replay, got %d %v", n, err)
+	}
+
+	failing := &failingSink{err: errors.New("backend down")}
+	n, err = Replay(replayInput(t, time.Now(), 2), failing)
+	if !errors.Is(err, failing.err) || n != 0 {
+		t.Fatalf("expected the sink error, got %d %v", n, err)
+	}
+}
+
+func TestReplayTimeFormat(t *testing.T) {
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(WithOutput(buf), WithCustomTimeFormat(time.DateTime))
+	jl.Info("custom")
+
+	sink := &recordingSink{}
+	if _, err := Replay(bytes.NewReader(buf.Bytes()), sink); err == nil {
+		t.Fatal("expected the default layout to reject the timestamp")
+	}
+	if n, err := Replay(bytes.NewReader(buf.Bytes()), sink, WithReplayTimeFormat(time.DateTime)); err != nil || n != 1 {
+		t.Fatalf("expected the custom layout to parse, got %d %v", n, err)
+	}
+}
+
+// failingSink rejects every entry.
+type failingSink struct {
+	recordingSink
+	err error
+}
+
+func (sink *failingSink) Write(Entry) error {
+	return sink.err
+}
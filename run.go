@@ -0,0 +1,57 @@
+package golog
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+)
+
+// defaultShutdownTimeout bounds the shutdown Run performs once its context
+// is done.
+const defaultShutdownTimeout = 5 * time.Second
+
+// WithShutdownTimeout sets how long Run may spend flushing queued entries
+// and closing the output and sinks after its context is done. Defaults to
+// five seconds.
+func WithShutdownTimeout(timeout time.Duration) Option {
+	return func(jsonLogger *JSONLogger) {
+		jsonLogger.shutdownTimeout = timeout
+	}
+}
+
+// Run ties the logger's lifetime to ctx for services built around a shared
+// lifecycle such as errgroup:
+//
+//	group, ctx := errgroup.WithContext(ctx)
+//	group.Go(func() error { return jl.Run(ctx) })
+//	group.Go(func() error { return server.Serve(ctx) })
+//
+// Run blocks until ctx is done, then switches the logger to synchronous
+// writes to os.Stderr, so entries logged by other goroutines while they
+// wind down are not lost, and shuts down the original output and sinks as
+// Shutdown does, within the WithShutdownTimeout budget. It returns nil after
+// a clean shutdown, or an error reporting the dropped entries.
+func (jsonLogger *JSONLogger) Run(ctx context.Context) error {
+	<-ctx.Done()
+	jsonLogger.stopped.Store(true)
+
+	timeout := jsonLogger.shutdownTimeout
+	if timeout <= 0 {
+		timeout = defaultShutdownTimeout
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), timeout)
+	defer cancel()
+	dropped, err := jsonLogger.Shutdown(shutdownCtx)
+	if err != nil {
+		return fmt.Errorf("golog: shutdown dropped %d entries: %w", dropped, err)
+	}
+	return nil
+}
+
+// writeStopped writes a record logged after Run began shutting down.
+func (jsonLogger *JSONLogger) writeStopped(record []byte) {
+	if _, err := os.Stderr.Write(record); err != nil {
+		jsonLogger.writeErrors.record(err)
+	}
+}
@@ -0,0 +1,51 @@
+package golog
+
+import (
+	"context"
+	"io"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestRunShutsDownWhenContextEnds(t *testing.T) {
+	output := &closeRecorder{}
+	sink := &recordingSink{}
+	jl := NewJSONLoggerWithOptions(WithOutput(output), WithAsync(AsyncConfig{}), WithSink(sink))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	result := make(chan error, 1)
+	go func() { result <- jl.Run(ctx) }()
+
+	jl.Info("queued")
+	cancel()
+	select {
+	case err := <-result:
+		if err != nil {
+			t.Fatalf("run: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Run did not return after cancel")
+	}
+
+	entries := readEntries(t, output.Bytes())
+	if len(entries) != 1 || entries[0].Message != "queued" || !output.closed || !sink.closed {
+		t.Fatalf("expected queued entries flushed and the output and sinks closed: %+v", entries)
+	}
+
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("pipe: %v", err)
+	}
+	stderr := os.Stderr
+	os.Stderr = writer
+	jl.Warn("after shutdown")
+	os.Stderr = stderr
+	_ = writer.Close()
+	late, _ := io.ReadAll(reader)
+
+	entries = readEntries(t, late)
+	if len(entries) != 1 || entries[0].Message != "after shutdown" || len(sink.entries) != 1 {
+		t.Fatalf("expected late entries on stderr only: %q, sink=%d", late, len(sink.entries))
+	}
+}
@@ -0,0 +1,217 @@
+package golog
+
+import (
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// SchemaViolationKey is the key of the field describing schema violations.
+const SchemaViolationKey = "schema_violation"
+
+// JSONType is a set of JSON value types, combined with |.
+type JSONType uint8
+
+// JSON value types as they appear in the encoded entry. A value's type is
+// decided by its encoding, so a time.Duration is a JSONInteger or a
+// JSONString depending on WithDurationFormat, and a float with no fraction is
+// a JSONInteger.
+const (
+	JSONString JSONType = 1 << iota
+	JSONInteger
+	JSONFloat
+	JSONBool
+	JSONObject
+	JSONArray
+	JSONNull
+
+	// JSONNumber accepts integers and floats.
+	JSONNumber = JSONInteger | JSONFloat
+)
+
+var jsonTypeNames = []string{"string", "integer", "float", "bool", "object", "array", "null"}
+
+func (jsonType JSONType) String() string {
+	var names []string
+	for i, name := range jsonTypeNames {
+		if jsonType&(1<<i) != 0 {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, "|")
+}
+
+// SchemaAction selects what happens to entries that violate a Schema.
+type SchemaAction uint8
+
+const (
+	// SchemaFlag writes the entry unchanged plus a schema_violation field
+	// describing the problems.
+	SchemaFlag SchemaAction = iota
+	// SchemaFix converts mistyped fields to strings when the schema allows
+	// strings for the key and drops them otherwise. Missing required fields
+	// can't be fixed and are flagged.
+	SchemaFix
+	// SchemaReject drops the entry.
+	SchemaReject
+)
+
+// Schema is a log contract checked against every leveled entry. Required
+// keys may be satisfied by base fields; only per-call fields are type
+// checked. Event, Count, Gauge and Healthcheck entries have shapes of their
+// own and are not validated.
+type Schema struct {
+	// Required lists keys every entry must carry.
+	Required []string
+	// Types restricts the JSON types allowed for a key. Keys not listed
+	// accept any type.
+	Types map[string]JSONType
+	// Action selects what happens to violating entries. Defaults to
+	// SchemaFlag.
+	Action SchemaAction
+}
+
+// WithSchema validates entries against schema, keeping log contracts stable
+// across teams. Stats reports how many entries violated it and how many were
+// rejected.
+//
+//	jl := NewJSONLoggerWithOptions(WithSchema(Schema{
+//	    Required: []string{"service", "request_id"},
+//	    Types:    map[string]JSONType{"status": JSONInteger, "user": JSONString},
+//	}))
+func WithSchema(schema Schema) Option {
+	return func(jsonLogger *JSONLogger) {
+		validator := &schemaValidator{schema: schema}
+		validator.schema.Required = append([]string(nil), schema.Required...)
+		validator.schema.Types = make(map[string]JSONType, len(schema.Types))
+		for key, jsonType := range schema.Types {
+			validator.schema.Types[key] = jsonType
+		}
+		jsonLogger.schema = validator
+	}
+}
+
+// schemaValidator applies a Schema and counts its outcomes.
+type schemaValidator struct {
+	schema     Schema
+	violations atomic.Uint64
+	rejected   atomic.Uint64
+}
+
+// validate returns the fields to write and false when the entry must be
+// dropped. Valid entries are returned unchanged without allocating.
+func (validator *schemaValidator) validate(jsonLogger *JSONLogger, fields []Field) ([]Field, bool) {
+	jsonLogger.baseFieldsOnce.Do(jsonLogger.buildBaseFieldsCache)
+
+	var problems []string
+	for _, key := range validator.schema.Required {
+		if !hasFieldKey(fields, key) && !hasFieldKey(jsonLogger.baseFieldList, key) {
+			problems = append(problems, "missing required field "+strconv.Quote(key))
+		}
+	}
+
+	// fixed holds the corrected fields once SchemaFix changed one.
+	var fixed []Field
+	violated := len(problems) > 0
+	for i := range fields {
+		allowed, ok := validator.schema.Types[fields[i].key]
+		if !ok {
+			if fixed != nil {
+				fixed = append(fixed, fields[i])
+			}
+			continue
+		}
+		actual := jsonLogger.encoder.fieldJSONType(fields[i])
+		switch {
+		case actual&allowed != 0:
+			if fixed != nil {
+				fixed = append(fixed, fields[i])
+			}
+		case validator.schema.Action == SchemaFix:
+			violated = true
+			if fixed == nil {
+				fixed = append(make([]Field, 0, len(fields)+1), fields[:i]...)
+			}
+			if allowed&JSONString != 0 {
+				fixed = append(fixed, Str(fields[i].key, string(jsonLogger.encoder.appendFieldValue(nil, fields[i]))))
+			}
+		default:
+			problems = append(problems, "field "+strconv.Quote(fields[i].key)+" is "+actual.String()+", want "+allowed.String())
+		}
+	}
+	if fixed != nil {
+		fields = fixed
+	}
+
+	if !violated && len(problems) == 0 {
+		return fields, true
+	}
+	validator.violations.Add(1)
+	if validator.schema.Action == SchemaReject {
+		validator.rejected.Add(1)
+		return nil, false
+	}
+	if len(problems) > 0 {
+		if fixed == nil {
+			fields = append(make([]Field, 0, len(fields)+1), fields...)
+		}
+		fields = append(fields, Str(SchemaViolationKey, strings.Join(problems, "; ")))
+	}
+	return fields, true
+}
+
+// hasFieldKey reports whether fields contains key.
+func hasFieldKey(fields []Field, key string) bool {
+	for i := range fields {
+		if fields[i].key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldJSONType returns the JSON type field is encoded as. Only values
+// whose type depends on encoding policy or content are encoded.
+func (enc *encoder) fieldJSONType(field Field) JSONType {
+	switch field.kind {
+	case fieldKindStr:
+		return JSONString
+	case fieldKindInt, fieldKindUint:
+		return JSONInteger
+	case fieldKindBool:
+		return JSONBool
+	}
+	encoded := AcquireBuffer()
+	*encoded = enc.appendFieldValue(*encoded, field)
+	jsonType := jsonTypeOf(*encoded)
+	ReleaseBuffer(encoded)
+	return jsonType
+}
+
+// jsonTypeOf classifies an encoded JSON value.
+func jsonTypeOf(encoded []byte) JSONType {
+	if len(encoded) == 0 {
+		return JSONNull
+	}
+	switch encoded[0] {
+	case '"':
+		return JSONString
+	case '{':
+		return JSONObject
+	case '[':
+		return JSONArray
+	case 't', 'f':
+		return JSONBool
+	case 'n':
+		return JSONNull
+	}
+	for _, c := range encoded {
+		if c == '.' || c == 'e' || c == 'E' {
+			return JSONFloat
+		}
+	}
+	return JSONInteger
+}
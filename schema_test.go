@@ -0,0 +1,117 @@
+package golog
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestSchemaFlagsViolations(t *testing.T) {
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(
+		WithOutput(buf),
+		WithBaseField("service", "api"),
+		WithSchema(Schema{
+			Required: []string{"service", "request_id"},
+			Types:    map[string]JSONType{"status": JSONInteger, "user": JSONString | JSONNull},
+		}),
+	)
+
+	jl.Info("ok", Str("request_id", "r1"), Int("status", 200), Any("user", nil))
+	jl.Info("bad", Str("status", "200"), Int("user", 7))
+
+	entries := readEntries(t, buf.Bytes())
+	if len(entries) != 2 {
+		t.Fatalf("expected two entries, got %d", len(entries))
+	}
+	if _, ok := entries[0].Field(SchemaViolationKey); ok {
+		t.Fatalf("expected a valid entry to be untouched: %v", entries[0].Fields)
+	}
+	got := entries[1].FieldMap()
+	want := `missing required field "request_id"; field "status" is string, want integer; field "user" is integer, want string|null`
+	if got[SchemaViolationKey] != want {
+		t.Fatalf("unexpected violation:\n got %v\nwant %v", got[SchemaViolationKey], want)
+	}
+	if got["status"] != "200" {
+		t.Fatalf("expected flagged fields to be kept: %v", got)
+	}
+	if stats := jl.Stats(); stats.SchemaViolations != 1 || stats.RejectedEntries != 0 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestSchemaFixesFields(t *testing.T) {
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(
+		WithOutput(buf),
+		WithDurationFormat(DurationString),
+		WithSchema(Schema{
+			Required: []string{"request_id"},
+			Types: map[string]JSONType{
+				"user":    JSONString,
+				"status":  JSONInteger,
+				"elapsed": JSONString,
+			},
+			Action: SchemaFix,
+		}),
+	)
+
+	fields := []Field{Int("user", 42), Str("status", "ok"), Duration("elapsed", time.Second), Str("request_id", "r1")}
+	jl.Info("fixed", fields...)
+	jl.Info("missing")
+
+	if fields[0].Key() != "user" || fields[1].Key() != "status" {
+		t.Fatalf("expected the caller's fields to be left alone: %v", fields)
+	}
+	entries := readEntries(t, buf.Bytes())
+	got := entries[0].FieldMap()
+	if got["user"] != "42" || got["elapsed"] != "1s" || got["request_id"] != "r1" {
+		t.Fatalf("unexpected fixed fields: %v", got)
+	}
+	if _, ok := got["status"]; ok {
+		t.Fatalf("expected a field that can't be converted to be dropped: %v", got)
+	}
+	if _, ok := got[SchemaViolationKey]; ok {
+		t.Fatalf("expected fixed entries not to be flagged: %v", got)
+	}
+	if entries[1].FieldMap()[SchemaViolationKey] != `missing required field "request_id"` {
+		t.Fatalf("expected missing fields to be flagged: %v", entries[1].Fields)
+	}
+	if stats := jl.Stats(); stats.SchemaViolations != 2 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestSchemaRejectsEntries(t *testing.T) {
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(
+		WithOutput(buf),
+		WithSchema(Schema{Types: map[string]JSONType{"ratio": JSONFloat}, Action: SchemaReject}),
+	)
+
+	jl.Info("kept", Float64("ratio", 0.5))
+	jl.Info("dropped", Float64("ratio", 2))
+	jl.Info("dropped", Any("ratio", []int{1}))
+
+	entries := readEntries(t, buf.Bytes())
+	if len(entries) != 1 || entries[0].Message != "kept" {
+		t.Fatalf("expected only the valid entry: %v", entries)
+	}
+	if stats := jl.Stats(); stats.SchemaViolations != 2 || stats.RejectedEntries != 2 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestSchemaValidEntryDoesNotAllocate(t *testing.T) {
+	jl := NewJSONLoggerWithOptions(
+		WithOutput(io.Discard),
+		WithSchema(Schema{Required: []string{"status"}, Types: map[string]JSONType{"status": JSONInteger}}),
+	)
+	allocs := testing.AllocsPerRun(100, func() {
+		jl.Info("ok", Int("status", 200))
+	})
+	if allocs != 0 {
+		t.Fatalf("expected no allocations, got %v", allocs)
+	}
+}
@@ -0,0 +1,60 @@
+package golog
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// fieldScope is one level of fields pushed with PushScope. Scopes chain to
+// the scope that was active in the parent context.
+type fieldScope struct {
+	parent *fieldScope
+	fields []Field
+	popped atomic.Bool
+}
+
+type scopeContextKey struct{}
+
+// PushScope attaches fields to every entry logged through the ctx-aware
+// methods (InfoContext and co.) with the returned context or contexts
+// derived from it, until the returned pop function is called:
+//
+//	ctx, pop := PushScope(ctx, Str("job_id", job.ID))
+//	defer pop()
+//	process(ctx, job) // every jl.InfoContext(ctx, …) below carries job_id
+//
+// Go has no goroutine-local storage, so the scope travels in ctx like any
+// other request-scoped value. Scopes nest, outer fields first; they are
+// written after the context extractors' fields and before the per-call
+// fields. Calling pop more than once is harmless.
+func PushScope(ctx context.Context, fields ...Field) (context.Context, func()) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	scope := &fieldScope{
+		parent: scopeFrom(ctx),
+		fields: append([]Field(nil), fields...),
+	}
+	return context.WithValue(ctx, scopeContextKey{}, scope), func() {
+		scope.popped.Store(true)
+	}
+}
+
+// scopeFrom returns the innermost scope stored in ctx, or nil.
+func scopeFrom(ctx context.Context) *fieldScope {
+	scope, _ := ctx.Value(scopeContextKey{}).(*fieldScope)
+	return scope
+}
+
+// appendScopeFields appends the fields of scope and its live parents to
+// fields, outermost scope first.
+func appendScopeFields(fields []Field, scope *fieldScope) []Field {
+	if scope == nil {
+		return fields
+	}
+	fields = appendScopeFields(fields, scope.parent)
+	if scope.popped.Load() {
+		return fields
+	}
+	return append(fields, scope.fields...)
+}
@@ -0,0 +1,55 @@
+package golog
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func TestPushScope(t *testing.T) {
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(WithOutput(buf))
+
+	ctx, popJob := PushScope(context.Background(), Str("job_id", "j1"))
+	inner, popStep := PushScope(ctx, Str("step", "fetch"))
+
+	jl.InfoContext(inner, "both", Int("attempt", 1))
+	popStep()
+	jl.InfoContext(inner, "outer only")
+	popJob()
+	popJob()
+	jl.InfoContext(inner, "none")
+	jl.Info("plain")
+
+	entries := readEntries(t, buf.Bytes())
+	if len(entries) != 4 {
+		t.Fatalf("expected four entries, got %d", len(entries))
+	}
+	wantKeys := [][]string{{"job_id", "step", "attempt"}, {"job_id"}, nil, nil}
+	for i, want := range wantKeys {
+		if len(entries[i].Fields) != len(want) {
+			t.Fatalf("entry %d: unexpected fields %v", i, entries[i].Fields)
+		}
+		for j, key := range want {
+			if entries[i].Fields[j].Key() != key {
+				t.Fatalf("entry %d: expected %s at %d, got %v", i, key, j, entries[i].Fields)
+			}
+		}
+	}
+}
+
+func TestPushScopeAfterExtractors(t *testing.T) {
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(WithOutput(buf), WithContextExtractor(func(ctx context.Context, fields []Field) []Field {
+		return append(fields, Str("trace_id", "t1"))
+	}))
+
+	ctx, pop := PushScope(context.Background(), Str("tenant", "acme"))
+	defer pop()
+	jl.WarnContext(ctx, "scoped")
+
+	fields := readEntries(t, buf.Bytes())[0].Fields
+	if len(fields) != 2 || fields[0].Key() != "trace_id" || fields[1].Key() != "tenant" {
+		t.Fatalf("unexpected field order: %v", fields)
+	}
+}
@@ -0,0 +1,147 @@
+package golog
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Scrubber masks sensitive data, such as emails or card numbers, inside
+// string values. Scrub returns value unchanged when there is nothing to
+// mask. Scrubbers run on every string value of an entry, in every output
+// format, so they should reject non-matching values cheaply.
+type Scrubber interface {
+	Scrub(value string) string
+}
+
+// ScrubberFunc adapts a function to the Scrubber interface.
+type ScrubberFunc func(value string) string
+
+// Scrub implements Scrubber.
+func (scrub ScrubberFunc) Scrub(value string) string {
+	return scrub(value)
+}
+
+// WithScrubbers masks string values with the given scrubbers, applied in
+// order. Unlike key-based redaction it catches sensitive data wherever it
+// appears, including free-form messages inside fields; the message itself
+// and keys are not scrubbed. Loggers without scrubbers pay nothing.
+//
+//	jl := NewJSONLoggerWithOptions(WithScrubbers(ScrubEmails(), ScrubCreditCards(), ScrubBearerTokens()))
+func WithScrubbers(scrubbers ...Scrubber) Option {
+	return func(jsonLogger *JSONLogger) {
+		jsonLogger.encoder.scrubbers = append(jsonLogger.encoder.scrubbers, scrubbers...)
+	}
+}
+
+// scrub applies the encoder's scrubbers to value.
+func (enc *encoder) scrub(value string) string {
+	for _, scrubber := range enc.scrubbers {
+		value = scrubber.Scrub(value)
+	}
+	return value
+}
+
+// RegexpScrubber replaces every match of Pattern with Replacement, which may
+// reference submatches as in regexp.Regexp.ReplaceAllString. When Contains
+// is set, values that don't contain it are skipped without running the
+// pattern.
+type RegexpScrubber struct {
+	Pattern     *regexp.Regexp
+	Replacement string
+	Contains    string
+}
+
+// Scrub implements Scrubber.
+func (scrubber *RegexpScrubber) Scrub(value string) string {
+	if scrubber.Contains != "" && !strings.Contains(value, scrubber.Contains) {
+		return value
+	}
+	return scrubber.Pattern.ReplaceAllString(value, scrubber.Replacement)
+}
+
+var (
+	emailPattern       = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	cardNumberPattern  = regexp.MustCompile(`\b(?:\d[ \-]?){12,18}\d\b`)
+	bearerTokenPattern = regexp.MustCompile(`(?i)(bearer\s+)[A-Za-z0-9\-._~+/]+=*`)
+)
+
+// ScrubEmails replaces email addresses with "<email>".
+func ScrubEmails() Scrubber {
+	return &RegexpScrubber{Pattern: emailPattern, Replacement: "<email>", Contains: "@"}
+}
+
+// ScrubBearerTokens replaces the token of "Bearer <token>" credentials,
+// keeping the scheme: "Bearer <redacted>".
+func ScrubBearerTokens() Scrubber {
+	return ScrubberFunc(func(value string) string {
+		if !containsFold(value, "bearer") {
+			return value
+		}
+		return bearerTokenPattern.ReplaceAllString(value, "${1}<redacted>")
+	})
+}
+
+// ScrubCreditCards replaces card numbers of 13 to 19 digits, optionally
+// grouped with spaces or dashes, that pass the Luhn checksum with
+// "<card>". The checksum keeps order IDs and timestamps intact.
+func ScrubCreditCards() Scrubber {
+	return ScrubberFunc(func(value string) string {
+		if !hasDigits(value, 13) {
+			return value
+		}
+		return cardNumberPattern.ReplaceAllStringFunc(value, func(match string) string {
+			if luhnValid(match) {
+				return "<card>"
+			}
+			return match
+		})
+	})
+}
+
+// containsFold reports whether value contains the lowercase ASCII word,
+// ignoring case, without allocating.
+func containsFold(value, word string) bool {
+	for i := 0; i+len(word) <= len(value); i++ {
+		if strings.EqualFold(value[i:i+len(word)], word) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasDigits reports whether value contains at least n digits, a cheap
+// pre-check before running the card pattern.
+func hasDigits(value string, n int) bool {
+	digits := 0
+	for i := 0; i < len(value); i++ {
+		if value[i] >= '0' && value[i] <= '9' {
+			digits++
+			if digits >= n {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// luhnValid checks the Luhn checksum of the digits in number, ignoring
+// separators.
+func luhnValid(number string) bool {
+	sum, double := 0, false
+	for i := len(number) - 1; i >= 0; i-- {
+		c := number[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		digit := int(c - '0')
+		if double {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+		double = !double
+	}
+	return sum%10 == 0
+}
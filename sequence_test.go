@@ -0,0 +1,102 @@
+package golog
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestSequenceNumbers(t *testing.T) {
+	buf := &bytes.Buffer{}
+	sink := &recordingSink{}
+	jl := NewJSONLoggerWithOptions(
+		WithOutput(buf),
+		WithSequenceNumbers(),
+		WithSink(sink),
+		WithBaseField("app", "api"),
+	)
+
+	jl.Info("first")
+	jl.Debug("filtered")
+	jl.Event("signup", map[string]any{"user": "u-1"})
+	jl.Warn("third")
+
+	if !strings.Contains(buf.String(), `"message":"first","seq":1,"app":"api"}`) {
+		t.Fatalf("expected seq right after the core keys: %s", buf.String())
+	}
+	entries := readEntries(t, buf.Bytes())
+	if len(entries) != 3 {
+		t.Fatalf("expected three entries, got %+v", entries)
+	}
+	for i, entry := range entries {
+		field, ok := entry.Field(SequenceKey)
+		if !ok || field.Value() != int64(i+1) {
+			t.Fatalf("expected seq %d on %q, got %+v", i+1, entry.Message, entry.Fields)
+		}
+	}
+	if len(sink.entries) != 3 {
+		t.Fatalf("expected three sink entries, got %+v", sink.entries)
+	}
+	for i, entry := range sink.entries {
+		field, ok := entry.Field(SequenceKey)
+		if !ok || field.Value() != uint64(i+1) {
+			t.Fatalf("expected the sink to see seq %d, got %+v", i+1, entry.Fields)
+		}
+	}
+}
+
+func TestSequenceNumbersWithCustomWriter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(WithOutput(buf), WithSequenceNumbers(), WithDevFormat())
+
+	jl.Info("one")
+	jl.Info("two")
+
+	if lines := strings.Split(strings.TrimSpace(buf.String()), "\n"); len(lines) != 2 ||
+		!strings.Contains(lines[0], "seq=1") || !strings.Contains(lines[1], "seq=2") {
+		t.Fatalf("expected seq on every line, got %q", buf.String())
+	}
+}
+
+func TestSequenceNumbersFollowOutputOrderUnderConcurrency(t *testing.T) {
+	buf := &lockedBuffer{}
+	jl := NewJSONLoggerWithOptions(WithOutput(buf), WithSequenceNumbers())
+
+	const workers, perWorker = 8, 200
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range perWorker {
+				jl.Info("tick")
+			}
+		}()
+	}
+	wg.Wait()
+
+	entries := readEntries(t, buf.Bytes())
+	if len(entries) != workers*perWorker {
+		t.Fatalf("expected %d entries, got %d", workers*perWorker, len(entries))
+	}
+	for i, entry := range entries {
+		// Entries must reach the output in seq order, or consumers would
+		// report reordering that never happened.
+		if field, _ := entry.Field(SequenceKey); field.Value() != int64(i+1) {
+			t.Fatalf("expected seq %d at line %d, got %v", i+1, i+1, field.Value())
+		}
+	}
+}
+
+func TestSequenceNumbersDoNotAllocate(t *testing.T) {
+	jl := NewJSONLoggerWithOptions(WithOutput(io.Discard), WithSequenceNumbers())
+
+	allocs := testing.AllocsPerRun(100, func() {
+		jl.Info("counted", Int("n", 1))
+	})
+	if allocs != 0 {
+		t.Fatalf("expected zero allocations with sequence numbers, got %v", allocs)
+	}
+}
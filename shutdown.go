@@ -0,0 +1,128 @@
+package golog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync/atomic"
+)
+
+// ContextWriter is implemented by outputs that can abandon a write, such as
+// HTTP or network shippers. The async writer passes each batch through
+// WriteContext with a deadline from AsyncConfig.BatchTimeout, and Shutdown
+// passes its own context, so a dead collector can't hang the process.
+type ContextWriter interface {
+	io.Writer
+	WriteContext(ctx context.Context, p []byte) (int, error)
+}
+
+// Shutdown stops the logger like Close, but gives up when ctx is done, so
+// graceful termination can't hang on an unreachable destination. With
+// WithAsync it writes whatever is queued before the deadline and reports how
+// many entries were dropped: failed batches plus entries still queued or in
+// flight when ctx ended. Its sinks are then closed under the same ctx; the
+// error says how many of them were abandoned still closing. Without
+// WithAsync nothing is queued and dropped is always zero. Writers wrapping
+// the async one, such as WithWriteWatchdog or WithCompressedOutput passed
+// after WithAsync, are closed first, so what they hold is queued too.
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+//	defer cancel()
+//	if dropped, err := jl.Shutdown(ctx); err != nil {
+//	    fmt.Fprintf(os.Stderr, "log shutdown: %d entries dropped: %v\n", dropped, err)
+//	}
+func (jsonLogger *JSONLogger) Shutdown(ctx context.Context) (dropped int, err error) {
+	config := jsonLogger.current()
+	if async := findAsync(config.output); async != nil {
+		jsonLogger.stopHealthchecks()
+		jsonLogger.stopLevelSummaries()
+		err = closeWrappers(ctx, config.output)
+		dropped, shutdownErr := async.shutdown(ctx)
+		return dropped, errors.Join(err, shutdownErr, closeSinks(ctx, config.sinks))
+	}
+
+	result := make(chan error, 1)
+	go func() {
+		result <- jsonLogger.Close()
+	}()
+	select {
+	case err := <-result:
+		return 0, err
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
+
+// outputWrapper is implemented by the writers options wrap the output in,
+// so Shutdown can reach an async writer beneath them.
+type outputWrapper interface {
+	io.Writer
+	// Unwrap returns the wrapped writer.
+	Unwrap() io.Writer
+	// closeWrapper writes out what the wrapper holds and stops it, leaving
+	// the wrapped writer open.
+	closeWrapper() error
+}
+
+// findAsync returns the async writer w is or wraps, or nil.
+func findAsync(w io.Writer) *asyncWriter {
+	for {
+		switch typed := w.(type) {
+		case *asyncWriter:
+			return typed
+		case outputWrapper:
+			w = typed.Unwrap()
+		default:
+			return nil
+		}
+	}
+}
+
+// closeWrappers closes the wrappers from w down to the async writer,
+// giving up when ctx is done; the async writer's shutdown then reports
+// ctx's error.
+func closeWrappers(ctx context.Context, w io.Writer) error {
+	result := make(chan error, 1)
+	go func() {
+		var err error
+		for wrapper, ok := w.(outputWrapper); ok; wrapper, ok = wrapper.Unwrap().(outputWrapper) {
+			if closeErr := wrapper.closeWrapper(); err == nil {
+				err = closeErr
+			}
+		}
+		result <- err
+	}()
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return nil
+	}
+}
+
+// closeSinks closes sinks in order, giving up when ctx is done. The sinks
+// still open then keep closing in the background.
+func closeSinks(ctx context.Context, sinks []Sink) error {
+	if len(sinks) == 0 {
+		return nil
+	}
+	var closed atomic.Int64
+	result := make(chan error, 1)
+	go func() {
+		var err error
+		for _, sink := range sinks {
+			if closeErr := sink.Close(); err == nil {
+				err = closeErr
+			}
+			closed.Add(1)
+		}
+		result <- err
+	}()
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("%d of %d sinks abandoned while closing: %w", len(sinks)-int(closed.Load()), len(sinks), ctx.Err())
+	}
+}
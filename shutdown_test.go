@@ -0,0 +1,193 @@
+package golog
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+// deadCollector accepts a connection but never answers, like a hung
+// network endpoint.
+type deadCollector struct {
+	countingWriter
+}
+
+func (collector *deadCollector) WriteContext(ctx context.Context, p []byte) (int, error) {
+	<-ctx.Done()
+	return 0, ctx.Err()
+}
+
+// blockingWriter blocks every Write until release is closed.
+type blockingWriter struct {
+	release chan struct{}
+}
+
+func (writer *blockingWriter) Write(p []byte) (int, error) {
+	<-writer.release
+	return len(p), nil
+}
+
+func TestAsyncBatchTimeoutDropsBatch(t *testing.T) {
+	output := &deadCollector{}
+	jl := NewJSONLoggerWithOptions(WithOutput(output), WithAsync(AsyncConfig{
+		MaxLatency:   time.Hour,
+		BatchTimeout: 10 * time.Millisecond,
+	}))
+
+	for i := 0; i < 5; i++ {
+		jl.Info("lost", Int("i", i))
+	}
+	if err := jl.Flush(); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the batch to time out, got %v", err)
+	}
+	if stats := jl.Stats(); stats.DroppedEntries != 5 || stats.BatchedEntries != 0 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+	if _, data := output.snapshot(); len(data) != 0 {
+		t.Fatalf("expected nothing written through Write, got %q", data)
+	}
+}
+
+func TestShutdownWritesQueuedEntries(t *testing.T) {
+	output := &countingWriter{}
+	jl := NewJSONLoggerWithOptions(WithOutput(output), WithAsync(AsyncConfig{MaxLatency: time.Hour}))
+
+	for i := 0; i < 10; i++ {
+		jl.Info("queued", Int("i", i))
+	}
+	dropped, err := jl.Shutdown(context.Background())
+	if err != nil || dropped != 0 {
+		t.Fatalf("expected a clean shutdown, got dropped=%d err=%v", dropped, err)
+	}
+	if _, data := output.snapshot(); len(readEntries(t, data)) != 10 {
+		t.Fatalf("expected 10 entries written, got %q", data)
+	}
+	if dropped, err := jl.Shutdown(context.Background()); dropped != 0 || err != nil {
+		t.Fatalf("expected a second shutdown to be a no-op, got dropped=%d err=%v", dropped, err)
+	}
+}
+
+func TestShutdownGivesUpAtDeadline(t *testing.T) {
+	output := &blockingWriter{release: make(chan struct{})}
+	defer close(output.release)
+	jl := NewJSONLoggerWithOptions(WithOutput(output), WithAsync(AsyncConfig{MaxLatency: time.Hour}))
+
+	for i := 0; i < 3; i++ {
+		jl.Info("stuck", Int("i", i))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	started := time.Now()
+	dropped, err := jl.Shutdown(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the deadline error, got %v", err)
+	}
+	if dropped != 3 {
+		t.Fatalf("expected 3 dropped entries, got %d", dropped)
+	}
+	if elapsed := time.Since(started); elapsed > time.Second {
+		t.Fatalf("shutdown ignored the deadline, took %v", elapsed)
+	}
+}
+
+func TestShutdownReachesWrappedAsyncWriter(t *testing.T) {
+	output := &countingWriter{}
+	jl := NewJSONLoggerWithOptions(
+		WithOutput(output),
+		WithAsync(AsyncConfig{MaxLatency: time.Hour}),
+		WithCompressedOutput(GzipCodec),
+		WithWriteWatchdog(WatchdogConfig{Notify: NewWriterSink(io.Discard, nil)}),
+	)
+
+	for i := 0; i < 10; i++ {
+		jl.Info("queued", Int("i", i))
+	}
+	dropped, err := jl.Shutdown(context.Background())
+	if err != nil || dropped != 0 {
+		t.Fatalf("expected a clean shutdown, got dropped=%d err=%v", dropped, err)
+	}
+	_, data := output.snapshot()
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("gzip reader: %v", err)
+	}
+	decompressed, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("expected a complete gzip stream: %v", err)
+	}
+	if entries := readEntries(t, decompressed); len(entries) != 10 {
+		t.Fatalf("expected 10 entries written, got %d", len(entries))
+	}
+}
+
+func TestShutdownReportsDropsBeneathWrappers(t *testing.T) {
+	output := &blockingWriter{release: make(chan struct{})}
+	defer close(output.release)
+	jl := NewJSONLoggerWithOptions(
+		WithOutput(output),
+		WithAsync(AsyncConfig{MaxLatency: time.Hour}),
+		WithWriteWatchdog(WatchdogConfig{Notify: NewWriterSink(io.Discard, nil)}),
+	)
+
+	for i := 0; i < 3; i++ {
+		jl.Info("stuck", Int("i", i))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	dropped, err := jl.Shutdown(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) || dropped != 3 {
+		t.Fatalf("expected 3 dropped entries and the deadline error, got dropped=%d err=%v", dropped, err)
+	}
+}
+
+// stuckSink blocks Close until release is closed, like a sink pushing to
+// a hung backend.
+type stuckSink struct {
+	recordingSink
+	release chan struct{}
+}
+
+func (sink *stuckSink) Close() error {
+	<-sink.release
+	return nil
+}
+
+func TestShutdownGivesUpOnSinks(t *testing.T) {
+	closed, stuck := &recordingSink{}, &stuckSink{release: make(chan struct{})}
+	defer close(stuck.release)
+	jl := NewJSONLoggerWithOptions(WithOutput(&countingWriter{}), WithAsync(AsyncConfig{}), WithSink(closed), WithSink(stuck))
+	jl.Info("bye")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	started := time.Now()
+	dropped, err := jl.Shutdown(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "1 of 2 sinks abandoned") {
+		t.Fatalf("expected the abandoned sink to be reported, got %v", err)
+	}
+	if dropped != 0 {
+		t.Fatalf("expected the queued entry to be written, got %d dropped", dropped)
+	}
+	if elapsed := time.Since(started); elapsed > time.Second {
+		t.Fatalf("shutdown ignored the deadline, took %v", elapsed)
+	}
+}
+
+func TestShutdownWithoutAsyncClosesOutput(t *testing.T) {
+	output := &closeRecorder{}
+	jl := NewJSONLoggerWithOptions(WithOutput(output))
+
+	if dropped, err := jl.Shutdown(context.Background()); dropped != 0 || err != nil {
+		t.Fatalf("unexpected result: dropped=%d err=%v", dropped, err)
+	}
+	if !output.closed {
+		t.Fatalf("expected the output to be closed")
+	}
+}
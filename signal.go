@@ -0,0 +1,62 @@
+package golog
+
+import (
+	"os"
+	"os/signal"
+)
+
+// HandleSignals installs the conventional daemon controls:
+//   - SIGUSR1 makes the logger more verbose by one level (info to debug)
+//   - SIGUSR2 makes it less verbose by one level (info to warn)
+//   - SIGHUP calls Reopen, for log rotation
+//
+// Level changes are logged regardless of the level. Call the returned stop
+// function to uninstall the handlers. On platforms without these signals
+// HandleSignals does nothing.
+func (jsonLogger *JSONLogger) HandleSignals() (stop func()) {
+	if len(controlSignals) == 0 {
+		return func() {}
+	}
+	signals := make(chan os.Signal, 4)
+	done := make(chan struct{})
+	signal.Notify(signals, controlSignals...)
+
+	go func() {
+		for {
+			select {
+			case received := <-signals:
+				jsonLogger.handleSignal(received)
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		signal.Stop(signals)
+		select {
+		case <-done:
+		default:
+			close(done)
+		}
+	}
+}
+
+// handleSignal applies one control signal.
+func (jsonLogger *JSONLogger) handleSignal(received os.Signal) {
+	switch received {
+	case moreVerboseSignal, lessVerboseSignal:
+		level := jsonLogger.Level()
+		if received == moreVerboseSignal && level > DebugLevel {
+			level--
+		} else if received == lessVerboseSignal && level < ErrorLevel {
+			level++
+		}
+		jsonLogger.SetLevel(level)
+		jsonLogger.logUnfiltered("log level changed", []Field{Str("log_level", level.String()), Str("signal", received.String())})
+	case reopenSignal:
+		if err := jsonLogger.Reopen(); err != nil {
+			jsonLogger.writeErrors.record(err)
+			jsonLogger.logUnfiltered("log reopen failed", []Field{Str("error", err.Error())})
+		}
+	}
+}
@@ -0,0 +1,12 @@
+//go:build !unix
+
+package golog
+
+import "os"
+
+var (
+	moreVerboseSignal os.Signal
+	lessVerboseSignal os.Signal
+	reopenSignal      os.Signal
+	controlSignals    []os.Signal
+)
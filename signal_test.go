@@ -0,0 +1,62 @@
+//go:build unix
+
+package golog
+
+import (
+	"bytes"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestHandleSignalsChangesLevel(t *testing.T) {
+	buf := &lockedBuffer{}
+	jl := NewJSONLoggerWithOptions(WithOutput(buf))
+	stop := jl.HandleSignals()
+	defer stop()
+
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGUSR1); err != nil {
+		t.Fatalf("kill: %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for jl.Level() != DebugLevel {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected SIGUSR1 to lower the level to debug, got %v", jl.Level())
+		}
+		time.Sleep(time.Millisecond)
+	}
+	stop()
+	stop()
+
+	jl.handleSignal(syscall.SIGUSR1)
+	jl.handleSignal(syscall.SIGUSR2)
+	jl.handleSignal(syscall.SIGUSR2)
+	if jl.Level() != WarnLevel {
+		t.Fatalf("expected warn after clamping at debug and two SIGUSR2, got %v", jl.Level())
+	}
+
+	entries := readEntries(t, buf.Bytes())
+	if len(entries) < 4 || entries[0].Message != "log level changed" || entries[0].FieldMap()["log_level"] != "debug" {
+		t.Fatalf("unexpected entries: %+v", entries)
+	}
+}
+
+func TestHandleSignalReopens(t *testing.T) {
+	output := &reopenRecorder{}
+	jl := NewJSONLoggerWithOptions(WithOutput(output))
+
+	jl.handleSignal(syscall.SIGHUP)
+	if output.reopened != 1 {
+		t.Fatalf("expected SIGHUP to reopen the output")
+	}
+}
+
+type reopenRecorder struct {
+	bytes.Buffer
+	reopened int
+}
+
+func (recorder *reopenRecorder) Reopen() error {
+	recorder.reopened++
+	return nil
+}
@@ -0,0 +1,15 @@
+//go:build unix
+
+package golog
+
+import (
+	"os"
+	"syscall"
+)
+
+var (
+	moreVerboseSignal os.Signal = syscall.SIGUSR1
+	lessVerboseSignal os.Signal = syscall.SIGUSR2
+	reopenSignal      os.Signal = syscall.SIGHUP
+	controlSignals              = []os.Signal{moreVerboseSignal, lessVerboseSignal, reopenSignal}
+)
@@ -0,0 +1,264 @@
+package golog
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Sink is a log destination that receives decoded entries rather than
+// encoded bytes, so it can pick its own format or ship entries in structured
+// form. A logger delivers every entry it writes to its output to each sink
+// registered with WithSink, and Flush and Close on the logger flush and close
+// its sinks.
+//
+// entry.Fields holds the base fields followed by the per-call fields and is
+// reused after Write returns, so sinks must not retain it.
+type Sink interface {
+	Write(entry Entry) error
+	Flush() error
+	Close() error
+}
+
+// WithSink adds a destination that receives every entry alongside the
+// output. To log only to sinks, pass WithOutput(io.Discard). Each sink picks
+// its own format, so one logger can write console lines to stdout and JSON
+// to a shipper at the same time:
+//
+//	jl := NewJSONLoggerWithOptions(
+//	    WithOutput(io.Discard),
+//	    WithSink(NewWriterSink(os.Stdout, &ConsoleLogWriter{})),
+//	    WithSink(NewWriterSink(shipper, nil)),
+//	)
+//
+// Writers from this package inside a WriterSink follow the logger's
+// configuration (time format, level strings, encoding policy).
+func WithSink(sink Sink) Option {
+	return func(jsonLogger *JSONLogger) {
+		if sink == nil {
+			return
+		}
+		if binder, ok := sink.(loggerBinder); ok {
+			binder.bindLogger(jsonLogger)
+		}
+		jsonLogger.sinks = append(jsonLogger.sinks, sink)
+	}
+}
+
+// writeSinksAt delivers an entry, whose control fields have already been
+// applied, to every sink. Like the output path, it copies the fields into a
+// pooled slice so the caller's variadic slice stays on the stack.
+func (jsonLogger *JSONLogger) writeSinksAt(seq uint64, timestamp time.Time, logLevel Level, message string, fields []Field) {
+	jsonLogger.baseFieldsOnce.Do(jsonLogger.buildBaseFieldsCache)
+	fields = jsonLogger.sortedFields(jsonLogger.encoder.limitFields(fields))
+
+	scratchPtr := fieldScratchPool.Get().(*[]Field)
+	scratch := jsonLogger.appendGeneratedFields((*scratchPtr)[:0], seq)
+	scratch = append(scratch, jsonLogger.baseFieldList...)
+	scratch = append(scratch, fields...)
+
+	entry := Entry{Time: timestamp, Level: logLevel, Message: message, Fields: scratch, logger: jsonLogger}
+	for _, sink := range jsonLogger.sinks {
+		if err := sink.Write(entry); err != nil {
+			jsonLogger.writeErrors.record(err)
+		}
+	}
+
+	clear(scratch)
+	*scratchPtr = scratch[:0]
+	fieldScratchPool.Put(scratchPtr)
+}
+
+// WriterSink formats entries with a LogWriter and writes them to an
+// io.Writer. It is safe for concurrent use.
+type WriterSink struct {
+	mutex  sync.Mutex
+	output io.Writer
+	writer LogWriter
+	buffer []byte
+}
+
+// NewWriterSink returns a sink writing entries formatted by writer to
+// output. A nil writer selects compact JSON.
+func NewWriterSink(output io.Writer, writer LogWriter) *WriterSink {
+	if writer == nil {
+		writer = jsonLogWriter{logger: unboundWriterConfig}
+	}
+	if binder, ok := writer.(outputBinder); ok {
+		binder.bindOutput(output)
+	}
+	return &WriterSink{output: output, writer: writer}
+}
+
+// outputBinder is implemented by writers that inspect their destination,
+// such as ConsoleLogWriter detecting a terminal.
+type outputBinder interface {
+	bindOutput(output io.Writer)
+}
+
+func (sink *WriterSink) bindLogger(jsonLogger *JSONLogger) {
+	switch writer := sink.writer.(type) {
+	case jsonLogWriter:
+		sink.writer = jsonLogWriter{logger: jsonLogger}
+	case loggerBinder:
+		writer.bindLogger(jsonLogger)
+	}
+}
+
+// Write implements Sink.
+func (sink *WriterSink) Write(entry Entry) error {
+	sink.mutex.Lock()
+	defer sink.mutex.Unlock()
+	sink.buffer = sink.writer.AppendLog(sink.buffer[:0], entry)
+	_, err := sink.output.Write(sink.buffer)
+	return err
+}
+
+// Flush flushes the output if it buffers data.
+func (sink *WriterSink) Flush() error {
+	flusher, ok := sink.output.(interface{ Flush() error })
+	if !ok {
+		return nil
+	}
+	sink.mutex.Lock()
+	defer sink.mutex.Unlock()
+	return flusher.Flush()
+}
+
+// Close flushes the output and closes it, leaving the standard streams
+// open.
+func (sink *WriterSink) Close() error {
+	err := sink.Flush()
+	sink.mutex.Lock()
+	defer sink.mutex.Unlock()
+	if closeErr := closeOutput(sink.output); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// SinkFactory creates a sink from a URL such as
+// "loki://logs.internal:3100?tenant=a".
+type SinkFactory func(target *url.URL) (Sink, error)
+
+var sinkRegistry = struct {
+	sync.RWMutex
+	factories map[string]SinkFactory
+}{
+	factories: map[string]SinkFactory{
+		"file":   openFileSink,
+		"stdout": func(target *url.URL) (Sink, error) { return openStreamSink(os.Stdout, target) },
+		"stderr": func(target *url.URL) (Sink, error) { return openStreamSink(os.Stderr, target) },
+	},
+}
+
+// RegisterSink makes a sink available to OpenSink under scheme. Like
+// sql.Register, it panics if factory is nil or scheme is already taken.
+// The "file", "stdout" and "stderr" schemes are built in.
+func RegisterSink(scheme string, factory SinkFactory) {
+	scheme = strings.ToLower(scheme)
+	sinkRegistry.Lock()
+	defer sinkRegistry.Unlock()
+	if factory == nil {
+		panic("golog: RegisterSink factory is nil")
+	}
+	if _, dup := sinkRegistry.factories[scheme]; dup {
+		panic("golog: RegisterSink called twice for scheme " + scheme)
+	}
+	sinkRegistry.factories[scheme] = factory
+}
+
+// OpenSink creates a sink from a configuration string, dispatching on the
+// URL scheme:
+//
+//	file:///var/log/app.json     append JSON lines to a file
+//	file:///var/log/app.json.gz  the same, gzip compressed
+//	stdout: or stderr:           JSON lines on a standard stream
+//
+// The built-in schemes accept a format query parameter selecting the
+// LogWriter: json (the default), pretty, console, dev, cbor or msgpack, as
+// in "stdout:?format=console".
+func OpenSink(rawURL string) (Sink, error) {
+	target, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	sinkRegistry.RLock()
+	factory, ok := sinkRegistry.factories[strings.ToLower(target.Scheme)]
+	sinkRegistry.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown sink scheme %q", target.Scheme)
+	}
+	return factory(target)
+}
+
+// openFileSink opens the file named by target for appending. Paths ending
+// in ".gz" are gzip compressed.
+func openFileSink(target *url.URL) (Sink, error) {
+	path := target.Path
+	if path == "" {
+		path = target.Opaque
+	}
+	if path == "" {
+		return nil, fmt.Errorf("file sink %q has no path", target.String())
+	}
+	writer, err := sinkFormat(target)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasSuffix(path, ".gz") {
+		return NewWriterSink(file, writer), nil
+	}
+	return NewWriterSink(&compressedFile{CompressedWriter: NewCompressedWriter(file, GzipCodec), file: file}, writer), nil
+}
+
+func openStreamSink(stream *os.File, target *url.URL) (Sink, error) {
+	writer, err := sinkFormat(target)
+	if err != nil {
+		return nil, err
+	}
+	return NewWriterSink(stream, writer), nil
+}
+
+// sinkFormat returns the LogWriter named by the format query parameter, or
+// nil for JSON.
+func sinkFormat(target *url.URL) (LogWriter, error) {
+	switch format := target.Query().Get("format"); format {
+	case "", "json":
+		return nil, nil
+	case "pretty":
+		return &PrettyJSONLogWriter{}, nil
+	case "console":
+		return &ConsoleLogWriter{}, nil
+	case "dev":
+		return &ConsoleLogWriter{TimeFormat: devTimeFormat, Glyphs: true}, nil
+	case "cbor":
+		return &BinaryLogWriter{Format: BinaryCBOR}, nil
+	case "msgpack":
+		return &BinaryLogWriter{Format: BinaryMessagePack}, nil
+	default:
+		return nil, fmt.Errorf("unknown sink format %q", format)
+	}
+}
+
+// compressedFile terminates the compressed stream before closing the file.
+type compressedFile struct {
+	*CompressedWriter
+	file *os.File
+}
+
+func (compressed *compressedFile) Close() error {
+	err := compressed.CompressedWriter.Close()
+	if closeErr := compressed.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
@@ -0,0 +1,171 @@
+package golog
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// recordingSink keeps a copy of every entry it receives.
+type recordingSink struct {
+	entries []Entry
+	flushes int
+	closed  bool
+}
+
+func (sink *recordingSink) Write(entry Entry) error {
+	entry.Fields = append([]Field(nil), entry.Fields...)
+	sink.entries = append(sink.entries, entry)
+	return nil
+}
+
+func (sink *recordingSink) Flush() error {
+	sink.flushes++
+	return nil
+}
+
+func (sink *recordingSink) Close() error {
+	sink.closed = true
+	return nil
+}
+
+func TestWithSinkReceivesEntries(t *testing.T) {
+	buf := &bytes.Buffer{}
+	sink := &recordingSink{}
+	jl := NewJSONLoggerWithOptions(WithOutput(buf), WithSink(sink), WithBaseField("service", "api"))
+
+	jl.Info("hello", Int("n", 1))
+	jl.Debug("filtered")
+	jl.Event("signup", nil)
+
+	if len(sink.entries) != 2 || len(readEntries(t, buf.Bytes())) != 2 {
+		t.Fatalf("expected the sink and the output to see the same entries, got %d", len(sink.entries))
+	}
+	fields := sink.entries[0].FieldMap()
+	if sink.entries[0].Message != "hello" || fields["service"] != "api" || fields["n"] != int64(1) {
+		t.Fatalf("unexpected sink entry: %+v", sink.entries[0])
+	}
+
+	if err := jl.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if sink.flushes == 0 || !sink.closed {
+		t.Fatalf("expected Close to flush and close the sink: %+v", sink)
+	}
+}
+
+func TestOpenSinkFile(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"app.json", "app.json.gz"} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(dir, name)
+			sink, err := OpenSink("file://" + path)
+			if err != nil {
+				t.Fatalf("open sink: %v", err)
+			}
+			jl := NewJSONLoggerWithOptions(WithOutput(io.Discard), WithSink(sink))
+			jl.Info("to file")
+			if err := jl.Close(); err != nil {
+				t.Fatalf("close: %v", err)
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("read: %v", err)
+			}
+			if strings.HasSuffix(name, ".gz") {
+				reader, err := gzip.NewReader(bytes.NewReader(data))
+				if err != nil {
+					t.Fatalf("gzip: %v", err)
+				}
+				if data, err = io.ReadAll(reader); err != nil {
+					t.Fatalf("decompress: %v", err)
+				}
+			}
+			if entries := readEntries(t, data); len(entries) != 1 || entries[0].Message != "to file" {
+				t.Fatalf("unexpected file contents: %q", data)
+			}
+		})
+	}
+}
+
+// registeredSinks numbers test schemes, since RegisterSink panics when a
+// scheme is reused (for example under go test -count).
+var registeredSinks atomic.Int32
+
+func TestRegisterSink(t *testing.T) {
+	var opened *url.URL
+	scheme := fmt.Sprintf("test-memory-%d", registeredSinks.Add(1))
+	RegisterSink(scheme, func(target *url.URL) (Sink, error) {
+		opened = target
+		return &recordingSink{}, nil
+	})
+
+	if _, err := OpenSink(scheme + "://bucket?tenant=a"); err != nil {
+		t.Fatalf("open registered sink: %v", err)
+	}
+	if opened.Host != "bucket" || opened.Query().Get("tenant") != "a" {
+		t.Fatalf("unexpected URL passed to factory: %v", opened)
+	}
+	if _, err := OpenSink("nowhere://x"); err == nil {
+		t.Fatalf("expected an unknown scheme to fail")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected duplicate registration to panic")
+		}
+	}()
+	RegisterSink("file", openFileSink)
+}
+
+func TestWriterSinksChooseTheirOwnFormat(t *testing.T) {
+	console, shipper := &bytes.Buffer{}, &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(
+		WithOutput(io.Discard),
+		WithSink(NewWriterSink(console, &ConsoleLogWriter{})),
+		WithSink(NewWriterSink(shipper, nil)),
+		WithLevelStrings(map[Level]string{InfoLevel: "INFO"}),
+		WithBaseField("service", "api"),
+	)
+
+	jl.Info("started", Int("port", 8080))
+
+	if !strings.Contains(console.String(), "INFO  started service=api port=8080\n") {
+		t.Fatalf("unexpected console sink output: %q", console.String())
+	}
+	if !strings.Contains(shipper.String(), `"level":"INFO","message":"started","service":"api","port":8080}`) {
+		t.Fatalf("expected JSON sink to follow the logger's level strings, got %q", shipper.String())
+	}
+}
+
+func TestOpenSinkFormat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	sink, err := OpenSink("file://" + path + "?format=dev")
+	if err != nil {
+		t.Fatalf("open sink: %v", err)
+	}
+	jl := NewJSONLoggerWithOptions(WithOutput(io.Discard), WithSink(sink))
+	jl.Warn("disk low")
+	if err := jl.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if !strings.HasSuffix(string(data), " ▲ disk low\n") {
+		t.Fatalf("expected a dev format line, got %q", data)
+	}
+	if _, err := OpenSink("stdout:?format=yaml"); err == nil {
+		t.Fatalf("expected an unknown format to fail")
+	}
+}
@@ -0,0 +1,151 @@
+package golog
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"sync"
+)
+
+// spillHeaderSize is the size of a spill record header: the payload length
+// and the number of entries it holds, both big-endian uint32.
+const spillHeaderSize = 8
+
+// spillFile is the on-disk overflow of an async writer. Each record holds
+// one or more encoded entries behind a header, so entries of any format,
+// binary ones included, are read back whole. Records are appended at size
+// and delivered from readOffset; the file is truncated once every record is
+// delivered.
+type spillFile struct {
+	mutex      sync.Mutex
+	file       *os.File
+	path       string
+	readOffset int64
+	size       int64
+	// entries is the number of undelivered entries.
+	entries int
+	header  [spillHeaderSize]byte
+}
+
+// openSpillFile opens or creates the spill file at path. Records left by a
+// previous process are kept for delivery; a record cut short by a crash is
+// discarded.
+func openSpillFile(path string) (*spillFile, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	spill := &spillFile{file: file, path: path}
+	for {
+		if _, err := file.ReadAt(spill.header[:], spill.size); err != nil {
+			break
+		}
+		length := int64(binary.BigEndian.Uint32(spill.header[:4]))
+		end := spill.size + spillHeaderSize + length
+		if info, err := file.Stat(); err != nil || end > info.Size() {
+			break
+		}
+		spill.entries += int(binary.BigEndian.Uint32(spill.header[4:]))
+		spill.size = end
+	}
+	if err := file.Truncate(spill.size); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return spill, nil
+}
+
+// append writes p, holding entries encoded entries, as one record.
+func (spill *spillFile) append(p []byte, entries int) error {
+	spill.mutex.Lock()
+	defer spill.mutex.Unlock()
+	if spill.file == nil {
+		return ErrWriterClosed
+	}
+	binary.BigEndian.PutUint32(spill.header[:4], uint32(len(p)))
+	binary.BigEndian.PutUint32(spill.header[4:], uint32(entries))
+	if _, err := spill.file.WriteAt(spill.header[:], spill.size); err != nil {
+		return err
+	}
+	if _, err := spill.file.WriteAt(p, spill.size+spillHeaderSize); err != nil {
+		// The record is incomplete; the next append overwrites it.
+		return err
+	}
+	spill.size += spillHeaderSize + int64(len(p))
+	spill.entries += entries
+	return nil
+}
+
+// pending reports whether the file holds undelivered records.
+func (spill *spillFile) pending() bool {
+	spill.mutex.Lock()
+	defer spill.mutex.Unlock()
+	return spill.readOffset < spill.size
+}
+
+// undelivered returns the number of bytes and entries waiting in the file.
+func (spill *spillFile) undelivered() (int64, int) {
+	spill.mutex.Lock()
+	defer spill.mutex.Unlock()
+	return spill.size - spill.readOffset, spill.entries
+}
+
+// next reads the undelivered records, up to maxBytes of payload but at
+// least one record, appending their payloads to dst. It returns the new
+// buffer, the entries read and the offset to pass to advance once they are
+// delivered.
+func (spill *spillFile) next(dst []byte, maxBytes int) ([]byte, int, int64, error) {
+	spill.mutex.Lock()
+	defer spill.mutex.Unlock()
+	if spill.file == nil {
+		return dst, 0, 0, ErrWriterClosed
+	}
+	offset, entries := spill.readOffset, 0
+	for offset < spill.size && (entries == 0 || len(dst) < maxBytes) {
+		if _, err := spill.file.ReadAt(spill.header[:], offset); err != nil {
+			return dst, 0, 0, err
+		}
+		length := int(binary.BigEndian.Uint32(spill.header[:4]))
+		if entries > 0 && len(dst)+length > maxBytes {
+			break
+		}
+		start := len(dst)
+		dst = append(dst, make([]byte, length)...)
+		if _, err := spill.file.ReadAt(dst[start:], offset+spillHeaderSize); err != nil && !errors.Is(err, io.EOF) {
+			return dst[:start], 0, 0, err
+		}
+		entries += int(binary.BigEndian.Uint32(spill.header[4:]))
+		offset += spillHeaderSize + int64(length)
+	}
+	return dst, entries, offset, nil
+}
+
+// advance marks the records before offset, holding entries entries, as
+// delivered, truncating the file once nothing is left.
+func (spill *spillFile) advance(offset int64, entries int) error {
+	spill.mutex.Lock()
+	defer spill.mutex.Unlock()
+	spill.readOffset = offset
+	spill.entries -= entries
+	if spill.readOffset < spill.size || spill.file == nil {
+		return nil
+	}
+	spill.readOffset, spill.size, spill.entries = 0, 0, 0
+	return spill.file.Truncate(0)
+}
+
+// close closes the file, removing it when nothing is left to deliver.
+func (spill *spillFile) close() error {
+	spill.mutex.Lock()
+	defer spill.mutex.Unlock()
+	if spill.file == nil {
+		return nil
+	}
+	err := spill.file.Close()
+	spill.file = nil
+	if err == nil && spill.readOffset >= spill.size {
+		err = os.Remove(spill.path)
+	}
+	return err
+}
@@ -0,0 +1,161 @@
+package golog
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// outageWriter fails every write while down is set.
+type outageWriter struct {
+	countingWriter
+	down atomic.Bool
+}
+
+func (writer *outageWriter) Write(p []byte) (int, error) {
+	if writer.down.Load() {
+		return 0, errors.New("collector unavailable")
+	}
+	return writer.countingWriter.Write(p)
+}
+
+func assertSequence(t *testing.T, data []byte, count int) {
+	t.Helper()
+	entries := readEntries(t, data)
+	if len(entries) != count {
+		t.Fatalf("expected %d entries, got %d", count, len(entries))
+	}
+	for i, entry := range entries {
+		if field, _ := entry.Field("i"); field.Value() != int64(i) {
+			t.Fatalf("entries out of order at %d: %v", i, field.Value())
+		}
+	}
+}
+
+func TestAsyncSpillSavesFailedBatches(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spill")
+	output := &outageWriter{}
+	output.down.Store(true)
+	jl := NewJSONLoggerWithOptions(WithOutput(output), WithAsync(AsyncConfig{MaxLatency: time.Hour, SpillPath: path}))
+
+	for i := range 3 {
+		jl.Info("audit", Int("i", i))
+	}
+	if err := jl.Flush(); err == nil {
+		t.Fatal("expected the failed write to be reported")
+	}
+	for i := 3; i < 5; i++ {
+		jl.Info("audit", Int("i", i))
+	}
+	stats := jl.Stats()
+	if stats.DroppedEntries != 0 || stats.SpilledEntries != 5 || stats.SpillEntries != 5 || stats.SpillBytes == 0 {
+		t.Fatalf("expected every entry to be spilled: %+v", stats)
+	}
+
+	output.down.Store(false)
+	if err := jl.Flush(); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	_, data := output.snapshot()
+	assertSequence(t, data, 5)
+	if stats := jl.Stats(); stats.SpillBytes != 0 || stats.SpillEntries != 0 || stats.BatchedEntries != 5 {
+		t.Fatalf("expected the spill file to be drained: %+v", stats)
+	}
+
+	if err := jl.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("expected the empty spill file to be removed: %v", err)
+	}
+}
+
+func TestAsyncSpillSurvivesRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spill")
+	down := &outageWriter{}
+	down.down.Store(true)
+	jl := NewJSONLoggerWithOptions(WithOutput(down), WithAsync(AsyncConfig{MaxLatency: time.Hour, SpillPath: path}))
+	for i := range 4 {
+		jl.Info("audit", Int("i", i))
+	}
+	if err := jl.Close(); err == nil {
+		t.Fatal("expected the failed write to be reported")
+	}
+
+	// A record cut short by a crash is discarded.
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatalf("open spill file: %v", err)
+	}
+	file.Write([]byte{0, 0, 1, 0, 0, 0, 0, 1, '{'})
+	file.Close()
+
+	output := &countingWriter{}
+	jl = NewJSONLoggerWithOptions(WithOutput(output), WithAsync(AsyncConfig{MaxLatency: time.Hour, SpillPath: path}))
+	if stats := jl.Stats(); stats.SpillEntries != 4 {
+		t.Fatalf("expected the previous entries to be pending: %+v", stats)
+	}
+	if err := jl.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	_, data := output.snapshot()
+	assertSequence(t, data, 4)
+}
+
+func TestAsyncSpillInsteadOfBlocking(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spill")
+	output := newGateWriter()
+	output.block()
+	jl := NewJSONLoggerWithOptions(WithOutput(output), WithAsync(AsyncConfig{
+		MaxBatchBytes: 1,
+		MaxQueueBytes: 1,
+		MaxLatency:    time.Hour,
+		SpillPath:     path,
+	}))
+
+	jl.Info("audit", Int("i", 0))
+	<-output.entered
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 1; i < 50; i++ {
+			jl.Info("audit", Int("i", i))
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected logging calls not to block while the output is stalled")
+	}
+	if stats := jl.Stats(); stats.SpilledEntries == 0 {
+		t.Fatalf("expected entries to be spilled: %+v", stats)
+	}
+
+	output.release()
+	go func() {
+		for range output.entered {
+		}
+	}()
+	if err := jl.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	assertSequence(t, output.Bytes(), 50)
+}
+
+func TestAsyncSpillUnavailable(t *testing.T) {
+	output := &outageWriter{}
+	output.down.Store(true)
+	jl := NewJSONLoggerWithOptions(WithOutput(output), WithAsync(AsyncConfig{
+		MaxLatency: time.Hour,
+		SpillPath:  filepath.Join(t.TempDir(), "missing", "spill"),
+	}))
+
+	jl.Info("lost")
+	_ = jl.Flush()
+	if stats := jl.Stats(); stats.DroppedEntries != 1 || stats.SpilledEntries != 0 || stats.WriteErrors < 2 {
+		t.Fatalf("expected the writer to drop as without a spill file: %+v", stats)
+	}
+}
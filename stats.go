@@ -0,0 +1,91 @@
+package golog
+
+import "time"
+
+// Stats is a snapshot of the logger's internal counters.
+type Stats struct {
+	// Batches is the number of Write calls made by the async writer.
+	Batches uint64
+	// BatchedEntries is the number of entries written by those calls.
+	BatchedEntries uint64
+	// BatchedBytes is the number of bytes written by those calls.
+	BatchedBytes uint64
+	// DroppedEntries is the number of entries the async writer failed to
+	// write, dropped under its delivery policy or abandoned when Shutdown's
+	// context ended, plus those dropped by WithWriteWatchdog while the
+	// output was stalled.
+	DroppedEntries uint64
+	// Delivery is the async writer's delivery policy. The counters below
+	// record what each policy did while the queue was full: the logging
+	// calls that waited and for how long in total, the entries rejected and
+	// those discarded. See AsyncConfig.Delivery.
+	Delivery      DeliveryPolicy
+	BlockedWrites uint64
+	BlockedTime   time.Duration
+	DroppedNewest uint64
+	DroppedOldest uint64
+	// QueuedBytes is the number of bytes waiting in the async queue.
+	QueuedBytes int
+	// SpilledEntries is the number of entries the async writer saved to its
+	// spill file, and SpillBytes and SpillEntries what the file still holds;
+	// see AsyncConfig.SpillPath.
+	SpilledEntries uint64
+	SpillBytes     int64
+	SpillEntries   int
+	// WriteErrors is the number of failed writes to the output or sinks.
+	WriteErrors uint64
+	// LastWriteError is the most recent of those failures, or nil.
+	LastWriteError error
+	// SchemaViolations is the number of entries that violated the schema
+	// set with WithSchema, whether flagged, fixed or rejected.
+	SchemaViolations uint64
+	// RejectedEntries is the number of those entries dropped under
+	// SchemaReject.
+	RejectedEntries uint64
+	// SlowWrites is the number of output writes that took longer than the
+	// WithWriteWatchdog threshold.
+	SlowWrites uint64
+	// Levels counts the entries written at each level since the logger was
+	// created, and RecentLevels those written within the window. Both are
+	// zero unless WithLevelStats is enabled.
+	Levels       LevelCounts
+	RecentLevels LevelCounts
+}
+
+// Stats returns a snapshot of the logger's counters. Batching counters are
+// zero unless WithAsync is enabled.
+func (jsonLogger *JSONLogger) Stats() Stats {
+	config := jsonLogger.current()
+	stats := Stats{
+		WriteErrors:    jsonLogger.writeErrors.count.Load(),
+		LastWriteError: jsonLogger.writeErrors.lastError(),
+	}
+	if schema := config.schema; schema != nil {
+		stats.SchemaViolations = schema.violations.Load()
+		stats.RejectedEntries = schema.rejected.Load()
+	}
+	if async := config.async; async != nil {
+		stats.Batches = async.batches.Load()
+		stats.BatchedEntries = async.batchedEntries.Load()
+		stats.BatchedBytes = async.batchedBytes.Load()
+		stats.DroppedEntries = async.droppedEntries.Load()
+		stats.QueuedBytes = async.queuedBytes()
+		stats.Delivery = async.config.Delivery
+		stats.BlockedWrites = async.blockedWrites.Load()
+		stats.BlockedTime = time.Duration(async.blockedTime.Load())
+		stats.DroppedNewest = async.droppedNewest.Load()
+		stats.DroppedOldest = async.droppedOldest.Load()
+		stats.SpilledEntries = async.spilledEntries.Load()
+		if async.spill != nil {
+			stats.SpillBytes, stats.SpillEntries = async.spill.undelivered()
+		}
+	}
+	if watchdog := config.watchdog; watchdog != nil {
+		stats.SlowWrites = watchdog.slowWrites.Load()
+		stats.DroppedEntries += watchdog.dropped.Load()
+	}
+	if levelStats := config.levelStats; levelStats != nil {
+		stats.Levels, stats.RecentLevels = levelStats.snapshot(config.clock())
+	}
+	return stats
+}
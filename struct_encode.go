@@ -0,0 +1,540 @@
+package golog
+
+import (
+	"cmp"
+	"encoding"
+	"encoding/json"
+	"iter"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxReflectDepth bounds recursion through pointers and nested values on the
+// reflection path so self-referencing structures can't overflow the stack.
+const maxReflectDepth = 32
+
+var (
+	timeType          = reflect.TypeFor[time.Time]()
+	durationType      = reflect.TypeFor[time.Duration]()
+	rawMessageType    = reflect.TypeFor[json.RawMessage]()
+	rawJSONSourceType = reflect.TypeFor[rawJSONSource]()
+	byteSliceType     = reflect.TypeFor[[]byte]()
+	errorType         = reflect.TypeFor[error]()
+)
+
+// structPlan is the compiled encoding plan for a struct type: the exported
+// fields to write, in order, with their pre-encoded keys.
+type structPlan struct {
+	fields []structFieldPlan
+}
+
+type structFieldPlan struct {
+	name string
+	// key is the pre-encoded `"name":` prefix.
+	key       []byte
+	index     []int
+	omitEmpty bool
+	// quoted writes a scalar value as a JSON string, like the `string` tag
+	// option of encoding/json.
+	quoted bool
+}
+
+// structPlans caches compiled plans keyed by reflect.Type, so reflection over
+// struct tags happens once per type rather than once per log call.
+var structPlans sync.Map
+
+// planFor returns the cached plan for a struct type, compiling it on first
+// use.
+func planFor(structType reflect.Type) *structPlan {
+	if cached, ok := structPlans.Load(structType); ok {
+		return cached.(*structPlan)
+	}
+	plan := compileStructPlan(structType)
+	actual, _ := structPlans.LoadOrStore(structType, plan)
+	return actual.(*structPlan)
+}
+
+// compileStructPlan walks the exported fields of structType honoring `json`
+// tags (renames, "-", omitempty, string). Untagged embedded structs are
+// flattened into the parent. Names follow the encoding/json dominance rules:
+// the shallowest field wins, then the tagged one among equally shallow
+// fields, and a name still ambiguous after that is left out.
+func compileStructPlan(structType reflect.Type) *structPlan {
+	type pending struct {
+		structType reflect.Type
+		index      []int
+	}
+	type candidate struct {
+		structFieldPlan
+		tagged bool
+	}
+	var candidates []candidate
+
+	next := []pending{{structType: structType}}
+	nextCount := map[reflect.Type]int{structType: 1}
+	visited := make(map[reflect.Type]bool)
+	for len(next) > 0 {
+		queue, count := next, nextCount
+		next, nextCount = nil, make(map[reflect.Type]int)
+		for _, current := range queue {
+			if visited[current.structType] {
+				continue
+			}
+			visited[current.structType] = true
+			for i := 0; i < current.structType.NumField(); i++ {
+				field := current.structType.Field(i)
+				tag := field.Tag.Get("json")
+				if tag == "-" {
+					continue
+				}
+				name, options, _ := strings.Cut(tag, ",")
+
+				index := make([]int, len(current.index)+1)
+				copy(index, current.index)
+				index[len(current.index)] = i
+
+				if field.Anonymous && name == "" {
+					embeddedType := field.Type
+					if embeddedType.Kind() == reflect.Pointer {
+						embeddedType = embeddedType.Elem()
+					}
+					if embeddedType.Kind() == reflect.Struct {
+						nextCount[embeddedType]++
+						if nextCount[embeddedType] == 1 {
+							next = append(next, pending{structType: embeddedType, index: index})
+						}
+						continue
+					}
+				}
+				if !field.IsExported() {
+					continue
+				}
+				tagged := name != ""
+				if !tagged {
+					name = field.Name
+				}
+
+				key := appendQuoteStrict(nil, name)
+				key = append(key, ':')
+				fieldCandidate := candidate{
+					structFieldPlan: structFieldPlan{
+						name:      name,
+						key:       key,
+						index:     index,
+						omitEmpty: hasTagOption(options, "omitempty"),
+						quoted:    hasTagOption(options, "string") && isQuotableType(field.Type),
+					},
+					tagged: tagged,
+				}
+				candidates = append(candidates, fieldCandidate)
+				if count[current.structType] > 1 {
+					// The struct is embedded more than once at this depth,
+					// so its fields are ambiguous: a second copy makes sure
+					// they are left out.
+					candidates = append(candidates, fieldCandidate)
+				}
+			}
+		}
+	}
+
+	slices.SortFunc(candidates, func(a, b candidate) int {
+		if order := strings.Compare(a.name, b.name); order != 0 {
+			return order
+		}
+		if order := cmp.Compare(len(a.index), len(b.index)); order != 0 {
+			return order
+		}
+		if a.tagged != b.tagged {
+			if a.tagged {
+				return -1
+			}
+			return 1
+		}
+		return slices.Compare(a.index, b.index)
+	})
+	plan := &structPlan{}
+	for start := 0; start < len(candidates); {
+		end := start + 1
+		for end < len(candidates) && candidates[end].name == candidates[start].name {
+			end++
+		}
+		first := candidates[start]
+		if end-start == 1 || len(candidates[start+1].index) > len(first.index) || candidates[start+1].tagged != first.tagged {
+			plan.fields = append(plan.fields, first.structFieldPlan)
+		}
+		start = end
+	}
+
+	// Like encoding/json, promoted fields take the place of their embedded
+	// struct rather than following the parent's own fields.
+	slices.SortFunc(plan.fields, func(a, b structFieldPlan) int {
+		return slices.Compare(a.index, b.index)
+	})
+	return plan
+}
+
+// isQuotableType reports whether the `string` tag option applies to
+// fieldType: strings, numbers and bools, or pointers to them.
+func isQuotableType(fieldType reflect.Type) bool {
+	if fieldType.Kind() == reflect.Pointer {
+		fieldType = fieldType.Elem()
+	}
+	switch fieldType.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+func hasTagOption(options, option string) bool {
+	for options != "" {
+		var current string
+		current, options, _ = strings.Cut(options, ",")
+		if current == option {
+			return true
+		}
+	}
+	return false
+}
+
+// appendReflect encodes values the type switch in appendValue doesn't know
+// about, using cached struct plans for structs. depth counts the containers
+// around value; pointers and interfaces are followed without adding to it.
+func (enc *encoder) appendReflect(dst []byte, value reflect.Value, depth int) ([]byte, bool) {
+	for hops := 0; ; hops++ {
+		if depth > maxReflectDepth || hops > maxReflectDepth {
+			return dst, false
+		}
+		if !value.IsValid() {
+			return append(dst, "null"...), true
+		}
+		if value.CanInterface() {
+			switch value.Type() {
+			case timeType, durationType, rawMessageType, rawJSONSourceType, byteSliceType, jsonNumberType:
+				return enc.appendValue(dst, value.Interface())
+			case bigIntType, bigFloatType:
+				return enc.appendValue(dst, bigNumberPointer(value))
+			}
+			if isTextValueType(value.Type()) {
+				return enc.appendTextValue(dst, value.Interface()), true
+			}
+		}
+		if message, ok := enc.protoValue(value); ok {
+			return enc.appendProto(dst, message)
+		}
+		if isErrorValue(value) {
+			if value.Kind() == reflect.Pointer && value.IsNil() {
+				return append(dst, "null"...), true
+			}
+			return enc.appendError(dst, value.Interface().(error)), true
+		}
+		if value.Kind() != reflect.Pointer && value.Kind() != reflect.Interface {
+			if marshaler, ok := marshalerOf(value); ok {
+				return enc.appendMarshaler(dst, marshaler)
+			}
+			break
+		}
+		if value.IsNil() {
+			return append(dst, "null"...), true
+		}
+		value = value.Elem()
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		return enc.appendStringValue(dst, value.String()), true
+	case reflect.Bool:
+		if value.Bool() {
+			return append(dst, "true"...), true
+		}
+		return append(dst, "false"...), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.AppendInt(dst, value.Int(), 10), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.AppendUint(dst, value.Uint(), 10), true
+	case reflect.Float32:
+		return enc.appendFloat(dst, value.Float(), 32)
+	case reflect.Float64:
+		return enc.appendFloat(dst, value.Float(), 64)
+	case reflect.Struct:
+		return enc.appendStruct(dst, value, depth)
+	case reflect.Map:
+		if !isMapKeyType(value.Type().Key()) {
+			return dst, false
+		}
+		if value.IsNil() {
+			return append(dst, "null"...), true
+		}
+		if enc.depthExceeded(depth) {
+			return appendQuoteBytes(dst, TruncatedMarker), true
+		}
+		dst = append(dst, '{')
+		written := 0
+		for key, member := range reflectMapMembers(value, enc.sortMapKeys) {
+			if written == enc.maxFields && written > 0 {
+				dst = enc.appendTruncatedMembers(dst, value.Len()-written)
+				break
+			}
+			if written > 0 {
+				dst = append(dst, ',')
+			}
+			written++
+			name, ok := mapKeyString(key)
+			if !ok {
+				return dst, false
+			}
+			dst = enc.appendString(dst, name)
+			dst = append(dst, ':')
+			dst, ok = enc.appendReflect(dst, member, depth+1)
+			if !ok {
+				return dst, false
+			}
+		}
+		return append(dst, '}'), true
+	case reflect.Slice, reflect.Array:
+		if value.Kind() == reflect.Slice && value.IsNil() {
+			return append(dst, "null"...), true
+		}
+		if enc.depthExceeded(depth) {
+			return appendQuoteBytes(dst, TruncatedMarker), true
+		}
+		dst = append(dst, '[')
+		for i := 0; i < value.Len(); i++ {
+			if i == enc.maxFields && i > 0 {
+				dst = appendTruncatedElements(dst)
+				break
+			}
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			var ok bool
+			dst, ok = enc.appendReflect(dst, value.Index(i), depth+1)
+			if !ok {
+				return dst, false
+			}
+		}
+		return append(dst, ']'), true
+	default:
+		return dst, false
+	}
+}
+
+// isErrorValue reports whether value holds an error to be written as its
+// message. Marshalers keep their own encoding, as with encoding/json.
+func isErrorValue(value reflect.Value) bool {
+	return value.Kind() != reflect.Interface && value.CanInterface() &&
+		value.Type().Implements(errorType) && !isMarshalerType(value.Type())
+}
+
+// marshalerOf returns value, which isn't a pointer, as a json.Marshaler or
+// encoding.TextMarshaler when it implements one. Like encoding/json, methods
+// with a pointer receiver count only when value is addressable, as it is
+// when reached through a pointer.
+func marshalerOf(value reflect.Value) (any, bool) {
+	if !value.CanInterface() {
+		return nil, false
+	}
+	if !isMarshalerType(value.Type()) {
+		if !value.CanAddr() || !isMarshalerType(reflect.PointerTo(value.Type())) {
+			return nil, false
+		}
+		value = value.Addr()
+	}
+	return value.Interface(), true
+}
+
+func isMarshalerType(valueType reflect.Type) bool {
+	return valueType.Implements(jsonMarshalerType) || valueType.Implements(textMarshalerType)
+}
+
+// appendMarshaler encodes a value from marshalerOf: MarshalJSON output is
+// compacted into dst and MarshalText output is written as a string value.
+// Either failing makes the value unsupported.
+func (enc *encoder) appendMarshaler(dst []byte, marshaler any) ([]byte, bool) {
+	if jsonMarshaler, ok := marshaler.(json.Marshaler); ok {
+		encoded, err := jsonMarshaler.MarshalJSON()
+		if err != nil {
+			return dst, false
+		}
+		return appendCompactJSON(dst, encoded)
+	}
+	text, err := marshaler.(encoding.TextMarshaler).MarshalText()
+	if err != nil {
+		return dst, false
+	}
+	return enc.appendStringValue(dst, string(text)), true
+}
+
+// appendStruct encodes a struct value following its cached plan.
+func (enc *encoder) appendStruct(dst []byte, value reflect.Value, depth int) ([]byte, bool) {
+	if enc.depthExceeded(depth) {
+		return appendQuoteBytes(dst, TruncatedMarker), true
+	}
+	plan := planFor(value.Type())
+	dst = append(dst, '{')
+	written := 0
+	for i := range plan.fields {
+		fieldPlan := &plan.fields[i]
+		fieldValue, ok := fieldByIndex(value, fieldPlan.index)
+		if !ok {
+			continue
+		}
+		if fieldPlan.omitEmpty && isEmptyValue(fieldValue) {
+			continue
+		}
+		if written == enc.maxFields && written > 0 {
+			dst = enc.appendTruncatedMembers(dst, countStructFields(value, plan.fields[i:]))
+			break
+		}
+		if written > 0 {
+			dst = append(dst, ',')
+		}
+		written++
+		dst = append(dst, fieldPlan.key...)
+		dst, ok = enc.appendStructField(dst, fieldPlan, fieldValue, depth)
+		if !ok {
+			return dst, false
+		}
+	}
+	return append(dst, '}'), true
+}
+
+// countStructFields counts the fields in fieldPlans that appendStruct would
+// write for value.
+func countStructFields(value reflect.Value, fieldPlans []structFieldPlan) int {
+	count := 0
+	for i := range fieldPlans {
+		fieldValue, ok := fieldByIndex(value, fieldPlans[i].index)
+		if ok && !(fieldPlans[i].omitEmpty && isEmptyValue(fieldValue)) {
+			count++
+		}
+	}
+	return count
+}
+
+// appendStructField encodes one struct field value, quoting it when the
+// plan asks for the `string` option. Like encoding/json, null stays null.
+func (enc *encoder) appendStructField(dst []byte, fieldPlan *structFieldPlan, value reflect.Value, depth int) ([]byte, bool) {
+	mark := len(dst)
+	dst, ok := enc.appendReflect(dst, value, depth+1)
+	if !ok || !fieldPlan.quoted || string(dst[mark:]) == "null" {
+		return dst, ok
+	}
+	encoded := AcquireBuffer()
+	*encoded = append(*encoded, dst[mark:]...)
+	dst = enc.appendString(dst[:mark], string(*encoded))
+	ReleaseBuffer(encoded)
+	return dst, true
+}
+
+// fieldByIndex is reflect.Value.FieldByIndex that reports false instead of
+// panicking when it has to step through a nil embedded pointer.
+func fieldByIndex(value reflect.Value, index []int) (reflect.Value, bool) {
+	for i, fieldIndex := range index {
+		if i > 0 && value.Kind() == reflect.Pointer {
+			if value.IsNil() {
+				return reflect.Value{}, false
+			}
+			value = value.Elem()
+		}
+		value = value.Field(fieldIndex)
+	}
+	return value, true
+}
+
+// isEmptyValue mirrors encoding/json's definition of empty for omitempty.
+func isEmptyValue(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return value.Len() == 0
+	case reflect.Bool:
+		return !value.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return value.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return value.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return value.Float() == 0
+	case reflect.Interface, reflect.Pointer:
+		return value.IsNil()
+	default:
+		return false
+	}
+}
+
+// isMapKeyType reports whether maps keyed by t can be encoded: like
+// encoding/json, string, integer and encoding.TextMarshaler keys are.
+func isMapKeyType(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	default:
+		return t.Implements(textMarshalerType)
+	}
+}
+
+// mapKeyString returns the member name for a map key of a type accepted by
+// isMapKeyType, resolved as encoding/json does. It reports false when the
+// key's MarshalText fails.
+func mapKeyString(key reflect.Value) (string, bool) {
+	if key.Kind() == reflect.String {
+		return key.String(), true
+	}
+	if marshaler, ok := key.Interface().(encoding.TextMarshaler); ok {
+		if key.Kind() == reflect.Pointer && key.IsNil() {
+			return "", true
+		}
+		text, err := marshaler.MarshalText()
+		return string(text), err == nil
+	}
+	switch key.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(key.Int(), 10), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(key.Uint(), 10), true
+	default:
+		return "", false
+	}
+}
+
+// reflectMapMembers ranges over a map, in the order of the keys' member
+// names when sorted is set.
+func reflectMapMembers(value reflect.Value, sorted bool) iter.Seq2[reflect.Value, reflect.Value] {
+	return func(yield func(reflect.Value, reflect.Value) bool) {
+		if !sorted {
+			members := value.MapRange()
+			for members.Next() {
+				if !yield(members.Key(), members.Value()) {
+					return
+				}
+			}
+			return
+		}
+		type namedKey struct {
+			name string
+			key  reflect.Value
+		}
+		keys := make([]namedKey, 0, value.Len())
+		for _, key := range value.MapKeys() {
+			name, _ := mapKeyString(key)
+			keys = append(keys, namedKey{name: name, key: key})
+		}
+		slices.SortFunc(keys, func(a, b namedKey) int {
+			return strings.Compare(a.name, b.name)
+		})
+		for _, key := range keys {
+			if !yield(key.key, value.MapIndex(key.key)) {
+				return
+			}
+		}
+	}
+}
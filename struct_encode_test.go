@@ -0,0 +1,271 @@
+package golog
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type auditInfo struct {
+	CreatedBy string    `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type orderLine struct {
+	SKU      string  `json:"sku"`
+	Quantity int     `json:"qty"`
+	Price    float64 `json:"price,omitempty"`
+}
+
+type order struct {
+	auditInfo
+	ID       string            `json:"id"`
+	Lines    []orderLine       `json:"lines"`
+	Notes    string            `json:"notes,omitempty"`
+	Tags     map[string]string `json:"tags,omitempty"`
+	Customer *string           `json:"customer"`
+	Secret   string            `json:"-"`
+	internal int
+	Timeout  time.Duration
+}
+
+func TestEncoderStructMatchesEncodingJSON(t *testing.T) {
+	customer := "c-1"
+	value := order{
+		auditInfo: auditInfo{CreatedBy: "ops", CreatedAt: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)},
+		ID:        "o-1",
+		Lines:     []orderLine{{SKU: "a", Quantity: 2, Price: 9.5}, {SKU: "b", Quantity: 1}},
+		Customer:  &customer,
+		Secret:    "hidden",
+		internal:  7,
+		Timeout:   time.Second,
+	}
+	assertMatchesEncodingJSON(t, value)
+}
+
+// assertMatchesEncodingJSON checks that the reflection encoder writes value
+// as json.Marshal does, and returns the encoding.
+func assertMatchesEncodingJSON(t *testing.T, value any) []byte {
+	t.Helper()
+	enc := encoder{reflectFallback: true}
+	got, ok := enc.appendValue(nil, value)
+	if !ok {
+		t.Fatalf("appendValue returned false: %s", got)
+	}
+
+	want, err := json.Marshal(value)
+	if err != nil {
+		t.Fatalf("json.Marshal: %v", err)
+	}
+	var gotDecoded, wantDecoded any
+	if err := json.Unmarshal(got, &gotDecoded); err != nil {
+		t.Fatalf("unmarshal encoded struct: %v -- %s", err, got)
+	}
+	if err := json.Unmarshal(want, &wantDecoded); err != nil {
+		t.Fatalf("unmarshal expected: %v", err)
+	}
+	if !reflect.DeepEqual(gotDecoded, wantDecoded) {
+		t.Fatalf("struct encoding mismatch:\n got: %s\nwant: %s", got, want)
+	}
+	return got
+}
+
+// deviceID encodes itself as text, like a UUID type.
+type deviceID [4]byte
+
+func (id deviceID) MarshalText() ([]byte, error) {
+	return []byte(hex.EncodeToString(id[:])), nil
+}
+
+// money encodes itself as JSON, with whitespace the encoder must compact.
+type money struct {
+	cents    int64
+	currency string
+}
+
+func (amount money) MarshalJSON() ([]byte, error) {
+	return fmt.Appendf(nil, "{\n  \"amount\": %d.%02d,\n  \"currency\": %q\n}", amount.cents/100, amount.cents%100, amount.currency), nil
+}
+
+// region has a pointer receiver, so it counts only for addressable values.
+type region struct{ code string }
+
+func (r *region) MarshalText() ([]byte, error) {
+	return []byte("region-" + r.code), nil
+}
+
+type brokenMarshaler struct{}
+
+func (brokenMarshaler) MarshalJSON() ([]byte, error) {
+	return nil, errors.New("no amount")
+}
+
+func TestEncoderHonorsMarshalers(t *testing.T) {
+	type payment struct {
+		Device   deviceID   `json:"device"`
+		Amount   money      `json:"amount"`
+		Refund   *money     `json:"refund"`
+		Region   region     `json:"region"`
+		Home     *region    `json:"home"`
+		Devices  []deviceID `json:"devices"`
+		Previous *deviceID  `json:"previous,omitempty"`
+	}
+	value := payment{
+		Device:  deviceID{1, 2, 3, 4},
+		Amount:  money{cents: 1250, currency: "EUR"},
+		Region:  region{code: "eu"},
+		Home:    &region{code: "us"},
+		Devices: []deviceID{{0xaa, 0xbb, 0xcc, 0xdd}},
+	}
+
+	got := string(assertMatchesEncodingJSON(t, value))
+	for _, want := range []string{
+		`"device":"01020304"`,
+		`"amount":{"amount":12.50,"currency":"EUR"}`,
+		`"refund":null`,
+		`"region":{}`,
+		`"home":"region-us"`,
+		`"devices":["aabbccdd"]`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("expected %s in %s", want, got)
+		}
+	}
+	assertMatchesEncodingJSON(t, &value)
+
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(WithOutput(buf))
+	jl.Info("charged", Any("device", deviceID{9, 9, 9, 9}), Any("broken", brokenMarshaler{}))
+	if !strings.Contains(buf.String(), `"device":"09090909"`) || !strings.Contains(buf.String(), `"broken":"<unsupported>"`) {
+		t.Fatalf("expected marshalers at the top level, and a failing one to be unsupported: %s", buf.String())
+	}
+}
+
+func TestEncoderStructDominance(t *testing.T) {
+	type name struct{ Name string }
+	type label struct{ Name string }
+	type taggedLabel struct {
+		Name string `json:"Name"`
+	}
+	type shared struct{ Shared string }
+	type left struct{ shared }
+	type right struct{ shared }
+
+	// Equally deep and equally tagged: the name is left out.
+	ambiguous := struct {
+		name
+		label
+		ID string
+	}{name{"a"}, label{"b"}, "1"}
+	if got := string(assertMatchesEncodingJSON(t, ambiguous)); got != `{"ID":"1"}` {
+		t.Fatalf("expected the ambiguous name to be dropped: %s", got)
+	}
+
+	// A tag settles it.
+	tagged := struct {
+		name
+		taggedLabel
+	}{name{"a"}, taggedLabel{"b"}}
+	if got := string(assertMatchesEncodingJSON(t, tagged)); got != `{"Name":"b"}` {
+		t.Fatalf("expected the tagged field to win: %s", got)
+	}
+
+	// The same struct reached twice at one depth is ambiguous too.
+	twice := struct {
+		left
+		right
+	}{left{shared{"l"}}, right{shared{"r"}}}
+	if got := string(assertMatchesEncodingJSON(t, twice)); got != `{}` {
+		t.Fatalf("expected the doubly embedded field to be dropped: %s", got)
+	}
+
+	// A shallower field still wins.
+	shallow := struct {
+		name
+		Name string
+	}{name{"a"}, "top"}
+	if got := string(assertMatchesEncodingJSON(t, shallow)); got != `{"Name":"top"}` {
+		t.Fatalf("expected the shallower field to win: %s", got)
+	}
+}
+
+func TestEncoderStructPlanIsCached(t *testing.T) {
+	first := planFor(reflect.TypeFor[orderLine]())
+	second := planFor(reflect.TypeFor[orderLine]())
+	if first != second {
+		t.Fatalf("expected cached plan to be reused")
+	}
+	if len(first.fields) != 3 || string(first.fields[1].key) != `"qty":` {
+		t.Fatalf("unexpected plan: %+v", first.fields)
+	}
+}
+
+func TestEncoderReflectRejectsCyclesAndUnsupported(t *testing.T) {
+	type node struct {
+		Next *node
+	}
+	cycle := &node{}
+	cycle.Next = cycle
+
+	enc := encoder{reflectFallback: true}
+	if _, ok := enc.appendValue(nil, cycle); ok {
+		t.Fatalf("expected cyclic value to be rejected")
+	}
+	if _, ok := enc.appendValue(nil, struct{ C chan int }{}); ok {
+		t.Fatalf("expected struct with chan field to be rejected")
+	}
+	if _, ok := defaultEncoder.appendValue(nil, orderLine{}); ok {
+		t.Fatalf("expected the default encoder to stay reflection-free")
+	}
+}
+
+func TestEncoderWritesErrorMessages(t *testing.T) {
+	enc := encoder{reflectFallback: true}
+	cases := []struct {
+		value any
+		want  string
+	}{
+		{errors.New("x"), `"x"`},
+		{struct{ E error }{errors.New("x")}, `{"E":"x"}`},
+		{struct{ E error }{errors.Join(errors.New("a"), errors.New("b"))}, `{"E":["a","b"]}`},
+		{struct{ E error }{}, `{"E":null}`},
+	}
+	for _, tc := range cases {
+		got, ok := enc.appendValue(nil, tc.value)
+		if !ok || string(got) != tc.want {
+			t.Fatalf("encoding %#v: got %s (%v), want %s", tc.value, got, ok, tc.want)
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(WithOutput(buf))
+	jl.Info("failed", Any("err", errors.New("x")))
+	if !strings.Contains(buf.String(), `"err":"x"`) {
+		t.Fatalf("expected the error message: %s", buf.String())
+	}
+}
+
+func TestLoggerEncodesStructFields(t *testing.T) {
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(WithOutput(buf))
+
+	jl.Info("order placed", Any("line", orderLine{SKU: "x", Quantity: 3}))
+
+	var got map[string]any
+	if err := json.Unmarshal([]byte(strings.TrimSpace(buf.String())), &got); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	line, ok := got["line"].(map[string]any)
+	if !ok || line["sku"] != "x" || line["qty"] != float64(3) {
+		t.Fatalf("unexpected struct field: %#v", got["line"])
+	}
+	if _, present := line["price"]; present {
+		t.Fatalf("expected omitempty price to be dropped: %#v", line)
+	}
+}
@@ -0,0 +1,62 @@
+package golog
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+)
+
+// TestNameKey carries the name of the test on entries written by
+// NewTestLogger.
+const TestNameKey = "test"
+
+// NewTestLogger returns a logger that writes each entry through tb.Logf, so
+// logs from the code under test appear interleaved with the test's own
+// output and, like any t.Log output, are printed only when the test fails or
+// runs with -v:
+//
+//	func TestCheckout(t *testing.T) {
+//	    svc := checkout.New(golog.NewTestLogger(t))
+//	    ...
+//	}
+//
+// Entries carry the test's name under TestNameKey, which tells parallel
+// subtests apart, and the level defaults to debug; options are applied
+// afterwards and can override both. Entries logged once the test has
+// finished, from goroutines it left behind, are dropped instead of making
+// the testing package panic.
+func NewTestLogger(tb testing.TB, options ...Option) *JSONLogger {
+	writer := &testWriter{tb: tb}
+	tb.Cleanup(writer.close)
+	return NewJSONLoggerWithOptions(append([]Option{
+		WithOutput(writer),
+		WithLevel(DebugLevel),
+		WithBaseField(TestNameKey, tb.Name()),
+	}, options...)...)
+}
+
+// testWriter logs every record written to it through tb.Logf.
+type testWriter struct {
+	tb testing.TB
+
+	mutex sync.Mutex
+	done  bool
+}
+
+// Write logs p without its trailing newline.
+func (writer *testWriter) Write(p []byte) (int, error) {
+	writer.mutex.Lock()
+	defer writer.mutex.Unlock()
+	if writer.done {
+		return len(p), nil
+	}
+	writer.tb.Helper()
+	writer.tb.Logf("%s", bytes.TrimSuffix(p, []byte{'\n'}))
+	return len(p), nil
+}
+
+func (writer *testWriter) close() {
+	writer.mutex.Lock()
+	writer.done = true
+	writer.mutex.Unlock()
+}
@@ -0,0 +1,71 @@
+package golog
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"testing"
+)
+
+// fakeTB records what NewTestLogger passes to a testing.TB.
+type fakeTB struct {
+	testing.TB
+	logs     []string
+	errors   []string
+	cleanups []func()
+}
+
+func (tb *fakeTB) Errorf(format string, args ...any) {
+	tb.errors = append(tb.errors, fmt.Sprintf(format, args...))
+}
+
+func (tb *fakeTB) Fatalf(format string, args ...any) {
+	tb.errors = append(tb.errors, fmt.Sprintf(format, args...))
+}
+
+// finish runs the registered cleanups like the end of a test.
+func (tb *fakeTB) finish() {
+	for _, cleanup := range slices.Backward(tb.cleanups) {
+		cleanup()
+	}
+	tb.cleanups = nil
+}
+
+func (tb *fakeTB) Helper()      {}
+func (tb *fakeTB) Name() string { return "TestParent/sub" }
+
+func (tb *fakeTB) Logf(format string, args ...any) {
+	tb.logs = append(tb.logs, fmt.Sprintf(format, args...))
+}
+
+func (tb *fakeTB) Cleanup(cleanup func()) {
+	tb.cleanups = append(tb.cleanups, cleanup)
+}
+
+func TestNewTestLogger(t *testing.T) {
+	tb := &fakeTB{}
+	jl := NewTestLogger(tb, WithBaseField("service", "api"))
+	jl.Debug("connecting", Str("addr", ":5432"))
+
+	if len(tb.logs) != 1 {
+		t.Fatalf("expected one Logf call, got %v", tb.logs)
+	}
+	var entry map[string]any
+	if err := json.Unmarshal([]byte(tb.logs[0]), &entry); err != nil {
+		t.Fatalf("expected one JSON entry without a trailing newline, got %q: %v", tb.logs[0], err)
+	}
+	if entry["level"] != "debug" || entry[TestNameKey] != "TestParent/sub" || entry["service"] != "api" || entry["addr"] != ":5432" {
+		t.Fatalf("unexpected entry: %v", entry)
+	}
+
+	tb.finish()
+	jl.Info("late")
+	if len(tb.logs) != 1 {
+		t.Fatalf("expected entries after the test finished to be dropped, got %v", tb.logs)
+	}
+}
+
+func TestNewTestLoggerWithRealT(t *testing.T) {
+	jl := NewTestLogger(t)
+	jl.Info("visible with -v", Int("n", 1))
+}
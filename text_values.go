@@ -0,0 +1,78 @@
+package golog
+
+import (
+	"net"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"time"
+)
+
+// The types below have a natural string form but encode badly by their
+// structure: net.IP is a byte slice (base64), the netip types and url.URL
+// have unexported or nested fields, and time.Month and time.Weekday are
+// integers. The encoders write them as strings instead.
+var (
+	ipType       = reflect.TypeFor[net.IP]()
+	addrType     = reflect.TypeFor[netip.Addr]()
+	addrPortType = reflect.TypeFor[netip.AddrPort]()
+	prefixType   = reflect.TypeFor[netip.Prefix]()
+	urlType      = reflect.TypeFor[url.URL]()
+	monthType    = reflect.TypeFor[time.Month]()
+	weekdayType  = reflect.TypeFor[time.Weekday]()
+)
+
+// isTextValueType reports whether values of valueType are written as
+// strings by appendTextValue.
+func isTextValueType(valueType reflect.Type) bool {
+	switch valueType {
+	case ipType, addrType, addrPortType, prefixType, urlType, monthType, weekdayType:
+		return true
+	}
+	return false
+}
+
+// textForm returns the string form of value, one of the types listed above
+// or a *url.URL. It reports false for empty addresses and nil URLs, which
+// are written as null. URLs have their password masked, as by
+// url.URL.Redacted.
+func textForm(value any) (string, bool) {
+	switch typedValue := value.(type) {
+	case net.IP:
+		if len(typedValue) == 0 {
+			return "", false
+		}
+		if addr, ok := netip.AddrFromSlice(typedValue); ok {
+			return addr.Unmap().String(), true
+		}
+		return typedValue.String(), true
+	case netip.Addr:
+		return typedValue.String(), typedValue.IsValid()
+	case netip.AddrPort:
+		return typedValue.String(), typedValue.IsValid()
+	case netip.Prefix:
+		return typedValue.String(), typedValue.IsValid()
+	case *url.URL:
+		if typedValue == nil {
+			return "", false
+		}
+		return typedValue.Redacted(), true
+	case url.URL:
+		return typedValue.Redacted(), true
+	case time.Month:
+		return typedValue.String(), true
+	case time.Weekday:
+		return typedValue.String(), true
+	}
+	return "", false
+}
+
+// appendTextValue writes the string form of value as a string value, so
+// scrubbers and WithMaxFieldLength apply to it.
+func (enc *encoder) appendTextValue(dst []byte, value any) []byte {
+	text, ok := textForm(value)
+	if !ok {
+		return append(dst, "null"...)
+	}
+	return enc.appendStringValue(dst, text)
+}
@@ -0,0 +1,109 @@
+package golog
+
+import (
+	"encoding/binary"
+	"sync/atomic"
+	"time"
+)
+
+// secondTextLen is the length of the "2006-01-02T15:04:05" part of an
+// RFC 3339 timestamp.
+const secondTextLen = 19
+
+// secondCache remembers the formatted date and time of the most recently
+// logged second, so entries written within the same second skip the calendar
+// arithmetic and only format the fraction.
+//
+// It is a seqlock over atomics: it never allocates, a writer that loses the
+// race simply doesn't update the cache, and readers that observe a write in
+// progress format the second themselves.
+type secondCache struct {
+	version atomic.Uint64
+	second  atomic.Int64
+	text    [3]atomic.Uint64
+}
+
+// timestampSecondCache is shared by every logger; the formatted text of a
+// second is the same regardless of configuration.
+var timestampSecondCache secondCache
+
+// appendSecond appends t (in UTC) formatted as "2006-01-02T15:04:05".
+func (cache *secondCache) appendSecond(dst []byte, t time.Time) []byte {
+	second := t.Unix()
+	var text [24]byte
+
+	version := cache.version.Load()
+	if version&1 == 0 && cache.second.Load() == second {
+		for i := range cache.text {
+			binary.LittleEndian.PutUint64(text[i*8:], cache.text[i].Load())
+		}
+		if cache.version.Load() == version {
+			return append(dst, text[:secondTextLen]...)
+		}
+	}
+
+	formatted := appendSecondText(text[:0], t)
+	cache.store(second, &text)
+	return append(dst, formatted...)
+}
+
+// store publishes text as the formatting of second unless another writer is
+// already updating the cache.
+func (cache *secondCache) store(second int64, text *[24]byte) {
+	version := cache.version.Load()
+	if version&1 != 0 || !cache.version.CompareAndSwap(version, version+1) {
+		return
+	}
+	cache.second.Store(second)
+	for i := range cache.text {
+		cache.text[i].Store(binary.LittleEndian.Uint64(text[i*8:]))
+	}
+	cache.version.Store(version + 2)
+}
+
+// fixedTimeLayouts maps the fixed-width RFC 3339 layouts, which all render
+// UTC times with a trailing "Z", to their number of fractional digits.
+var fixedTimeLayouts = map[string]int{
+	time.RFC3339:                          0,
+	RFC3339Milli:                          3,
+	RFC3339Micro:                          6,
+	"2006-01-02T15:04:05.000000000Z07:00": 9,
+	"2006-01-02T15:04:05Z":                0,
+	"2006-01-02T15:04:05.000Z":            3,
+	"2006-01-02T15:04:05.000000Z":         6,
+	"2006-01-02T15:04:05.000000000Z":      9,
+}
+
+// appendRFC3339FixedUTC appends t (in UTC) as RFC 3339 with exactly digits
+// fractional digits, truncating like time.Time.Format.
+func appendRFC3339FixedUTC(dst []byte, t time.Time, digits int) []byte {
+	dst = timestampSecondCache.appendSecond(dst, t)
+	if digits > 0 {
+		var frac [10]byte
+		frac[0] = '.'
+		nsec := t.Nanosecond()
+		for i := 9; i > 0; i-- {
+			frac[i] = decimalDigits[nsec%10]
+			nsec /= 10
+		}
+		dst = append(dst, frac[:digits+1]...)
+	}
+	return append(dst, 'Z')
+}
+
+func appendSecondText(dst []byte, t time.Time) []byte {
+	year, month, day := t.Date()
+	hour, minute, sec := t.Clock()
+
+	dst = appendFourDigitNumber(dst, year)
+	dst = append(dst, '-')
+	dst = appendTwoDigitNumber(dst, int(month))
+	dst = append(dst, '-')
+	dst = appendTwoDigitNumber(dst, day)
+	dst = append(dst, 'T')
+	dst = appendTwoDigitNumber(dst, hour)
+	dst = append(dst, ':')
+	dst = appendTwoDigitNumber(dst, minute)
+	dst = append(dst, ':')
+	return appendTwoDigitNumber(dst, sec)
+}
@@ -0,0 +1,95 @@
+package golog
+
+import (
+	"io"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAppendRFC3339NanoUTCMatchesTimeFormat(t *testing.T) {
+	base := time.Date(2024, 2, 29, 23, 59, 58, 0, time.UTC)
+	offsets := []time.Duration{0, 1, 120 * time.Millisecond, time.Second, time.Second + 5, 2 * time.Second, -time.Hour * 24 * 400, 0}
+
+	for _, offset := range offsets {
+		ts := base.Add(offset)
+		got := string(appendRFC3339NanoUTC(nil, ts))
+		if want := ts.Format(time.RFC3339Nano); got != want {
+			t.Fatalf("expected %q, got %q", want, got)
+		}
+	}
+}
+
+func TestSecondCacheConcurrentUse(t *testing.T) {
+	var wg sync.WaitGroup
+	for worker := 0; worker < 8; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ts := time.Date(2000+worker, 1, 1, 0, 0, 0, 0, time.UTC)
+			for i := 0; i < 500; i++ {
+				current := ts.Add(time.Duration(i%3) * time.Second)
+				if got, want := string(appendRFC3339NanoUTC(nil, current)), current.Format(time.RFC3339Nano); got != want {
+					t.Errorf("expected %q, got %q", want, got)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestNoFieldCallDoesNotAllocate(t *testing.T) {
+	jl := NewJSONLoggerWithOptions(WithOutput(io.Discard), WithBaseField("service", "api"))
+
+	allocs := testing.AllocsPerRun(100, func() {
+		jl.Info("no fields")
+	})
+	if allocs != 0 {
+		t.Fatalf("expected zero allocations for a call without fields, got %v", allocs)
+	}
+}
+
+func TestAppendRFC3339FixedUTCMatchesTimeFormat(t *testing.T) {
+	base := time.Date(2024, 2, 29, 23, 59, 58, 0, time.UTC)
+	offsets := []time.Duration{0, 1, 7 * time.Microsecond, 120 * time.Millisecond, 999999999, time.Second + 5, -time.Hour * 24 * 400}
+
+	for layout, digits := range fixedTimeLayouts {
+		for _, offset := range offsets {
+			ts := base.Add(offset)
+			got := string(appendRFC3339FixedUTC(nil, ts, digits))
+			if want := ts.Format(layout); got != want {
+				t.Fatalf("layout %q: expected %q, got %q", layout, want, got)
+			}
+		}
+	}
+}
+
+func TestFixedTimeFormatDoesNotAllocate(t *testing.T) {
+	jl := NewJSONLoggerWithOptions(WithOutput(io.Discard), WithCustomTimeFormat(RFC3339Milli))
+
+	allocs := testing.AllocsPerRun(100, func() {
+		jl.Info("fixed width")
+	})
+	if allocs != 0 {
+		t.Fatalf("expected zero allocations with RFC3339Milli, got %v", allocs)
+	}
+}
+
+func BenchmarkTimestampFixedMilli(b *testing.B) {
+	ts := time.Date(2024, 2, 29, 23, 59, 58, 123456789, time.UTC)
+	buf := make([]byte, 0, 64)
+	b.ReportAllocs()
+	for b.Loop() {
+		buf = appendRFC3339FixedUTC(buf[:0], ts, 3)
+	}
+}
+
+func BenchmarkTimestampAppendFormatMilli(b *testing.B) {
+	ts := time.Date(2024, 2, 29, 23, 59, 58, 123456789, time.UTC)
+	buf := make([]byte, 0, 64)
+	b.ReportAllocs()
+	for b.Loop() {
+		buf = ts.AppendFormat(buf[:0], RFC3339Milli)
+	}
+}
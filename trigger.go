@@ -0,0 +1,121 @@
+package golog
+
+import (
+	"sync"
+	"time"
+)
+
+// AlertMessage is the message of the entries written by WithTrigger when it
+// has no action.
+const AlertMessage = "log alert threshold exceeded"
+
+// Keys of the fields on the alert entries written by WithTrigger.
+const (
+	AlertLevelKey  = "alert_level"
+	AlertCountKey  = "alert_count"
+	AlertWindowKey = "alert_window"
+)
+
+// Alert describes a WithTrigger threshold being exceeded.
+type Alert struct {
+	// Level is the trigger's level; entries at it or above were counted.
+	Level Level
+	// Count is the number of those entries within Window, one more than the
+	// trigger's count.
+	Count  int
+	Window time.Duration
+	// Time is the time of the entry that exceeded the threshold.
+	Time time.Time
+}
+
+// WithTrigger fires when more than count entries at level or above are
+// written within window, for self-alerting in deployments without external
+// monitoring:
+//
+//	// Page when more than 50 errors are logged in 10 seconds.
+//	golog.WithTrigger(golog.ErrorLevel, 50, 10*time.Second, func(alert golog.Alert) {
+//	    go pager.Notify(alert)
+//	})
+//
+// action is called after the entry that crossed the threshold has been
+// written. A nil action writes an error entry with AlertMessage and fields
+// for the level, count and window instead. Counting starts over once the
+// trigger fires, so a sustained burst fires once per count+1 entries rather
+// than for each one. Entries are counted by their time, so WithClock and
+// WithEntryTime apply. Like middleware (see WithMiddleware), action runs
+// while the entry is being written and must not log through the same
+// logger. A negative count, a window that isn't positive or
+// OffLevel disables the trigger.
+func WithTrigger(level Level, count int, window time.Duration, action func(alert Alert)) Option {
+	if count < 0 || window <= 0 || level == OffLevel {
+		return func(*JSONLogger) {}
+	}
+	return func(jsonLogger *JSONLogger) {
+		trigger := &trigger{
+			level:  level,
+			window: window,
+			action: action,
+			times:  make([]time.Time, count+1),
+		}
+		WithMiddleware(trigger.middleware)(jsonLogger)
+	}
+}
+
+// trigger keeps the times of the last count+1 matching entries in a ring.
+type trigger struct {
+	level  Level
+	window time.Duration
+	action func(alert Alert)
+
+	mutex sync.Mutex
+	times []time.Time
+	// next is the ring index the next time is stored at, and filled the
+	// number of times stored since the trigger last fired.
+	next   int
+	filled int
+}
+
+func (trigger *trigger) middleware(next EntryFunc) EntryFunc {
+	return func(entry Entry) {
+		next(entry)
+		if entry.Level < trigger.level {
+			return
+		}
+		alert, fired := trigger.record(entry.Time)
+		if !fired {
+			return
+		}
+		if trigger.action != nil {
+			trigger.action(alert)
+			return
+		}
+		next(Entry{
+			Time:    entry.Time,
+			Level:   ErrorLevel,
+			Message: AlertMessage,
+			Fields: []Field{
+				Str(AlertLevelKey, alert.Level.String()),
+				Int(AlertCountKey, alert.Count),
+				Duration(AlertWindowKey, alert.Window),
+			},
+			logger: entry.logger,
+		})
+	}
+}
+
+// record adds an entry at now and reports whether the oldest of the last
+// count+1 entries is still within the window.
+func (trigger *trigger) record(now time.Time) (Alert, bool) {
+	trigger.mutex.Lock()
+	defer trigger.mutex.Unlock()
+
+	trigger.times[trigger.next] = now
+	trigger.next = (trigger.next + 1) % len(trigger.times)
+	trigger.filled = min(trigger.filled+1, len(trigger.times))
+	// With the ring full, next is the index of the oldest time.
+	if trigger.filled < len(trigger.times) || now.Sub(trigger.times[trigger.next]) >= trigger.window {
+		return Alert{}, false
+	}
+	trigger.filled = 0
+	return Alert{Level: trigger.level, Count: len(trigger.times), Window: trigger.window, Time: now}, true
+}
@@ -0,0 +1,93 @@
+package golog
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestWithTriggerAction(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	var alerts []Alert
+	jl := NewJSONLoggerWithOptions(
+		WithOutput(io.Discard),
+		WithClock(func() time.Time { return now }),
+		WithTrigger(ErrorLevel, 2, 10*time.Second, func(alert Alert) { alerts = append(alerts, alert) }),
+	)
+
+	jl.Error("a")
+	jl.Warn("not counted")
+	now = now.Add(11 * time.Second)
+	jl.Error("b")
+	jl.Error("c")
+	if len(alerts) != 0 {
+		t.Fatalf("expected errors spread over more than the window not to fire, got %v", alerts)
+	}
+	now = now.Add(time.Second)
+	jl.Error("d")
+	if len(alerts) != 1 {
+		t.Fatalf("expected 3 errors within 10s to fire once, got %v", alerts)
+	}
+	if alert := alerts[0]; alert.Level != ErrorLevel || alert.Count != 3 || alert.Window != 10*time.Second || !alert.Time.Equal(now) {
+		t.Fatalf("unexpected alert: %+v", alert)
+	}
+
+	jl.Error("e")
+	jl.Error("f")
+	if len(alerts) != 1 {
+		t.Fatalf("expected counting to start over after firing, got %v", alerts)
+	}
+	jl.Error("g")
+	if len(alerts) != 2 {
+		t.Fatalf("expected a second alert, got %v", alerts)
+	}
+}
+
+func TestWithTriggerEntry(t *testing.T) {
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(WithOutput(buf), WithTrigger(WarnLevel, 1, time.Minute, nil))
+	jl.Info("ignored")
+	jl.Warn("slow query")
+	jl.Error("query failed")
+
+	entries := readEntries(t, buf.Bytes())
+	if len(entries) != 4 {
+		t.Fatalf("expected 3 entries and an alert, got %d", len(entries))
+	}
+	alert := entries[3]
+	fields := alert.FieldMap()
+	if alert.Level != ErrorLevel || alert.Message != AlertMessage || fields[AlertLevelKey] != "warn" ||
+		fields[AlertCountKey] != int64(2) || fields[AlertWindowKey] != int64(time.Minute) {
+		t.Fatalf("unexpected alert entry: %+v %v", alert, fields)
+	}
+}
+
+func TestWithTriggerDisabled(t *testing.T) {
+	calls := 0
+	action := func(Alert) { calls++ }
+	jl := NewJSONLoggerWithOptions(
+		WithOutput(io.Discard),
+		WithTrigger(ErrorLevel, -1, time.Second, action),
+		WithTrigger(ErrorLevel, 0, 0, action),
+		WithTrigger(OffLevel, 0, time.Second, action),
+	)
+	jl.Error("a")
+	jl.Error("b")
+	if calls != 0 || jl.middleware != nil {
+		t.Fatalf("expected invalid triggers to be ignored, got %d calls", calls)
+	}
+}
+
+func TestWithTriggerAllocations(t *testing.T) {
+	if raceEnabled {
+		t.Skip("sync.Pool drops items under the race detector")
+	}
+	jl := NewJSONLoggerWithOptions(WithOutput(io.Discard), WithTrigger(ErrorLevel, 1000, time.Second, func(Alert) {}))
+	allocs := testing.AllocsPerRun(100, func() {
+		jl.Error("msg", Int("n", 1))
+	})
+	if allocs != 0 {
+		t.Fatalf("expected counting not to allocate, got %v", allocs)
+	}
+}
@@ -0,0 +1,275 @@
+package golog
+
+import (
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// WatchdogWriteDurationKey carries how long the stalled write has been (or
+// was) in progress on the warnings written by WithWriteWatchdog.
+const WatchdogWriteDurationKey = "write_duration"
+
+const defaultWatchdogThreshold = time.Second
+
+// WatchdogConfig tunes WithWriteWatchdog. Zero fields use the defaults.
+type WatchdogConfig struct {
+	// Threshold is how long a single Write may take before the output
+	// counts as stalled. Defaults to 1s.
+	Threshold time.Duration
+	// Notify receives the "log output stalled" and "log output recovered"
+	// warnings. It should not share the watched destination. Defaults to
+	// JSON lines on os.Stderr.
+	Notify Sink
+	// DropOnStall drops entries while the output is stalled instead of
+	// queuing them behind the hung write, so logging calls return at once.
+	// Drops are counted in Stats.DroppedEntries.
+	DropOnStall bool
+}
+
+// WithWriteWatchdog guards against an output that stops returning from
+// Write, such as a file on a hung NFS mount. A background goroutine watches
+// the write in progress; once it exceeds Threshold, a warning goes to
+// Notify and, with DropOnStall, later entries are dropped rather than
+// freezing every logging goroutine behind it. A second warning reports the
+// recovery and the number of entries dropped once the write returns. Writes
+// slower than Threshold are counted in Stats.SlowWrites.
+//
+// The stalled Write itself can't be interrupted: the goroutine that made it
+// stays blocked until the output returns. Wraps the output configured so
+// far; pass it after WithOutput and before WithAsync. Call Close to stop the
+// watchdog.
+func WithWriteWatchdog(config WatchdogConfig) Option {
+	return func(jsonLogger *JSONLogger) {
+		jsonLogger.watchdog = newWatchdogWriter(jsonLogger.output, config)
+		jsonLogger.output = jsonLogger.watchdog
+		// The watchdog serializes writes itself, and waiters must be able to
+		// give up on a stalled write rather than queue on the logger's lock.
+		jsonLogger.lockWrites = false
+	}
+}
+
+// watchdogWriter serializes writes to output and watches their latency.
+type watchdogWriter struct {
+	output io.Writer
+	config WatchdogConfig
+
+	// slot holds a token while a Write is in progress.
+	slot chan struct{}
+	// writeStart is the UnixNano start of the write in progress, or 0.
+	writeStart atomic.Int64
+	stalled    atomic.Bool
+	closed     atomic.Bool
+	dropped    atomic.Uint64
+	slowWrites atomic.Uint64
+
+	// mutex guards stall and droppedAtStall, and orders the notifications.
+	mutex sync.Mutex
+	// stall is closed while the output is stalled under DropOnStall.
+	stall          chan struct{}
+	droppedAtStall uint64
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+func newWatchdogWriter(output io.Writer, config WatchdogConfig) *watchdogWriter {
+	if config.Threshold <= 0 {
+		config.Threshold = defaultWatchdogThreshold
+	}
+	if config.Notify == nil {
+		config.Notify = NewWriterSink(os.Stderr, nil)
+	}
+	writer := &watchdogWriter{
+		output: output,
+		config: config,
+		slot:   make(chan struct{}, 1),
+		stall:  make(chan struct{}),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	go writer.run()
+	return writer
+}
+
+// Write writes p to the output unless the output is stalled under
+// DropOnStall, in which case p is dropped.
+func (writer *watchdogWriter) Write(p []byte) (int, error) {
+	if writer.closed.Load() {
+		return 0, ErrWriterClosed
+	}
+	if !writer.acquire() {
+		writer.dropped.Add(1)
+		return len(p), nil
+	}
+
+	start := time.Now()
+	writer.writeStart.Store(start.UnixNano())
+	n, err := writer.output.Write(p)
+	writer.writeStart.Store(0)
+	<-writer.slot
+
+	elapsed := time.Since(start)
+	if elapsed > writer.config.Threshold {
+		writer.slowWrites.Add(1)
+	}
+	if writer.stalled.Load() {
+		writer.recover(elapsed)
+	}
+	return n, err
+}
+
+// acquire takes the write slot. It reports false when the output stalled
+// under DropOnStall while waiting.
+func (writer *watchdogWriter) acquire() bool {
+	var stall chan struct{}
+	if writer.config.DropOnStall {
+		writer.mutex.Lock()
+		stall = writer.stall
+		writer.mutex.Unlock()
+	}
+	select {
+	case writer.slot <- struct{}{}:
+		return true
+	case <-stall:
+		return false
+	}
+}
+
+func (writer *watchdogWriter) run() {
+	defer close(writer.done)
+	interval := max(writer.config.Threshold/4, time.Millisecond)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			writer.check(now)
+		case <-writer.stop:
+			return
+		}
+	}
+}
+
+// check marks the output stalled when the write in progress has exceeded
+// the threshold.
+func (writer *watchdogWriter) check(now time.Time) {
+	start := writer.writeStart.Load()
+	if start == 0 || writer.stalled.Load() {
+		return
+	}
+	elapsed := time.Duration(now.UnixNano() - start)
+	if elapsed <= writer.config.Threshold {
+		return
+	}
+
+	writer.mutex.Lock()
+	defer writer.mutex.Unlock()
+	// Set stalled before looking at the write again: either the write is
+	// still in progress, and its Write sees stalled and recovers, or it has
+	// finished and the stall is undone here.
+	writer.stalled.Store(true)
+	if writer.writeStart.Load() != start {
+		writer.stalled.Store(false)
+		return
+	}
+	if writer.config.DropOnStall {
+		close(writer.stall)
+	}
+	writer.droppedAtStall = writer.dropped.Load()
+	writer.notify(now, "log output stalled",
+		Duration(WatchdogWriteDurationKey, elapsed),
+		Bool("dropping", writer.config.DropOnStall),
+	)
+}
+
+// recover ends a stall once the stalled write has returned.
+func (writer *watchdogWriter) recover(elapsed time.Duration) {
+	writer.mutex.Lock()
+	defer writer.mutex.Unlock()
+	if !writer.stalled.Load() {
+		return
+	}
+	writer.stalled.Store(false)
+	if writer.config.DropOnStall {
+		writer.stall = make(chan struct{})
+	}
+	writer.notify(time.Now(), "log output recovered",
+		Duration(WatchdogWriteDurationKey, elapsed),
+		Field{key: HealthDroppedEntriesKey, uintVal: writer.dropped.Load() - writer.droppedAtStall, kind: fieldKindUint},
+	)
+}
+
+func (writer *watchdogWriter) notify(now time.Time, message string, fields ...Field) {
+	_ = writer.config.Notify.Write(Entry{Time: now, Level: WarnLevel, Message: message, Fields: fields})
+}
+
+// Flush flushes the output if it buffers data. It returns ErrOutputStalled
+// without waiting when the output is stalled under DropOnStall.
+func (writer *watchdogWriter) Flush() error {
+	flusher, ok := writer.output.(interface{ Flush() error })
+	if !ok {
+		return nil
+	}
+	if !writer.acquire() {
+		return ErrOutputStalled
+	}
+	defer func() { <-writer.slot }()
+	return flusher.Flush()
+}
+
+// Reopen reopens the wrapped output.
+func (writer *watchdogWriter) Reopen() error {
+	if !writer.acquire() {
+		return ErrOutputStalled
+	}
+	defer func() { <-writer.slot }()
+	return reopenOutput(writer.output)
+}
+
+// Unwrap returns the watched output.
+func (writer *watchdogWriter) Unwrap() io.Writer {
+	return writer.output
+}
+
+// closeWrapper stops the watchdog and closes the Notify sink, leaving the
+// output open.
+func (writer *watchdogWriter) closeWrapper() error {
+	if writer.closed.Swap(true) {
+		return nil
+	}
+	close(writer.stop)
+	<-writer.done
+	return writer.config.Notify.Close()
+}
+
+// Close stops the watchdog, then flushes and closes the output (standard
+// streams excepted) and the Notify sink. A stalled output under DropOnStall
+// is left open and ErrOutputStalled returned; without DropOnStall Close
+// waits for the stalled write, so bound it with Shutdown. It is safe to call
+// more than once.
+func (writer *watchdogWriter) Close() error {
+	if writer.closed.Swap(true) {
+		return nil
+	}
+	close(writer.stop)
+	<-writer.done
+
+	if !writer.acquire() {
+		return ErrOutputStalled
+	}
+	var err error
+	if flusher, ok := writer.output.(interface{ Flush() error }); ok {
+		err = flusher.Flush()
+	}
+	if closeErr := closeOutput(writer.output); err == nil {
+		err = closeErr
+	}
+	<-writer.slot
+	if closeErr := writer.config.Notify.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
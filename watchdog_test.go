@@ -0,0 +1,233 @@
+package golog
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// gateWriter blocks every Write while its gate is closed.
+type gateWriter struct {
+	mutex   sync.Mutex
+	buffer  bytes.Buffer
+	gate    chan struct{}
+	entered chan struct{}
+}
+
+func newGateWriter() *gateWriter {
+	return &gateWriter{entered: make(chan struct{}, 16)}
+}
+
+func (writer *gateWriter) block() {
+	writer.mutex.Lock()
+	writer.gate = make(chan struct{})
+	writer.mutex.Unlock()
+}
+
+func (writer *gateWriter) release() {
+	writer.mutex.Lock()
+	close(writer.gate)
+	writer.gate = nil
+	writer.mutex.Unlock()
+}
+
+func (writer *gateWriter) Write(p []byte) (int, error) {
+	writer.mutex.Lock()
+	gate := writer.gate
+	writer.mutex.Unlock()
+	writer.entered <- struct{}{}
+	if gate != nil {
+		<-gate
+	}
+	writer.mutex.Lock()
+	defer writer.mutex.Unlock()
+	return writer.buffer.Write(p)
+}
+
+func (writer *gateWriter) Bytes() []byte {
+	writer.mutex.Lock()
+	defer writer.mutex.Unlock()
+	return append([]byte(nil), writer.buffer.Bytes()...)
+}
+
+// lockedSink is a recordingSink safe for concurrent use.
+type lockedSink struct {
+	mutex sync.Mutex
+	sink  recordingSink
+}
+
+func (sink *lockedSink) Write(entry Entry) error {
+	sink.mutex.Lock()
+	defer sink.mutex.Unlock()
+	return sink.sink.Write(entry)
+}
+
+func (sink *lockedSink) Flush() error { return nil }
+
+func (sink *lockedSink) Close() error {
+	sink.mutex.Lock()
+	defer sink.mutex.Unlock()
+	return sink.sink.Close()
+}
+
+func (sink *lockedSink) messages() []string {
+	sink.mutex.Lock()
+	defer sink.mutex.Unlock()
+	var messages []string
+	for _, entry := range sink.sink.entries {
+		messages = append(messages, entry.Message)
+	}
+	return messages
+}
+
+func waitForMessages(t *testing.T, sink *lockedSink, count int) []string {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if messages := sink.messages(); len(messages) >= count {
+			return messages
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("expected %d watchdog warnings, got %v", count, sink.messages())
+	return nil
+}
+
+func TestWriteWatchdogDropsWhileStalled(t *testing.T) {
+	output := newGateWriter()
+	notify := &lockedSink{}
+	jl := NewJSONLoggerWithOptions(
+		WithOutput(output),
+		WithWriteWatchdog(WatchdogConfig{Threshold: 10 * time.Millisecond, Notify: notify, DropOnStall: true}),
+	)
+
+	output.block()
+	stuck := make(chan struct{})
+	go func() {
+		defer close(stuck)
+		jl.Info("stuck")
+	}()
+	<-output.entered
+
+	if messages := waitForMessages(t, notify, 1); messages[0] != "log output stalled" {
+		t.Fatalf("expected a stall warning, got %v", messages)
+	}
+	jl.Info("dropped")
+	jl.Warn("dropped too")
+	if stats := jl.Stats(); stats.DroppedEntries != 2 {
+		t.Fatalf("expected two dropped entries, got %+v", stats)
+	}
+	if err := jl.Flush(); err != nil {
+		t.Fatalf("expected Flush to skip an output without Flush, got %v", err)
+	}
+
+	output.release()
+	<-stuck
+	messages := waitForMessages(t, notify, 2)
+	if messages[1] != "log output recovered" {
+		t.Fatalf("expected a recovery warning, got %v", messages)
+	}
+	recovered := notify.sink.entries[1]
+	if field, ok := recovered.Field(HealthDroppedEntriesKey); !ok || field.Value() != uint64(2) {
+		t.Fatalf("expected the recovery to report two drops, got %+v", recovered.Fields)
+	}
+	if stats := jl.Stats(); stats.SlowWrites != 1 {
+		t.Fatalf("expected one slow write, got %+v", stats)
+	}
+
+	jl.Info("after")
+	entries := readEntries(t, output.Bytes())
+	if len(entries) != 2 || entries[0].Message != "stuck" || entries[1].Message != "after" {
+		t.Fatalf("expected only the stuck and later entries, got %+v", entries)
+	}
+	if err := jl.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !notify.sink.closed {
+		t.Fatal("expected Close to close the notify sink")
+	}
+}
+
+func TestWriteWatchdogDropsWithSequenceNumbers(t *testing.T) {
+	output := newGateWriter()
+	notify := &lockedSink{}
+	jl := NewJSONLoggerWithOptions(
+		WithOutput(output),
+		WithSequenceNumbers(),
+		WithWriteWatchdog(WatchdogConfig{Threshold: 10 * time.Millisecond, Notify: notify, DropOnStall: true}),
+	)
+
+	output.block()
+	stuck := make(chan struct{})
+	go func() {
+		defer close(stuck)
+		jl.Info("stuck")
+	}()
+	<-output.entered
+	waitForMessages(t, notify, 1)
+
+	dropped := make(chan struct{})
+	go func() {
+		defer close(dropped)
+		jl.Info("dropped")
+	}()
+	select {
+	case <-dropped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the entry to be dropped instead of waiting behind the stalled write")
+	}
+
+	output.release()
+	<-stuck
+	jl.Info("after")
+	entries := readEntries(t, output.Bytes())
+	if len(entries) != 2 {
+		t.Fatalf("expected the stuck and later entries, got %+v", entries)
+	}
+	if field, ok := entries[1].Field(SequenceKey); !ok || field.Value() != int64(3) {
+		t.Fatalf("expected the drop to leave a gap in seq, got %+v", entries[1].Fields)
+	}
+	if err := jl.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWriteWatchdogWaitsWithoutDropMode(t *testing.T) {
+	output := newGateWriter()
+	notify := &lockedSink{}
+	jl := NewJSONLoggerWithOptions(
+		WithOutput(output),
+		WithWriteWatchdog(WatchdogConfig{Threshold: 10 * time.Millisecond, Notify: notify}),
+	)
+
+	output.block()
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		jl.Info("first")
+	}()
+	<-output.entered
+	waitForMessages(t, notify, 1)
+	go func() {
+		defer wg.Done()
+		jl.Info("second")
+	}()
+
+	output.release()
+	wg.Wait()
+	if entries := readEntries(t, output.Bytes()); len(entries) != 2 {
+		t.Fatalf("expected both entries to be written, got %+v", entries)
+	}
+	if stats := jl.Stats(); stats.DroppedEntries != 0 {
+		t.Fatalf("expected no drops without DropOnStall, got %+v", stats)
+	}
+	if err := jl.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := jl.watchdog.Write([]byte("late\n")); !errors.Is(err, ErrWriterClosed) {
+		t.Fatalf("expected ErrWriterClosed after Close, got %v", err)
+	}
+}
@@ -0,0 +1,97 @@
+package golog
+
+import "time"
+
+// Well-known field keys. Logging common attributes under the same names in
+// every service lets one query or dashboard work across all of them; the
+// package's own fields, such as AccessLog's, use these names too.
+const (
+	KeyUserID     = "user_id"
+	KeyRequestID  = "request_id"
+	KeyTraceID    = "trace_id"
+	KeySpanID     = "span_id"
+	KeyTenantID   = "tenant_id"
+	KeySessionID  = "session_id"
+	KeyComponent  = "component"
+	KeyDurationMS = "duration_ms"
+	KeyMethod     = "method"
+	KeyPath       = "path"
+	KeyStatus     = "status"
+	KeyRemoteAddr = "remote_addr"
+	KeyUserAgent  = "user_agent"
+)
+
+// Fields builds a list of fields under the well-known keys:
+//
+//	fields := golog.Fields{}.User(userID).Request(requestID).Duration(elapsed)
+//	jl.Info("checkout completed", fields...)
+//	jl.Event("checkout", fields.Map())
+//
+// Each method returns a new list, so a shared prefix can be extended in
+// several directions without the results overwriting each other.
+type Fields []Field
+
+// Add returns the list with fields appended, for keys without a method.
+func (fields Fields) Add(extra ...Field) Fields {
+	return append(fields[:len(fields):len(fields)], extra...)
+}
+
+// User adds a user_id field. Use Add(Int(KeyUserID, id)) for numeric IDs.
+func (fields Fields) User(id string) Fields {
+	return fields.Add(Str(KeyUserID, id))
+}
+
+// Request adds a request_id field.
+func (fields Fields) Request(id string) Fields {
+	return fields.Add(Str(KeyRequestID, id))
+}
+
+// Trace adds trace_id and span_id fields. An empty spanID is left out.
+func (fields Fields) Trace(traceID, spanID string) Fields {
+	if spanID == "" {
+		return fields.Add(Str(KeyTraceID, traceID))
+	}
+	return fields.Add(Str(KeyTraceID, traceID), Str(KeySpanID, spanID))
+}
+
+// Tenant adds a tenant_id field.
+func (fields Fields) Tenant(id string) Fields {
+	return fields.Add(Str(KeyTenantID, id))
+}
+
+// Session adds a session_id field.
+func (fields Fields) Session(id string) Fields {
+	return fields.Add(Str(KeySessionID, id))
+}
+
+// Component adds a component field naming the subsystem that logs.
+func (fields Fields) Component(name string) Fields {
+	return fields.Add(Str(KeyComponent, name))
+}
+
+// Duration adds a duration_ms field holding d in fractional milliseconds,
+// whatever the logger's DurationFormat.
+func (fields Fields) Duration(d time.Duration) Fields {
+	return fields.Add(Float64(KeyDurationMS, float64(d)/float64(time.Millisecond)))
+}
+
+// Status adds a status field, such as an HTTP status code.
+func (fields Fields) Status(code int) Fields {
+	return fields.Add(Int(KeyStatus, code))
+}
+
+// Err adds the field built by Err.
+func (fields Fields) Err(err error) Fields {
+	return fields.Add(Err(err))
+}
+
+// Map returns the fields as a map, for the APIs that take one, such as
+// Event and WithBaseFields. Later fields win over earlier ones with the
+// same key.
+func (fields Fields) Map() map[string]any {
+	values := make(map[string]any, len(fields))
+	for _, field := range fields {
+		values[field.key] = field.Value()
+	}
+	return values
+}
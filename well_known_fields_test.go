@@ -0,0 +1,51 @@
+package golog
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFieldsBuilder(t *testing.T) {
+	base := Fields{}.User("u-1").Tenant("acme")
+	first := base.Request("r-1")
+	second := base.Request("r-2").Trace("t-1", "").Duration(1500 * time.Microsecond).Status(201).Err(errors.New("retry"))
+
+	if first[2].Value() != "r-1" || second[2].Value() != "r-2" {
+		t.Fatalf("expected branches from a shared prefix not to overwrite each other: %v %v", first, second)
+	}
+
+	buf := &bytes.Buffer{}
+	jl := NewJSONLoggerWithOptions(WithOutput(buf))
+	jl.Info("checkout", second.Session("s-1").Component("cart").Add(Int("items", 3))...)
+
+	fields := readEntries(t, buf.Bytes())[0].FieldMap()
+	want := map[string]any{
+		KeyUserID:     "u-1",
+		KeyTenantID:   "acme",
+		KeyRequestID:  "r-2",
+		KeyTraceID:    "t-1",
+		KeyDurationMS: 1.5,
+		KeyStatus:     int64(201),
+		ErrorKey:      "retry",
+		KeySessionID:  "s-1",
+		KeyComponent:  "cart",
+		"items":       int64(3),
+	}
+	if len(fields) != len(want) {
+		t.Fatalf("unexpected fields: %v", fields)
+	}
+	for key, value := range want {
+		if fields[key] != value {
+			t.Fatalf("%s: got %v, want %v", key, fields[key], value)
+		}
+	}
+}
+
+func TestFieldsMap(t *testing.T) {
+	values := Fields{}.Trace("t-1", "s-1").Status(200).Add(Int(KeyStatus, 404)).Map()
+	if len(values) != 3 || values[KeyTraceID] != "t-1" || values[KeySpanID] != "s-1" || values[KeyStatus] != int64(404) {
+		t.Fatalf("unexpected map: %v", values)
+	}
+}